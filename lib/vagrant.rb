@@ -69,8 +69,21 @@ class VagrantLogger < Log4r::Logger
   end
 end
 
-if ENV["VAGRANT_LOG"] && ENV["VAGRANT_LOG"] != ""
-  level = Log4r::LNAMES.index(ENV["VAGRANT_LOG"].upcase)
+# The levels of subsystems, such as the communicator, can be set
+# separately from the level of all other logs. Like an invalid
+# VAGRANT_LOG level, an invalid filter is ignored.
+log_filter = {}
+log_filter_error = nil
+if ENV["VAGRANT_LOG_FILTER"] && ENV["VAGRANT_LOG_FILTER"] != ""
+  begin
+    log_filter = Vagrant::Util::Logging.parse_filter(ENV["VAGRANT_LOG_FILTER"])
+  rescue ArgumentError => e
+    log_filter_error = e.message
+  end
+end
+
+if (ENV["VAGRANT_LOG"] && ENV["VAGRANT_LOG"] != "") || !log_filter.empty?
+  level = Log4r::LNAMES.index(ENV["VAGRANT_LOG"].to_s.upcase)
   if level.nil?
     level = Log4r::LNAMES.index("FATAL")
   end
@@ -87,10 +100,20 @@ if ENV["VAGRANT_LOG"] && ENV["VAGRANT_LOG"] != ""
   # Set the logging level on all "vagrant" namespaced
   # logs as long as we have a valid level.
   if level
+    base_formatter = Log4r::BasicFormatter.new
+    if ENV["VAGRANT_LOG_TIMESTAMP"]
+      base_formatter = Log4r::PatternFormatter.new(
+        pattern: "%d [%5l] %m",
+        date_pattern: "%F %T"
+      )
+    end
+    formatter = Vagrant::Util::LoggingFormatter.new(base_formatter)
+
     ["vagrant", "vagrantplugins"].each do |lname|
       logger = VagrantLogger.new(lname)
       if ENV["VAGRANT_LOG_FILE"] && ENV["VAGRANT_LOG_FILE"] != ""
-        logger.outputters = Log4r::FileOutputter.new("vagrant", filename: ENV["VAGRANT_LOG_FILE"])
+        logger.outputters = Log4r::FileOutputter.new("vagrant",
+          filename: ENV["VAGRANT_LOG_FILE"], formatter: formatter)
       else
         logger.outputters = Log4r::Outputter.stderr
       end
@@ -98,15 +121,12 @@ if ENV["VAGRANT_LOG"] && ENV["VAGRANT_LOG"] != ""
     end
     Log4r::RootLogger.instance.level = level
 
-    base_formatter = Log4r::BasicFormatter.new
-    if ENV["VAGRANT_LOG_TIMESTAMP"]
-      base_formatter = Log4r::PatternFormatter.new(
-        pattern: "%d [%5l] %m",
-        date_pattern: "%F %T"
-      )
-    end
+    Log4r::Outputter.stderr.formatter = formatter
 
-    Log4r::Outputter.stderr.formatter = Vagrant::Util::LoggingFormatter.new(base_formatter)
+    # Loggers of the subsystems are created before any other loggers
+    # so the loggers within them use their levels
+    Vagrant::Util::Logging.apply_filter(log_filter,
+      Log4r::Logger["vagrant"].outputters)
   end
 end
 
@@ -128,6 +148,9 @@ Vagrant.global_logger = global_logger
 global_logger.info("Vagrant version: #{Vagrant::VERSION}")
 global_logger.info("Ruby version: #{RUBY_VERSION}")
 global_logger.info("RubyGems version: #{Gem::VERSION}")
+if log_filter_error
+  global_logger.warn("Ignoring invalid VAGRANT_LOG_FILTER: #{log_filter_error}")
+end
 ENV.each do |k, v|
   next if k.start_with?("VAGRANT_OLD")
   global_logger.info("#{k}=#{v.inspect}") if k.start_with?("VAGRANT_")
@@ -170,16 +193,20 @@ module Vagrant
   autoload :Bundler,        'vagrant/bundler'
   autoload :CLI,            'vagrant/cli'
   autoload :CapabilityHost, 'vagrant/capability_host'
+  autoload :Client,         'vagrant/client'
   autoload :Config,         'vagrant/config'
   autoload :Environment,    'vagrant/environment'
   autoload :Errors,         'vagrant/errors'
   autoload :Guest,          'vagrant/guest'
+  autoload :GuestCache,     'vagrant/guest_cache'
   autoload :Host,           'vagrant/host'
   autoload :Machine,        'vagrant/machine'
   autoload :MachineIndex,   'vagrant/machine_index'
   autoload :MachineState,   'vagrant/machine_state'
   autoload :Plugin,         'vagrant/plugin'
+  autoload :ProviderState,  'vagrant/provider_state'
   autoload :Registry,       'vagrant/registry'
+  autoload :Secret,         'vagrant/secret'
   autoload :UI,             'vagrant/ui'
   autoload :Util,           'vagrant/util'
   autoload :Vagrantfile,    'vagrant/vagrantfile'
@@ -203,9 +230,11 @@ module Vagrant
     c.register([:"2", :config])       { Plugin::V2::Config }
     c.register([:"2", :guest])        { Plugin::V2::Guest }
     c.register([:"2", :host])         { Plugin::V2::Host }
+    c.register([:"2", :lifecycle])    { Plugin::V2::Lifecycle }
     c.register([:"2", :provider])     { Plugin::V2::Provider }
     c.register([:"2", :provisioner])  { Plugin::V2::Provisioner }
     c.register([:"2", :push])         { Plugin::V2::Push }
+    c.register([:"2", :secret_provider]) { Plugin::V2::SecretProvider }
     c.register([:"2", :synced_folder]) { Plugin::V2::SyncedFolder }
 
     c.register(:remote)               { Plugin::Remote::Plugin }
@@ -239,6 +268,18 @@ module Vagrant
     Plugin::Manager.instance.plugin_installed?(name, version)
   end
 
+  # This references a secret from the Vagrantfile so that the value
+  # does not need to be stored in the Vagrantfile. The reference is
+  # replaced with the value of the secret when the configuration is
+  # loaded. References have the format "PROVIDER:PATH#FIELD", such as
+  # "env:TOKEN" or "vault:secret/data/app#token".
+  #
+  # @param [String] reference
+  # @return [Secret::Reference]
+  def self.secret(reference)
+    Secret::Reference.new(reference)
+  end
+
   # Returns a superclass to use when creating a plugin for Vagrant.
   # Given a specific version, this returns a proper superclass to use
   # to register plugins for that version.
@@ -33,6 +33,8 @@ module Vagrant
       autoload :HandleBoxUrl, "vagrant/action/builtin/handle_box_url"
       autoload :HandleForwardedPortCollisions, "vagrant/action/builtin/handle_forwarded_port_collisions"
       autoload :HasProvisioner, "vagrant/action/builtin/has_provisioner"
+      autoload :HostsFileRemove, "vagrant/action/builtin/hosts_file_remove"
+      autoload :HostsFileUpdate, "vagrant/action/builtin/hosts_file_update"
       autoload :IsEnvSet, "vagrant/action/builtin/is_env_set"
       autoload :IsState, "vagrant/action/builtin/is_state"
       autoload :Lock, "vagrant/action/builtin/lock"
@@ -45,6 +47,7 @@ module Vagrant
       autoload :SetHostname, "vagrant/action/builtin/set_hostname"
       autoload :SSHExec, "vagrant/action/builtin/ssh_exec"
       autoload :SSHRun,  "vagrant/action/builtin/ssh_run"
+      autoload :SyncTime, "vagrant/action/builtin/sync_time"
       autoload :SyncedFolderCleanup, "vagrant/action/builtin/synced_folder_cleanup"
       autoload :SyncedFolders, "vagrant/action/builtin/synced_folders"
       autoload :Trigger, "vagrant/action/builtin/trigger"
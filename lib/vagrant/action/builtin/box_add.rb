@@ -6,6 +6,7 @@ require "log4r"
 require "pathname"
 require "uri"
 
+require "vagrant/box_checksum_verifier"
 require "vagrant/box_metadata"
 require "vagrant/util/downloader"
 require "vagrant/util/file_checksum"
@@ -192,6 +193,7 @@ module Vagrant
             env,
             checksum: env[:box_checksum],
             checksum_type: env[:box_checksum_type],
+            checksum_key: env[:box_checksum_key],
             architecture: env[:box_architecture]
           )
         end
@@ -393,6 +395,7 @@ module Vagrant
             env,
             checksum: metadata_provider.checksum,
             checksum_type: metadata_provider.checksum_type,
+            checksum_key: env[:box_checksum_key],
             architecture: arch_name,
           )
         end
@@ -465,18 +468,26 @@ module Vagrant
               else
                 env[:ui].detail(I18n.t("vagrant.actions.box.add.checksumming"))
                 validate_checksum(
-                  opts[:checksum_type], opts[:checksum], box_url)
+                  opts[:checksum_type], opts[:checksum], box_url,
+                  key: opts[:checksum_key])
               end
             end
 
             # Add the box!
-            box = env[:box_collection].add(
-              box_url, name, version,
+            add_opts = {
               force: env[:box_force],
               metadata_url: md_url,
               providers: provider,
               architecture: opts[:architecture]
-            )
+            }
+            begin
+              box = env[:box_collection].add(box_url, name, version, **add_opts)
+            rescue Errors::BoxBaseNotFound => e
+              # The box is derived from a base box which isn't installed,
+              # so add the base box first and then try again.
+              add_base_box(e.extra_data, env, **opts)
+              box = env[:box_collection].add(box_url, name, version, **add_opts)
+            end
           ensure
             # Make sure we delete the temporary file after we add it,
             # unless we were interrupted, in which case we keep it around
@@ -506,13 +517,46 @@ module Vagrant
           box
         end
 
+        # Adds the base box of a derived box. Errors adding the base box
+        # are reported as an error for the base box.
+        #
+        # @param [Hash] base Information about the base box
+        # @param [Hash] env
+        def add_base_box(base, env, **opts)
+          env[:ui].detail(I18n.t(
+            "vagrant.box_adding_base",
+            box: base[:box],
+            name: base[:name],
+            version: base[:version]))
+
+          env[:action_runner].run(Vagrant::Action.action_box_add, env.merge(
+            box_name: base[:name],
+            box_url: base[:url] || base[:name],
+            box_version: base[:version],
+            box_provider: base[:provider],
+            box_architecture: opts[:architecture],
+            box_force: false,
+            box_checksum: nil,
+            box_checksum_type: nil,
+          ))
+        rescue Errors::BoxAlreadyExists
+          # The base box was added by another process
+        rescue Errors::VagrantError => e
+          raise Errors::BoxBaseUnavailable,
+            box: base[:box],
+            name: base[:name],
+            version: base[:version],
+            error: e.message
+        end
+
         # Returns the download options for the download.
         #
         # @return [Hash]
         def downloader(url, env, **opts)
           opts[:ui] = true if !opts.key?(:ui)
 
-          temp_path = env[:tmp_path].join("box" + Digest::SHA1.hexdigest(url))
+          temp_path = env[:tmp_path].join("box" + Digest::SHA1.hexdigest(url) + ".part")
+          resume = env[:box_download_resume] != false
           @logger.info("Downloading box: #{url} => #{temp_path}")
 
           if File.file?(url) || url !~ /^[a-z0-9]+:.*$/i
@@ -531,6 +575,9 @@ module Vagrant
             if env[:box_clean]
               @logger.info("Cleaning existing temp box file.")
               delete = true
+            elsif !resume
+              @logger.info("Resume is disabled. Removing existing temp box file.")
+              delete = true
             elsif temp_path.mtime.to_i < (Time.now.to_i - RESUME_DELAY)
               @logger.info("Existing temp file is too old. Removing.")
               delete = true
@@ -542,8 +589,9 @@ module Vagrant
           downloader_options = {}
           downloader_options[:ca_cert] = env[:box_download_ca_cert]
           downloader_options[:ca_path] = env[:box_download_ca_path]
-          downloader_options[:continue] = true
+          downloader_options[:continue] = resume
           downloader_options[:insecure] = env[:box_download_insecure]
+          downloader_options[:limit_rate] = env[:box_download_limit_rate]
           downloader_options[:client_cert] = env[:box_download_client_cert]
           downloader_options[:headers] = ["Accept: application/json"] if opts[:json]
           downloader_options[:ui] = env[:ui] if opts[:ui]
@@ -662,18 +710,17 @@ module Vagrant
           !!(match.last.chomp =~ /application\/json/)
         end
 
-        def validate_checksum(checksum_type, _checksum, path)
-          checksum = _checksum.strip()
+        def validate_checksum(checksum_type, checksum, path, **opts)
           @logger.info("Validating checksum with #{checksum_type}")
-          @logger.info("Expected checksum: #{checksum}")
-
-          _actual = FileChecksum.new(path, checksum_type).checksum
-          actual = _actual.strip()
-          @logger.info("Actual checksum: #{actual}")
-          if actual.casecmp(checksum) != 0
-            raise Errors::BoxChecksumMismatch,
-              actual: actual,
-              expected: checksum
+
+          verifier = BoxChecksumVerifier.for(checksum_type, checksum, **opts)
+          begin
+            verifier.verify_file!(path)
+          rescue Errors::BoxChecksumMismatch, Errors::BoxSignatureInvalid
+            # Remove the box so a bad download is not resumed later
+            @logger.warn("Removing box that failed verification: #{path}")
+            File.delete(path) if File.exist?(path)
+            raise
           end
         end
       end
@@ -24,7 +24,7 @@ module Vagrant
             if machine.provider.capability?(:cleanup_disks)
               machine.provider.capability(:cleanup_disks, defined_disks, disk_meta_file)
             else
-              env[:ui].warn(I18n.t("vagrant.actions.disk.provider_unsupported",
+              env[:ui].warn(I18n.t("vagrant.actions.disk.cleanup_provider_unsupported",
                                    provider: machine.provider_name))
             end
           end
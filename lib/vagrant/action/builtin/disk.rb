@@ -3,6 +3,8 @@
 
 require "json"
 
+require "vagrant/util/numeric"
+
 module Vagrant
   module Action
     module Builtin
@@ -19,12 +21,23 @@ module Vagrant
           # Call into providers machine implementation for disk management
           configured_disks = {}
           if !defined_disks.empty?
-            if machine.provider.capability?(:configure_disks)
-             configured_disks = machine.provider.capability(:configure_disks, defined_disks)
-            else
-              env[:ui].warn(I18n.t("vagrant.actions.disk.provider_unsupported",
-                                 provider: machine.provider_name))
+            if !machine.provider.capability?(:configure_disks)
+              raise Errors::DisksProviderUnsupported,
+                machine: machine.name.to_s,
+                provider: machine.provider_name.to_s
+            end
+
+            # Growing the primary disk does not grow the partitions or
+            # filesystems within the guest so notify the user the first
+            # time the primary disk size is configured.
+            primary = primary_resize(defined_disks, read_disk_metadata(machine))
+            if primary
+              env[:ui].detail(I18n.t("vagrant.actions.disk.primary_resize",
+                name: primary.name,
+                size: Vagrant::Util::Numeric.bytes_to_string(primary.size)))
             end
+
+            configured_disks = machine.provider.capability(:configure_disks, defined_disks)
           end
 
           # Always write the disk metadata even if the configured
@@ -36,6 +49,30 @@ module Vagrant
           @app.call(env)
         end
 
+        # Find the primary disk which has a size defined for the first
+        # time since the last run. The provider grows the existing disk
+        # to the defined size.
+        #
+        # @param [Array<VagrantPlugins::Kernel_V2::VagrantConfigDisk>] defined_disks
+        # @param [Hash] disk_meta Disk metadata from the last run
+        # @return [VagrantPlugins::Kernel_V2::VagrantConfigDisk, nil]
+        def primary_resize(defined_disks, disk_meta)
+          configured = disk_meta.values.flatten.map { |d| d["name"] }
+          defined_disks.detect do |disk|
+            disk.primary && disk.size && !configured.include?(disk.name)
+          end
+        end
+
+        def read_disk_metadata(machine)
+          meta_file = machine.data_dir.join("disk_meta")
+          return {} if !File.file?(meta_file)
+
+          JSON.parse(meta_file.read)
+        rescue JSON::ParserError
+          @logger.warn("Failed to parse disk metadata file #{meta_file}")
+          {}
+        end
+
         def write_disk_metadata(machine, current_disks)
           meta_file = machine.data_dir.join("disk_meta")
           @logger.debug("Writing disk metadata file to #{meta_file}")
@@ -8,9 +8,22 @@ module Vagrant
   module Action
     module Builtin
       # This middleware class will attempt to perform a graceful shutdown
-      # of the machine using the guest implementation. This middleware is
-      # compatible with the {Call} middleware so you can branch based on
-      # the result, which is true if the halt succeeded and false otherwise.
+      # of the machine. This middleware is compatible with the {Call}
+      # middleware so you can branch based on the result, which is true if
+      # the halt succeeded and false otherwise.
+      #
+      # The shutdown is escalated in steps, each waiting up to
+      # `config.vm.graceful_halt_timeout` for the machine to stop:
+      #
+      #   1. The guest is shut down from within with the communicator,
+      #      using `config.vm.graceful_halt_command` if it is set, or the
+      #      `halt` capability of the guest.
+      #   2. If the provider has the `acpi_shutdown` capability, the
+      #      machine is sent an ACPI shutdown signal.
+      #
+      # The step which stopped the machine is stored in `env[:halt_method]`
+      # as `:guest` or `:acpi`. If neither step stops the machine, the
+      # result is false so the caller can force it to power off.
       class GracefulHalt
         # Note: Any of the arguments can be arrays as well.
         #
@@ -49,24 +62,10 @@ module Vagrant
           if graceful
             env[:ui].output(I18n.t("vagrant.actions.vm.halt.graceful"))
 
-            begin
-              env[:machine].guest.capability(:halt)
-
-              @logger.debug("Waiting for target graceful halt state: #{@target_state}")
-              begin
-                Timeout.timeout(env[:machine].config.vm.graceful_halt_timeout) do
-                  while env[:machine].state.id != @target_state
-                    sleep 1
-                  end
-                end
-              rescue Timeout::Error
-                # Don't worry about it, we catch the case later.
-              end
-            rescue Errors::GuestCapabilityNotFound
-              # This happens if insert_public_key is called on a guest that
-              # doesn't support it. This will block a destroy so we let it go.
-            rescue Errors::MachineGuestNotReady
-              env[:ui].detail(I18n.t("vagrant.actions.vm.halt.guest_not_ready"))
+            if guest_halt(env) && wait_for_target_state(env)
+              env[:halt_method] = :guest
+            elsif acpi_shutdown(env) && wait_for_target_state(env)
+              env[:halt_method] = :acpi
             end
 
             # The result of this matters on whether we reached our
@@ -74,14 +73,80 @@ module Vagrant
             env[:result] = env[:machine].state.id == @target_state
 
             if env[:result]
-              @logger.info("Gracefully halted.")
+              env[:halt_method] ||= :guest
+              @logger.info("Gracefully halted using: #{env[:halt_method]}")
             else
-              @logger.info("Graceful halt failed.")
+              @logger.info("Graceful halt failed, the machine must be forced to halt.")
             end
           end
 
           @app.call(env)
         end
+
+        protected
+
+        # Shut down the guest from within
+        #
+        # @return [Boolean] the shutdown was started
+        def guest_halt(env)
+          machine = env[:machine]
+          command = machine.config.vm.graceful_halt_command
+          if command
+            @logger.info("Halting the guest with the configured command: #{command}")
+            begin
+              machine.communicate.sudo(command, error_check: false)
+            rescue IOError, Errors::SSHDisconnected
+              # The connection is expected to be lost as the guest halts
+            end
+          else
+            @logger.info("Halting the guest with its halt capability")
+            machine.guest.capability(:halt)
+          end
+
+          true
+        rescue Errors::GuestCapabilityNotFound
+          # This happens if insert_public_key is called on a guest that
+          # doesn't support it. This will block a destroy so we let it go.
+          @logger.info("Guest does not support halting")
+          false
+        rescue Errors::MachineGuestNotReady
+          env[:ui].detail(I18n.t("vagrant.actions.vm.halt.guest_not_ready"))
+          false
+        end
+
+        # Send an ACPI shutdown signal to the machine, if the provider
+        # supports it
+        #
+        # @return [Boolean] the signal was sent
+        def acpi_shutdown(env)
+          provider = env[:machine].provider
+          return false if !provider.capability?(:acpi_shutdown)
+
+          # The guest may have halted since the timeout ran out
+          return true if env[:machine].state.id == @target_state
+
+          @logger.info("Sending ACPI shutdown signal to the machine")
+          env[:ui].detail(I18n.t("vagrant.actions.vm.halt.acpi"))
+          provider.capability(:acpi_shutdown)
+          true
+        end
+
+        # Wait for the machine to reach the target state
+        #
+        # @return [Boolean] the target state was reached
+        def wait_for_target_state(env)
+          @logger.debug("Waiting for target graceful halt state: #{@target_state}")
+          Timeout.timeout(env[:machine].config.vm.graceful_halt_timeout) do
+            while env[:machine].state.id != @target_state
+              sleep 1
+            end
+          end
+
+          true
+        rescue Timeout::Error
+          @logger.info("Machine did not reach #{@target_state} within the graceful halt timeout")
+          false
+        end
       end
     end
   end
@@ -68,6 +68,11 @@ module Vagrant
           box_download_insecure = machine.config.vm.box_download_insecure
           box_download_checksum_type = machine.config.vm.box_download_checksum_type
           box_download_checksum = machine.config.vm.box_download_checksum
+          box_download_checksum_key = machine.config.vm.box_download_checksum_key
+          if box_download_checksum_key
+            box_download_checksum_key = File.expand_path(
+              box_download_checksum_key, machine.env.root_path)
+          end
           box_download_location_trusted = machine.config.vm.box_download_location_trusted
           box_download_disable_ssl_revoke_best_effort = machine.config.vm.box_download_disable_ssl_revoke_best_effort
           box_extra_download_options = machine.config.vm.box_extra_download_options
@@ -96,6 +101,7 @@ module Vagrant
               box_download_insecure: box_download_insecure,
               box_checksum_type: box_download_checksum_type,
               box_checksum: box_download_checksum,
+              box_checksum_key: box_download_checksum_key,
               box_download_location_trusted: box_download_location_trusted,
               box_download_disable_ssl_revoke_best_effort: box_download_disable_ssl_revoke_best_effort,
               box_extra_download_options: box_extra_download_options,
@@ -95,8 +95,10 @@ module Vagrant
           usable_ports.subtract(extra_in_use.keys)
 
           # Pass one, remove all defined host ports from usable ports
+          defined_ports = Set.new
           with_forwarded_ports(env) do |options|
             usable_ports.delete(options[:host])
+            defined_ports.add(options[:host])
           end
 
           # Pass two, detect/handle any collisions
@@ -136,22 +138,45 @@ module Vagrant
               @logger.info("Attempting to repair FP collision: #{host_port}")
 
               repaired_port = nil
-              while !usable_ports.empty?
-                # Attempt to repair the forwarded port
-                repaired_port = usable_ports.to_a.sort[0]
-                usable_ports.delete(repaired_port)
+              if options[:host_range]
+                # Ports within the range of this forwarded port are used
+                # instead of the usable port range
+                repaired_port = Array(options[:host_range]).map(&:to_i).sort.uniq.detect do |port|
+                  next false if defined_ports.include?(port)
+
+                  !(is_forwarded_already(extra_in_use, port, host_ip) ||
+                    call_port_checker(port_checker, host_ip, port) ||
+                    lease_check(host_ip, port))
+                end
 
-                # If the port is in use, then we can't use this either...
-                in_use = is_forwarded_already(extra_in_use, repaired_port, host_ip) ||
-                  call_port_checker(port_checker, host_ip, repaired_port) ||
-                  lease_check(host_ip, repaired_port)
-                if in_use
-                  @logger.info("Repaired port also in use: #{repaired_port}. Trying another...")
-                  next
+                if !repaired_port
+                  raise Errors::ForwardPortHostRangeExhausted,
+                    vm_name:    env[:machine].name,
+                    guest_port: guest_port.to_s,
+                    host_port:  host_port.to_s,
+                    host_range: options[:host_range].to_s
                 end
 
-                # We have a port so break out
-                break
+                defined_ports.add(repaired_port)
+                usable_ports.delete(repaired_port)
+              else
+                while !usable_ports.empty?
+                  # Attempt to repair the forwarded port
+                  repaired_port = usable_ports.to_a.sort[0]
+                  usable_ports.delete(repaired_port)
+
+                  # If the port is in use, then we can't use this either...
+                  in_use = is_forwarded_already(extra_in_use, repaired_port, host_ip) ||
+                    call_port_checker(port_checker, host_ip, repaired_port) ||
+                    lease_check(host_ip, repaired_port)
+                  if in_use
+                    @logger.info("Repaired port also in use: #{repaired_port}. Trying another...")
+                    next
+                  end
+
+                  # We have a port so break out
+                  break
+                end
               end
 
               # If we have no usable ports then we can't repair
@@ -0,0 +1,36 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require "log4r"
+
+require_relative "hosts_file_update"
+
+module Vagrant
+  module Action
+    module Builtin
+      # This middleware removes the hosts file entries added for the
+      # machine by {HostsFileUpdate}. It should be placed before the
+      # machine is destroyed so the ID of the entries is still available.
+      class HostsFileRemove
+        def initialize(app, env)
+          @app = app
+          @logger = Log4r::Logger.new("vagrant::action::builtin::hosts_file_remove")
+        end
+
+        def call(env)
+          machine = env[:machine]
+          id = HostsFileUpdate.hosts_file_id(machine)
+          host = env[:env].host
+          if !id.nil? && host.capability?(:hosts_file_remove)
+            @logger.info("Removing hosts file entries for #{machine.name}")
+            if host.capability(:hosts_file_remove, id)
+              env[:ui].info(I18n.t("vagrant.actions.vm.hosts_file.removing"))
+            end
+          end
+
+          @app.call(env)
+        end
+      end
+    end
+  end
+end
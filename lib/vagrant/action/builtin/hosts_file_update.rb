@@ -0,0 +1,73 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require "log4r"
+
+module Vagrant
+  module Action
+    module Builtin
+      # This middleware adds the hostnames configured on the private
+      # networks of the machine to the hosts file of the host using the
+      # `hosts_file_update` host capability. A hostname is configured by
+      # setting the `hostname` option of a private network to a string, or
+      # an array of strings:
+      #
+      #     config.vm.network "private_network", ip: "192.168.56.10", hostname: "web.test"
+      #
+      # The entries are managed as a single block for the machine, so
+      # running this middleware multiple times updates the existing
+      # entries rather than adding duplicates.
+      class HostsFileUpdate
+        def initialize(app, env)
+          @app = app
+          @logger = Log4r::Logger.new("vagrant::action::builtin::hosts_file_update")
+        end
+
+        def call(env)
+          @app.call(env)
+
+          machine = env[:machine]
+          entries = self.class.entries(machine)
+          id = self.class.hosts_file_id(machine)
+          return if id.nil?
+
+          host = env[:env].host
+          if !host.capability?(:hosts_file_update)
+            if !entries.empty?
+              env[:ui].warn(I18n.t("vagrant.actions.vm.hosts_file.unsupported"))
+            end
+            return
+          end
+
+          # Entries are updated even when none are configured so that
+          # hostnames removed from the configuration are removed from the
+          # hosts file. The capability is a no-op if nothing changed.
+          @logger.info("Hosts file entries for #{machine.name}: #{entries.inspect}")
+          env[:ui].info(I18n.t("vagrant.actions.vm.hosts_file.updating")) if !entries.empty?
+          host.capability(:hosts_file_update, id, entries)
+        end
+
+        # Hostname entries configured for the machine
+        #
+        # @param [Vagrant::Machine] machine
+        # @return [Array<Array(String, String)>] IP and hostname pairs
+        def self.entries(machine)
+          machine.config.vm.networks.flat_map do |type, options|
+            next [] if type != :private_network || !options[:ip]
+
+            hostnames = Array(options[:hostname]).select { |h| h.is_a?(String) }
+            hostnames.map { |hostname| [options[:ip].to_s, hostname] }
+          end
+        end
+
+        # ID of the hosts file block for the machine
+        #
+        # @param [Vagrant::Machine] machine
+        # @return [String, nil]
+        def self.hosts_file_id(machine)
+          machine.index_uuid || machine.id
+        end
+      end
+    end
+  end
+end
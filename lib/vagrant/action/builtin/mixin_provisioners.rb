@@ -53,6 +53,7 @@ module Vagrant
               before:  provisioner.before,
               after:  provisioner.after,
               communicator_required: provisioner.communicator_required,
+              skip_unchanged: provisioner.skip_unchanged,
             }
 
             # Return the result
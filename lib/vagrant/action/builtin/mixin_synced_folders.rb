@@ -199,7 +199,8 @@ module Vagrant
         #
         # This will return a hash with three keys: "added", "removed",
         # and "modified". These will contain a set of IDs of folders
-        # that were added, removed, or modified, respectively.
+        # that were added, removed, or modified, respectively. A folder
+        # is modified if its host path, guest path, or type changed.
         #
         # The parameters should be results from the {#synced_folders} call.
         #
@@ -208,14 +209,14 @@ module Vagrant
           existing_ids = {}
           one.each do |impl, fs|
             fs.each do |id, data|
-              existing_ids[id] = data
+              existing_ids[id] = [impl, data]
             end
           end
 
           result = Hash.new { |h, k| h[k] = Set.new }
           two.each do |impl, fs|
             fs.each do |id, data|
-              existing = existing_ids.delete(id)
+              existing_impl, existing = existing_ids.delete(id)
               if !existing
                 result[:added] << id
                 next
@@ -224,7 +225,8 @@ module Vagrant
               # Exists, so we have to compare the host and guestpath, which
               # is most important...
               if existing[:hostpath] != data[:hostpath] ||
-                existing[:guestpath] != data[:guestpath]
+                existing[:guestpath] != data[:guestpath] ||
+                existing_impl.to_s != impl.to_s
                 result[:modified] << id
               end
             end
@@ -1,6 +1,8 @@
 # Copyright IBM Corp. 2010, 2025
 # SPDX-License-Identifier: BUSL-1.1
 
+require "json"
+
 require "log4r"
 
 require_relative "mixin_provisioners"
@@ -14,9 +16,18 @@ module Vagrant
       # This action should be placed BEFORE the machine is booted so it
       # can do some setup, and then run again (on the return path) against
       # a running machine.
+      #
+      # Provisioners configured with `skip_unchanged` which implement
+      # `fingerprint` are skipped if their fingerprint is the same as when
+      # they last ran successfully, unless `:provision_force` is set.
+      # Provisioners configured to run always are never skipped.
       class Provision
         include MixinProvisioners
 
+        # Name of the file within the machine data directory which stores
+        # the fingerprints of the provisioners
+        FINGERPRINTS_FILE = "provision_fingerprints".freeze
+
         def initialize(app, env)
           @app             = app
           @logger          = Log4r::Logger.new("vagrant::action::builtin::provision")
@@ -95,15 +106,19 @@ module Vagrant
           end
 
           # Write the sentinel if we have to
-          if update_sentinel || !sentinel_path.file?
+          if env[:provision_dry_run]
+            env[:ui].info(I18n.t("vagrant.actions.vm.provision.dry_run.beginning"))
+          elsif update_sentinel || !sentinel_path.file?
             @logger.info("Writing provisioning sentinel so we don't provision again")
             sentinel_path.open("w") do |f|
               f.write("1.5:#{env[:machine].id}")
             end
           end
 
+          fingerprints = stored_fingerprints(env[:machine])
+
           type_map = provisioner_type_map(env)
-          provisioner_instances(env).each do |p, options|
+          provisioner_instances(env).each_with_index do |(p, options), index|
             type_name = type_map[p]
 
             if options[:run] == :never
@@ -122,15 +137,43 @@ module Vagrant
               name = "#{options[:name]} (#{type_name})"
             end
 
+            key = (options[:name] || "#{type_name}-#{index}").to_s
+            fingerprint = nil
+            if options[:skip_unchanged] && p.respond_to?(:fingerprint)
+              fingerprint = p.fingerprint
+            end
+            if fingerprint && fingerprints[key] == fingerprint &&
+                options[:run] != :always && !env[:provision_force]
+              env[:ui].info(I18n.t(
+                "vagrant.actions.vm.provision.unchanged",
+                provisioner: name))
+              next
+            end
+
+            if env[:provision_dry_run]
+              plan_provisioner(env, p, name)
+              next
+            end
+
             env[:ui].info(I18n.t(
               "vagrant.actions.vm.provision.beginning",
               provisioner: name))
 
-            env[:hook].call(:provisioner_run, env.merge(
-              callable: method(:run_provisioner),
-              provisioner: p,
-              provisioner_name: type_name,
-            ))
+            attributes = Util::Tracing.machine_attributes(
+              env[:machine], env[:machine_action]).merge(
+              "vagrant.provisioner" => name)
+            Util::Tracing.span("vagrant.provision", attributes) do
+              env[:hook].call(:provisioner_run, env.merge(
+                callable: method(:run_provisioner),
+                provisioner: p,
+                provisioner_name: type_name,
+              ))
+            end
+
+            if fingerprint
+              fingerprints[key] = fingerprint
+              store_fingerprints(env[:machine], fingerprints)
+            end
           end
         end
 
@@ -140,6 +183,61 @@ module Vagrant
         def run_provisioner(env)
           env[:provisioner].provision
         end
+
+        # Fingerprints are stored with the machine ID so they are not used
+        # for a new machine created with the same data directory.
+        #
+        # @param [Vagrant::Machine] machine
+        # @return [Hash<String, String>] fingerprints of the provisioners
+        #   when they last ran successfully
+        def stored_fingerprints(machine)
+          path = machine.data_dir.join(FINGERPRINTS_FILE)
+          return {} if !path.file?
+
+          data = JSON.parse(path.read)
+          return {} if !data.is_a?(Hash) || data["id"] != machine.id.to_s
+
+          data["fingerprints"] || {}
+        rescue JSON::ParserError
+          @logger.warn("Ignoring invalid provisioner fingerprints: #{path}")
+          {}
+        end
+
+        # @param [Vagrant::Machine] machine
+        # @param [Hash<String, String>] fingerprints
+        def store_fingerprints(machine, fingerprints)
+          machine.data_dir.join(FINGERPRINTS_FILE).write(JSON.dump(
+            "id" => machine.id.to_s,
+            "fingerprints" => fingerprints,
+          ))
+        end
+
+        # Show the actions a provisioner would take without running it
+        #
+        # @param [Hash] env
+        # @param [Vagrant::Plugin::V2::Provisioner] provisioner
+        # @param [String] name Name shown for the provisioner
+        def plan_provisioner(env, provisioner, name)
+          env[:ui].info(I18n.t(
+            "vagrant.actions.vm.provision.dry_run.provisioner",
+            provisioner: name))
+
+          steps = provisioner.respond_to?(:plan) ? provisioner.plan : nil
+          if steps.nil?
+            env[:ui].detail(I18n.t("vagrant.actions.vm.provision.dry_run.unknown"))
+            return
+          end
+
+          steps = Array(steps)
+          if steps.empty?
+            env[:ui].detail(I18n.t("vagrant.actions.vm.provision.dry_run.none"))
+          end
+
+          steps.each do |step|
+            env[:ui].detail(I18n.t(
+              "vagrant.actions.vm.provision.dry_run.step", step: step.to_s))
+          end
+        end
       end
     end
   end
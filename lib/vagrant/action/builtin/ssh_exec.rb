@@ -39,7 +39,28 @@ module Vagrant
           end
 
           # Exec!
-          SSH.exec(info, env[:ssh_opts])
+          SSH.exec(info, environment_opts(env))
+        end
+
+        protected
+
+        # The environment variables of the machine are exported by running
+        # a login shell through the remote command, since forwarding them
+        # with SendEnv requires the SSH server to accept them. Nothing is
+        # exported when extra arguments are given to SSH, since they may
+        # include a command of their own.
+        #
+        # @return [Hash, nil] the SSH options
+        def environment_opts(env)
+          opts = env[:ssh_opts]
+          machine_config = env[:machine].config
+          environment = machine_config.vm.environment
+          return opts if environment.nil? || environment.empty?
+          return opts if machine_config.vm.communicator == :winssh
+          return opts if opts && !Array(opts[:extra_args]).empty?
+
+          command = "#{SSH.environment_exports(environment)}exec \"$SHELL\" -l"
+          (opts || {}).merge(extra_args: ["-t", command])
         end
       end
     end
@@ -37,15 +37,23 @@ module Vagrant
             raise Errors::SSHRunRequiresKeys
           end
 
-          # Get the command and wrap it in a login shell
-          command = ShellQuote.escape(env[:ssh_run_command], "'")
-
           if env[:machine].config.vm.communicator == :winssh
             shell = env[:machine].config.winssh.shell
           else
             shell = env[:machine].config.ssh.shell
           end
 
+          # Export the environment variables of the machine before running
+          # the command in POSIX shells
+          command = env[:ssh_run_command]
+          environment = env[:machine].config.vm.environment
+          if environment && !environment.empty? && shell != "cmd" && shell != "powershell"
+            command = "#{SSH.environment_exports(environment)}#{command}"
+          end
+
+          # Get the command and wrap it in a login shell
+          command = ShellQuote.escape(command, "'")
+
           if shell == "cmd"
             # Add an extra space to the command so cmd.exe quoting works
             # properly
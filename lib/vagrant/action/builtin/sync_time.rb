@@ -0,0 +1,63 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require "log4r"
+
+module Vagrant
+  module Action
+    module Builtin
+      # This middleware synchronizes the clock of the guest with the
+      # `sync_time` guest capability, as the clock is behind after the
+      # machine is resumed or a snapshot is restored. It is skipped when
+      # "vm.sync_time" is false or the communicator is not ready, and
+      # failures only result in a warning. This middleware should be
+      # placed such that after the @app.call, the machine is running
+      # (this generally means after the WaitForCommunicator middleware).
+      class SyncTime
+        def initialize(app, env)
+          @app = app
+          @logger = Log4r::Logger.new("vagrant::action::builtin::sync_time")
+        end
+
+        def call(env)
+          @app.call(env)
+
+          machine = env[:machine]
+          if !machine.config.vm.sync_time
+            @logger.info("`sync_time` set to false, not synchronizing the guest time")
+            return
+          end
+
+          if !machine.communicate.ready?
+            @logger.info("Communicator is not ready, not synchronizing the guest time")
+            return
+          end
+
+          if !machine.guest.capability?(:sync_time)
+            @logger.info("Guest does not support synchronizing the time")
+            return
+          end
+
+          env[:ui].info(I18n.t("vagrant.actions.vm.sync_time.syncing"))
+          begin
+            result = machine.guest.capability(:sync_time)
+          rescue StandardError => e
+            @logger.error("Failed to synchronize the guest time: #{e.class}: #{e}")
+            env[:ui].warn(I18n.t("vagrant.actions.vm.sync_time.failed",
+              message: e.message))
+            return
+          end
+
+          # The offsets are only known when the tool of the guest reports them
+          if result.is_a?(Hash) && (result[:before] || result[:after])
+            @logger.info("Guest time offset before synchronizing: #{result[:before].inspect}, " \
+              "after: #{result[:after].inspect}")
+            env[:ui].detail(I18n.t("vagrant.actions.vm.sync_time.offset",
+              before: result[:before] || I18n.t("vagrant.actions.vm.sync_time.unknown"),
+              after: result[:after] || I18n.t("vagrant.actions.vm.sync_time.unknown")))
+          end
+        end
+      end
+    end
+  end
+end
@@ -71,6 +71,11 @@ module Vagrant
             end
           end
 
+          # When reloading the synced folders of a running machine, only
+          # the folders which changed since they were last applied are
+          # mounted or unmounted.
+          return reload(env, folders) if env[:synced_folders_reload]
+
           # Build up the instances of the synced folders. We do this once
           # so that they can store state.
           folders = folders.map do |impl_name, fs|
@@ -81,8 +86,12 @@ module Vagrant
           # Go through each folder and prepare the folders
           folders.each do |impl, impl_name, fs|
             if !env[:synced_folders_disable]
+              warn_unsupported_options(env[:machine], impl, impl_name, fs)
+
               @logger.info("Invoking synced folder prepare for: #{impl_name}")
-              impl.prepare(env[:machine], fs, impl_opts(impl_name, env))
+              trace_synced_folder(env, impl_name, :prepare) do
+                impl.prepare(env[:machine], fs, impl_opts(impl_name, env))
+              end
             end
           end
 
@@ -93,7 +102,11 @@ module Vagrant
           folders.each do |impl, impl_name, fs|
             if !env[:synced_folders_disable]
               @logger.info("Invoking synced folder enable: #{impl_name}")
-              impl.enable(env[:machine], fs, impl_opts(impl_name, env))
+              trace_synced_folder(env, impl_name, :enable) do
+                check_guest_tools(env, impl_name) do
+                  impl.enable(env[:machine], fs, impl_opts(impl_name, env))
+                end
+              end
               next
             end
 
@@ -132,7 +145,151 @@ module Vagrant
             save_synced_folders(env[:machine], original_folders, **save_opts)
           end
 
-          # Persist the mounts by adding them to fstab (only if the guest is available)
+          persist_mounts(env, original_folders)
+        end
+
+        protected
+
+        # Apply the changes between the synced folders which were last
+        # applied to the running machine and the given folders. Folders
+        # which were removed are disabled, folders which were added are
+        # enabled, and modified folders are disabled and then enabled
+        # again. Unchanged folders are not touched.
+        #
+        # @param [Hash] env
+        # @param [Hash] folders Folders from {#synced_folders}
+        def reload(env, folders)
+          machine = env[:machine]
+          cached = synced_folders(machine, cached: true)
+          diff = synced_folders_diff(cached, folders)
+          removed = select_synced_folders(cached, diff[:removed] + diff[:modified])
+          added = select_synced_folders(folders, diff[:added] + diff[:modified])
+
+          if removed.empty? && added.empty?
+            machine.ui.info(I18n.t("vagrant.actions.vm.share_folders.reload_unchanged"))
+            return @app.call(env)
+          end
+
+          instances = (removed.keys + added.keys).map(&:to_sym).uniq.map do |impl_name|
+            [impl_name, plugins[impl_name][0].new]
+          end.to_h
+
+          # Verify all the changes can be applied before applying any
+          # of them so the machine is not left partially updated
+          types = instances.reject { |_, impl| impl.hot_mount?(machine) }.keys
+          if !types.empty?
+            raise Errors::ReloadSyncedFoldersRebootRequired,
+              name: machine.name.to_s,
+              types: types.map(&:to_s).sort.join(", ")
+          end
+
+          removed.each do |impl_name, fs|
+            @logger.info("Invoking synced folder disable: #{impl_name}")
+            fs.each do |id, data|
+              machine.ui.detail(I18n.t("vagrant.actions.vm.share_folders.removing_entry",
+                guestpath: data[:guestpath].to_s))
+            end
+            instances[impl_name.to_sym].disable(machine, fs, impl_opts(impl_name, env))
+          end
+
+          added.each do |impl_name, fs|
+            warn_unsupported_options(machine, instances[impl_name.to_sym], impl_name, fs)
+
+            @logger.info("Invoking synced folder prepare for: #{impl_name}")
+            instances[impl_name.to_sym].prepare(machine, fs, impl_opts(impl_name, env))
+          end
+
+          @app.call(env)
+
+          added.each do |impl_name, fs|
+            @logger.info("Invoking synced folder enable: #{impl_name}")
+            check_guest_tools(env, impl_name) do
+              instances[impl_name.to_sym].enable(machine, fs, impl_opts(impl_name, env))
+            end
+          end
+
+          # Update the saved folders to be the set applied to the machine
+          removed.each do |impl_name, fs|
+            fs.each_key { |id| cached[impl_name].delete(id) }
+            cached.delete(impl_name) if cached[impl_name].empty?
+          end
+          added.each do |impl_name, fs|
+            cached[impl_name] ||= {}
+            cached[impl_name].merge!(fs)
+          end
+          save_synced_folders(machine, cached)
+
+          persist_mounts(env, folders)
+        end
+
+        # @param [Hash] folders Folders from {#synced_folders}
+        # @param [Enumerable<String>] ids IDs of the folders to select
+        # @return [Hash] selected folders keyed by implementation
+        def select_synced_folders(folders, ids)
+          folders.map { |impl_name, fs|
+            [impl_name, fs.select { |id, _| ids.include?(id) }]
+          }.reject { |_, fs| fs.empty? }.to_h
+        end
+
+        # Run the block which enables folders of the given synced folder
+        # type. Before folders are mounted using the synced folder type
+        # of the provider, the version of the guest tools installed in the
+        # guest is compared with the version expected by the provider, and
+        # a warning is shown if they differ. If the versions are different
+        # enough to explain a mount failure, the failure is reported with
+        # the fix.
+        def check_guest_tools(env, impl_name)
+          machine = env[:machine]
+          return yield if impl_name.to_s != machine.provider_name.to_s
+
+          installed, expected = Util::GuestTools.versions(machine)
+          return yield if expected.nil?
+
+          mismatch = Util::GuestTools.mismatch(installed, expected)
+          msg_opts = {
+            provider: machine.provider_name.to_s,
+            installed: installed.to_s,
+            expected: expected.to_s,
+          }
+          if mismatch == :missing
+            machine.ui.warn(I18n.t("vagrant.guest_tools_not_detected", **msg_opts))
+          elsif mismatch
+            machine.ui.warn(I18n.t("vagrant.guest_tools_mismatch", **msg_opts))
+          end
+
+          begin
+            yield
+          rescue Errors::VirtualBoxMountFailed, Errors::LinuxMountFailed => e
+            raise if !Util::GuestTools.severe?(mismatch)
+
+            error = Errors::GuestToolsMismatchMountFailed
+            error = Errors::GuestToolsMissingMountFailed if mismatch == :missing
+            raise error, msg_opts.merge(error: e.message)
+          end
+        end
+
+        # Warn about the access options of folders which the
+        # implementation can not honor, rather than ignoring them
+        def warn_unsupported_options(machine, impl, impl_name, fs)
+          unsupported = impl.unsupported_options(machine)
+          return if unsupported.empty?
+
+          fs.each do |_, data|
+            unsupported.each do |option|
+              value = data[option]
+              next if !value || (value.respond_to?(:empty?) && value.empty?)
+
+              machine.ui.warn(I18n.t("vagrant.actions.vm.share_folders.option_unsupported",
+                type: impl_name.to_s,
+                option: option.to_s,
+                guestpath: data[:guestpath].to_s))
+            end
+          end
+        end
+
+        # Persist the mounts by adding them to fstab (only if the guest
+        # is available)
+        def persist_mounts(env, folders)
           begin
             persist_mount = env[:machine].guest.capability?(:persist_mount_shared_folder)
           rescue Errors::MachineGuestNotReady
@@ -141,13 +298,22 @@ module Vagrant
           if persist_mount
             # Persist the mounts by adding them to fstab
             if env[:machine].config.vm.allow_fstab_modification
-              fstab_folders = original_folders
+              fstab_folders = folders
             else
               fstab_folders = nil
             end
             env[:machine].guest.capability(:persist_mount_shared_folder, fstab_folders)
           end
         end
+
+        # Run the block within a span for a synced folder implementation
+        def trace_synced_folder(env, impl_name, phase, &block)
+          attributes = Util::Tracing.machine_attributes(
+            env[:machine], env[:machine_action]).merge(
+            "vagrant.synced_folder.type" => impl_name,
+            "vagrant.synced_folder.phase" => phase)
+          Util::Tracing.span("vagrant.synced_folders", attributes, &block)
+        end
       end
     end
   end
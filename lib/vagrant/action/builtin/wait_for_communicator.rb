@@ -13,68 +13,79 @@ module Vagrant
         end
 
         def call(env)
-          # Wait for ready in a thread so that we can continually check
-          # for interrupts.
-          ready_thr = Thread.new do
-            Thread.current[:result] = env[:machine].communicate.wait_for_ready(
-              env[:machine].config.vm.boot_timeout)
-          end
+          ready_thr = nil
+          states_thr = nil
+
+          attributes = Util::Tracing.machine_attributes(
+            env[:machine], env[:machine_action])
+          Util::Tracing.span("vagrant.boot_wait", attributes) do
+            context = Util::Tracing.current_context
 
-          # Start a thread that verifies the VM stays in a good state.
-          states_thr = Thread.new do
-            Thread.current[:result] = true
+            # Wait for ready in a thread so that we can continually check
+            # for interrupts.
+            ready_thr = Thread.new do
+              Util::Tracing.with_context(context) do
+                Thread.current[:result] = env[:machine].communicate.wait_for_ready(
+                  env[:machine].config.vm.boot_timeout)
+              end
+            end
 
-            # Otherwise, periodically verify the VM isn't in a bad state.
-            while true
-              state = env[:machine].state.id
+            # Start a thread that verifies the VM stays in a good state.
+            states_thr = Thread.new do
+              Thread.current[:result] = true
 
-              # Used to report invalid states
-              Thread.current[:last_known_state] = state
+              # Otherwise, periodically verify the VM isn't in a bad state.
+              while true
+                state = env[:machine].state.id
 
-              # Check if we have the proper state so we can break out
-              if @states && !@states.include?(state)
-                Thread.current[:result] = false
-                break
+                # Used to report invalid states
+                Thread.current[:last_known_state] = state
+
+                # Check if we have the proper state so we can break out
+                if @states && !@states.include?(state)
+                  Thread.current[:result] = false
+                  break
+                end
+
+                # Sleep a bit so we don't hit 100% CPU constantly.
+                sleep 1
               end
+            end
 
-              # Sleep a bit so we don't hit 100% CPU constantly.
+            # Wait for a result or an interrupt
+            env[:ui].output(I18n.t("vagrant.boot_waiting"))
+            while ready_thr.alive? && states_thr.alive?
               sleep 1
+              return if env[:interrupted]
             end
-          end
 
-          # Wait for a result or an interrupt
-          env[:ui].output(I18n.t("vagrant.boot_waiting"))
-          while ready_thr.alive? && states_thr.alive?
-            sleep 1
-            return if env[:interrupted]
-          end
+            # Join so that they can raise exceptions if there were any
+            ready_thr.join if !ready_thr.alive?
+            states_thr.join if !states_thr.alive?
 
-          # Join so that they can raise exceptions if there were any
-          ready_thr.join if !ready_thr.alive?
-          states_thr.join if !states_thr.alive?
+            # If it went into a bad state, then raise an error
+            if !states_thr[:result]
+              raise Errors::VMBootBadState,
+                valid: @states.join(", "),
+                invalid: states_thr[:last_known_state]
+            end
 
-          # If it went into a bad state, then raise an error
-          if !states_thr[:result]
-            raise Errors::VMBootBadState,
-              valid: @states.join(", "),
-              invalid: states_thr[:last_known_state]
-          end
+            # If it didn't boot, raise an error
+            if !ready_thr[:result]
+              raise Errors::VMBootTimeout
+            end
 
-          # If it didn't boot, raise an error
-          if !ready_thr[:result]
-            raise Errors::VMBootTimeout
+            env[:ui].output(I18n.t("vagrant.boot_completed"))
           end
 
-          env[:ui].output(I18n.t("vagrant.boot_completed"))
-
           # Make sure our threads are all killed
-          ready_thr.kill
-          states_thr.kill
+          ready_thr.kill if ready_thr
+          states_thr.kill if states_thr
 
           @app.call(env)
         ensure
-          ready_thr.kill
-          states_thr.kill
+          ready_thr.kill if ready_thr
+          states_thr.kill if states_thr
         end
       end
     end
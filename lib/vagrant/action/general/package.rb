@@ -50,6 +50,57 @@ module Vagrant
           end
         end
 
+        # This will copy the generated private key of the machine into the
+        # box directory and use it for SSH by default. We have to do this
+        # because we now generate random keypairs on boot, so packaged boxes
+        # would stop working without this.
+        #
+        # @param [Vagrant::Machine] machine Machine being packaged
+        # @param [String] directory Directory containing the box files
+        def self.setup_private_key(machine, directory)
+          # If we don't have machine, we do nothing (weird)
+          return if !machine
+
+          # If we don't have a data dir, we also do nothing (base package)
+          return if !machine.data_dir
+
+          # If we don't have a generated private key, we do nothing
+          path = machine.data_dir.join("private_key")
+          if !path.file?
+            # If we have a private key that was copied into this box,
+            # then we copy that. This is a bit of a heuristic and can be a
+            # security risk if the key is named the correct thing, but
+            # we'll take that risk for dev environments.
+            (machine.config.ssh.private_key_path || []).each do |p|
+              # If we have the correctly named key, copy it
+              if File.basename(p) == "vagrant_private_key"
+                path = Pathname.new(p)
+                break
+              end
+            end
+          end
+
+          # If we still have no matching key, do nothing
+          return if !path.file?
+
+          # Copy it into our box directory
+          dir = Pathname.new(directory)
+          new_path = dir.join("vagrant_private_key")
+          FileUtils.cp(path, new_path)
+
+          # Append it to the Vagrantfile (or create a Vagrantfile)
+          vf_path = dir.join("Vagrantfile")
+          mode = "w+"
+          mode = "a" if vf_path.file?
+          vf_path.open(mode) do |f|
+            f.binmode
+            f.puts
+            f.puts %Q[Vagrant.configure("2") do |config|]
+            f.puts %Q[  config.ssh.private_key_path = File.expand_path("../vagrant_private_key", __FILE__)]
+            f.puts %Q[end]
+          end
+        end
+
         # Calculate the full path of the given path, relative to the current
         # working directory (where the command was run).
         #
@@ -186,52 +237,10 @@ module Vagrant
           File.write(meta_path, {provider: provider_name}.to_json)
         end
 
-        # This will copy the generated private key into the box and use
-        # it for SSH by default. We have to do this because we now generate
-        # random keypairs on boot, so packaged boxes would stop working
-        # without this.
+        # Copy the generated private key into the box. See
+        # {.setup_private_key}.
         def setup_private_key
-          # If we don't have machine, we do nothing (weird)
-          return if !@env[:machine]
-
-          # If we don't have a data dir, we also do nothing (base package)
-          return if !@env[:machine].data_dir
-
-          # If we don't have a generated private key, we do nothing
-          path = @env[:machine].data_dir.join("private_key")
-          if !path.file?
-            # If we have a private key that was copied into this box,
-            # then we copy that. This is a bit of a heuristic and can be a
-            # security risk if the key is named the correct thing, but
-            # we'll take that risk for dev environments.
-            (@env[:machine].config.ssh.private_key_path || []).each do |p|
-              # If we have the correctly named key, copy it
-              if File.basename(p) == "vagrant_private_key"
-                path = Pathname.new(p)
-                break
-              end
-            end
-          end
-
-          # If we still have no matching key, do nothing
-          return if !path.file?
-
-          # Copy it into our box directory
-          dir = Pathname.new(@env["package.directory"])
-          new_path = dir.join("vagrant_private_key")
-          FileUtils.cp(path, new_path)
-
-          # Append it to the Vagrantfile (or create a Vagrantfile)
-          vf_path = dir.join("Vagrantfile")
-          mode = "w+"
-          mode = "a" if vf_path.file?
-          vf_path.open(mode) do |f|
-            f.binmode
-            f.puts
-            f.puts %Q[Vagrant.configure("2") do |config|]
-            f.puts %Q[  config.ssh.private_key_path = File.expand_path("../vagrant_private_key", __FILE__)]
-            f.puts %Q[end]
-          end
+          self.class.setup_private_key(@env[:machine], @env["package.directory"])
         end
 
         # Check to see if package.info is a valid file and titled info.json
@@ -1,6 +1,8 @@
 # Copyright IBM Corp. 2010, 2025
 # SPDX-License-Identifier: BUSL-1.1
 
+require 'etc'
+require 'set'
 require 'thread'
 require "log4r"
 
@@ -8,9 +10,14 @@ module Vagrant
   # This class executes multiple actions as a single batch, parallelizing
   # the action calls if possible.
   class BatchAction
-    def initialize(allow_parallel=true)
+    # @param [Boolean] allow_parallel Run actions in parallel if possible
+    # @param [Integer] max_workers Maximum number of actions to run at
+    #   the same time when running in parallel. Defaults to the number
+    #   of processors.
+    def initialize(allow_parallel=true, max_workers: nil)
       @actions          = []
       @allow_parallel   = allow_parallel
+      @max_workers      = max_workers
       @logger           = Log4r::Logger.new("vagrant::batch_action")
     end
 
@@ -35,9 +42,15 @@ module Vagrant
 
     # Run all the queued up actions, parallelizing if possible.
     #
-    # This will parallelize if and only if the provider of every machine
-    # supports parallelization and parallelization is possible from
-    # initialization of the class.
+    # This will parallelize if the provider of any machine supports
+    # parallelization and parallelization is possible from initialization
+    # of the class. Actions on machines with a provider that doesn't
+    # support parallelization are run one at a time.
+    #
+    # Actions on a machine are not started until the actions on the
+    # machines it depends on (`config.vm.depends_on`) have completed. If
+    # an action fails while running in parallel, running actions are
+    # allowed to complete and the remaining actions are cancelled.
     def run
       par = false
 
@@ -47,12 +60,9 @@ module Vagrant
       end
 
       if par
-        @actions.each do |machine, _, _|
-          if !machine.provider_options[:parallel]
-            @logger.info("Disabling parallelization because provider doesn't support it: #{machine.provider_name}")
-            par = false
-            break
-          end
+        if @actions.none? { |machine, _, _| machine.provider_options[:parallel] }
+          @logger.info("Disabling parallelization because no provider supports it")
+          par = false
         end
       end
 
@@ -64,75 +74,14 @@ module Vagrant
       @logger.info("Batch action will parallelize: #{par.inspect}")
 
       threads = []
-      @actions.each do |machine, action, options|
-        @logger.info("Starting action: #{machine} #{action} #{options}")
-
-        # Create the new thread to run our action. This is basically just
-        # calling the action but also contains some error handling in it
-        # as well.
-        thread = Thread.new do
-          Thread.current[:error] = nil
-
-          # Note that this thread is being used for running
-          # a batch action
-          Thread.current[:batch_parallel_action] = par
-
-          # Record our pid when we started in order to figure out if
-          # we've forked...
-          start_pid = Process.pid
-
-          begin
-            if action.is_a?(Proc)
-              action.call(machine)
-            else
-              machine.send(:action, action, options)
-            end
-          rescue Exception => e
-            # If we're not parallelizing, then raise the error. We also
-            # don't raise the error if we've forked, because it'll hang
-            # the process.
-            raise if !par && Process.pid == start_pid
-
-            # Store the exception that will be processed later
-            Thread.current[:error] = e
-
-            # We can only do the things below if we do not fork, otherwise
-            # it'll hang the process.
-            if Process.pid == start_pid
-              # Let the user know that this process had an error early
-              # so that they see it while other things are happening.
-              machine.ui.error(I18n.t("vagrant.general.batch_notify_error"))
-            end
-          end
-
-          # If we forked during the process run, we need to do a hard
-          # exit here. Ruby's fork only copies the running process (which
-          # would be us), so if we return from this thread, it results
-          # in a zombie Ruby process.
-          if Process.pid != start_pid
-            # We forked.
-
-            exit_status = true
-            if Thread.current[:error]
-              # We had an error, print the stack trace and exit immediately.
-              exit_status = false
-              error = Thread.current[:error]
-              @logger.error(error.inspect)
-              @logger.error(error.message)
-              @logger.error(error.backtrace.join("\n"))
-            end
-
-            Process.exit!(exit_status)
-          end
-        end
-
-        # Set some attributes on the thread for later
-        thread[:machine] = machine
-
-        if !par
+      if par
+        threads = run_parallel(ordered_actions)
+      else
+        ordered_actions.each do |machine, action, options|
+          thread = start_action(machine, action, options, par)
           thread.join(THREAD_MAX_JOIN_TIMEOUT) while thread.alive?
+          threads << thread
         end
-        threads << thread
       end
 
       errors = []
@@ -177,5 +126,186 @@ module Vagrant
         end
       end
     end
+
+    protected
+
+    # Run the actions in parallel using at most `max_workers` threads.
+    #
+    # @param [Array] actions Actions to run, in dependency order
+    # @return [Array<Thread>] threads of all started actions
+    def run_parallel(actions)
+      workers = (@max_workers || Etc.nprocessors).to_i
+      workers = 1 if workers < 1
+      @logger.info("Running batch actions with up to #{workers} workers")
+
+      # Number of actions yet to complete for each machine, used to
+      # determine when the dependencies of a machine are complete
+      remaining = Hash.new(0)
+      actions.each { |machine, _, _| remaining[machine.name.to_s] += 1 }
+
+      done    = Queue.new
+      pending = actions.dup
+      running = []
+      serial  = Set.new
+      threads = []
+      failed  = false
+
+      loop do
+        if failed
+          pending.each do |machine, _, _|
+            @logger.info("Cancelling action on #{machine.name} due to previous failure")
+            machine.ui.warn(I18n.t("vagrant.general.batch_cancelled"))
+          end
+          pending.clear
+        end
+
+        pending.each_with_index do |(machine, action, options), idx|
+          break if running.length >= workers
+          next if !dependencies(machine).all? { |name| remaining[name] == 0 }
+
+          if !machine.provider_options[:parallel]
+            next if serial.include?(machine.provider_name)
+            serial.add(machine.provider_name)
+          end
+
+          pending[idx] = nil
+          thread = start_action(machine, action, options, true, done)
+          running << thread
+          threads << thread
+        end
+        pending.compact!
+
+        break if running.empty?
+
+        thread = done.pop
+        running.delete(thread)
+        machine = thread[:machine]
+        serial.delete(machine.provider_name) if !machine.provider_options[:parallel]
+        remaining[machine.name.to_s] -= 1
+        failed = true if thread[:error]
+      end
+
+      threads
+    end
+
+    # Start a thread running the action on the machine.
+    #
+    # @param [Machine] machine The machine to run the action on
+    # @param [Symbol, Proc] action The action to run
+    # @param [Hash] options Any additional options to send in.
+    # @param [Boolean] par Action is running in parallel
+    # @param [Queue] done Queue the thread is pushed to once complete
+    # @return [Thread]
+    def start_action(machine, action, options, par, done=nil)
+      @logger.info("Starting action: #{machine} #{action} #{options}")
+
+      # Continue the current trace within the thread so the action is
+      # recorded as part of the command.
+      trace_context = Util::Tracing.current_context
+
+      # Create the new thread to run our action. This is basically just
+      # calling the action but also contains some error handling in it
+      # as well.
+      thread = Thread.new do
+        Thread.current[:error] = nil
+
+        # Note that this thread is being used for running
+        # a batch action
+        Thread.current[:batch_parallel_action] = par
+
+        # Record our pid when we started in order to figure out if
+        # we've forked...
+        start_pid = Process.pid
+
+        begin
+          Util::Tracing.with_context(trace_context) do
+            if action.is_a?(Proc)
+              action.call(machine)
+            else
+              machine.send(:action, action, options)
+            end
+          end
+        rescue Exception => e
+          # If we're not parallelizing, then raise the error. We also
+          # don't raise the error if we've forked, because it'll hang
+          # the process.
+          raise if !par && Process.pid == start_pid
+
+          # Store the exception that will be processed later
+          Thread.current[:error] = e
+
+          # We can only do the things below if we do not fork, otherwise
+          # it'll hang the process.
+          if Process.pid == start_pid
+            # Let the user know that this process had an error early
+            # so that they see it while other things are happening.
+            machine.ui.error(I18n.t("vagrant.general.batch_notify_error"))
+          end
+        end
+
+        # If we forked during the process run, we need to do a hard
+        # exit here. Ruby's fork only copies the running process (which
+        # would be us), so if we return from this thread, it results
+        # in a zombie Ruby process.
+        if Process.pid != start_pid
+          # We forked.
+
+          exit_status = true
+          if Thread.current[:error]
+            # We had an error, print the stack trace and exit immediately.
+            exit_status = false
+            error = Thread.current[:error]
+            @logger.error(error.inspect)
+            @logger.error(error.message)
+            @logger.error(error.backtrace.join("\n"))
+          end
+
+          Process.exit!(exit_status)
+        end
+
+        # Notify the scheduler that this action has completed
+        done << Thread.current if done
+      end
+
+      # Set some attributes on the thread for later
+      thread[:machine] = machine
+      thread
+    end
+
+    # Order the actions so that the actions on a machine come after the
+    # actions on the machines it depends on. The original order is kept
+    # otherwise.
+    #
+    # @return [Array]
+    def ordered_actions
+      names = @actions.map { |machine, _, _| machine.name.to_s }.uniq
+      deps = {}
+      @actions.each do |machine, _, _|
+        deps[machine.name.to_s] ||= dependencies(machine) & names
+      end
+
+      order = []
+      visit = lambda do |name, path|
+        next if order.include?(name)
+        if path.include?(name)
+          cycle = path[path.index(name)..-1] + [name]
+          raise Errors::BatchDependencyCycle, machines: cycle.join(" -> ")
+        end
+
+        deps[name].each { |dep| visit.call(dep, path + [name]) }
+        order << name
+      end
+      names.each { |name| visit.call(name, []) }
+
+      @actions.each_with_index.sort_by { |(machine, _, _), idx|
+        [order.index(machine.name.to_s), idx]
+      }.map(&:first)
+    end
+
+    # @param [Machine] machine
+    # @return [Array<String>] names of the machines the machine depends on
+    def dependencies(machine)
+      Array(machine.config.vm.depends_on).map(&:to_s)
+    end
   end
 end
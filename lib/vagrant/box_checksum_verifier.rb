@@ -0,0 +1,258 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require "base64"
+require "openssl"
+
+require "log4r"
+
+require "vagrant/util/file_checksum"
+
+module Vagrant
+  # A box checksum verifier validates the content of a box against an
+  # expected value. Verifiers are selected by checksum type and the box
+  # file is read in chunks by {#verify_file!} so that the box never needs
+  # to be held in memory.
+  #
+  # Custom verifiers can be provided by subclassing this class,
+  # implementing {#update} and {#verify!}, and registering the subclass
+  # with {.register}.
+  class BoxChecksumVerifier
+    # Size of chunks read when verifying a file
+    BUFFER_SIZE = 1024 * 8
+
+    # Register a verifier for the given checksum type
+    #
+    # @param [String, Symbol] type Checksum type
+    # @param [Class] klass Verifier class
+    def self.register(type, klass)
+      BoxChecksumVerifier.verifiers[type.to_s.downcase.to_sym] = klass
+    end
+
+    # @return [Hash<Symbol, Class>] registered verifiers
+    def self.verifiers
+      @verifiers ||= {}
+    end
+
+    # Create a verifier for the given checksum type
+    #
+    # @param [String, Symbol] type Checksum type
+    # @param [String] expected Expected checksum value
+    # @param [Hash] opts Options for the verifier
+    # @return [BoxChecksumVerifier]
+    def self.for(type, expected, **opts)
+      klass = BoxChecksumVerifier.verifiers[type.to_s.downcase.to_sym]
+      if klass.nil?
+        raise Errors::BoxChecksumInvalidType,
+          type: type.to_s,
+          types: BoxChecksumVerifier.verifiers.keys.join(", ")
+      end
+
+      klass.new(type, expected, **opts)
+    end
+
+    # @return [Symbol] checksum type
+    attr_reader :type
+
+    # @return [String] expected checksum value
+    attr_reader :expected
+
+    # @param [String, Symbol] type Checksum type
+    # @param [String] expected Expected checksum value
+    # @param [Hash] opts Options for the verifier
+    def initialize(type, expected, **opts)
+      @logger = Log4r::Logger.new("vagrant::box_checksum_verifier")
+      @type = type.to_s.downcase.to_sym
+      @expected = expected.to_s.strip
+      @options = opts
+    end
+
+    # Read the content of the file through the verifier and verify
+    #
+    # @param [String, Pathname] path Path to file
+    def verify_file!(path)
+      @logger.info("Verifying #{path} using #{type}")
+      buf = ""
+      File.open(path, "rb") do |f|
+        while f.read(BUFFER_SIZE, buf)
+          update(buf)
+        end
+      end
+
+      verify!
+    end
+
+    protected
+
+    # Add a chunk of the content to the verifier
+    #
+    # @param [String] data
+    def update(data)
+      raise NotImplementedError
+    end
+
+    # Verify all content that has been provided. An error is
+    # raised if the content does not match the expected value.
+    def verify!
+      raise NotImplementedError
+    end
+
+    # Verifies content using a digest of the content
+    class DigestVerifier < BoxChecksumVerifier
+      def initialize(type, expected, **opts)
+        super
+        @digest = Util::FileChecksum::CHECKSUM_MAP.fetch(@type).new
+      end
+
+      protected
+
+      def update(data)
+        @digest.update(data)
+      end
+
+      def verify!
+        actual = @digest.hexdigest
+        @logger.info("Expected checksum: #{expected}")
+        @logger.info("Actual checksum: #{actual}")
+        if actual.casecmp(expected) != 0
+          raise Errors::BoxChecksumMismatch,
+            actual: actual,
+            expected: expected
+        end
+      end
+    end
+
+    # Verifies content using a minisign detached signature. The expected
+    # value is the content of the signature file, or the path to it, and
+    # the public key path is provided with the `:key` option. Only
+    # prehashed signatures (the minisign default) are supported as legacy
+    # signatures require the entire content to be held in memory.
+    class MinisignVerifier < BoxChecksumVerifier
+      # Algorithm identifier for the public key
+      KEY_ALGORITHM = "Ed".freeze
+
+      # Algorithm identifier for prehashed signatures
+      PREHASHED_ALGORITHM = "ED".freeze
+
+      # Algorithm identifier for legacy signatures
+      LEGACY_ALGORITHM = "Ed".freeze
+
+      # Prefix of the trusted comment line within a signature
+      TRUSTED_COMMENT_PREFIX = "trusted comment: ".freeze
+
+      def initialize(type, expected, **opts)
+        super
+        require "ed25519"
+
+        key_path = @options[:key].to_s
+        if key_path.empty?
+          raise Errors::BoxSignatureKeyMissing, type: type.to_s
+        end
+
+        @key_id, @verify_key = parse_key(File.read(File.expand_path(key_path)))
+        @signature_key_id, @signature, @trusted_comment, @global_signature = parse_signature
+        @digest = OpenSSL::Digest.new("BLAKE2b512")
+      rescue Errno::ENOENT, Errno::EACCES => e
+        raise Errors::BoxSignatureMalformed, message: e.message
+      end
+
+      protected
+
+      def update(data)
+        @digest.update(data)
+      end
+
+      def verify!
+        if @signature_key_id != @key_id
+          raise Errors::BoxSignatureInvalid,
+            key_id: hex(@key_id),
+            signature_key_id: hex(@signature_key_id)
+        end
+
+        begin
+          @verify_key.verify(@signature, @digest.digest)
+          @verify_key.verify(@global_signature, @signature + @trusted_comment)
+        rescue Ed25519::VerifyError
+          raise Errors::BoxSignatureInvalid,
+            key_id: hex(@key_id),
+            signature_key_id: hex(@signature_key_id)
+        end
+
+        @logger.info("Verified signature from key #{hex(@key_id)} (#{@trusted_comment})")
+      end
+
+      # Parse a minisign public key
+      #
+      # @param [String] content Public key file content or raw key
+      # @return [Array<String, Ed25519::VerifyKey>] key ID and key
+      def parse_key(content)
+        lines = content.lines.map(&:strip).reject(&:empty?)
+        lines.shift if lines.first.to_s.start_with?("untrusted comment:")
+        decoded = decode(lines.first, 42)
+        if decoded[0, 2] != KEY_ALGORITHM
+          raise Errors::BoxSignatureMalformed,
+            message: "unsupported public key algorithm"
+        end
+
+        [decoded[2, 8], Ed25519::VerifyKey.new(decoded[10, 32])]
+      end
+
+      # Parse the minisign signature provided as the expected value
+      #
+      # @return [Array<String>] key ID, signature, trusted comment and
+      #   global signature
+      def parse_signature
+        content = expected
+        content = File.read(content) if !content.include?("\n") && File.file?(content)
+        lines = content.lines.map(&:strip).reject(&:empty?)
+        lines.shift if lines.first.to_s.start_with?("untrusted comment:")
+        if lines.length != 3 || !lines[1].start_with?(TRUSTED_COMMENT_PREFIX)
+          raise Errors::BoxSignatureMalformed,
+            message: "signature must include a trusted comment and global signature"
+        end
+
+        decoded = decode(lines[0], 74)
+        if decoded[0, 2] == LEGACY_ALGORITHM
+          raise Errors::BoxSignatureMalformed,
+            message: "legacy signatures are not supported, sign the box with a current version of minisign"
+        elsif decoded[0, 2] != PREHASHED_ALGORITHM
+          raise Errors::BoxSignatureMalformed,
+            message: "unsupported signature algorithm"
+        end
+
+        trusted_comment = lines[1][TRUSTED_COMMENT_PREFIX.length..-1]
+        [decoded[2, 8], decoded[10, 64], trusted_comment, decode(lines[2], 64)]
+      end
+
+      # Decode a base64 value of an expected length
+      #
+      # @param [String] value Base64 encoded value
+      # @param [Integer] length Expected length of decoded value
+      # @return [String]
+      def decode(value, length)
+        decoded = Base64.strict_decode64(value.to_s)
+        if decoded.bytesize != length
+          raise Errors::BoxSignatureMalformed,
+            message: "unexpected length of encoded value"
+        end
+        decoded
+      rescue ArgumentError
+        raise Errors::BoxSignatureMalformed,
+          message: "invalid encoding"
+      end
+
+      # Format a key ID as minisign displays it
+      #
+      # @param [String] key_id
+      # @return [String]
+      def hex(key_id)
+        key_id.to_s.reverse.unpack1("H*").upcase
+      end
+    end
+
+    Util::FileChecksum::CHECKSUM_MAP.each_key do |type|
+      register(type, DigestVerifier)
+    end
+    register(:minisign, MinisignVerifier)
+  end
+end
@@ -3,6 +3,7 @@
 
 require "digest/sha1"
 require "fileutils"
+require "json"
 require "monitor"
 require "tmpdir"
 require "log4r"
@@ -105,60 +106,72 @@ module Vagrant
         box.destroy!
       end
 
-      with_collection_lock do
-        log_provider = providers ? providers.join(", ") : "any provider"
-        @logger.debug("Adding box: #{name} (#{log_provider} - #{architecture.inspect}) from #{path}")
-
-        # Verify the box doesn't exist early if we're given a provider. This
-        # can potentially speed things up considerably since we don't need
-        # to unpack any files.
-        check_box_exists.call(providers, architecture) if providers
-
-        # Create a temporary directory since we're not sure at this point if
-        # the box we're unpackaging already exists (if no provider was given)
-        with_temp_dir do |temp_dir|
-          # Extract the box into a temporary directory.
-          @logger.debug("Unpacking box into temporary directory: #{temp_dir}")
-          result = Util::Subprocess.execute(
-            "bsdtar", "--no-same-owner", "--no-same-permissions", "-v", "-x", "-m", "-S", "-s", "|\\\\\|/|", "-C", temp_dir.to_s, "-f", path.to_s)
-          if result.exit_code != 0
-            raise Errors::BoxUnpackageFailure,
-              output: result.stderr.to_s
-          end
+      log_provider = providers ? providers.join(", ") : "any provider"
+      @logger.debug("Adding box: #{name} (#{log_provider} - #{architecture.inspect}) from #{path}")
+
+      # Verify the box doesn't exist early if we're given a provider. This
+      # can potentially speed things up considerably since we don't need
+      # to unpack any files.
+      with_collection_lock { check_box_exists.call(providers, architecture) } if providers
+
+      # Create a temporary directory since we're not sure at this point if
+      # the box we're unpackaging already exists (if no provider was given).
+      # The box is unpacked without holding the collection lock so several
+      # boxes can be unpacked at the same time.
+      with_temp_dir do |temp_dir|
+        # Extract the box into a temporary directory.
+        @logger.debug("Unpacking box into temporary directory: #{temp_dir}")
+        result = Util::Subprocess.execute(
+          "bsdtar", "--no-same-owner", "--no-same-permissions", "-v", "-x", "-m", "-S", "-s", "|\\\\\|/|", "-C", temp_dir.to_s, "-f", path.to_s)
+        if result.exit_code != 0
+          raise Errors::BoxUnpackageFailure,
+            output: result.stderr.to_s
+        end
 
-          # If we get a V1 box, we want to update it in place
-          if v1_box?(temp_dir)
-            @logger.debug("Added box is a V1 box. Upgrading in place.")
-            temp_dir = v1_upgrade(temp_dir)
-          end
+        # If we get a V1 box, we want to update it in place
+        if v1_box?(temp_dir)
+          @logger.debug("Added box is a V1 box. Upgrading in place.")
+          temp_dir = v1_upgrade(temp_dir)
+        end
 
-          # We re-wrap ourselves in the safety net in case we upgraded.
-          # If we didn't upgrade, then this is still safe because the
-          # helper will only delete the directory if it exists
-          with_temp_dir(temp_dir) do |final_temp_dir|
-            # Get an instance of the box we just added before it is finalized
-            # in the system so we can inspect and use its metadata.
-            box = Box.new(name, nil, version, final_temp_dir)
-
-            # Get the provider, since we'll need that to at the least add it
-            # to the system or check that it matches what is given to us.
-            box_provider = box.metadata["provider"]
-
-            if providers
-              found = providers.find { |p| p.to_sym == box_provider.to_sym }
-              if !found
-                @logger.error("Added box provider doesnt match expected: #{log_provider}")
-                raise Errors::BoxProviderDoesntMatch,
-                  expected: log_provider, actual: box_provider
-              end
-            else
-              # Verify the box doesn't already exist
-              check_box_exists.call([box_provider], architecture)
+        # We re-wrap ourselves in the safety net in case we upgraded.
+        # If we didn't upgrade, then this is still safe because the
+        # helper will only delete the directory if it exists
+        with_temp_dir(temp_dir) do |final_temp_dir|
+          # Get an instance of the box we just added before it is finalized
+          # in the system so we can inspect and use its metadata.
+          box = Box.new(name, nil, version, final_temp_dir)
+
+          # Get the provider, since we'll need that to at the least add it
+          # to the system or check that it matches what is given to us.
+          box_provider = box.metadata["provider"]
+
+          if providers
+            found = providers.find { |p| p.to_sym == box_provider.to_sym }
+            if !found
+              @logger.error("Added box provider doesnt match expected: #{log_provider}")
+              raise Errors::BoxProviderDoesntMatch,
+                expected: log_provider, actual: box_provider
             end
+          end
+
+          with_collection_lock do
+            # Verify the box doesn't already exist. This is checked again
+            # even if a provider was given since another box may have been
+            # added while this one was unpacked.
+            check_box_exists.call([box_provider], architecture)
 
             # We weren't given a provider, so store this one.
             provider = box_provider.to_sym
 
+            # If the box is derived from a base box, the base box must
+            # already be installed so the box can be layered over it.
+            base_box = nil
+            if box.metadata.key?("base_box")
+              base_box = find_base_box(name, box.metadata["base_box"],
+                provider, architecture)
+            end
+
             # Create the directory for this box, not including the provider
             root_box_dir = @directory.join(dir_name(name))
             box_dir = root_box_dir.join(version)
@@ -182,6 +195,9 @@ module Vagrant
             # Move to final destination
             provider_dir.mkpath
 
+            # Layer the box over the contents of the base box
+            link_base_box(base_box, provider_dir) if base_box
+
             # Recursively move individual files from the temporary directory
             # to the final location. We do this instead of moving the entire
             # directory to avoid issues on Windows. [GH-1424]
@@ -199,12 +215,26 @@ module Vagrant
                   next
                 end
 
+                # Replace files linked from the base box
+                dest.delete if base_box && dest.file?
+
                 # Copy the single file
                 @logger.debug("Moving: #{f} => #{dest}")
                 FileUtils.mv(f, dest)
               end
             end
 
+            # The metadata of the box is merged with the metadata of the
+            # base box, and records the version of the base box used.
+            if base_box
+              metadata = base_box.metadata.merge(box.metadata)
+              metadata["base_box"] = box.metadata["base_box"].merge(
+                "resolved_version" => base_box.version)
+              provider_dir.join("metadata.json").open("w") do |f|
+                f.write(JSON.dump(metadata))
+              end
+            end
+
             if opts[:metadata_url]
               root_box_dir.join("metadata_url").open("w") do |f|
                 f.write(opts[:metadata_url])
@@ -541,6 +571,61 @@ module Vagrant
       end
     end
 
+    # Find the installed base box for a derived box
+    #
+    # @param [String] name Name of the derived box
+    # @param [Hash] base The `base_box` entry of the box metadata
+    # @param [Symbol] provider Provider of the box
+    # @param [String, Symbol] architecture Architecture of the box
+    # @return [Box]
+    def find_base_box(name, base, provider, architecture)
+      if !base.is_a?(Hash) || base["name"].to_s.empty?
+        raise Errors::BoxBaseInvalid, name: name
+      end
+
+      version = base["version"].to_s
+      version = ">= 0" if version.empty?
+      base_box = find(base["name"], provider, version, architecture || :auto)
+      if !base_box
+        raise Errors::BoxBaseNotFound,
+          box: name,
+          name: base["name"],
+          version: version,
+          provider: provider.to_s,
+          url: base["url"]
+      end
+
+      @logger.debug("Using base box #{base_box.name} v#{base_box.version} for #{name}")
+      base_box
+    end
+
+    # Add the files of a base box to a box directory. Files are hard
+    # linked where possible so the contents of the base box are not
+    # duplicated.
+    #
+    # @param [Box] base_box
+    # @param [Pathname] dir Directory of the derived box
+    def link_base_box(base_box, dir)
+      copy_pairs = [[base_box.directory, dir]]
+      while !copy_pairs.empty?
+        from, to = copy_pairs.shift
+        from.children(true).each do |f|
+          dest = to.join(f.basename)
+          if f.directory?
+            dest.mkpath
+            copy_pairs << [f, dest]
+            next
+          end
+
+          begin
+            FileUtils.ln(f, dest)
+          rescue SystemCallError
+            FileUtils.cp(f, dest)
+          end
+        end
+      end
+    end
+
     # This is a helper that makes sure that our temporary directories
     # are cleaned up no matter what.
     #
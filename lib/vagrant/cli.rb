@@ -60,12 +60,28 @@ module Vagrant
 
       Util::CheckpointClient.instance.display
 
+      if Util::Tracing.libraries_missing?
+        @env.ui.warn(I18n.t("vagrant.tracing_libraries_missing"), prefix: false)
+      end
+
       # Initialize and execute the command class, returning the exit status.
       result = 0
       begin
-        @triggers.fire(@sub_command, :before, nil, :command)
-        result = command_class.new(@sub_args, @env).execute
-        @triggers.fire(@sub_command, :after, nil, :command)
+        Util::Tracing.span("vagrant.command", "vagrant.command" => @sub_command) do |span|
+          @triggers.fire(@sub_command, :before, nil, :command)
+          begin
+            result = command_class.new(@sub_args, @env).execute
+          rescue Errors::VagrantError => e
+            # Triggers with a condition are fired after a failed command
+            # so they can match on its exit code
+            @triggers.fire(@sub_command, :after, nil, :command,
+              exit_code: e.status_code, conditional_only: true)
+            raise
+          end
+          @triggers.fire(@sub_command, :after, nil, :command,
+            exit_code: result.is_a?(Integer) ? result : 0)
+          span.set_attribute("vagrant.exit_code", result) if span && result.is_a?(Integer)
+        end
       rescue Interrupt
         @env.ui.info(I18n.t("vagrant.cli_interrupt"))
         result = 1
@@ -0,0 +1,216 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require "log4r"
+
+module Vagrant
+  # This is an interface for running Vagrant operations on a project from
+  # another Ruby program instead of running the `vagrant` command. The
+  # operations return structured results instead of only writing to the
+  # UI, and the UI can be given so the program can capture the output.
+  #
+  #     Vagrant::Client.open("/path/to/project", ui: ui) do |client|
+  #       client.up(provision: false).each do |result|
+  #         puts "#{result.name}: #{result.error || result.state}"
+  #       end
+  #     end
+  #
+  # Operations on a client run one at a time. Clients for different
+  # projects can be used from different threads at the same time.
+  class Client
+    # The result of an operation on a machine.
+    #
+    # @!attribute name
+    #   @return [Symbol] name of the machine
+    # @!attribute provider
+    #   @return [Symbol] provider of the machine
+    # @!attribute state
+    #   @return [Symbol] state of the machine after the operation
+    # @!attribute error
+    #   @return [Errors::VagrantError, nil] error if the operation failed
+    Result = Struct.new(:name, :provider, :state, :error) do
+      # @return [Boolean] the operation succeeded
+      def success?
+        error.nil?
+      end
+    end
+
+    # The status of a machine.
+    #
+    # @!attribute name
+    #   @return [Symbol] name of the machine
+    # @!attribute provider
+    #   @return [Symbol] provider of the machine
+    # @!attribute state
+    #   @return [Symbol] state of the machine
+    # @!attribute short_description
+    #   @return [String] short description of the state
+    # @!attribute long_description
+    #   @return [String] long description of the state
+    Status = Struct.new(:name, :provider, :state, :short_description, :long_description)
+
+    # @return [Environment] the environment of the project
+    attr_reader :env
+
+    # Open a client for the project in the given directory. If a block
+    # is given, the client is yielded and closed when the block returns.
+    #
+    # @param [String, Pathname] cwd Directory of the project
+    # @param [UI::Interface] ui UI to write output to, defaults to no output
+    # @param [Hash] opts Extra options for the {Environment}
+    # @return [Client, Object] the client, or the result of the block
+    def self.open(cwd, ui: nil, **opts)
+      client = new(cwd, ui: ui, **opts)
+      return client if !block_given?
+
+      begin
+        yield client
+      ensure
+        client.close
+      end
+    end
+
+    # @param [String, Pathname] cwd Directory of the project
+    # @param [UI::Interface] ui UI to write output to, defaults to no output
+    # @param [Hash] opts Extra options for the {Environment}
+    def initialize(cwd, ui: nil, **opts)
+      @logger = Log4r::Logger.new("vagrant::client")
+      @lock = Mutex.new
+      @closed = false
+      @env = Environment.new(**opts.merge(cwd: cwd, ui: ui || UI::Silent.new))
+    end
+
+    # Bring up machines. Machines which fail to come up do not prevent
+    # the remaining machines from coming up.
+    #
+    # @param [Array<String, Symbol>] targets Names of the machines, defaults
+    #   to the machines which are started automatically
+    # @param [Symbol] provider Provider to use for the machines
+    # @param [Boolean] provision Run or skip the provisioners, defaults to
+    #   only running them the first time the machine is created
+    # @param [Boolean] destroy_on_error Destroy a newly created machine if
+    #   it fails to come up
+    # @return [Array<Result>]
+    def up(targets=nil, provider: nil, provision: nil, destroy_on_error: true)
+      options = {
+        destroy_on_error: destroy_on_error,
+        provision_ignore_sentinel: !provision.nil?,
+      }
+      options[:provision_enabled] = provision if !provision.nil?
+
+      synchronize do
+        targets ||= @env.vagrantfile.machine_names_and_options.map do |name, opts|
+          name if opts.fetch(:autostart, true)
+        end.compact
+
+        machines(targets, provider: provider).map do |machine|
+          run_action(machine, :up, options)
+        end
+      end
+    end
+
+    # Destroy machines. Machines are destroyed without asking for
+    # confirmation unless `force` is false.
+    #
+    # @param [Array<String, Symbol>] targets Names of the machines, defaults
+    #   to all the machines
+    # @param [Boolean] force Destroy without asking for confirmation
+    # @return [Array<Result>]
+    def destroy(targets=nil, force: true)
+      synchronize do
+        machines(targets).reverse.map do |machine|
+          run_action(machine, :destroy,
+            force_confirm_destroy: force, force_halt: true)
+        end
+      end
+    end
+
+    # @param [Array<String, Symbol>] targets Names of the machines, defaults
+    #   to all the machines
+    # @return [Array<Status>] status of the machines
+    def status(targets=nil)
+      synchronize do
+        machines(targets).map do |machine|
+          state = machine.state
+          Status.new(machine.name, machine.provider_name, state.id,
+            state.short_description, state.long_description)
+        end
+      end
+    end
+
+    # Close the client. This unloads the environment, which releases
+    # the resources used by plugins such as pooled SSH connections. The
+    # client can not be used once it is closed.
+    def close
+      @lock.synchronize do
+        return if @closed
+
+        @closed = true
+        @logger.info("Closing client for #{@env.cwd}")
+        @env.unload
+      end
+    end
+
+    # @return [Boolean] the client is closed
+    def closed?
+      @closed
+    end
+
+    protected
+
+    # Run the block while no other operation is running on the client
+    def synchronize
+      @lock.synchronize do
+        raise Errors::ClientClosed, cwd: @env.cwd.to_s if @closed
+
+        yield
+      end
+    end
+
+    # @param [Machine] machine
+    # @param [Symbol] name Name of the action
+    # @param [Hash] options Options for the action
+    # @return [Result]
+    def run_action(machine, name, options)
+      error = nil
+      begin
+        machine.action(name, options)
+      rescue Errors::VagrantError => e
+        @logger.error("Failed to #{name} #{machine.name}: #{e}")
+        error = e
+      end
+
+      Result.new(machine.name, machine.provider_name, machine.state.id, error)
+    end
+
+    # Load the machines with the given names. Machines which already
+    # exist use their provider, other machines use the given provider
+    # or the default provider.
+    #
+    # @param [Array<String, Symbol>] targets Names of the machines, or nil
+    #   for all the machines
+    # @param [Symbol] provider Provider for machines which don't exist
+    # @return [Array<Machine>]
+    def machines(targets, provider: nil)
+      names = targets.nil? ? @env.machine_names : Array(targets).map(&:to_sym)
+      active = Hash[@env.active_machines]
+      provider = provider.to_sym if provider
+
+      names.map do |name|
+        if !@env.machine_names.include?(name)
+          raise Errors::VMNotFoundError, name: name.to_s
+        end
+
+        if provider && active[name] && active[name] != provider
+          raise Errors::ActiveMachineWithDifferentProvider,
+            name: name.to_s,
+            active_provider: active[name].to_s,
+            requested_provider: provider.to_s
+        end
+
+        @env.machine(name, active[name] || provider ||
+          @env.default_provider(machine: name))
+      end
+    end
+  end
+end
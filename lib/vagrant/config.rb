@@ -29,6 +29,22 @@ module Vagrant
     VERSIONS_ORDER = ["1", "2"]
     CURRENT_VERSION = VERSIONS_ORDER.last
 
+    # Helpers which can be called anywhere within a Vagrantfile without
+    # a receiver, including the `Vagrant.configure` blocks.
+    module VagrantfileHelpers
+      private
+
+      # Reference a secret, the same as {Vagrant.secret}.
+      #
+      #     config.ssh.password = secret("env:VM_SSH_PASSWORD")
+      #
+      # @param [String] reference
+      # @return [Secret::Reference]
+      def secret(reference)
+        Vagrant.secret(reference)
+      end
+    end
+
     # This is the method which is called by all Vagrantfiles to configure Vagrant.
     # This method expects a block which accepts a single argument representing
     # an instance of the {Config::Top} class.
@@ -27,6 +27,7 @@ module Vagrant
         @logger        = Log4r::Logger.new("vagrant::config::loader")
         @config_cache  = {}
         @proc_cache    = {}
+        @provenance    = {}
         @sources       = {}
         @versions      = versions
         @version_order = version_order
@@ -104,6 +105,9 @@ module Vagrant
         warnings = []
         errors   = []
 
+        # Keep track of the sources that contributed to each setting
+        provenance = {}
+
         if !@sources[:root].nil? && @sources[:root].eql?(@sources[:home])
           # Vagrants home dir is set to the same dir as its project directory
           # so we don't want to load and merge the same Vagrantfile config
@@ -193,14 +197,40 @@ module Vagrant
             cache_data = @config_cache[proc]
             result = current_config_klass.merge(result, cache_data[0])
 
+            # Record where the merged settings came from
+            record_provenance(provenance, key, current_config_klass,
+              cache_data[0], result)
+
             # Append the total warnings/errors
             warnings += cache_data[1]
             errors   += cache_data[2]
           end
         end
 
+        @provenance = provenance
+
+        # Secrets are resolved once the configuration is merged, and again
+        # once finalized since finalizing can evaluate more configuration
+        # blocks, such as the provider blocks
+        result = Secret.resolve_all(result)
+
         @logger.debug("Configuration loaded successfully, finalizing and returning")
-        [current_config_klass.finalize(result), warnings, errors]
+        [Secret.resolve_all(current_config_klass.finalize(result)), warnings, errors]
+      end
+
+      # Returns the sources that contributed to each setting during the
+      # most recent call to {#load}. The result is a hash keyed by the
+      # fully qualified setting name (e.g. "vm.box_url") with values being
+      # the names of the sources, in the order they were merged.
+      #
+      # A setting that was replaced by a later source will only list
+      # that later source. A setting whose value was combined across
+      # sources (such as arrays that are concatenated) will list every
+      # source that contributed.
+      #
+      # @return [Hash<String, Array<Symbol>>]
+      def provenance
+        Hash[@provenance.map { |key, sources| [key, sources.dup] }]
       end
 
       # This method is used for doing partial loads of the
@@ -250,6 +280,36 @@ module Vagrant
 
       protected
 
+      # Records the source of the settings defined within a configuration
+      # object that was just merged into the result.
+      #
+      # @param [Hash] provenance Provenance being collected for this load
+      # @param [Symbol] source Name of the source being merged
+      # @param [Class] config_klass Configuration version class
+      # @param [Object] config Configuration loaded from the source
+      # @param [Object] merged Result of the merge
+      def record_provenance(provenance, source, config_klass, config, merged)
+        merged_values = config_klass.defined_values(merged)
+
+        config_klass.defined_values(config).each do |name, value|
+          sources = provenance[name] ||= []
+          next if sources.last == source
+
+          # Collections are generally combined when merged instead of
+          # being replaced. If the merged value still holds more than
+          # what this source defined, the previous sources contributed
+          # to the value as well.
+          merged_value = merged_values[name]
+          if !sources.empty? &&
+              (merged_value.is_a?(Array) || merged_value.is_a?(Hash)) &&
+              merged_value != value
+            sources << source
+          else
+            sources.replace([source])
+          end
+        end
+      end
+
       # This returns an array of `Proc` objects for the given source.
       # The `Proc` objects returned will expect a single argument for
       # the configuration object and are expected to mutate this
@@ -282,6 +342,10 @@ module Vagrant
       def procs_for_path(path)
         @logger.debug("Load procs for pathname: #{path}")
 
+        # Vagrantfiles are evaluated by the top level object, so the
+        # helpers are available within them
+        TOPLEVEL_BINDING.receiver.extend(Config::VagrantfileHelpers)
+
         return Config.capture_configures do
           begin
             Kernel.load path
@@ -104,6 +104,43 @@ module Vagrant
           end
         end
 
+        # Returns the values explicitly set within the configuration keyed
+        # by "namespace.attribute". Internal state (instance variables
+        # prefixed with a double underscore), unset values and empty
+        # collections are not included. The entries of hashes are also
+        # included individually keyed by "namespace.attribute.key", so the
+        # source of each entry can be found. Configurations which hold
+        # configurations of providers can include their settings by
+        # implementing `provider_defined_values`.
+        #
+        # @param [V2::Root] config
+        # @return [Hash<String, Object>]
+        def self.defined_values(config)
+          {}.tap do |values|
+            config.__internal_state["keys"].each do |namespace, instance|
+              instance.instance_variables.each do |key|
+                next if key.to_s.start_with?("@__")
+
+                value = instance.instance_variable_get(key)
+                next if value == Vagrant::Plugin::V2::Config::UNSET_VALUE
+                next if (value.is_a?(Array) || value.is_a?(Hash)) && value.empty?
+
+                name = "#{namespace}.#{key.to_s[1..-1]}"
+                values[name] = value
+                if value.is_a?(Hash)
+                  value.each do |k, v|
+                    values["#{name}.#{k}"] = v
+                  end
+                end
+              end
+
+              if instance.respond_to?(:provider_defined_values)
+                values.merge!(instance.provider_defined_values)
+              end
+            end
+          end
+        end
+
         # Upgrade a V1 configuration to a V2 configuration. We do this by
         # creating a V2 configuration, and calling "upgrade" on each of the
         # V1 configurations, expecting them to set the right settings on the
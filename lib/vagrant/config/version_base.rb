@@ -61,6 +61,21 @@ module Vagrant
         raise NotImplementedError
       end
 
+      # Returns the values that were explicitly set within the given
+      # configuration object as a flat hash keyed by the fully qualified
+      # name of the setting (e.g. "vm.box_url"). This is used by the
+      # configuration loader to track which sources contributed to the
+      # final value of a setting.
+      #
+      # This is an optional method to implement. The default implementation
+      # returns an empty hash, which disables provenance tracking.
+      #
+      # @param [Object] obj Configuration object.
+      # @return [Hash<String, Object>]
+      def self.defined_values(obj)
+        {}
+      end
+
       # This is called if a previous version of configuration needs to be
       # upgraded to this version. Each version of configuration should know
       # how to upgrade the version immediately prior to it. This should be
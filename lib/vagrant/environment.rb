@@ -12,6 +12,7 @@ require 'log4r'
 require 'vagrant/util/file_mode'
 require 'vagrant/util/platform'
 require 'vagrant/util/hash_with_indifferent_access'
+require 'vagrant/util/oci_registry'
 require "vagrant/util/silence_warnings"
 require "vagrant/vagrantfile"
 require "vagrant/version"
@@ -294,11 +295,16 @@ module Vagrant
     #
     # This handles the case where batch actions are disabled by the
     # VAGRANT_NO_PARALLEL environmental variable.
-    def batch(parallel=true)
+    #
+    # @param [Boolean] parallel Run actions in parallel if possible
+    # @param [Hash] opts Options for the batch action
+    # @option opts [Integer] :max_workers Maximum number of actions to
+    #   run at the same time
+    def batch(parallel=true, **opts)
       parallel = false if ENV["VAGRANT_NO_PARALLEL"]
 
       @batch_lock.synchronize do
-        BatchAction.new(parallel).tap do |b|
+        BatchAction.new(parallel, **opts).tap do |b|
           # Yield it so that the caller can setup actions
           yield b
 
@@ -326,6 +332,8 @@ module Vagrant
     # @param force_default [Boolean] (true) whether to prefer the value of
     #   VAGRANT_DEFAULT_PROVIDER over other strategies if it is set
     # @param machine [Symbol] (nil) a machine name to scope this lookup
+    # @param provider_order [Array<Symbol>] (nil) ordered list of providers
+    #   to try, overriding `config.vm.provider_order`
     # @return [Symbol] Name of the default provider.
     def default_provider(**opts)
       opts[:exclude]       = Set.new(opts[:exclude]) if opts[:exclude]
@@ -344,6 +352,13 @@ module Vagrant
       # (Step 1 is done by the caller; this method is only called if --provider
       # wasn't given.)
       #
+      # 1.5. If the --provider-order flag is given, the first usable provider
+      #      in that list is chosen.
+
+      if opts[:provider_order] && !opts[:provider_order].empty?
+        return ordered_provider(opts[:provider_order], **opts)
+      end
+
       # 2. If the VAGRANT_DEFAULT_PROVIDER environmental variable is set, it
       #    takes next priority and will be the provider chosen.
 
@@ -371,6 +386,14 @@ module Vagrant
         root_config = machine_info[:config]
       end
 
+      # 2.25. If the Vagrantfile sets config.vm.provider_order, the first
+      #       usable provider in that list is chosen.
+
+      order = root_config.vm.provider_order
+      if order.is_a?(Array) && !order.empty?
+        return ordered_provider(order, **opts)
+      end
+
       # Get the list of providers within our configuration, in order.
       config = root_config.vm.__providers
 
@@ -510,6 +533,7 @@ module Vagrant
       home_vagrantfile = nil
       root_vagrantfile = nil
       home_vagrantfile = find_vagrantfile(home_path) if home_path
+      org_vagrantfile  = find_org_vagrantfile
       if root_path
         root_vagrantfile = find_vagrantfile(root_path, @vagrantfile_name)
       end
@@ -517,6 +541,7 @@ module Vagrant
       @config_loader = Config::Loader.new(
         Config::VERSIONS, Config::VERSIONS_ORDER)
       @config_loader.set(:home, home_vagrantfile) if home_vagrantfile
+      @config_loader.set(:org, org_vagrantfile) if org_vagrantfile
       @config_loader.set(:root, root_vagrantfile) if root_vagrantfile
       @config_loader
     end
@@ -826,11 +851,67 @@ module Vagrant
     #
     # This Vagrantfile is comprised of two major sources: the Vagrantfile
     # in the user's home directory as well as the "root" Vagrantfile or
-    # the Vagrantfile in the working directory (or parent).
+    # the Vagrantfile in the working directory (or parent). If an
+    # organization Vagrantfile is configured, it is loaded between the
+    # two.
     #
     # @return [Vagrantfile]
     def vagrantfile
-      @vagrantfile ||= Vagrantfile.new(config_loader, [:home, :root])
+      return @vagrantfile if @vagrantfile
+
+      @vagrantfile = Vagrantfile.new(config_loader, [:home, :org, :root])
+
+      # Machines cloned with `vagrant clone` are defined with the
+      # configuration of the machine they were cloned from
+      clones.each do |name, source|
+        if !@vagrantfile.config.vm.defined_vms.key?(source)
+          @logger.warn("Ignoring clone #{name} of undefined machine #{source}")
+          next
+        end
+
+        @vagrantfile.config.vm.define_clone(name, source)
+      end
+
+      @vagrantfile
+    end
+
+    # The machines of this environment which were cloned from another
+    # machine with `vagrant clone`.
+    #
+    # @return [Hash<Symbol, Symbol>] name of each clone mapped to the name
+    #   of the machine it was cloned from
+    def clones
+      path = clones_path
+      return {} if !path || !path.file?
+
+      data = JSON.parse(path.read)
+      return {} if !data.is_a?(Hash)
+
+      Hash[data.map { |name, source| [name.to_sym, source.to_sym] }]
+    rescue JSON::ParserError => e
+      @logger.warn("Ignoring invalid clones file: #{e}")
+      {}
+    end
+
+    # Register a machine cloned from another machine so it is defined
+    # by the Vagrantfile.
+    #
+    # @param [Symbol] name Name of the clone
+    # @param [Symbol] source Name of the machine it was cloned from
+    def register_clone(name, source)
+      write_clones(clones.merge(name.to_sym => source.to_sym))
+      @vagrantfile = nil
+    end
+
+    # Remove a machine cloned from another machine.
+    #
+    # @param [Symbol] name Name of the clone
+    def unregister_clone(name)
+      current = clones
+      return if !current.delete(name.to_sym)
+
+      write_clones(current)
+      @vagrantfile = nil
     end
 
     #---------------------------------------------------------------
@@ -961,6 +1042,85 @@ module Vagrant
 
     protected
 
+    # @return [Pathname, nil] path to the file storing the clones
+    def clones_path
+      return if !@local_data_path
+
+      @local_data_path.join("clones.json")
+    end
+
+    # @param [Hash<Symbol, Symbol>] data
+    def write_clones(data)
+      path = clones_path
+      return if !path
+
+      FileUtils.mkdir_p(path.dirname)
+      if data.empty?
+        path.delete if path.file?
+      else
+        path.write(JSON.pretty_generate(
+          Hash[data.map { |name, source| [name.to_s, source.to_s] }]))
+      end
+    end
+
+    # Returns the first provider of the given order which is usable. The
+    # reasons earlier providers were skipped are shown along with the
+    # chosen provider. The result is cached so the output is only shown
+    # once per machine.
+    #
+    # @param [Array<Symbol>] order Provider names in order of preference
+    # @return [Symbol] Name of the chosen provider
+    def ordered_provider(order, **opts)
+      order = order.map(&:to_sym)
+      order = order.reject { |name| opts[:exclude].include?(name) } if opts[:exclude]
+      providers = Vagrant.plugin("2").manager.providers
+
+      # Without checking usability the first known provider is used, or
+      # the first provider if none are known so it can be installed.
+      if !opts[:check_usable]
+        return order.detect { |name| providers.key?(name) } || order.first
+      end
+
+      @provider_order_cache ||= {}
+      key = [opts[:machine], order]
+      return @provider_order_cache[key] if @provider_order_cache.key?(key)
+
+      skipped = []
+      chosen = order.detect do |name|
+        reason = nil
+        if !providers.key?(name)
+          reason = I18n.t("vagrant.provider_order_not_found")
+        else
+          begin
+            if !providers[name][0].usable?(true)
+              reason = I18n.t("vagrant.provider_order_not_usable")
+            end
+          rescue Errors::VagrantError => e
+            reason = e.message.strip.lines.first.to_s.strip
+          end
+        end
+
+        @logger.debug("Skipping provider `#{name}` in provider order: #{reason}") if reason
+        skipped << [name, reason] if reason
+        reason.nil?
+      end
+
+      if !chosen
+        raise Errors::ProviderOrderNotUsable,
+          reasons: skipped.map { |name, reason| "#{name}: #{reason}" }.join("\n")
+      end
+
+      ui = Vagrant::UI::Prefixed.new(@ui, opts[:machine].to_s)
+      skipped.each do |name, reason|
+        ui.detail(I18n.t("vagrant.provider_order_skipped",
+          provider: name.to_s, reason: reason))
+      end
+      ui.info(I18n.t("vagrant.provider_order_chosen",
+        provider: chosen.to_s, order: order.join(", ")))
+
+      @provider_order_cache[key] = chosen
+    end
+
     # Attempt to guess the configured provider in use. Will fallback
     # to the default provider if an explicit provider name is not
     # provided. This can be pretty error prone, but is used during
@@ -1144,6 +1304,26 @@ module Vagrant
       nil
     end
 
+    # Finds the organization Vagrantfile defined by the
+    # VAGRANT_ORG_VAGRANTFILE environment variable. The value may be
+    # a local path or an OCI reference (oci://registry/path:tag). Content
+    # fetched from a registry is cached in the data directory.
+    #
+    # @return [Pathname, nil]
+    def find_org_vagrantfile
+      location = ENV["VAGRANT_ORG_VAGRANTFILE"].to_s
+      return if location.empty?
+
+      if Util::OCIRegistry.reference?(location)
+        return Util::OCIRegistry.new(location).
+          fetch_vagrantfile(data_dir.join("oci"))
+      end
+
+      path = Pathname.new(File.expand_path(location, cwd))
+      raise Errors::OrgVagrantfileNotFound, path: path.to_s if !path.file?
+      path
+    end
+
     # Returns the key used for the host capability for provider installs
     # of the given name.
     def provider_install_key(name)
@@ -115,6 +115,10 @@ module Vagrant
       error_key(:alias_invalid_error)
     end
 
+    class BatchDependencyCycle < VagrantError
+      error_key(:batch_dependency_cycle)
+    end
+
     class BatchMultiError < VagrantError
       error_key(:batch_multi_error)
     end
@@ -123,6 +127,10 @@ module Vagrant
       error_key(:box_add_direct_version)
     end
 
+    class BoxAddManifestNotFound < VagrantError
+      error_key(:box_add_manifest_not_found)
+    end
+
     class BoxAddMetadataMultiURL < VagrantError
       error_key(:box_add_metadata_multi_url)
     end
@@ -163,6 +171,18 @@ module Vagrant
       error_key(:box_add_exists)
     end
 
+    class BoxBaseInvalid < VagrantError
+      error_key(:box_base_invalid)
+    end
+
+    class BoxBaseNotFound < VagrantError
+      error_key(:box_base_not_found)
+    end
+
+    class BoxBaseUnavailable < VagrantError
+      error_key(:box_base_unavailable)
+    end
+
     class BoxChecksumInvalidType < VagrantError
       error_key(:box_checksum_invalid_type)
     end
@@ -255,6 +275,18 @@ module Vagrant
       error_key(:box_server_not_set)
     end
 
+    class BoxSignatureInvalid < VagrantError
+      error_key(:box_signature_invalid)
+    end
+
+    class BoxSignatureKeyMissing < VagrantError
+      error_key(:box_signature_key_missing)
+    end
+
+    class BoxSignatureMalformed < VagrantError
+      error_key(:box_signature_malformed)
+    end
+
     class BoxUnpackageFailure < VagrantError
       error_key(:untar_failure, "vagrant.actions.box.unpackage")
     end
@@ -335,6 +367,14 @@ module Vagrant
       error_key(:cli_invalid_options)
     end
 
+    class ClientClosed < VagrantError
+      error_key(:client_closed)
+    end
+
+    class CloneAddressCollision < VagrantError
+      error_key(:clone_address_collision)
+    end
+
     class CloneNotFound < VagrantError
       error_key(:clone_not_found)
     end
@@ -343,6 +383,14 @@ module Vagrant
       error_key(:clone_machine_not_found)
     end
 
+    class CloneMachineExists < VagrantError
+      error_key(:clone_machine_exists)
+    end
+
+    class CloneMachineUnsupported < VagrantError
+      error_key(:clone_machine_unsupported)
+    end
+
     class CloudInitNotFound < VagrantError
       error_key(:cloud_init_not_found)
     end
@@ -375,6 +423,14 @@ module Vagrant
       error_key(:config_invalid)
     end
 
+    class ConfigKeyInvalid < VagrantError
+      error_key(:config_key_invalid)
+    end
+
+    class ConfigValidationFindings < VagrantError
+      error_key(:config_validation_findings)
+    end
+
     class ConfigUpgradeErrors < VagrantError
       error_key(:config_upgrade_errors)
     end
@@ -403,6 +459,10 @@ module Vagrant
       error_key(:destroy_requires_force)
     end
 
+    class DisksProviderUnsupported < VagrantError
+      error_key(:disks_provider_unsupported)
+    end
+
     class DotfileUpgradeJSONError < VagrantError
       error_key(:dotfile_upgrade_json_error)
     end
@@ -451,6 +511,22 @@ module Vagrant
       error_key(:hyperv_virtualbox_error)
     end
 
+    class ExportArchiveFailed < VagrantError
+      error_key(:export_archive_failed)
+    end
+
+    class ExportMachineRunning < VagrantError
+      error_key(:export_machine_running)
+    end
+
+    class ExportMachineUnsupported < VagrantError
+      error_key(:export_machine_unsupported)
+    end
+
+    class ExportOutputExists < VagrantError
+      error_key(:export_output_exists)
+    end
+
     class ForwardPortAdapterNotFound < VagrantError
       error_key(:forward_port_adapter_not_found)
     end
@@ -459,6 +535,10 @@ module Vagrant
       error_key(:auto_empty, "vagrant.actions.vm.forward_ports")
     end
 
+    class ForwardPortHostRangeExhausted < VagrantError
+      error_key(:host_range_exhausted, "vagrant.actions.vm.forward_ports")
+    end
+
     class ForwardPortHostIPNotFound < VagrantError
       error_key(:host_ip_not_found, "vagrant.actions.vm.forward_ports")
     end
@@ -483,6 +563,18 @@ module Vagrant
       error_key(:guest_not_detected)
     end
 
+    class GuestToolsMissingMountFailed < VagrantError
+      error_key(:guest_tools_missing_mount_failed)
+    end
+
+    class GuestToolsMismatchMountFailed < VagrantError
+      error_key(:guest_tools_mismatch_mount_failed)
+    end
+
+    class HostsFileUpdateFailed < VagrantError
+      error_key(:hosts_file_update_failed)
+    end
+
     class HostExplicitNotDetected < VagrantError
       error_key(:host_explicit_not_detected)
     end
@@ -515,6 +607,14 @@ module Vagrant
       error_key(:machine_guest_not_ready)
     end
 
+    class MachineIndexIdCollision < VagrantError
+      error_key(:machine_index_id_collision)
+    end
+
+    class MachineLifecycleVetoed < VagrantError
+      error_key(:machine_lifecycle_vetoed)
+    end
+
     class MachineLocked < VagrantError
       error_key(:machine_locked)
     end
@@ -619,6 +719,34 @@ module Vagrant
       error_key(:no_env)
     end
 
+    class OCIDigestMismatch < VagrantError
+      error_key(:oci_digest_mismatch)
+    end
+
+    class OCIManifestInvalid < VagrantError
+      error_key(:oci_manifest_invalid)
+    end
+
+    class OCINotFound < VagrantError
+      error_key(:oci_not_found)
+    end
+
+    class OCIReferenceInvalid < VagrantError
+      error_key(:oci_reference_invalid)
+    end
+
+    class OCIRequestFailed < VagrantError
+      error_key(:oci_request_failed)
+    end
+
+    class OCIUnauthorized < VagrantError
+      error_key(:oci_unauthorized)
+    end
+
+    class OrgVagrantfileNotFound < VagrantError
+      error_key(:org_vagrantfile_not_found)
+    end
+
     class OscdimgCommandMissingError < VagrantError
       error_key(:oscdimg_command_missing)
     end
@@ -671,6 +799,10 @@ module Vagrant
       error_key(:provider_install_failed)
     end
 
+    class ProviderOrderNotUsable < VagrantError
+      error_key(:provider_order_not_usable)
+    end
+
     class ProviderNotFound < VagrantError
       error_key(:provider_not_found)
     end
@@ -683,6 +815,18 @@ module Vagrant
       error_key(:provider_not_usable)
     end
 
+    class ProviderStateCorrupt < VagrantError
+      error_key(:provider_state_corrupt)
+    end
+
+    class ProviderStateInvalidValue < VagrantError
+      error_key(:provider_state_invalid_value)
+    end
+
+    class ProviderStateTypeMismatch < VagrantError
+      error_key(:provider_state_type_mismatch)
+    end
+
     class ProvisionerFlagInvalid < VagrantError
       error_key(:provisioner_flag_invalid)
     end
@@ -771,6 +915,26 @@ module Vagrant
       error_key(:push_strategy_not_provided)
     end
 
+    class ReloadNetworksRebootRequired < VagrantError
+      error_key(:reload_networks_reboot_required)
+    end
+
+    class ReloadNotRunning < VagrantError
+      error_key(:reload_not_running)
+    end
+
+    class ReloadSyncedFoldersRebootRequired < VagrantError
+      error_key(:reload_synced_folders_reboot_required)
+    end
+
+    class RSyncBackFolderDisabled < VagrantError
+      error_key(:rsync_back_folder_disabled)
+    end
+
+    class RSyncBackFolderNotFound < VagrantError
+      error_key(:rsync_back_folder_not_found)
+    end
+
     class RSyncPostCommandError < VagrantError
       error_key(:rsync_post_command_error)
     end
@@ -799,6 +963,22 @@ module Vagrant
       error_key(:scp_unavailable)
     end
 
+    class SecretNotFound < VagrantError
+      error_key(:secret_not_found)
+    end
+
+    class SecretProviderFailed < VagrantError
+      error_key(:secret_provider_failed)
+    end
+
+    class SecretProviderNotFound < VagrantError
+      error_key(:secret_provider_not_found)
+    end
+
+    class SecretReferenceInvalid < VagrantError
+      error_key(:secret_reference_invalid)
+    end
+
     class SharedFolderCreateFailed < VagrantError
       error_key(:shared_folder_create_failed)
     end
@@ -907,6 +1087,10 @@ module Vagrant
       error_key(:ssh_unavailable_windows)
     end
 
+    class SyncedFolderReadonlyFailed < VagrantError
+      error_key(:synced_folder_readonly_failed)
+    end
+
     class SyncedFolderUnusable < VagrantError
       error_key(:synced_folder_unusable)
     end
@@ -915,10 +1099,17 @@ module Vagrant
       error_key(:triggers_bad_exit_codes)
     end
 
+    class TriggersConditionInvalid < VagrantError
+      error_key(:triggers_condition_invalid)
+    end
+
     class TriggersGuestNotExist < VagrantError
       error_key(:triggers_guest_not_exist)
     end
 
+    # @deprecated Triggers skip remote scripts on guests which are not
+    #   running instead of raising this error. Kept for plugins which
+    #   rescue it.
     class TriggersGuestNotRunning < VagrantError
       error_key(:triggers_guest_not_running)
     end
@@ -955,6 +1146,10 @@ module Vagrant
       error_key(:upload_source_missing)
     end
 
+    class UploadVerificationFailed < VagrantError
+      error_key(:upload_verification_failed)
+    end
+
     class UploaderError < VagrantError
       error_key(:uploader_error)
     end
@@ -1039,6 +1234,10 @@ module Vagrant
       error_key(:virtualbox_disks_unsupported_controller)
     end
 
+    class VirtualBoxExportRunning < VagrantError
+      error_key(:virtualbox_export_running)
+    end
+
     class VirtualBoxGuestPropertyNotFound < VagrantError
       error_key(:virtualbox_guest_property_not_found)
     end
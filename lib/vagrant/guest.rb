@@ -30,10 +30,19 @@ module Vagrant
     end
 
     # This will detect the proper guest OS for the machine and set up
-    # the class to actually execute capabilities.
+    # the class to actually execute capabilities. The detected guest is
+    # cached in the {GuestCache} of the machine.
     def detect!
       guest_name = @machine.config.vm.guest
+      if !guest_name
+        cache = @machine.guest_cache
+        cached = cache.get(:guest)
+        # The cached guest is ignored if its plugin is no longer installed
+        guest_name = cached.to_sym if cached && @guests[cached.to_sym]
+      end
+
       initialize_capabilities!(guest_name, @guests, @capabilities, @machine)
+      cache.store(:guest, name.to_s) if cache && ready?
     rescue Errors::CapabilityHostExplicitNotDetected => e
       raise Errors::GuestExplicitNotDetected, value: e.extra_data[:value]
     rescue Errors::CapabilityHostNotDetected
@@ -68,5 +77,10 @@ module Vagrant
     def ready?
       !!capability_host_chain
     end
+
+    # Forget the detected guest so it is detected again by {#detect!}.
+    def reset!
+      @cap_host_chain = nil
+    end
   end
 end
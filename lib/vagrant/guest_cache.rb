@@ -0,0 +1,155 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require "digest/sha2"
+require "json"
+require "securerandom"
+
+require "log4r"
+
+module Vagrant
+  # This caches the results of detecting the guest of a machine, such as
+  # the guest OS and the init system it uses, so the commands which
+  # detect them are only run once for each Vagrant command.
+  #
+  # When `config.vm.guest_cache` is enabled the results are also stored
+  # in the data directory of the machine along with a fingerprint of the
+  # machine and its box. Later commands use the stored results until the
+  # fingerprint changes, such as when the machine is recreated from a new
+  # version of the box.
+  #
+  # Only positive results are stored. Negative results are only cached
+  # for the current command, so a detection which did not succeed is
+  # tried again by the next command, and detections which raise an error
+  # are never cached.
+  class GuestCache
+    # Name of the file within the data directory storing the results
+    CACHE_FILE = "guest_cache.json".freeze
+
+    # @param [Machine] machine
+    def initialize(machine)
+      @logger = Log4r::Logger.new("vagrant::guest_cache")
+      @machine = machine
+      @lock = Mutex.new
+      @values = {}
+    end
+
+    # Get a cached result. If there is no result, the block is called to
+    # detect it and the result is cached.
+    #
+    # @param [String, Symbol] key Name of the detection
+    # @return [Object] the result
+    def fetch(key)
+      key = key.to_s
+      @lock.synchronize do
+        return @values[key] if @values.key?(key)
+
+        stored = read
+        if stored.key?(key)
+          @logger.debug("Using stored guest detection result: #{key}")
+          return @values[key] = stored[key]
+        end
+      end
+
+      # The detection is run without holding the lock since it may use
+      # other cached results
+      value = yield
+      store(key, value)
+      value
+    end
+
+    # @param [String, Symbol] key Name of the detection
+    # @return [Object, nil] the cached result, or nil if there is none
+    def get(key)
+      key = key.to_s
+      @lock.synchronize do
+        @values.key?(key) ? @values[key] : read[key]
+      end
+    end
+
+    # Cache a result
+    #
+    # @param [String, Symbol] key Name of the detection
+    # @param [Object] value The result, which must be serializable to JSON
+    #   to be stored
+    # @return [Object] the result
+    def store(key, value)
+      key = key.to_s
+      @lock.synchronize do
+        @values[key] = value
+        write(key, value) if value
+      end
+
+      value
+    end
+
+    # Remove all the cached and stored results
+    def clear
+      @lock.synchronize do
+        @logger.info("Clearing guest detection results")
+        @values.clear
+        path.delete if path && path.file?
+      end
+    end
+
+    # @return [Pathname, nil] path to the stored results
+    def path
+      return if !@machine.data_dir
+
+      @machine.data_dir.join(CACHE_FILE)
+    end
+
+    # The fingerprint of the machine the stored results are valid for.
+    # Results are only stored once the machine is created.
+    #
+    # @return [String, nil]
+    def fingerprint
+      return if !@machine.id
+
+      parts = [@machine.id, @machine.provider_name]
+      box = @machine.box
+      parts += [box.name, box.version, box.provider] if box
+      Digest::SHA256.hexdigest(parts.map(&:to_s).join("\0"))
+    end
+
+    protected
+
+    # @return [Boolean] results are stored
+    def persist?
+      !!(@machine.config.vm.guest_cache && path)
+    end
+
+    # @return [Hash] stored results, if they are valid for the machine
+    def read
+      return {} if !persist? || !path.file?
+
+      data = JSON.parse(path.read)
+      return {} if !data.is_a?(Hash) || data["fingerprint"] != fingerprint
+
+      data["values"] || {}
+    rescue JSON::ParserError => e
+      @logger.warn("Ignoring invalid guest detection results: #{e}")
+      {}
+    end
+
+    # Store a result. Stored results for a different fingerprint are
+    # replaced.
+    def write(key, value)
+      return if !persist?
+
+      current = fingerprint
+      return if !current
+
+      values = read.merge(key => value)
+      tmp = path.dirname.join(".#{CACHE_FILE}.#{SecureRandom.hex(4)}")
+      begin
+        tmp.write(JSON.dump("fingerprint" => current, "values" => values))
+        File.rename(tmp, path)
+      ensure
+        tmp.delete if tmp.exist?
+      end
+    rescue SystemCallError => e
+      @logger.warn("Failed to store guest detection results: #{e}")
+    end
+  end
+end
@@ -35,6 +35,11 @@ module Vagrant
     # @return [Environment]
     attr_reader :env
 
+    # Cache of the results of detecting the guest of this machine.
+    #
+    # @return [GuestCache]
+    attr_reader :guest_cache
+
     # ID of the machine. This ID comes from the provider and is not
     # guaranteed to be of any particular format except that it is
     # a string.
@@ -113,6 +118,7 @@ module Vagrant
         self,
         Vagrant.plugin("2").manager.guests,
         Vagrant.plugin("2").manager.guest_capabilities)
+      @guest_cache     = GuestCache.new(self)
       @name            = name
       @provider_config = provider_config
       @provider_name   = provider_name
@@ -197,6 +203,13 @@ module Vagrant
 
       # Lock this machine for the duration of this action
       return_env = locker.call("machine-action-#{id}") do
+        # Forget the guest detection results if requested so the guest
+        # is detected again
+        if extra_env[:guest_redetect]
+          @guest_cache.clear
+          @guest.reset!
+        end
+
         # Get the callable from the provider.
         callable = @provider.action(name)
 
@@ -208,10 +221,30 @@ module Vagrant
             provider: @provider.to_s
         end
 
+        # Notify lifecycle hooks of the pending state change. Hooks
+        # may prevent the action from running.
+        hooks = lifecycle_hooks
+        old_state = nil
+        if !hooks.empty?
+          old_state = state
+          run_lifecycle_hooks(hooks, :before, name, old_state, nil)
+        end
+
         # Call the action
         ui.machine("action", name.to_s, "start")
-        action_result = action_raw(name, callable, extra_env)
+        action_result = Util::Tracing.span("vagrant.action",
+          Util::Tracing.machine_attributes(self, name)) do
+          action_raw(name, callable, extra_env)
+        end
         ui.machine("action", name.to_s, "end")
+
+        if !hooks.empty?
+          new_state = state
+          if new_state.id != old_state.id
+            run_lifecycle_hooks(hooks, :after, name, old_state, new_state)
+          end
+        end
+
         action_result
       end
       # preserve returning environment after machine action runs
@@ -241,7 +274,9 @@ module Vagrant
         machine: self,
         machine_action: name
       )
-      @env.action_runner.run(callable, env)
+      Util::Logging.with_fields(machine: @name.to_s, action: name.to_s) do
+        @env.action_runner.run(callable, env)
+      end
     end
 
     # Returns a communication object for executing commands on the remote
@@ -337,7 +372,10 @@ module Vagrant
             }
           end
 
-          entry = @env.machine_index.set(entry)
+          new_id = nil
+          new_id = deterministic_index_uuid if @config.vagrant.deterministic_ids
+
+          entry = @env.machine_index.set(entry, new_id: new_id)
           @env.machine_index.release(entry)
 
           # Store our UUID so we can access it later
@@ -376,11 +414,24 @@ module Vagrant
       # Store the ID locally
       @id = value.nil? ? nil : value.to_s
 
+      # Guest detection results are only valid for the previous machine
+      @guest_cache.clear
+
       # Notify the provider that the ID changed in case it needs to do
       # any accounting from it.
       @provider.machine_id_changed
     end
 
+    # Returns the store for provider specific state of this machine.
+    #
+    # @return [ProviderState, nil] state store, or nil if the machine
+    #   has no data directory
+    def provider_state
+      return nil if !@data_dir
+
+      @provider_state ||= ProviderState.new(@data_dir)
+    end
+
     # Returns the UUID associated with this machine in the machine
     # index. We only have a UUID if an ID has been set.
     #
@@ -490,6 +541,8 @@ module Vagrant
       info[:connect_timeout] = @config.ssh.connect_timeout
       info[:connect_retries] = @config.ssh.connect_retries
       info[:connect_retry_delay] = @config.ssh.connect_retry_delay
+      info[:connect_retry_backoff] = @config.ssh.connect_retry_backoff
+      info[:connect_retry_max_delay] = @config.ssh.connect_retry_max_delay
 
       info[:ssh_command] = @config.ssh.ssh_command if @config.ssh.ssh_command
 
@@ -637,6 +690,78 @@ module Vagrant
 
     protected
 
+    # Returns the registered machine lifecycle hooks.
+    #
+    # @return [Hash<Symbol, Class>]
+    def lifecycle_hooks
+      Vagrant.plugin("2").manager.lifecycles.to_hash
+    end
+
+    # Run the lifecycle hooks for a state change of this machine. During
+    # the before phase hooks may raise a Vagrant error to prevent the
+    # action from running. Any other errors raised by a hook are logged
+    # and reported without interrupting the action.
+    #
+    # @param [Hash<Symbol, Class>] hooks Lifecycle hooks to run
+    # @param [Symbol] phase Either :before or :after
+    # @param [Symbol] action Name of the action
+    # @param [MachineState] old_state State before the action
+    # @param [MachineState, nil] new_state State after the action
+    def run_lifecycle_hooks(hooks, phase, action, old_state, new_state)
+      target = Plugin::V2::Lifecycle::Target.new(
+        @name.to_s, id, @provider_name.to_s, @env.root_path)
+
+      hooks.each do |hook_name, klass|
+        @logger.debug("Running lifecycle hook #{hook_name} (#{phase} #{action})")
+        begin
+          klass.new.on_state_change(old_state, new_state, target,
+            phase: phase, action: action)
+        rescue StandardError, ScriptError => e
+          if phase == :before && e.is_a?(Errors::VagrantError)
+            raise Errors::MachineLifecycleVetoed,
+              action: action.to_s,
+              hook: hook_name.to_s,
+              message: e.message,
+              name: @name.to_s
+          end
+
+          @logger.error("Lifecycle hook #{hook_name} failed: #{e.class}: #{e}")
+          @logger.error(e.backtrace.join("\n")) if e.backtrace
+          ui.warn(I18n.t("vagrant.general.lifecycle_hook_error",
+            action: action.to_s,
+            hook: hook_name.to_s,
+            message: e.message))
+        end
+      end
+    end
+
+    # Returns the UUID for this machine in the machine index derived from
+    # the project path and the machine name. A UUID which belongs to a
+    # machine of another project is only reused if that machine no longer
+    # exists.
+    #
+    # @return [String]
+    def deterministic_index_uuid
+      uuid = MachineIndex.deterministic_id(@env.root_path, @name)
+      return uuid if !@env.machine_index.include?(uuid)
+
+      other = @env.machine_index.find { |entry| entry.id == uuid }
+      return uuid if !other
+      return uuid if other.name == @name.to_s &&
+        other.vagrantfile_path.to_s == @env.root_path.to_s
+
+      if other.valid?(@env.home_path)
+        raise Errors::MachineIndexIdCollision,
+          id: uuid,
+          name: @name.to_s,
+          other_name: other.name.to_s,
+          other_path: other.vagrantfile_path.to_s
+      end
+
+      @logger.info("Replacing stale machine index entry: #{uuid}")
+      uuid
+    end
+
     # Returns the path to the file that stores the UID.
     def uid_file
       return nil if !@data_dir
@@ -1,6 +1,7 @@
 # Copyright IBM Corp. 2010, 2025
 # SPDX-License-Identifier: BUSL-1.1
 
+require "digest/sha2"
 require "json"
 require "pathname"
 require "securerandom"
@@ -43,6 +44,18 @@ module Vagrant
 
     include Enumerable
 
+    # Derive a stable UUID for a machine from the path of its project and
+    # its name, so the same machine gets the same UUID each time it is
+    # created. The UUID has the same format as a random UUID.
+    #
+    # @param [Pathname, String] vagrantfile_path Path of the project
+    # @param [String, Symbol] name Name of the machine
+    # @return [String]
+    def self.deterministic_id(vagrantfile_path, name)
+      path = File.expand_path(vagrantfile_path.to_s)
+      Digest::SHA256.hexdigest("#{path}\0#{name}")[0, 32]
+    end
+
     # Initializes a MachineIndex at the given file location.
     #
     # @param [Pathname] data_dir Path to the directory where data for the
@@ -184,9 +197,13 @@ module Vagrant
     # If the entry isn't new (has a UUID). then this process must hold
     # that entry's lock or else this set will fail.
     #
+    # If `new_id` is given, it is used as the UUID of a new entry
+    # instead of a random one, replacing any entry with that UUID.
+    #
     # @param [Entry] entry
+    # @param [String] new_id UUID for the entry if it is new
     # @return [Entry]
-    def set(entry)
+    def set(entry, new_id: nil)
       # Get the struct and update the updated_at attribute
       struct = entry.to_json_struct
 
@@ -211,7 +228,9 @@ module Vagrant
               end
             end
 
-            # If we still don't have an ID, generate a random one
+            # If we still don't have an ID, use the requested one or
+            # generate a random one
+            id ||= new_id
             id = SecureRandom.uuid.gsub("-", "") if !id
 
             # Get a lock on this machine
@@ -15,15 +15,19 @@ module Vagrant
       autoload :Communicator, "vagrant/plugin/v2/communicator"
       autoload :Components, "vagrant/plugin/v2/components"
       autoload :Config, "vagrant/plugin/v2/config"
+      autoload :ConfigWarning, "vagrant/plugin/v2/config_warning"
       autoload :Guest,  "vagrant/plugin/v2/guest"
       autoload :Host,   "vagrant/plugin/v2/host"
+      autoload :Lifecycle, "vagrant/plugin/v2/lifecycle"
       autoload :Manager, "vagrant/plugin/v2/manager"
       autoload :Plugin, "vagrant/plugin/v2/plugin"
       autoload :Provider, "vagrant/plugin/v2/provider"
       autoload :Push, "vagrant/plugin/v2/push"
       autoload :Provisioner, "vagrant/plugin/v2/provisioner"
+      autoload :SecretProvider, "vagrant/plugin/v2/secret_provider"
       autoload :SyncedFolder, "vagrant/plugin/v2/synced_folder"
       autoload :Trigger, "vagrant/plugin/v2/trigger"
+      autoload :TriggerCondition, "vagrant/plugin/v2/trigger_condition"
 
       # Errors
       autoload :Error, "vagrant/plugin/v2/error"
@@ -78,6 +78,9 @@ module Vagrant
         # @option options [Symbol] :provider The provider to back the
         #   machines with. All machines will be backed with this
         #   provider. If none is given, a sensible default is chosen.
+        # @option options [Array<Symbol>] :provider_order Providers to try
+        #   in order when choosing the default provider for machines
+        #   which have not been created.
         # @option options [Boolean] :reverse If true, the resulting order
         #   of machines is reversed.
         # @option options [Boolean] :single_target If true, then an
@@ -177,7 +180,8 @@ module Vagrant
             end
 
             # Use the default provider if nothing else
-            provider_to_use ||= @env.default_provider(machine: name)
+            provider_to_use ||= @env.default_provider(
+              machine: name, provider_order: options[:provider_order])
 
             # Get the right machine with the right provider
             @env.machine(name, provider_to_use)
@@ -87,6 +87,53 @@ module Vagrant
         def upload(from, to)
         end
 
+        # This returns true if the communicator implements the primitives
+        # used to upload large files in chunks, which are {#upload_chunk},
+        # {#file_size}, {#file_checksum} and {#move_file}. The chunks are
+        # uploaded by {Vagrant::Util::ChunkedUpload}.
+        #
+        # @return [Boolean]
+        def chunked_upload?
+          false
+        end
+
+        # Upload a chunk of a file to the remote machine. The chunk is
+        # written to the remote file at the given offset, which is either
+        # zero, in which case the remote file is replaced, or the size of
+        # the remote file, in which case the chunk is appended to it.
+        #
+        # @param [String] from Path of the chunk locally to upload.
+        # @param [String] to Path of the file on the remote machine.
+        # @param [Integer] offset Offset of the chunk within the file.
+        def upload_chunk(from, to, offset)
+        end
+
+        # Read the size of a file on the remote machine.
+        #
+        # @param [String] path Path of the file on the remote machine.
+        # @return [Integer, nil] Size of the file in bytes, or nil if the
+        #   file does not exist.
+        def file_size(path)
+        end
+
+        # Calculate the checksum of a file on the remote machine.
+        #
+        # @param [String] path Path of the file on the remote machine.
+        # @param [Symbol] type Type of the checksum, which is one of the
+        #   keys of {Vagrant::Util::ChunkedUpload::CHECKSUM_TYPES}.
+        # @return [String, nil] Hex digest of the file, or nil if it can
+        #   not be calculated.
+        def file_checksum(path, type)
+        end
+
+        # Move a file on the remote machine, replacing the destination if
+        # it exists.
+        #
+        # @param [String] from Path of the file on the remote machine.
+        # @param [String] to Path to move the file to.
+        def move_file(from, to)
+        end
+
         # Execute a command on the remote machine. The exact semantics
         # of this method are up to the implementor, but in general the
         # users of this class will expect this to be a shell.
@@ -46,6 +46,11 @@ module Vagrant
         # @return [Hash<Symbol, Registry>]
         attr_reader :host_capabilities
 
+        # This contains all the machine lifecycle hooks by name.
+        #
+        # @return [Registry<Symbol, Class>]
+        attr_reader :lifecycles
+
         # This contains all the provider plugins by name, and returns
         # the provider class and options.
         #
@@ -62,6 +67,11 @@ module Vagrant
         # @return [Registry<Symbol, Array<Class, Hash>>]
         attr_reader :pushes
 
+        # This contains all the secret provider implementations by name.
+        #
+        # @return [Registry<Symbol, Class>]
+        attr_reader :secret_providers
+
         # This contains all the synced folder implementations by name.
         #
         # @return [Registry<Symbol, Array<Class, Integer>>]
@@ -82,9 +92,11 @@ module Vagrant
           @guest_capabilities = Hash.new { |h, k| h[k] = Registry.new }
           @hosts   = Registry.new
           @host_capabilities = Hash.new { |h, k| h[k] = Registry.new }
+          @lifecycles = Registry.new
           @providers = Registry.new
           @provider_capabilities = Hash.new { |h, k| h[k] = Registry.new }
           @pushes = Registry.new
+          @secret_providers = Registry.new
           @synced_folders = Registry.new
           @synced_folder_capabilities = Hash.new { |h, k| h[k] = Registry.new }
         end
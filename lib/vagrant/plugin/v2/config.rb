@@ -18,6 +18,19 @@ module Vagrant
 
         UNSET_VALUE = :__UNSET__VALUE__
 
+        # This returns the schema of the configuration, which is used by
+        # {#validate_config} to check the types of the values which are
+        # set. The schema is a hash of attribute names and the types that
+        # are valid for the attribute. Types are classes, or `:boolean`
+        # for true or false.
+        #
+        # Plugins should override this to declare their schema.
+        #
+        # @return [Hash<Symbol, Array<Class, Symbol>>]
+        def self.schema
+          {}
+        end
+
         # This is called as a last-minute hook that allows the configuration
         # object to finalize itself before it will be put into use. This is
         # a useful place to do some defaults in the case the user didn't
@@ -68,6 +81,10 @@ module Vagrant
           other_invalid = other.instance_variable_get(:"@__invalid_methods") || Set.new
           result.instance_variable_set(:"@__invalid_methods", this_invalid + other_invalid)
 
+          this_locations  = @__invalid_locations || {}
+          other_locations = other.instance_variable_get(:"@__invalid_locations") || {}
+          result.instance_variable_set(:"@__invalid_locations", other_locations.merge(this_locations))
+
           result
         end
 
@@ -96,6 +113,10 @@ module Vagrant
           @__invalid_methods ||= Set.new
           @__invalid_methods.add(name)
 
+          # Remember where the setting was made so it can be reported
+          @__invalid_locations ||= {}
+          @__invalid_locations[name] ||= _caller_location
+
           # Return the dummy object so that anything else works
           ::Vagrant::Config::V2::DummyConfig.new
         end
@@ -142,13 +163,61 @@ module Vagrant
           return { self.to_s => _detected_errors }
         end
 
+        # Validates this configuration against its {.schema}. This
+        # reports values which are of a type not allowed by the schema as
+        # warnings. Settings which do not exist are reported by
+        # {#_detected_errors} when the configuration is validated.
+        #
+        # @param [Machine] machine Access to the machine that is being
+        #   validated.
+        # @return [Array<ConfigWarning>]
+        def validate_config(machine)
+          warnings = []
+          self.class.schema.each do |key, types|
+            var = :"@#{key}"
+            next if !instance_variable_defined?(var)
+
+            value = instance_variable_get(var)
+            next if value.nil? || value == UNSET_VALUE
+
+            types = Array(types)
+            next if types.any? { |t| t == :boolean ? value == true || value == false : value.is_a?(t) }
+
+            warnings << ConfigWarning.new(:warning, key.to_s,
+              I18n.t("vagrant.config.common.wrong_type",
+                field: key.to_s,
+                types: types.map(&:to_s).join(", "),
+                actual: value.class.to_s))
+          end
+
+          warnings
+        end
+
         # This returns any automatically detected errors.
         #
         # @return [Array<String>]
         def _detected_errors
           return [] if !@__invalid_methods || @__invalid_methods.empty?
+
+          fields = @__invalid_methods.to_a.sort.map do |name|
+            location = @__invalid_locations[name] if @__invalid_locations
+            location ? "#{name} (#{location})" : name
+          end
           return [I18n.t("vagrant.config.common.bad_field",
-                         fields: @__invalid_methods.to_a.sort.join(", "))]
+                         fields: fields.join(", "))]
+        end
+
+        # Returns the location of the first caller outside of Vagrant
+        # itself, which is generally the line of a Vagrantfile.
+        #
+        # @return [String, nil] path and line of the caller
+        def _caller_location
+          roots = ["lib", "plugins"].map { |d| Vagrant.source_root.join(d).to_s }
+          location = caller_locations.detect do |l|
+            path = l.absolute_path || l.path
+            !path.start_with?("<") && roots.none? { |r| path.start_with?(r) }
+          end
+          "#{location.path}:#{location.lineno}" if location
         end
 
         # An internal finalize call that no subclass should override.
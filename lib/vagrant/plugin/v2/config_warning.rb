@@ -0,0 +1,26 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+module Vagrant
+  module Plugin
+    module V2
+      # A problem found when validating a configuration section against
+      # its schema with {Config#validate_config}.
+      #
+      # @!attribute level
+      #   @return [Symbol] `:error` or `:warning`
+      # @!attribute key
+      #   @return [String] name of the setting
+      # @!attribute message
+      #   @return [String] description of the problem
+      # @!attribute location
+      #   @return [String, nil] path and line where the setting was set
+      ConfigWarning = Struct.new(:level, :key, :message, :location) do
+        # @return [Boolean] this is an error level finding
+        def error?
+          level == :error
+        end
+      end
+    end
+  end
+end
@@ -0,0 +1,33 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+module Vagrant
+  module Plugin
+    module V2
+      # This is the base class for a machine lifecycle hook. Lifecycle
+      # hooks are notified before an action is run on a machine and
+      # after the action has changed the state of the machine.
+      class Lifecycle
+        # Information about the machine the state change applies to.
+        Target = Struct.new(:name, :id, :provider, :vagrantfile_path)
+
+        # Called when the state of a machine is about to change or has
+        # changed.
+        #
+        # During the `:before` phase the new state is not yet known and
+        # is nil. Raising an error based on {Vagrant::Errors::VagrantError}
+        # during the `:before` phase will prevent the action from being
+        # run. Errors raised during the `:after` phase are reported but
+        # do not change the result of the action.
+        #
+        # @param [MachineState] old_state State of the machine before the action
+        # @param [MachineState, nil] new_state State of the machine after the action
+        # @param [Target] target Machine the state change applies to
+        # @param [Symbol] phase Either `:before` or `:after`
+        # @param [Symbol] action Name of the action being run
+        def on_state_change(old_state, new_state, target, phase:, action:)
+        end
+      end
+    end
+  end
+end
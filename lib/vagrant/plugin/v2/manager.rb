@@ -169,6 +169,17 @@ module Vagrant
           results
         end
 
+        # This returns all registered machine lifecycle hooks.
+        #
+        # @return [Registry]
+        def lifecycles
+          Registry.new.tap do |result|
+            @registered.each do |plugin|
+              result.merge!(plugin.components.lifecycles)
+            end
+          end
+        end
+
         # This returns all registered providers.
         #
         # @return [Hash]
@@ -250,6 +261,17 @@ module Vagrant
           end
         end
 
+        # This returns all registered secret providers.
+        #
+        # @return [Registry]
+        def secret_providers
+          Registry.new.tap do |result|
+            @registered.each do |plugin|
+              result.merge!(plugin.components.secret_providers)
+            end
+          end
+        end
+
         # This returns all synced folder implementations.
         #
         # @return [Registry]
@@ -214,6 +214,16 @@ module Vagrant
           nil
         end
 
+        # Registers a machine lifecycle hook. The block should return a
+        # class that inherits from {Lifecycle}. The hook is notified of
+        # state changes on every machine.
+        #
+        # @param [String] name Name of the lifecycle hook.
+        def self.lifecycle(name, &block)
+          components.lifecycles.register(name.to_sym, &block)
+          nil
+        end
+
         # Registers additional provisioners to be available.
         #
         # @param [String] name Name of the provisioner.
@@ -239,6 +249,16 @@ module Vagrant
           nil
         end
 
+        # Registers additional secret providers, which read the secrets
+        # referenced in a Vagrantfile.
+        #
+        # @param [String] name Name of the secret provider, used as the
+        #   prefix of secret references.
+        def self.secret_provider(name, &block)
+          components.secret_providers.register(name.to_sym, &block)
+          nil
+        end
+
         # Registers additional synced folder implementations.
         #
         # @param [String] name Name of the implementation.
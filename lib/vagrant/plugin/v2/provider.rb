@@ -42,6 +42,30 @@ module Vagrant
           true
         end
 
+        # This is called to find all the machines of this provider on the
+        # host, such as by `vagrant doctor` to check whether the machines
+        # in the machine index still exist. This should return the IDs of
+        # the machines, which are the same IDs as {Machine#id}.
+        #
+        # @return [Array<String>, nil] IDs of the machines, or nil if the
+        #   machines can not be found
+        def self.machine_ids
+          nil
+        end
+
+        # This is called to find the machines created by Vagrant with this
+        # provider on the host, such as by `vagrant doctor` to find
+        # machines which are not in the machine index. Machines which are
+        # shared by other machines, such as the masters of linked clones,
+        # should not be returned.
+        #
+        # @param [Vagrant::Environment] env
+        # @return [Array<String>, nil] IDs of the machines, or nil if the
+        #   machines can not be found
+        def self.created_machine_ids(env)
+          nil
+        end
+
         # Initialize the provider to represent the given machine.
         #
         # @param [Vagrant::Machine] machine The machine that this provider
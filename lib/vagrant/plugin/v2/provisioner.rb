@@ -45,11 +45,45 @@ module Vagrant
         def provision
         end
 
+        # This is the method called when a dry run of the provisioner is
+        # requested. It should describe the actions the provisioner would
+        # take without running anything on the machine.
+        #
+        # @return [Array<String>, nil] planned actions, or nil if the
+        #   provisioner does not support dry runs
+        def plan
+        end
+
+        # This is the method called to check if the provisioner needs to
+        # run again. The fingerprint should change whenever running the
+        # provisioner would have a different result, for example when the
+        # contents of a script change, so it should cover the whole
+        # configuration of the provisioner (see #config_values).
+        # Provisioners configured with `skip_unchanged` are skipped when
+        # their fingerprint is the same as the last successful run.
+        #
+        # @return [String, nil] fingerprint, or nil if the provisioner
+        #   should always run
+        def fingerprint
+        end
+
         # This is the method called when destroying a machine that allows
         # for any state related to the machine created by the provisioner
         # to be cleaned up.
         def cleanup
         end
+
+        protected
+
+        # @return [Hash] the values of the provisioner configuration, sorted
+        #   by name, for use in a fingerprint
+        def config_values
+          return {} if !config.respond_to?(:instance_variables_hash)
+
+          config.instance_variables_hash.reject do |name, _|
+            name.start_with?("_") || name == "logger"
+          end.sort.to_h
+        end
       end
     end
   end
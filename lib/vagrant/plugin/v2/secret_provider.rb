@@ -0,0 +1,26 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+module Vagrant
+  module Plugin
+    module V2
+      # This is the base class for a secret provider for the V2 API. A
+      # secret provider reads the values of secrets referenced in a
+      # Vagrantfile with `Vagrant.secret("NAME:PATH")`, where NAME is the
+      # name the provider is registered with.
+      class SecretProvider
+        # Read the value of a secret. Errors raised while reading the
+        # secret are reported to the user along with the reference.
+        #
+        # @param [String] path Path of the secret, which is the part of
+        #   the reference after the provider name and before any `#`
+        # @param [String, nil] field Field of the secret, which is the part
+        #   of the reference after the `#`
+        # @return [String, nil] the value, or nil if the secret does not exist
+        def resolve(path, field)
+          nil
+        end
+      end
+    end
+  end
+end
@@ -49,6 +49,11 @@ module Vagrant
 
         include CapabilityHost
 
+        # Options of synced folders which control the access of the guest
+        # to the folder. Implementations which can not honor some of these
+        # options return them from {#unsupported_options}.
+        ACCESS_OPTIONS = [:owner, :group, :mode, :mount_options, :readonly].freeze
+
         # This is called early when the synced folder is set to determine
         # if this implementation can be used for this machine. This should
         # return true or false.
@@ -60,6 +65,28 @@ module Vagrant
         def usable?(machine, raise_error=false)
         end
 
+        # This is called before the synced folders of a running machine
+        # are modified by `vagrant reload --only-synced-folders`. This
+        # should return true only if folders of this type can be added
+        # with {#enable} and removed with {#disable} while the machine is
+        # running.
+        #
+        # @param [Machine] machine
+        # @return [Boolean]
+        def hot_mount?(machine)
+          false
+        end
+
+        # This returns the options within {ACCESS_OPTIONS} which this
+        # implementation can not honor. Folders which set these options
+        # are warned about before they are enabled.
+        #
+        # @param [Machine] machine
+        # @return [Array<Symbol>]
+        def unsupported_options(machine)
+          []
+        end
+
         # DEPRECATED: This will be removed.
         #
         # @deprecated
@@ -40,7 +40,11 @@ module Vagrant
         # @param [Symbol] stage :before or :after
         # @param [String] guest The guest that invoked firing the triggers
         # @param [Symbol] type Type of trigger to fire (:action, :hook, :command)
-        def fire(name, stage, guest, type, all: false)
+        # @param [Integer] exit_code Exit code of the command, for after
+        #   triggers of commands
+        # @param [Boolean] conditional_only Only fire triggers which have
+        #   an `only_if` or `unless` condition
+        def fire(name, stage, guest, type, all: false, exit_code: nil, conditional_only: false)
           if community_plugin_detected?
             @logger.warn("Community plugin `vagrant-triggers detected, so core triggers will not fire")
             return
@@ -54,6 +58,8 @@ module Vagrant
 
           # get all triggers matching action
           triggers = find(name, stage, guest, type, all: all)
+          triggers = triggers.select { |t| t.only_if || t.unless } if conditional_only
+          triggers = filter_conditions(triggers, exit_code) if !triggers.empty?
 
           if !triggers.empty?
             @logger.info("Firing trigger for #{type} #{name} on guest #{guest}")
@@ -176,6 +182,39 @@ module Vagrant
           return triggers
         end
 
+        # Filters triggers to be fired based on their `only_if` and
+        # `unless` conditions
+        #
+        # @param [Array] triggers An array of triggers to be filtered
+        # @param [Integer] exit_code Exit code of the command
+        # @return [Array] The filtered array of triggers
+        def filter_conditions(triggers, exit_code)
+          context = TriggerCondition::Context.new(machine: @machine, exit_code: exit_code)
+
+          triggers.select do |trigger|
+            begin
+              if trigger.only_if && !TriggerCondition.parse(trigger.only_if).evaluate(context)
+                @logger.debug("Trigger #{trigger.id} skipped, only_if condition is false: #{trigger.only_if}")
+                next false
+              end
+
+              if trigger.unless && TriggerCondition.parse(trigger.unless).evaluate(context)
+                @logger.debug("Trigger #{trigger.id} skipped, unless condition is true: #{trigger.unless}")
+                next false
+              end
+
+              true
+            rescue Errors::VagrantError => e
+              @ui.error(e.message)
+              raise e if trigger.on_error == :halt
+
+              @logger.debug("Trigger condition encountered an error. Continuing on anyway...")
+              @ui.warn(I18n.t("vagrant.trigger.on_error_continue"))
+              false
+            end
+          end
+        end
+
         # Execute all triggers in the given array
         #
         # @param [Array] triggers An array of triggers to be fired
@@ -212,7 +251,7 @@ module Vagrant
             end
 
             if trigger.ruby_block
-              execute_ruby(trigger.ruby_block)
+              execute_ruby(trigger.ruby_block, trigger.on_error)
             end
           end
         end
@@ -278,7 +317,8 @@ module Vagrant
             end
             if !exit_codes.include?(result.exit_code)
               raise Errors::TriggersBadExitCodes,
-                code: result.exit_code
+                code: result.exit_code,
+                output: "#{result.stdout}#{result.stderr}"
             end
           rescue => e
             @ui.error(I18n.t("vagrant.errors.triggers_run_fail"))
@@ -307,33 +347,37 @@ module Vagrant
               @ui.warn(I18n.t("vagrant.trigger.on_error_continue"))
               return
             end
-          elsif @machine.state.id != :running
-            if on_error == :halt
-              raise Errors::TriggersGuestNotRunning,
-                machine_name: @machine.name,
-                state: @machine.state.id
-            else
-              @machine.ui.error(I18n.t("vagrant.errors.triggers_guest_not_running",
-                                        machine_name: @machine.name,
-                                        state: @machine.state.id))
-              @machine.ui.warn(I18n.t("vagrant.trigger.on_error_continue"))
-              return
-            end
+          elsif !@machine.communicate.ready?
+            # The script can only be run when the guest can be reached
+            # with its communicator, so it is skipped otherwise.
+            @logger.debug("Guest is not reachable, skipping remote trigger script")
+            @machine.ui.warn(I18n.t("vagrant.trigger.run_remote_skip",
+                                    machine_name: @machine.name,
+                                    state: @machine.state.id))
+            return
           end
 
           prov = VagrantPlugins::Shell::Provisioner.new(@machine, config)
+          prov.error_check = false
 
           begin
             prov.provision
+            if !exit_codes.include?(prov.exit_code)
+              @logger.debug("Remote trigger script output:\n#{prov.output}")
+              raise Errors::TriggersBadExitCodes,
+                code: prov.exit_code,
+                output: prov.output
+            end
           rescue => e
             @machine.ui.error(I18n.t("vagrant.errors.triggers_run_fail"))
+            @machine.ui.error(e.message)
 
             if on_error == :halt
               @logger.debug("Trigger run encountered an error. Halting on error...")
               raise e
             else
               @logger.debug("Trigger run encountered an error. Continuing on anyway...")
-              @machine.ui.error(e.message)
+              @machine.ui.warn(I18n.t("vagrant.trigger.on_error_continue"))
             end
           end
         end
@@ -358,8 +402,16 @@ module Vagrant
         # Calls the given ruby block for execution
         #
         # @param [Proc] ruby_block
-        def execute_ruby(ruby_block)
+        # @param [Symbol] on_error :halt or :continue
+        def execute_ruby(ruby_block, on_error=:halt)
           ruby_block.call(@env, @machine)
+        rescue => e
+          raise e if on_error == :halt
+
+          @ui.error(I18n.t("vagrant.errors.triggers_run_fail"))
+          @ui.error(e.message)
+          @logger.debug("Trigger ruby block encountered an error. Continuing on anyway...")
+          @ui.warn(I18n.t("vagrant.trigger.on_error_continue"))
         end
       end
     end
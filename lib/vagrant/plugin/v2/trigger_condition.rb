@@ -0,0 +1,299 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require "strscan"
+
+module Vagrant
+  module Plugin
+    module V2
+      # This parses the `only_if` and `unless` conditions of a trigger into
+      # a tree of predicates which is evaluated when the trigger fires.
+      #
+      # Conditions compare variables with literal values, and can be
+      # combined with `&&`, `||`, `!` and parentheses:
+      #
+      #     state == :running && env.DEPLOY == "1"
+      #     exit_code != 0 || !env.CI
+      #
+      # The available variables are:
+      #
+      #   * `state` - The state of the machine, or nil if there is no machine
+      #   * `exit_code` - The exit code of the command, for after triggers
+      #     of commands, otherwise nil
+      #   * `env.NAME` - The value of the environment variable NAME
+      #
+      # Literals are integers, quoted strings, symbols, regular expressions,
+      # `true`, `false` and `nil`. Values are compared with `==`, `!=`, `<`,
+      # `<=`, `>`, `>=`, `=~` and `!~`.
+      class TriggerCondition
+        # Values available to the condition when it is evaluated
+        class Context
+          # @return [Integer, nil] exit code of the command
+          attr_reader :exit_code
+
+          # @param [Vagrant::Machine] machine Machine triggers are fired for
+          # @param [Integer] exit_code Exit code of the command
+          # @param [Hash] env Environment variables
+          def initialize(machine: nil, exit_code: nil, env: ENV)
+            @machine = machine
+            @exit_code = exit_code
+            @env = env
+          end
+
+          # The machine state is only read when it is used since reading
+          # it may require calling out to the provider.
+          #
+          # @return [String, nil] state of the machine
+          def state
+            return if !@machine
+
+            @state ||= @machine.state.id.to_s
+          end
+
+          # @param [String] name Name of the environment variable
+          # @return [String, nil]
+          def env(name)
+            @env[name]
+          end
+        end
+
+        # Node combining two conditions with `&&` or `||`
+        Logical = Struct.new(:op, :left, :right) do
+          def evaluate(context)
+            if op == "&&"
+              left.evaluate(context) && right.evaluate(context)
+            else
+              left.evaluate(context) || right.evaluate(context)
+            end
+          end
+        end
+
+        # Node negating a condition
+        Negate = Struct.new(:expr) do
+          def evaluate(context)
+            !expr.evaluate(context)
+          end
+        end
+
+        # Node comparing two values
+        Comparison = Struct.new(:op, :left, :right) do
+          def evaluate(context)
+            TriggerCondition.compare(op, left.value(context), right.value(context))
+          end
+        end
+
+        # Node for a single value, which is true unless it is nil, false,
+        # or an empty string
+        Truthy = Struct.new(:operand) do
+          def evaluate(context)
+            value = operand.value(context)
+            !(value.nil? || value == false || value == "")
+          end
+        end
+
+        # Operand for a variable
+        Variable = Struct.new(:name, :key) do
+          def value(context)
+            name == "env" ? context.env(key) : context.send(name)
+          end
+        end
+
+        # Operand for a literal value
+        Literal = Struct.new(:literal) do
+          def value(_context)
+            literal
+          end
+        end
+
+        # Names of the variables which can be used in a condition
+        VARIABLES = ["exit_code", "state"].freeze
+
+        # Operators comparing two values
+        COMPARISONS = ["==", "!=", "<=", ">=", "<", ">", "=~", "!~"].freeze
+
+        TOKENS = {
+          space: /\s+/,
+          op: /&&|\|\||==|!=|=~|!~|<=|>=|<|>|!|\(|\)/,
+          integer: /-?\d+/,
+          string: /"(?:[^"\\]|\\.)*"|'(?:[^'\\]|\\.)*'/,
+          symbol: /:[A-Za-z_]\w*/,
+          regexp: %r{/(?:[^/\\]|\\.)*/},
+          ident: /[A-Za-z_]\w*(?:\.[A-Za-z_]\w*)?/,
+        }.freeze
+
+        # Parse a condition
+        #
+        # @param [String] source The condition
+        # @return [#evaluate] the root node of the condition
+        def self.parse(source)
+          new(source).parse
+        end
+
+        # Compare two values. Integers are compared with strings which
+        # contain integers by value, since environment variables are
+        # always strings, and symbols are compared as strings.
+        #
+        # @param [String] op Comparison operator
+        # @return [Boolean]
+        def self.compare(op, left, right)
+          left = left.to_s if left.is_a?(Symbol)
+          right = right.to_s if right.is_a?(Symbol)
+
+          case op
+          when "=~", "!~"
+            return op == "!~" if left.nil?
+
+            pattern = right.is_a?(Regexp) ? right : Regexp.new(Regexp.escape(right.to_s))
+            pattern.match?(left.to_s) == (op == "=~")
+          when "==", "!="
+            equal =
+              if left.nil? || right.nil?
+                left.nil? && right.nil?
+              elsif (values = coerce(left, right))
+                values[0] == values[1]
+              else
+                left.to_s == right.to_s
+              end
+            equal == (op == "==")
+          else
+            values = coerce(left, right)
+            return false if values.nil?
+
+            values[0].send(op, values[1])
+          end
+        end
+
+        # @return [Array(Integer, Integer), nil] the values as integers, or
+        #   nil if they can not both be integers
+        def self.coerce(left, right)
+          values = [left, right].map do |v|
+            next v if v.is_a?(Integer)
+            next v.to_i if v.is_a?(String) && v.strip.match?(/\A-?\d+\z/)
+          end
+          return if values.include?(nil) || ![left, right].any? { |v| v.is_a?(Integer) }
+
+          values
+        end
+
+        # @param [String] source The condition
+        def initialize(source)
+          @source = source.to_s
+        end
+
+        # @return [#evaluate] the root node of the condition
+        def parse
+          @tokens = tokenize
+          @pos = 0
+          node = parse_or
+          fail_parse("unexpected '#{peek[1]}'") if peek
+
+          node
+        end
+
+        protected
+
+        def tokenize
+          scanner = StringScanner.new(@source)
+          tokens = []
+          until scanner.eos?
+            type, _ = TOKENS.detect { |_, pattern| scanner.scan(pattern) }
+            fail_parse("unexpected '#{scanner.peek(1)}'") if type.nil?
+            tokens << [type, scanner.matched] if type != :space
+          end
+          fail_parse("the condition is empty") if tokens.empty?
+
+          tokens
+        end
+
+        def peek
+          @tokens[@pos]
+        end
+
+        def next_token
+          token = peek
+          fail_parse("unexpected end of condition") if token.nil?
+          @pos += 1
+          token
+        end
+
+        def accept(op)
+          return false if peek != [:op, op]
+
+          @pos += 1
+          true
+        end
+
+        def parse_or
+          node = parse_and
+          node = Logical.new("||", node, parse_and) while accept("||")
+          node
+        end
+
+        def parse_and
+          node = parse_not
+          node = Logical.new("&&", node, parse_not) while accept("&&")
+          node
+        end
+
+        def parse_not
+          return Negate.new(parse_not) if accept("!")
+          if accept("(")
+            node = parse_or
+            fail_parse("missing ')'") if !accept(")")
+            return node
+          end
+
+          left = parse_operand
+          token = peek
+          if token && token[0] == :op && COMPARISONS.include?(token[1])
+            @pos += 1
+            return Comparison.new(token[1], left, parse_operand)
+          end
+
+          Truthy.new(left)
+        end
+
+        def parse_operand
+          type, text = next_token
+          case type
+          when :integer
+            Literal.new(text.to_i)
+          when :string
+            Literal.new(text[1...-1].gsub(/\\(.)/, '\1'))
+          when :symbol
+            Literal.new(text[1..-1])
+          when :regexp
+            Literal.new(Regexp.new(text[1...-1]))
+          when :ident
+            parse_identifier(text)
+          else
+            fail_parse("unexpected '#{text}'")
+          end
+        rescue RegexpError => e
+          fail_parse(e.message)
+        end
+
+        def parse_identifier(text)
+          case text
+          when "true" then Literal.new(true)
+          when "false" then Literal.new(false)
+          when "nil" then Literal.new(nil)
+          else
+            name, key = text.split(".", 2)
+            if (name == "env" && key) || (VARIABLES.include?(name) && !key)
+              Variable.new(name, key)
+            else
+              fail_parse("unknown variable '#{text}'")
+            end
+          end
+        end
+
+        def fail_parse(message)
+          raise Errors::TriggersConditionInvalid,
+            condition: @source,
+            error: message
+        end
+      end
+    end
+  end
+end
@@ -0,0 +1,216 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require "json"
+require "securerandom"
+
+require "log4r"
+
+module Vagrant
+  # This stores provider specific state of a machine, such as the IDs
+  # of resources created for the machine, in the machine data directory.
+  # Providers should use this instead of writing their own files so the
+  # state can be inspected with `vagrant debug provider-state`.
+  #
+  # Values are stored with their type and must be one of the types in
+  # {TYPES}. Reading a value can require it to be of a specific type so
+  # a provider does not need to validate values it reads back.
+  #
+  # The state is written to a temporary file which is then renamed so
+  # an interrupted write can not corrupt the stored state, and all access
+  # is done while holding a lock on the state so concurrent commands for
+  # the same machine are serialized.
+  class ProviderState
+    # Name of the file within the data directory storing the state
+    STATE_FILE = "provider_state.json".freeze
+
+    # Name of the lock file within the data directory
+    LOCK_FILE = "provider_state.lock".freeze
+
+    # Supported value types, and the classes of values of each type
+    TYPES = {
+      "array" => [Array],
+      "boolean" => [TrueClass, FalseClass],
+      "float" => [Float],
+      "hash" => [Hash],
+      "integer" => [Integer],
+      "null" => [NilClass],
+      "string" => [String],
+    }.freeze
+
+    # @return [Pathname] path to the state file
+    attr_reader :path
+
+    # @param [Pathname] data_dir Machine data directory
+    def initialize(data_dir)
+      @logger = Log4r::Logger.new("vagrant::provider_state")
+      @data_dir = Pathname.new(data_dir.to_s)
+      @path = @data_dir.join(STATE_FILE)
+      @lock_path = @data_dir.join(LOCK_FILE)
+    end
+
+    # Get a value
+    #
+    # @param [String, Symbol] key
+    # @param [String, Symbol] type If given, the value must be of this type
+    # @param [Object] default Value returned if the key is not set
+    # @return [Object]
+    def get(key, type: nil, default: nil)
+      entry = with_lock(File::LOCK_SH) { read }[key.to_s]
+      return default if entry.nil?
+
+      entry_value(key, entry, type)
+    end
+
+    # Set a value
+    #
+    # @param [String, Symbol] key
+    # @param [Object] value
+    # @return [Object] the value
+    def set(key, value)
+      type = type_of(key, value)
+
+      with_lock(File::LOCK_EX) do
+        state = read
+        state[key.to_s] = { "type" => type, "value" => value }
+        write(state)
+      end
+
+      value
+    end
+
+    # Update a value. The lock is held while the value is read, given
+    # to the block, and the value returned by the block is stored, so
+    # the value can not be changed by another command in between.
+    #
+    #     provider_state.update("attached", default: []) { |ids| ids + [id] }
+    #
+    # @param [String, Symbol] key
+    # @param [String, Symbol] type If given, the current value must be of
+    #   this type
+    # @param [Object] default Value given to the block if the key is not set
+    # @yieldparam [Object] value Current value
+    # @yieldreturn [Object] New value
+    # @return [Object] the new value
+    def update(key, type: nil, default: nil)
+      with_lock(File::LOCK_EX) do
+        state = read
+        entry = state[key.to_s]
+        value = yield(entry.nil? ? default : entry_value(key, entry, type))
+        state[key.to_s] = { "type" => type_of(key, value), "value" => value }
+        write(state)
+        value
+      end
+    end
+
+    # Delete a value
+    #
+    # @param [String, Symbol] key
+    # @return [Object] the deleted value
+    def delete(key)
+      with_lock(File::LOCK_EX) do
+        state = read
+        entry = state.delete(key.to_s)
+        write(state) if entry
+        entry && entry["value"]
+      end
+    end
+
+    # @return [Boolean] value is set for the key
+    def key?(key)
+      with_lock(File::LOCK_SH) { read }.key?(key.to_s)
+    end
+
+    # @return [Array<String>] keys with values set
+    def keys
+      with_lock(File::LOCK_SH) { read }.keys.sort
+    end
+
+    # All the stored values, with their types
+    #
+    # @return [Hash<String, Hash>] type and value for each key
+    def to_h
+      with_lock(File::LOCK_SH) { read }
+    end
+
+    protected
+
+    # @return [Object] value of the entry
+    # @raise [Errors::ProviderStateTypeMismatch] if a type is given and
+    #   the entry is of a different type
+    def entry_value(key, entry, type)
+      if type && entry["type"] != type.to_s
+        raise Errors::ProviderStateTypeMismatch,
+          key: key.to_s,
+          expected: type.to_s,
+          actual: entry["type"]
+      end
+
+      entry["value"]
+    end
+
+    # @return [String] type name of the value
+    def type_of(key, value)
+      type, _ = TYPES.detect { |_, classes| classes.any? { |c| value.is_a?(c) } }
+      if type.nil? || !serializable?(value)
+        raise Errors::ProviderStateInvalidValue,
+          key: key.to_s,
+          type: value.class.to_s
+      end
+
+      type
+    end
+
+    # @return [Boolean] value can be stored
+    def serializable?(value)
+      case value
+      when Hash
+        value.all? { |k, v| k.is_a?(String) && serializable?(v) }
+      when Array
+        value.all? { |v| serializable?(v) }
+      when Float
+        value.finite?
+      else
+        TYPES.values.flatten.any? { |c| value.is_a?(c) }
+      end
+    end
+
+    # Run the block while holding the state lock
+    def with_lock(mode)
+      @data_dir.mkpath
+      File.open(@lock_path, File::RDWR | File::CREAT, 0644) do |lock|
+        lock.flock(mode)
+        yield
+      end
+    end
+
+    # @return [Hash] stored state
+    def read
+      return {} if !path.file?
+
+      JSON.parse(path.read)
+    rescue JSON::ParserError => e
+      raise Errors::ProviderStateCorrupt,
+        path: path.to_s,
+        error: e.message
+    end
+
+    # Atomically replace the stored state
+    #
+    # @param [Hash] state
+    def write(state)
+      tmp = @data_dir.join(".#{STATE_FILE}.#{SecureRandom.hex(4)}")
+      begin
+        File.open(tmp, "w", 0600) do |f|
+          f.write(JSON.dump(state))
+          f.flush
+          f.fsync
+        end
+        File.rename(tmp, path)
+        @logger.debug("stored provider state: #{path}")
+      ensure
+        tmp.delete if tmp.exist?
+      end
+    end
+  end
+end
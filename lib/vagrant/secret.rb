@@ -0,0 +1,163 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require "log4r"
+
+module Vagrant
+  # Secrets keep sensitive values, such as credentials, out of the
+  # Vagrantfile. A secret is referenced in the Vagrantfile with
+  # {Vagrant.secret} using the format "PROVIDER:PATH#FIELD":
+  #
+  #     config.vm.provider "docker" do |d|
+  #       d.password = Vagrant.secret("vault:secret/data/registry#password")
+  #     end
+  #
+  # References are resolved by the secret provider plugin registered
+  # with the name PROVIDER once the configuration is merged. Resolved
+  # values are registered as sensitive so they are removed from the
+  # output and logs.
+  module Secret
+    # Placeholder for a secret within the configuration until it is
+    # resolved
+    class Reference
+      # @return [String] the reference
+      attr_reader :reference
+
+      # @return [Symbol] name of the secret provider
+      attr_reader :provider
+
+      # @return [String] path of the secret
+      attr_reader :path
+
+      # @return [String, nil] field of the secret
+      attr_reader :field
+
+      # @param [String] reference Reference in the format "PROVIDER:PATH#FIELD"
+      def initialize(reference)
+        @reference = reference.to_s
+        provider, rest = @reference.split(":", 2)
+        @path, @field = rest.to_s.split("#", 2)
+        if provider.to_s.empty? || @path.to_s.empty?
+          raise Errors::SecretReferenceInvalid, reference: @reference
+        end
+
+        @provider = provider.to_sym
+        @field = nil if @field == ""
+      end
+
+      def ==(other)
+        other.is_a?(Reference) && other.reference == reference
+      end
+      alias_method :eql?, :==
+
+      def hash
+        reference.hash
+      end
+
+      # The value is never included, since it is not known until the
+      # reference is resolved.
+      def to_s
+        "secret(#{reference})"
+      end
+      alias_method :inspect, :to_s
+    end
+
+    @lock = Mutex.new
+    @resolved = {}
+
+    # Resolve a reference to the value of the secret. Values are cached
+    # so each secret is only read once.
+    #
+    # @param [Reference] reference
+    # @return [String] value of the secret
+    def self.resolve(reference)
+      @lock.synchronize do
+        @resolved[reference.reference] ||= read(reference)
+      end
+    end
+
+    # Replace the references within the configuration with the values of
+    # the secrets. Configuration objects, arrays and hashes are updated in
+    # place.
+    #
+    # @param [Object] object Configuration to resolve references within
+    # @return [Object] the object, or the value if it is a reference
+    def self.resolve_all(object, seen={})
+      return resolve(object) if object.is_a?(Reference)
+      return object if !walk?(object) || seen.key?(object.object_id)
+
+      seen[object.object_id] = true
+      case object
+      when Array
+        object.each_with_index do |value, i|
+          resolved = resolve_all(value, seen)
+          object[i] = resolved if !resolved.equal?(value)
+        end
+      when Hash
+        object.each do |key, value|
+          resolved = resolve_all(value, seen)
+          object[key] = resolved if !resolved.equal?(value)
+        end
+      else
+        object.instance_variables.each do |name|
+          value = object.instance_variable_get(name)
+          resolved = resolve_all(value, seen)
+          object.instance_variable_set(name, resolved) if !resolved.equal?(value)
+        end
+      end
+
+      object
+    end
+
+    # @private
+    # Reset the cached values. This is not considered a public API and
+    # should only be used for testing.
+    def self.reset!
+      @lock.synchronize { @resolved.clear }
+    end
+
+    # @return [Boolean] object may contain references
+    def self.walk?(object)
+      return false if object.frozen?
+
+      object.is_a?(Array) || object.is_a?(Hash) ||
+        object.is_a?(Plugin::V2::Config) || object.is_a?(Config::V2::Root)
+    end
+    private_class_method :walk?
+
+    # @param [Reference] reference
+    # @return [String] value of the secret
+    def self.read(reference)
+      logger = Log4r::Logger.new("vagrant::secret")
+      providers = Vagrant.plugin("2").manager.secret_providers
+      klass = providers.get(reference.provider)
+      if !klass
+        raise Errors::SecretProviderNotFound,
+          reference: reference.reference,
+          provider: reference.provider.to_s,
+          providers: providers.keys.map(&:to_s).sort.join(", ")
+      end
+
+      logger.info("Resolving secret: #{reference.reference}")
+      begin
+        value = klass.new.resolve(reference.path, reference.field)
+      rescue Errors::VagrantError
+        raise
+      rescue StandardError => e
+        raise Errors::SecretProviderFailed,
+          reference: reference.reference,
+          error: e.message
+      end
+
+      if value.nil?
+        raise Errors::SecretNotFound,
+          reference: reference.reference
+      end
+
+      value = value.to_s
+      Util::CredentialScrubber.sensitive(value)
+      value
+    end
+    private_class_method :read
+  end
+end
@@ -130,7 +130,7 @@ module Vagrant
 
         # Prepare the data by replacing characters that aren't outputted
         data.each_index do |i|
-          data[i] = data[i].to_s.dup
+          data[i] = Util::CredentialScrubber.desensitize(data[i])
           data[i].gsub!(",", "%!(VAGRANT_COMMA)")
           data[i].gsub!("\n", "\\n")
           data[i].gsub!("\r", "\\r")
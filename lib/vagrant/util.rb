@@ -7,6 +7,7 @@ module Vagrant
     autoload :Busy,                      'vagrant/util/busy'
     autoload :Caps,                      'vagrant/util/caps'
     autoload :CheckpointClient,          'vagrant/util/checkpoint_client'
+    autoload :ChunkedUpload,             'vagrant/util/chunked_upload'
     autoload :CommandDeprecation,        'vagrant/util/command_deprecation'
     autoload :Counter,                   'vagrant/util/counter'
     autoload :CredentialScrubber,        'vagrant/util/credential_scrubber'
@@ -22,6 +23,7 @@ module Vagrant
     autoload :GuestHosts,                'vagrant/util/guest_hosts'
     autoload :GuestInspection,           'vagrant/util/guest_inspection'
     autoload :GuestNetworks,             'vagrant/util/guest_networks'
+    autoload :GuestTools,                'vagrant/util/guest_tools'
     autoload :HashWithIndifferentAccess, 'vagrant/util/hash_with_indifferent_access'
     autoload :HCLogFormatter,            'vagrant/util/logging_formatter'
     autoload :HCLogOutputter,            'vagrant/util/logging_formatter'
@@ -35,6 +37,7 @@ module Vagrant
     autoload :Keypair,                   'vagrant/util/keypair'
     autoload :LineBuffer,                'vagrant/util/line_buffer'
     autoload :LineEndingHelpers,         'vagrant/util/line_ending_helpers'
+    autoload :Logging,                   'vagrant/util/logging'
     autoload :LoggingFormatter,          'vagrant/util/logging_formatter'
     autoload :MapCommandOptions,         'vagrant/util/map_command_options'
     autoload :Mime,                      'vagrant/util/mime'
@@ -56,6 +59,7 @@ module Vagrant
     autoload :StringBlockEditor,         'vagrant/util/string_block_editor'
     autoload :Subprocess,                'vagrant/util/subprocess'
     autoload :TemplateRenderer,          'vagrant/util/template_renderer'
+    autoload :Tracing,                   'vagrant/util/tracing'
     autoload :Uploader,                  'vagrant/util/uploader'
     autoload :Which,                     'vagrant/util/which'
     autoload :WindowsPath,               'vagrant/util/windows_path'
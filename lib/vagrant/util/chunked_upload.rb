@@ -0,0 +1,227 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require "digest"
+require "shellwords"
+require "tempfile"
+
+require "log4r"
+
+module Vagrant
+  module Util
+    # This uploads large files to a machine in chunks, so the memory used
+    # does not depend on the size of the file, and an interrupted upload
+    # continues with the data already uploaded.
+    #
+    # The chunks are appended to a partial file next to the destination
+    # with the primitives of communicators which support chunked uploads
+    # (see {Plugin::V2::Communicator#chunked_upload?}), which are
+    # {Plugin::V2::Communicator#upload_chunk},
+    # {Plugin::V2::Communicator#file_size},
+    # {Plugin::V2::Communicator#file_checksum} and
+    # {Plugin::V2::Communicator#move_file}. The name of the partial file
+    # depends on the size and modification time of the source, so an
+    # upload is only resumed if the source has not changed. Once all the
+    # chunks are uploaded, the size and optionally the checksum of the
+    # partial file are verified before it is moved to the destination.
+    class ChunkedUpload
+      # Default size of the chunks, in bytes
+      DEFAULT_CHUNK_SIZE = 64 * 1024 * 1024
+
+      # Supported types of checksums
+      CHECKSUM_TYPES = {
+        md5: Digest::MD5,
+        sha1: Digest::SHA1,
+        sha256: Digest::SHA256,
+        sha384: Digest::SHA384,
+        sha512: Digest::SHA512,
+      }.freeze
+
+      # Commands implementing the primitives with a POSIX shell
+      module PosixCommands
+        # @return [String] command printing the size of the file
+        def self.file_size(path)
+          path = Shellwords.escape(path)
+          "if [ -f #{path} ]; then wc -c < #{path}; fi"
+        end
+
+        # @return [String] command writing the chunk to the file at the
+        #   offset and removing the chunk
+        def self.write_chunk(chunk, path, offset)
+          redirect = offset == 0 ? ">" : ">>"
+          chunk = Shellwords.escape(chunk)
+          "cat #{chunk} #{redirect} #{Shellwords.escape(path)} && rm -f #{chunk}"
+        end
+
+        # @return [String] command printing the checksum of the file
+        def self.file_checksum(path, type)
+          path = Shellwords.escape(path)
+          if type.to_sym == :md5
+            "md5sum #{path} 2>/dev/null || md5 -q #{path}"
+          else
+            bits = type.to_s.sub("sha", "")
+            "sha#{bits}sum #{path} 2>/dev/null || shasum -a #{bits} #{path}"
+          end
+        end
+
+        # @return [String] command moving the file
+        def self.move_file(from, to)
+          "mv -f #{Shellwords.escape(from)} #{Shellwords.escape(to)}"
+        end
+      end
+
+      # Commands implementing the primitives with PowerShell
+      module PowerShellCommands
+        # @return [String] command printing the size of the file
+        def self.file_size(path)
+          path = quote(path)
+          "if (Test-Path -LiteralPath #{path} -PathType Leaf) { (Get-Item -LiteralPath #{path}).Length }"
+        end
+
+        # @return [String] command writing the chunk to the file at the
+        #   offset and removing the chunk
+        def self.write_chunk(chunk, path, offset)
+          chunk = quote(chunk)
+          <<-EOH.gsub(/^ {12}/, "")
+            $file = [System.IO.File]::Open(#{quote(path)}, 'OpenOrCreate', 'Write')
+            try {
+              $file.SetLength(#{offset.to_i})
+              $file.Seek(0, 'End') | Out-Null
+              $chunk = [System.IO.File]::OpenRead(#{chunk})
+              try { $chunk.CopyTo($file) } finally { $chunk.Close() }
+            } finally {
+              $file.Close()
+            }
+            Remove-Item -LiteralPath #{chunk} -Force
+          EOH
+        end
+
+        # @return [String] command printing the checksum of the file
+        def self.file_checksum(path, type)
+          "(Get-FileHash -LiteralPath #{quote(path)} -Algorithm #{type.to_s.upcase}).Hash"
+        end
+
+        # @return [String] command moving the file
+        def self.move_file(from, to)
+          "Move-Item -LiteralPath #{quote(from)} -Destination #{quote(to)} -Force"
+        end
+
+        # @return [String] the string quoted for PowerShell
+        def self.quote(value)
+          "'#{value.to_s.gsub("'", "''")}'"
+        end
+      end
+
+      # @param [Vagrant::Plugin::V2::Communicator] communicator
+      # @param [Integer] chunk_size Size of the chunks, in bytes
+      # @param [Symbol, nil] checksum_type Type of checksum to verify the
+      #   upload with, in addition to its size
+      def initialize(communicator, chunk_size: DEFAULT_CHUNK_SIZE, checksum_type: nil)
+        @communicator = communicator
+        @chunk_size = chunk_size
+        @checksum_type = checksum_type && checksum_type.to_sym
+        @logger = Log4r::Logger.new("vagrant::util::chunked_upload")
+      end
+
+      # @return [Boolean] uploads are verified with a checksum, so files
+      #   which are not uploaded in chunks are verified too
+      def checksum?
+        !@checksum_type.nil?
+      end
+
+      # @param [String] path Local path
+      # @return [Boolean] the file is uploaded in chunks
+      def chunked?(path)
+        File.file?(path) && File.size(path) > @chunk_size
+      end
+
+      # Upload the file in chunks. If the upload can not be verified after
+      # resuming it, the whole file is uploaded again.
+      #
+      # @param [String] from Local path of the file
+      # @param [String] to Remote path of the file
+      # @return [Boolean] false if the communicator does not support
+      #   uploading chunks, in which case nothing is uploaded
+      def upload(from, to)
+        return false if !@communicator.chunked_upload?
+
+        size = File.size(from)
+        partial = partial_path(from, to)
+        offset = @communicator.file_size(partial).to_i
+        offset = 0 if offset > size
+        @logger.info("Resuming upload of #{from} at #{offset} bytes") if offset > 0
+
+        begin
+          upload_chunks(from, partial, offset, size)
+          verify!(from, partial)
+        rescue Errors::UploadVerificationFailed
+          raise if offset == 0
+
+          @logger.warn("Resumed upload of #{from} failed verification, uploading it again")
+          offset = 0
+          retry
+        end
+
+        @communicator.move_file(partial, to)
+        true
+      end
+
+      # Verify the size, and the checksum if a type was given, of the
+      # remote file are the same as the local file. Nothing is verified
+      # if the communicator does not support chunked uploads.
+      #
+      # @param [String] from Local path of the file
+      # @param [String] to Remote path of the file
+      # @raise [Errors::UploadVerificationFailed]
+      def verify!(from, to)
+        return if !@communicator.chunked_upload?
+
+        size = File.size(from)
+        remote_size = @communicator.file_size(to)
+        if remote_size != size
+          raise Errors::UploadVerificationFailed,
+            from: from, to: to, expected: "#{size} bytes", actual: "#{remote_size.to_i} bytes"
+        end
+
+        return if !@checksum_type
+
+        expected = CHECKSUM_TYPES.fetch(@checksum_type).file(from).hexdigest
+        actual = @communicator.file_checksum(to, @checksum_type).to_s.downcase
+        if actual != expected
+          raise Errors::UploadVerificationFailed,
+            from: from, to: to, expected: "#{@checksum_type} #{expected}",
+            actual: "#{@checksum_type} #{actual}"
+        end
+      end
+
+      protected
+
+      # Upload the chunks of the file after the offset
+      def upload_chunks(from, partial, offset, size)
+        File.open(from, "rb") do |file|
+          while offset < size
+            length = [@chunk_size, size - offset].min
+            @logger.debug("Uploading #{length} bytes of #{from} at #{offset}")
+
+            # Only the chunk being uploaded is copied, to a temporary file
+            Tempfile.create("vagrant-upload-chunk") do |chunk|
+              chunk.binmode
+              IO.copy_stream(file, chunk, length, offset)
+              chunk.close
+              @communicator.upload_chunk(chunk.path, partial, offset)
+            end
+
+            offset += length
+          end
+        end
+      end
+
+      # @return [String] remote path of the partial file
+      def partial_path(from, to)
+        stat = File.stat(from)
+        id = Digest::SHA1.hexdigest("#{File.expand_path(from)}:#{stat.size}:#{stat.mtime.to_i}")
+        "#{to}.vagrant-partial-#{id[0, 8]}"
+      end
+    end
+  end
+end
@@ -2,6 +2,7 @@
 # SPDX-License-Identifier: BUSL-1.1
 
 require "cgi"
+require "json"
 require "uri"
 
 require "log4r"
@@ -26,6 +27,18 @@ module Vagrant
       #     Vagrant/1.7.4 (+https://www.vagrantup.com; ruby2.1.0)
       USER_AGENT = "Vagrant/#{VERSION} (+https://www.vagrantup.com; #{RUBY_ENGINE}#{RUBY_VERSION}) #{ENV['VAGRANT_USER_AGENT_PROVISIONAL_STRING']}".strip.freeze
 
+      # Suffix of the file next to the destination which stores the
+      # details of the source used to verify a partial download can be
+      # resumed.
+      RESUME_SUFFIX = ".resume".freeze
+
+      # cURL exit codes of transfers which were interrupted by the network
+      # and can be resumed.
+      RESUMABLE_EXIT_CODES = [18, 28, 56].freeze
+
+      # Number of times an interrupted transfer is resumed before failing.
+      RESUME_RETRIES = 3
+
       attr_accessor :source
       attr_reader :destination
       attr_accessor :headers
@@ -58,6 +71,7 @@ module Vagrant
         @continue    = options[:continue]
         @headers     = Array(options[:headers])
         @insecure    = options[:insecure]
+        @limit_rate  = options[:limit_rate]
         @ui          = options[:ui]
         @client_cert = options[:client_cert]
         @location_trusted = options[:location_trusted]
@@ -103,7 +117,10 @@ module Vagrant
         @logger.info("  -- Source: #{@source}")
         @logger.info("  -- Destination: #{@destination}")
 
+        prepare_resume! if @continue
+
         retried = false
+        resumes = 0
         begin
           # Get the command line args and the subprocess opts based
           # on our downloader settings.
@@ -117,11 +134,20 @@ module Vagrant
           # Go!
           execute_curl(options, subprocess_options, &data_proc)
         rescue Errors::DownloaderError => e
+          @logger.error("Exit code: #{e.extra_data[:code]}")
+
+          # If the transfer was interrupted, resume it from where it
+          # stopped.
+          if @continue && resumes < RESUME_RETRIES &&
+              RESUMABLE_EXIT_CODES.include?(e.extra_data[:code].to_i)
+            resumes += 1
+            @logger.warn("Download interrupted. Resuming (attempt #{resumes} of #{RESUME_RETRIES}).")
+            retry
+          end
+
           # If we already retried, raise it.
           raise if retried
 
-          @logger.error("Exit code: #{e.extra_data[:code]}")
-
           # If its any error other than 33, it is an error.
           raise if e.extra_data[:code].to_i != 33
 
@@ -144,6 +170,7 @@ module Vagrant
         end
 
         validate_download!(@source, @destination, @checksums)
+        File.delete(resume_path) if File.exist?(resume_path)
 
         # Everything succeeded
         true
@@ -162,6 +189,94 @@ module Vagrant
 
       protected
 
+      # @return [String] path of the file storing the source details
+      def resume_path
+        "#{@destination}#{RESUME_SUFFIX}"
+      end
+
+      # Checks that a partial download at the destination can be resumed.
+      # A partial download is only resumed if the server accepts range
+      # requests and the ETag, Last-Modified and size of the source are
+      # the same as when the download was started. Otherwise the partial
+      # download is removed so the source is downloaded from scratch.
+      def prepare_resume!
+        uri = URI.parse(@source) rescue nil
+        return if !uri || !uri.scheme.to_s.start_with?("http")
+
+        remote = remote_info
+        if File.file?(@destination) && File.size(@destination) > 0
+          reason = resume_conflict(stored_info, remote, File.size(@destination))
+          if reason
+            @logger.info("Partial download can not be resumed (#{reason}). Downloading from scratch.")
+            File.delete(@destination)
+          else
+            @logger.info("Resuming partial download at #{File.size(@destination)} bytes")
+          end
+        end
+
+        if remote && remote["ranges"] && (remote["etag"] || remote["last_modified"])
+          File.write(resume_path, JSON.dump(remote))
+        else
+          @logger.info("Source can not be verified for resuming. Disabling resume.")
+          File.delete(resume_path) if File.exist?(resume_path)
+          @continue = false
+        end
+      end
+
+      # @param [Hash, nil] stored Source details when the download started
+      # @param [Hash, nil] remote Current source details
+      # @param [Integer] size Size of the partial download
+      # @return [String, nil] reason the download can not be resumed
+      def resume_conflict(stored, remote, size)
+        return "source details are unknown" if stored.nil? || remote.nil?
+        return "server does not accept range requests" if !remote["ranges"]
+
+        ["etag", "last_modified", "size"].each do |key|
+          return "source #{key} changed" if stored[key] != remote[key]
+        end
+        return "partial download is larger than the source" if remote["size"] && size > remote["size"]
+
+        nil
+      end
+
+      # @return [Hash, nil] source details stored when the download started
+      def stored_info
+        return if !File.file?(resume_path)
+
+        JSON.parse(File.read(resume_path))
+      rescue JSON::ParserError
+        nil
+      end
+
+      # Requests the headers of the source to get the details used to
+      # verify a partial download can be resumed.
+      #
+      # @return [Hash, nil] ETag, Last-Modified, size and range support of
+      #   the source, or nil if the request failed
+      def remote_info
+        continue, @continue = @continue, false
+        # Redirects each add a set of headers, and the last set is from
+        # the source itself
+        response = head.split(/\r?\n\r?\n/).reject { |r| r.strip.empty? }.last.to_s
+        headers = {}
+        response.each_line do |line|
+          key, value = line.split(":", 2)
+          headers[key.strip.downcase] = value.strip if value
+        end
+
+        {
+          "etag" => headers["etag"],
+          "last_modified" => headers["last-modified"],
+          "size" => headers["content-length"] && headers["content-length"].to_i,
+          "ranges" => headers["accept-ranges"].to_s.downcase.include?("bytes"),
+        }
+      rescue Errors::DownloaderError => e
+        @logger.warn("Failed to request source headers: #{e}")
+        nil
+      ensure
+        @continue = continue
+      end
+
       # Apply any checksum validations based on provided
       # options content
       #
@@ -249,6 +364,7 @@ module Vagrant
         options += ["--capath", @ca_path] if @ca_path
         options += ["--continue-at", "-"] if @continue
         options << "--insecure" if @insecure
+        options << "--limit-rate" << @limit_rate.to_s if @limit_rate
         options << "--cert" << @client_cert if @client_cert
         options << "-u" << @auth if @auth
         options << "--location-trusted" if @location_trusted
@@ -15,7 +15,9 @@ module Vagrant
         #
         # @return [Boolean]
         def systemd?(comm)
-          comm.test("ps -o comm= 1 | grep systemd", sudo: true)
+          guest_cached(comm, :systemd) do
+            comm.test("ps -o comm= 1 | grep systemd", sudo: true)
+          end
         end
 
         # systemd-networkd.service is in use
@@ -112,6 +114,20 @@ module Vagrant
             !comm.test("nmcli -t d show #{device_name} | grep unmanaged")
         end
 
+        protected
+
+        # Cache the result of the block in the guest cache of the machine
+        # the communicator is connected to, if it is known
+        #
+        # @param [Vagrant::Plugin::V2::Communicator] comm Guest communicator
+        # @param [Symbol] key Name of the result
+        # @return [Object]
+        def guest_cached(comm, key, &block)
+          machine = comm.machine if comm.respond_to?(:machine)
+          return yield if !machine.respond_to?(:guest_cache)
+
+          machine.guest_cache.fetch(key, &block)
+        end
       end
     end
   end
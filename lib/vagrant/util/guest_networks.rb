@@ -1,12 +1,117 @@
 # Copyright IBM Corp. 2010, 2025
 # SPDX-License-Identifier: BUSL-1.1
 
+require "ipaddr"
+require "tempfile"
+
+require_relative "guest_inspection"
+
 module Vagrant
   module Util
     # Helper methods for configuring guest networks
     module GuestNetworks
       module Linux
+        include GuestInspection::Linux
+
         NETWORK_MANAGER_DEVICE_DIRECTORY = "/etc/NetworkManager/system-connections".freeze
+        NETWORKD_DIRECTORY = "/etc/systemd/network".freeze
+
+        # Determine the service which manages the network configuration
+        # of the guest. Only services which are active are considered, so
+        # a guest which includes systemd but has its network managed by
+        # NetworkManager will not be configured using systemd-networkd.
+        #
+        # @param [Vagrant::Plugin::V2::Communicator] comm Guest communicator
+        # @return [Symbol, nil] `:network_manager`, `:networkd`, or nil if
+        #   no supported network configurator is active
+        def network_configurator(comm)
+          if systemd_network_manager?(comm) && nmcli?(comm)
+            :network_manager
+          elsif systemd_networkd?(comm)
+            :networkd
+          end
+        end
+
+        def configure_networkd(machine, networks, **opts)
+          comm = machine.communicate
+          networkd_directory = opts.fetch(:networkd_directory, NETWORKD_DIRECTORY)
+
+          interfaces = machine.guest.capability(:network_interfaces)
+          devices = []
+
+          networks.each.with_index do |network, i|
+            device = interfaces[network[:interface]]
+            devices << device
+
+            remote_path = "/tmp/vagrant-network-entry-#{device}-#{Time.now.to_i}-#{i}"
+            final_path = "#{networkd_directory}/50-vagrant-#{device}.network"
+
+            Tempfile.open("vagrant-networkd-configure-networks") do |f|
+              f.binmode
+              f.write(networkd_unit(device, network) + "\n")
+              f.fsync
+              f.close
+              comm.upload(f.path, remote_path)
+            end
+
+            [
+              "mkdir -p '#{networkd_directory}'",
+              "chown root:root '#{remote_path}'",
+              "chmod 0644 '#{remote_path}'",
+              "mv -f '#{remote_path}' '#{final_path}'",
+            ].each do |cmd|
+              comm.sudo(cmd)
+            end
+          end
+
+          # Reload the units and apply them to the devices. Versions of
+          # systemd which do not support reloading require a restart.
+          reconfigure = devices.map { |d| "networkctl reconfigure '#{d}'" }
+          comm.sudo(["networkctl reload", *reconfigure].join(" && ") +
+            " || systemctl restart systemd-networkd.service")
+        end
+
+        # Render the systemd-networkd unit of a network
+        #
+        # @param [String] device Name of the device
+        # @param [Hash] network Network options
+        # @return [String]
+        def networkd_unit(device, network)
+          net_conf = ["[Match]", "Name=#{device}", "[Network]"]
+          if network[:type].to_s == "dhcp"
+            net_conf << "DHCP=yes"
+          else
+            net_conf << "DHCP=no"
+            net_conf << "Address=#{network_address(network)}"
+            net_conf << "Gateway=#{network[:gateway]}" if network[:gateway]
+          end
+          net_conf.join("\n")
+        end
+
+        # Address of a static network including the prefix length of
+        # the netmask, as used by systemd-networkd and netplan
+        #
+        # @param [Hash] network Network options
+        # @return [String]
+        def network_address(network)
+          mask = network[:netmask]
+          begin
+            ipv4 = IPAddr.new(network[:ip].to_s).ipv4?
+          rescue IPAddr::Error => err
+            raise Vagrant::Errors::NetworkAddressInvalid,
+              address: network[:ip],
+              mask: mask,
+              error: err.to_s
+          end
+          if mask && ipv4
+            begin
+              mask = IPAddr.new(mask).to_i.to_s(2).count("1")
+            rescue IPAddr::Error
+              # ignore and use given value
+            end
+          end
+          [network[:ip], mask].compact.join("/")
+        end
 
         def configure_network_manager(machine, networks, **opts)
           comm = machine.communicate
@@ -0,0 +1,78 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+module Vagrant
+  module Util
+    # Helper methods for comparing the version of the guest tools
+    # installed in a guest, such as the VirtualBox Guest Additions, with
+    # the version expected by the provider of the machine.
+    #
+    # Providers implement the `guest_tools_version` provider capability,
+    # which returns the expected version. The installed version is read
+    # with the `guest_tools_installed_version` provider capability when
+    # the provider can inspect the guest itself, and otherwise with the
+    # `guest_tools_version` guest capability, which returns the version of
+    # the tools for the given provider installed in the guest.
+    module GuestTools
+      # Mismatches which are likely to cause synced folders of the
+      # provider to fail to mount
+      SEVERE_MISMATCHES = [:missing, :major].freeze
+
+      # Read the installed and expected guest tools versions
+      #
+      # @param [Vagrant::Machine] machine
+      # @return [Array(String, String), nil] installed and expected
+      #   versions, or nil if the versions can not be compared
+      def self.versions(machine)
+        return if !machine.provider.capability?(:guest_tools_version)
+
+        expected = machine.provider.capability(:guest_tools_version)
+        return if expected.nil?
+
+        if machine.provider.capability?(:guest_tools_installed_version)
+          installed = machine.provider.capability(:guest_tools_installed_version)
+        else
+          return if !machine.guest.capability?(:guest_tools_version)
+
+          installed = machine.guest.capability(:guest_tools_version, machine.provider_name)
+        end
+        [installed, expected]
+      rescue Errors::MachineGuestNotReady
+        nil
+      end
+
+      # Compare the installed and expected versions
+      #
+      # @param [String, nil] installed Installed version
+      # @param [String] expected Expected version
+      # @return [Symbol, nil] `:missing` if no tools are installed,
+      #   `:major` if the major versions are different, `:minor` if the
+      #   minor versions are different, or nil if the versions match
+      def self.mismatch(installed, expected)
+        return :missing if installed.nil?
+
+        installed = segments(installed)
+        expected = segments(expected)
+        return :major if installed[0] != expected[0]
+        return :minor if installed[1] != expected[1]
+
+        nil
+      end
+
+      # @param [Symbol, nil] mismatch Result of {mismatch}
+      # @return [Boolean] mismatch is likely to cause mount failures
+      def self.severe?(mismatch)
+        SEVERE_MISMATCHES.include?(mismatch)
+      end
+
+      # @param [String] version
+      # @return [Array<Integer>] major, minor, and patch segments
+      def self.segments(version)
+        match = /(\d+)(?:\.(\d+))?(?:\.(\d+))?/.match(version.to_s)
+        return [] if !match
+
+        match.captures.map(&:to_i)
+      end
+    end
+  end
+end
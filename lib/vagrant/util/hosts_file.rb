@@ -0,0 +1,190 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require "fileutils"
+require "shellwords"
+require "tempfile"
+
+require "log4r"
+
+require "vagrant/util/string_block_editor"
+require "vagrant/util/subprocess"
+
+module Vagrant
+  module Util
+    # This class manages Vagrant owned blocks of entries within a hosts
+    # file. Each block is identified by a unique ID (generally the
+    # machine index UUID) and is wrapped in VAGRANT-BEGIN and VAGRANT-END
+    # comments so it can be updated or removed without modifying any
+    # other content of the file.
+    #
+    # Modifications are made while holding an exclusive lock on the lock
+    # path so concurrent Vagrant processes do not overwrite each other's
+    # changes.
+    class HostsFile
+      # Implementation of the `hosts_file_update` and `hosts_file_remove`
+      # host capabilities. Host capability classes extend this module and
+      # override {#hosts_file_path} and {#hosts_file_sudo?} as needed.
+      module Capability
+        # Set the hosts file entries for the given ID
+        #
+        # @param [Vagrant::Environment] env
+        # @param [String] id Unique ID of the entries
+        # @param [Array<Array(String, String)>] entries IP and hostname pairs
+        # @return [Boolean] true if the hosts file was modified
+        def hosts_file_update(env, id, entries)
+          hosts_file(env).update(id, entries)
+        end
+
+        # Remove the hosts file entries for the given ID
+        #
+        # @param [Vagrant::Environment] env
+        # @param [String] id Unique ID of the entries
+        # @return [Boolean] true if the hosts file was modified
+        def hosts_file_remove(env, id)
+          hosts_file(env).remove(id)
+        end
+
+        # @return [String] path to the hosts file
+        def hosts_file_path
+          "/etc/hosts"
+        end
+
+        # @return [Boolean] use sudo when the hosts file is not writable
+        def hosts_file_sudo?
+          true
+        end
+
+        # @param [Vagrant::Environment] env
+        # @return [HostsFile]
+        def hosts_file(env)
+          HostsFile.new(hosts_file_path,
+            lock_path: env.data_dir.join("hosts.lock"), sudo: hosts_file_sudo?)
+        end
+      end
+
+      # @return [Pathname] path to the hosts file
+      attr_reader :path
+
+      # @param [String, Pathname] path Path to the hosts file
+      # @param [String, Pathname] lock_path Path to the lock file
+      # @param [Boolean] sudo Use sudo when the hosts file is not writable
+      def initialize(path, lock_path:, sudo: true)
+        @logger = Log4r::Logger.new("vagrant::util::hosts_file")
+        @path = Pathname.new(path.to_s)
+        @lock_path = Pathname.new(lock_path.to_s)
+        @sudo = sudo
+      end
+
+      # Format the entries of a block
+      #
+      # @param [Array<Array(String, String)>] entries IP and hostname pairs
+      # @return [String]
+      def self.format_entries(entries)
+        entries.map { |ip, hostname| "#{ip}\t#{hostname}" }.join("\n")
+      end
+
+      # Set the entries of the block with the given ID. If the block
+      # already contains the same entries the file is not modified.
+      #
+      # @param [String] id Block ID
+      # @param [Array<Array(String, String)>] entries IP and hostname pairs
+      # @return [Boolean] true if the hosts file was modified
+      def update(id, entries)
+        return remove(id) if entries.empty?
+
+        block = self.class.format_entries(entries)
+        modify do |editor|
+          next false if editor.get(id) == block
+
+          editor.delete(id)
+          editor.value << "\n" if !editor.value.empty? && !editor.value.end_with?("\n")
+          editor.insert(id, block)
+          true
+        end
+      end
+
+      # Remove the block with the given ID
+      #
+      # @param [String] id Block ID
+      # @return [Boolean] true if the hosts file was modified
+      def remove(id)
+        modify do |editor|
+          next false if !editor.keys.include?(id)
+
+          editor.delete(id)
+          true
+        end
+      end
+
+      protected
+
+      # Lock the hosts file and yield an editor of its content. The
+      # content is written back if the block returns true.
+      def modify
+        @lock_path.dirname.mkpath
+        File.open(@lock_path, File::RDWR | File::CREAT, 0644) do |lock|
+          lock.flock(File::LOCK_EX)
+
+          editor = StringBlockEditor.new(read)
+          if !yield(editor)
+            @logger.debug("hosts file entries unchanged: #{path}")
+            return false
+          end
+
+          write(editor.value)
+          true
+        end
+      end
+
+      # @return [String] content of the hosts file
+      def read
+        return "" if !path.exist?
+
+        path.read
+      rescue Errno::EACCES => e
+        raise Errors::HostsFileUpdateFailed,
+          path: path.to_s,
+          error: e.message
+      end
+
+      # Write new content to the hosts file. If the file is not writable
+      # and sudo is enabled the content is copied into place using sudo
+      # so the ownership and mode of the existing file are retained.
+      #
+      # @param [String] content
+      def write(content)
+        @logger.info("updating hosts file: #{path}")
+        if path.exist? ? path.writable? : path.dirname.writable?
+          path.write(content)
+          return
+        end
+
+        if !@sudo
+          raise Errors::HostsFileUpdateFailed,
+            path: path.to_s,
+            error: "Permission denied"
+        end
+
+        tmp = Tempfile.new("vagrant-hosts")
+        begin
+          tmp.write(content)
+          tmp.close
+          cmd = ["sudo", "cp", tmp.path, path.to_s]
+          result = Subprocess.execute(*cmd)
+          if result.exit_code != 0
+            raise Errors::HostsFileUpdateFailed,
+              path: path.to_s,
+              error: "#{cmd.shelljoin}: #{result.stderr.strip}"
+          end
+        ensure
+          tmp.close!
+        end
+      rescue Errno::EACCES, Errno::EPERM => e
+        raise Errors::HostsFileUpdateFailed,
+          path: path.to_s,
+          error: e.message
+      end
+    end
+  end
+end
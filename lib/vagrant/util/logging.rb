@@ -0,0 +1,116 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require "log4r"
+
+module Vagrant
+  module Util
+    # Helpers for the subsystems and structured fields of log messages.
+    #
+    # Loggers are grouped into subsystems by the prefix of their names,
+    # so the level of each subsystem can be set with `VAGRANT_LOG_FILTER`:
+    #
+    #     VAGRANT_LOG_FILTER=communicator=debug,plugin=info
+    #
+    # Fields, such as the name of the machine and the action being run,
+    # are added to the messages logged within {with_fields}.
+    module Logging
+      # Prefixes of the names of the loggers within each subsystem
+      SUBSYSTEMS = {
+        "communicator" => ["vagrant::communication"],
+        "loader" => ["vagrant::config", "vagrant::vagrantfile"],
+        "plugin" => ["vagrant::plugin", "vagrant::bundler"],
+        "provider" => [
+          "vagrant::provider", "vagrant::docker", "vagrant::hyperv",
+          "vagrant::plugins::hyperv", "vagrant::plugins::virtualbox",
+        ],
+        "synced_folder" => ["vagrant::synced_folders"],
+      }.freeze
+
+      # Name of the thread variable storing the fields
+      FIELDS_KEY = :vagrant_logging_fields
+
+      # Parse the levels of subsystems in the format of `VAGRANT_LOG_FILTER`.
+      #
+      # @param [String] filter Comma separated list of "subsystem=level"
+      # @return [Hash<String, Integer>] level of each subsystem
+      # @raise [ArgumentError] if a subsystem or level is invalid
+      def self.parse_filter(filter)
+        filter.to_s.split(",").map(&:strip).reject(&:empty?).map do |entry|
+          subsystem, level_name = entry.split("=", 2).map { |v| v.to_s.strip }
+          subsystem = subsystem.downcase.tr("-", "_")
+          if !SUBSYSTEMS.key?(subsystem)
+            raise ArgumentError, "Invalid subsystem `#{subsystem}`. Valid " \
+              "subsystems are: #{SUBSYSTEMS.keys.join(", ")}"
+          end
+
+          level = Log4r::LNAMES.index(level_name.upcase)
+          if !level || level_name.empty?
+            raise ArgumentError, "Invalid level `#{level_name}` for " \
+              "subsystem `#{subsystem}`"
+          end
+
+          [subsystem, level]
+        end.to_h
+      end
+
+      # Set the levels of subsystems. Loggers created afterwards within a
+      # subsystem use its level.
+      #
+      # Messages are not passed to parent loggers with a higher level, so
+      # the loggers of the subsystems write to the outputters directly
+      # instead of passing messages to the "vagrant" logger.
+      #
+      # @param [Hash<String, Integer>] levels level of each subsystem
+      # @param [Array<Log4r::Outputter>] outputters
+      def self.apply_filter(levels, outputters)
+        levels.each do |subsystem, level|
+          SUBSYSTEMS[subsystem].each do |prefix|
+            logger = Log4r::Logger.factory(prefix)
+            logger.outputters = outputters
+            logger.additive = false
+            logger.level = level
+          end
+        end
+      end
+
+      # @param [String] name Name of the logger
+      # @return [String, nil] subsystem of the logger
+      def self.subsystem(name)
+        name = name.to_s
+        SUBSYSTEMS.each do |subsystem, prefixes|
+          return subsystem if prefixes.any? { |p| name == p || name.start_with?("#{p}::") }
+        end
+
+        nil
+      end
+
+      # Add fields to the messages logged by the current thread within
+      # the block. Fields are nested, so fields of an outer block are
+      # included unless they are replaced.
+      #
+      # @param [Hash] new_fields
+      def self.with_fields(**new_fields)
+        previous = Thread.current[FIELDS_KEY]
+        Thread.current[FIELDS_KEY] = (previous || {}).merge(new_fields)
+        yield
+      ensure
+        Thread.current[FIELDS_KEY] = previous
+      end
+
+      # @return [Hash] fields of the messages logged by the current thread
+      def self.fields
+        Thread.current[FIELDS_KEY] || {}
+      end
+
+      # @param [Log4r::LogEvent] event
+      # @return [Hash] fields of the message, including its subsystem
+      def self.event_fields(event)
+        result = {}
+        subsystem = subsystem(event.fullname)
+        result[:subsystem] = subsystem if subsystem
+        result.merge(fields)
+      end
+    end
+  end
+end
@@ -2,13 +2,16 @@
 # SPDX-License-Identifier: BUSL-1.1
 
 require "vagrant/util/credential_scrubber"
+require "vagrant/util/logging"
 require "log4r/formatter/formatter"
 
 module Vagrant
   module Util
     # Wrapper for logging formatting to provide
     # information scrubbing prior to being written
-    # to output target
+    # to output target. The fields of the message,
+    # such as its subsystem and machine, are added
+    # to the end of the message.
     class LoggingFormatter < Log4r::BasicFormatter
       # @return [Log4r::PatternFormatter]
       attr_reader :formatter
@@ -23,8 +26,25 @@ module Vagrant
       # Format event and scrub output
       def format(event)
         msg = formatter.format(event)
+        fields = Logging.event_fields(event)
+        if !fields.empty?
+          newline = msg.end_with?("\n") ? "\n" : ""
+          msg = "#{msg.chomp} #{format_fields(fields)}#{newline}"
+        end
         CredentialScrubber.desensitize(msg)
       end
+
+      protected
+
+      # @param [Hash] fields
+      # @return [String] fields in the format of "key=value"
+      def format_fields(fields)
+        fields.map do |key, value|
+          value = value.to_s
+          value = value.inspect if value.empty? || value =~ /[\s"=]/
+          "#{key}=#{value}"
+        end.join(" ")
+      end
     end
 
     class HCLogFormatter < Log4r::BasicFormatter
@@ -54,6 +74,9 @@ module Vagrant
             "@message" => msg,
           }
           d["@caller"] = event.tracer[0] if event.tracer
+          Logging.event_fields(event).each do |key, value|
+            d[key.to_s] = value.to_s
+          end
           d.to_json + "\n"
         end
       end
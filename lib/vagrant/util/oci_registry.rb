@@ -0,0 +1,343 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require "base64"
+require "digest"
+require "fileutils"
+require "json"
+require "net/http"
+require "openssl"
+require "pathname"
+require "uri"
+
+require "log4r"
+
+require "vagrant/util/downloader"
+require "vagrant/util/subprocess"
+require "vagrant/util/which"
+
+module Vagrant
+  module Util
+    # This class fetches content from an OCI compliant registry. It
+    # is used to load Vagrantfiles which are stored as the config blob
+    # of an artifact within a registry, referenced using the format:
+    #
+    #     oci://registry.example.com/path/to/artifact:tag
+    #     oci://registry.example.com/path/to/artifact@sha256:DIGEST
+    #
+    # Authentication uses the credentials configured for docker,
+    # including any configured credential helpers.
+    class OCIRegistry
+      # Scheme used for OCI references
+      SCHEME = "oci".freeze
+
+      # Media types accepted when requesting a manifest
+      MANIFEST_MEDIA_TYPES = [
+        "application/vnd.oci.image.manifest.v1+json",
+        "application/vnd.docker.distribution.manifest.v2+json",
+      ].freeze
+
+      # Maximum number of redirects to follow when fetching from
+      # the registry
+      MAX_REDIRECTS = 10
+
+      # Parsed form of an OCI reference
+      Reference = Struct.new(:registry, :repository, :tag, :digest) do
+        # @return [String] reference name for use in error messages
+        def to_s
+          name = "#{SCHEME}://#{registry}/#{repository}"
+          digest ? "#{name}@#{digest}" : "#{name}:#{tag}"
+        end
+      end
+
+      # Checks if the given location is an OCI reference
+      #
+      # @param [String] location
+      # @return [Boolean]
+      def self.reference?(location)
+        location.to_s.start_with?("#{SCHEME}://")
+      end
+
+      # Parse an OCI reference
+      #
+      # @param [String] location Location in the form oci://registry/path:tag
+      # @return [Reference]
+      def self.parse(location)
+        raw = location.to_s.sub(/^#{SCHEME}:\/\//, "")
+        registry, path = raw.split("/", 2)
+        if registry.to_s.empty? || path.to_s.empty?
+          raise Errors::OCIReferenceInvalid, reference: location.to_s
+        end
+
+        digest = nil
+        tag = nil
+        if path.include?("@")
+          path, digest = path.split("@", 2)
+        elsif path.split("/").last.include?(":")
+          path, _, tag = path.rpartition(":")
+        end
+        tag = "latest" if digest.nil? && tag.to_s.empty?
+
+        if path.empty? || (digest && digest !~ /^[a-z0-9]+:[a-f0-9]+$/)
+          raise Errors::OCIReferenceInvalid, reference: location.to_s
+        end
+
+        Reference.new(registry, path, tag, digest)
+      end
+
+      # @return [Reference]
+      attr_reader :reference
+
+      # @param [String, Reference] reference OCI reference
+      # @param [Hash] opts
+      # @option opts [Hash] :credentials Username and secret to use for
+      #   authentication instead of docker configured credentials
+      def initialize(reference, **opts)
+        @logger = Log4r::Logger.new("vagrant::util::oci_registry")
+        @reference = reference.is_a?(Reference) ? reference : self.class.parse(reference)
+        @credentials = opts[:credentials]
+        @token = nil
+      end
+
+      # Fetch the Vagrantfile stored in the config blob of the referenced
+      # artifact. The content is cached within the given directory keyed
+      # by the blob digest so it is only downloaded once.
+      #
+      # @param [Pathname] cache_dir Directory to store fetched content
+      # @return [Pathname] path to the Vagrantfile
+      def fetch_vagrantfile(cache_dir)
+        digest = manifest.fetch("config", {})["digest"].to_s
+        if digest.empty?
+          raise Errors::OCIManifestInvalid, reference: reference.to_s
+        end
+
+        path = Pathname.new(cache_dir).join(digest.sub(":", "-"), "Vagrantfile")
+        if path.file?
+          @logger.debug("using cached Vagrantfile for #{reference} (#{digest})")
+          return path
+        end
+
+        data = blob(digest)
+        FileUtils.mkdir_p(path.dirname)
+        tmp_path = path.dirname.join(".Vagrantfile.#{Process.pid}")
+        File.binwrite(tmp_path, data)
+        File.rename(tmp_path, path)
+        @logger.info("cached Vagrantfile for #{reference} at #{path}")
+        path
+      ensure
+        File.delete(tmp_path) if tmp_path && File.exist?(tmp_path)
+      end
+
+      # Fetch the manifest for the reference
+      #
+      # @return [Hash]
+      def manifest
+        return @manifest if @manifest
+
+        data = request("manifests/#{reference.digest || reference.tag}",
+          MANIFEST_MEDIA_TYPES.join(", "))
+        verify_digest!(reference.digest, data) if reference.digest
+        @manifest = JSON.parse(data)
+      rescue JSON::ParserError
+        raise Errors::OCIManifestInvalid, reference: reference.to_s
+      end
+
+      # Fetch a blob from the repository. The content of the blob is
+      # verified against the digest before being returned.
+      #
+      # @param [String] digest Digest of the blob
+      # @return [String]
+      def blob(digest)
+        request("blobs/#{digest}", "*/*").tap do |data|
+          verify_digest!(digest, data)
+        end
+      end
+
+      protected
+
+      # Verify content matches the expected digest
+      #
+      # @param [String] digest Expected digest
+      # @param [String] data Content to verify
+      def verify_digest!(digest, data)
+        algorithm, expected = digest.split(":", 2)
+        klass = {"sha256" => Digest::SHA256, "sha512" => Digest::SHA512}[algorithm]
+        return if klass.nil?
+
+        actual = klass.hexdigest(data)
+        if actual != expected
+          raise Errors::OCIDigestMismatch,
+            reference: reference.to_s,
+            expected: digest,
+            actual: "#{algorithm}:#{actual}"
+        end
+      end
+
+      # Perform a request against the registry API for the referenced
+      # repository, authenticating when requested by the registry.
+      #
+      # @param [String] path Path within the repository API
+      # @param [String] accept Accepted media types
+      # @return [String] response body
+      def request(path, accept)
+        uri = URI("https://#{reference.registry}/v2/#{reference.repository}/#{path}")
+        response = get(uri, accept)
+        if response.code == "401" && @token.nil?
+          authenticate!(response["www-authenticate"])
+          response = get(uri, accept)
+        end
+
+        case response.code
+        when "200"
+          response.body
+        when "401", "403"
+          raise Errors::OCIUnauthorized, reference: reference.to_s
+        when "404"
+          raise Errors::OCINotFound, reference: reference.to_s
+        else
+          raise Errors::OCIRequestFailed,
+            reference: reference.to_s,
+            code: response.code,
+            message: response.message
+        end
+      end
+
+      # Perform a GET request following any redirects. Authorization
+      # is only sent to the registry itself.
+      #
+      # @param [URI] uri
+      # @param [String] accept
+      # @return [Net::HTTPResponse]
+      def get(uri, accept)
+        MAX_REDIRECTS.times do
+          @logger.debug("GET #{uri}")
+          request = Net::HTTP::Get.new(uri)
+          request["Accept"] = accept
+          request["User-Agent"] = Downloader::USER_AGENT
+          if uri.host == reference.registry.split(":").first && @token
+            request["Authorization"] = @token
+          end
+
+          response = Net::HTTP.start(uri.host, uri.port, use_ssl: uri.scheme == "https") do |http|
+            http.request(request)
+          end
+          return response if !response.is_a?(Net::HTTPRedirection)
+
+          uri = URI.join(uri.to_s, response["location"])
+        end
+
+        raise Errors::OCIRequestFailed,
+          reference: reference.to_s,
+          code: "redirect",
+          message: "too many redirects"
+      rescue SocketError, SystemCallError, Net::OpenTimeout, Net::ReadTimeout, OpenSSL::SSL::SSLError => e
+        raise Errors::OCIRequestFailed,
+          reference: reference.to_s,
+          code: e.class.name,
+          message: e.message
+      end
+
+      # Set the authorization for requests based on the challenge
+      # provided by the registry.
+      #
+      # @param [String] challenge Value of the WWW-Authenticate header
+      def authenticate!(challenge)
+        scheme, params = challenge.to_s.split(" ", 2)
+        creds = @credentials || docker_credentials
+        basic = nil
+        if creds
+          basic = "Basic " + Base64.strict_encode64("#{creds[:username]}:#{creds[:secret]}")
+        end
+
+        if scheme.to_s.casecmp("basic") == 0
+          raise Errors::OCIUnauthorized, reference: reference.to_s if basic.nil?
+          @token = basic
+          return
+        end
+
+        if scheme.to_s.casecmp("bearer") != 0
+          raise Errors::OCIUnauthorized, reference: reference.to_s
+        end
+
+        values = Hash[params.to_s.scan(/(\w+)="([^"]*)"/)]
+        token_uri = URI(values.fetch("realm") {
+          raise Errors::OCIUnauthorized, reference: reference.to_s
+        })
+        query = URI.decode_www_form(token_uri.query.to_s)
+        query << ["service", values["service"]] if values["service"]
+        query << ["scope", values["scope"] || "repository:#{reference.repository}:pull"]
+        token_uri.query = URI.encode_www_form(query)
+
+        request = Net::HTTP::Get.new(token_uri)
+        request["Authorization"] = basic if basic
+        response = Net::HTTP.start(token_uri.host, token_uri.port, use_ssl: token_uri.scheme == "https") do |http|
+          http.request(request)
+        end
+        if response.code != "200"
+          raise Errors::OCIUnauthorized, reference: reference.to_s
+        end
+
+        body = JSON.parse(response.body)
+        @token = "Bearer #{body["token"] || body["access_token"]}"
+      rescue JSON::ParserError
+        raise Errors::OCIUnauthorized, reference: reference.to_s
+      end
+
+      # Load credentials for the registry from the docker configuration.
+      # Credential helpers are preferred over stored credentials.
+      #
+      # @return [Hash, nil] credentials with :username and :secret
+      def docker_credentials
+        config_dir = ENV["DOCKER_CONFIG"] || File.join(Dir.home, ".docker")
+        config_path = File.join(config_dir, "config.json")
+        return if !File.file?(config_path)
+
+        config = JSON.parse(File.read(config_path))
+        registry = reference.registry
+        helper = config.fetch("credHelpers", {})[registry] || config["credsStore"]
+        if helper
+          creds = helper_credentials(helper, registry)
+          return creds if creds
+        end
+
+        auth = config.fetch("auths", {}).fetch(registry, {})["auth"]
+        return if auth.to_s.empty?
+
+        username, secret = Base64.decode64(auth).split(":", 2)
+        {username: username, secret: secret}
+      rescue JSON::ParserError => e
+        @logger.warn("failed to parse docker configuration #{config_path}: #{e}")
+        nil
+      end
+
+      # Request credentials from a docker credential helper
+      #
+      # @param [String] helper Name of the credential helper
+      # @param [String] registry Registry to request credentials for
+      # @return [Hash, nil] credentials with :username and :secret
+      def helper_credentials(helper, registry)
+        helper_path = Which.which("docker-credential-#{helper}")
+        if helper_path.nil?
+          @logger.warn("docker credential helper `#{helper}` is not installed")
+          return
+        end
+
+        result = Subprocess.execute(helper_path, "get", notify: [:stdin]) do |type, io|
+          if type == :stdin
+            io.write(registry)
+            io.close
+          end
+        end
+        if result.exit_code != 0
+          @logger.debug("no credentials for #{registry} from `#{helper}`: #{result.stdout}")
+          return
+        end
+
+        info = JSON.parse(result.stdout)
+        {username: info["Username"], secret: info["Secret"]}
+      rescue JSON::ParserError
+        nil
+      end
+    end
+  end
+end
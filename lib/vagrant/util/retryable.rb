@@ -10,6 +10,10 @@ module Vagrant
       # event the specified exception is raised. If the retries
       # run out, the final exception is raised.
       #
+      # The `:sleep` option sets the number of seconds to wait between
+      # tries. If a `:backoff` multiplier is provided, the time to wait is
+      # multiplied after each try, up to the `:max_sleep` number of seconds.
+      #
       # This code is adapted slightly from the following blog post:
       # http://blog.codefront.net/2008/01/14/retrying-code-blocks-in-ruby-on-exceptions-whatever/
       def retryable(opts=nil)
@@ -23,7 +27,13 @@ module Vagrant
             logger = Log4r::Logger.new("vagrant::util::retryable")
             logger.info("Retryable exception raised: #{e.inspect}")
 
-            sleep opts[:sleep].to_f if opts[:sleep]
+            if opts[:sleep]
+              sleep opts[:sleep].to_f
+              if opts[:backoff]
+                opts[:sleep] = opts[:sleep].to_f * opts[:backoff]
+                opts[:sleep] = [opts[:sleep], opts[:max_sleep].to_f].min if opts[:max_sleep]
+              end
+            end
             retry
           end
           raise
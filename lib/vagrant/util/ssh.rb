@@ -2,9 +2,11 @@
 # SPDX-License-Identifier: BUSL-1.1
 
 require "log4r"
+require "shellwords"
 
 require 'childprocess'
 
+require "vagrant/util/credential_scrubber"
 require "vagrant/util/file_mode"
 require "vagrant/util/platform"
 require "vagrant/util/safe_exec"
@@ -57,6 +59,26 @@ module Vagrant
         raise Errors::SSHKeyBadPermissions, key_path: key_path
       end
 
+      # Builds the commands which export environment variables within a
+      # POSIX shell on the remote machine. The values are escaped so they
+      # are not expanded by the shell. Escaped secrets are registered as
+      # sensitive too, as they are also escaped when the SSH command is
+      # logged.
+      #
+      # @param [Hash] environment Names and values of the variables
+      # @return [String]
+      def self.environment_exports(environment)
+        environment.map do |name, value|
+          value = value.to_s
+          escaped = Shellwords.escape(value)
+          if CredentialScrubber.sensitive_strings.include?(value)
+            CredentialScrubber.sensitive(escaped)
+            CredentialScrubber.sensitive(escaped.inspect[1...-1])
+          end
+          "export #{name}=#{escaped}; "
+        end.join
+      end
+
       # Halts the running of this process and replaces it with a full-fledged
       # SSH shell into a remote machine.
       #
@@ -9,6 +9,7 @@ require 'log4r'
 require 'vagrant/util/io'
 require 'vagrant/util/platform'
 require 'vagrant/util/safe_chdir'
+require 'vagrant/util/tracing'
 require 'vagrant/util/which'
 
 module Vagrant
@@ -145,6 +146,11 @@ module Vagrant
           jailbreak(process.environment)
         end
 
+        # Propagate the current trace context to the process
+        Tracing.propagation_env.each do |k, v|
+          process.environment[k] = v
+        end
+
         # Set the environment on the process if we must
         if @options[:env]
           @options[:env].each do |k, v|
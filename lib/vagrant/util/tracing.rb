@@ -0,0 +1,160 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require "log4r"
+
+module Vagrant
+  module Util
+    # This module creates OpenTelemetry spans for the major phases of
+    # running a command. Tracing is only enabled when an exporter is
+    # configured using the standard OTEL_* environment variables, for
+    # example OTEL_TRACES_EXPORTER or OTEL_EXPORTER_OTLP_ENDPOINT. When
+    # tracing is disabled the OpenTelemetry libraries are never loaded
+    # and spans are a no-op.
+    #
+    # The OpenTelemetry libraries are not dependencies of Vagrant. They
+    # are installed as plugins by users who enable tracing, and tracing
+    # stays disabled if they are missing.
+    module Tracing
+      # Name of the tracer used for spans created by Vagrant
+      TRACER_NAME = "vagrant".freeze
+
+      # Environment variables which enable tracing when set
+      EXPORTER_VARIABLES = [
+        "OTEL_TRACES_EXPORTER",
+        "OTEL_EXPORTER_OTLP_ENDPOINT",
+        "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT",
+      ].freeze
+
+      @@logger = Log4r::Logger.new("vagrant::util::tracing")
+      @@tracer = nil
+      @@enabled = nil
+      @@libraries_missing = false
+      @@mutex = Mutex.new
+
+      # @return [Boolean] tracing is enabled
+      def self.enabled?
+        return @@enabled if !@@enabled.nil?
+
+        @@mutex.synchronize do
+          @@enabled = setup if @@enabled.nil?
+        end
+        @@enabled
+      end
+
+      # @return [Boolean] tracing is configured but the OpenTelemetry
+      #   libraries are not installed
+      def self.libraries_missing?
+        enabled?
+        @@libraries_missing
+      end
+
+      # Run the block within a new span. When tracing is disabled the
+      # block is called with nil.
+      #
+      # @param [String] name Name of the span
+      # @param [Hash] attributes Attributes of the span. Nil values are
+      #   not included.
+      # @yieldparam [OpenTelemetry::Trace::Span, nil] span
+      # @return [Object] result of the block
+      def self.span(name, attributes={})
+        return yield(nil) if !enabled?
+
+        attributes = attributes.reject { |_, v| v.nil? }.map { |k, v|
+          [k.to_s, v.is_a?(Numeric) || v == true || v == false ? v : v.to_s]
+        }.to_h
+        @@tracer.in_span(name, attributes: attributes) do |span, _|
+          yield span
+        end
+      end
+
+      # Attributes describing the machine an action is run on so spans
+      # can be grouped by machine.
+      #
+      # @param [Vagrant::Machine] machine
+      # @param [Symbol, String] action Name of the action
+      # @return [Hash]
+      def self.machine_attributes(machine, action=nil)
+        {
+          "vagrant.target.name" => machine.name,
+          "vagrant.provider" => machine.provider_name,
+          "vagrant.action" => action,
+        }
+      end
+
+      # @return [Object, nil] the current trace context, used to continue
+      #   a trace within another thread
+      def self.current_context
+        return if !enabled?
+
+        OpenTelemetry::Context.current
+      end
+
+      # Run the block with the given trace context as the current context
+      #
+      # @param [Object, nil] context Context from {current_context}
+      # @return [Object] result of the block
+      def self.with_context(context)
+        return yield if !enabled? || context.nil?
+
+        OpenTelemetry::Context.with_current(context) { yield }
+      end
+
+      # Environment variables which propagate the current trace context
+      # to a subprocess using the W3C trace context format.
+      #
+      # @return [Hash<String, String>]
+      def self.propagation_env
+        return {} if !enabled?
+
+        carrier = {}
+        OpenTelemetry.propagation.inject(carrier)
+        carrier.map { |k, v| [k.upcase, v] }.to_h
+      end
+
+      # Reset the tracing state. This is only used by tests.
+      def self.reset!
+        @@mutex.synchronize do
+          @@enabled = nil
+          @@libraries_missing = false
+          @@tracer = nil
+        end
+      end
+
+      # Load and configure the OpenTelemetry SDK if an exporter is
+      # configured.
+      #
+      # @return [Boolean] tracing was enabled
+      def self.setup
+        configured = EXPORTER_VARIABLES.any? { |v| !ENV[v].to_s.empty? }
+        return false if !configured
+        return false if ENV["OTEL_TRACES_EXPORTER"].to_s.strip == "none"
+        return false if ENV["OTEL_SDK_DISABLED"].to_s.downcase == "true"
+
+        begin
+          require "opentelemetry/sdk"
+          require "opentelemetry/exporter/otlp"
+        rescue LoadError => e
+          @@logger.warn("OpenTelemetry libraries are not installed: #{e}")
+          @@libraries_missing = true
+          return false
+        end
+
+        OpenTelemetry::SDK.configure do |c|
+          c.service_name = ENV.fetch("OTEL_SERVICE_NAME", "vagrant")
+          c.service_version = Vagrant::VERSION
+        end
+        @@tracer = OpenTelemetry.tracer_provider.tracer(TRACER_NAME, Vagrant::VERSION)
+
+        # Flush any pending spans before exiting
+        at_exit { OpenTelemetry.tracer_provider.shutdown }
+
+        @@logger.info("OpenTelemetry tracing enabled")
+        true
+      rescue StandardError => e
+        @@logger.warn("failed to enable OpenTelemetry tracing: #{e.class}: #{e}")
+        false
+      end
+    end
+  end
+end
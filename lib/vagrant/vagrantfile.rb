@@ -47,7 +47,10 @@ module Vagrant
     # @return [Machine]
     def machine(name, provider, boxes, data_path, env)
       # Load the configuration for the machine
-      results = machine_config(name, provider, boxes, data_path)
+      results = Util::Tracing.span("vagrant.config.load",
+        "vagrant.target.name" => name, "vagrant.provider" => provider) do
+        machine_config(name, provider, boxes, data_path)
+      end
       box             = results[:box]
       config          = results[:config]
       config_errors   = results[:config_errors]
@@ -105,6 +108,7 @@ module Vagrant
     #   - config_warnings: list of warnings, if any
     #   - provider_cls: class of the provider backing the machine
     #   - provider_options: options for the provider
+    #   - provenance: the locations that contributed to each setting
     #
     # @param [Symbol] name Name of the machine.
     # @param [Symbol] provider The provider the machine should
@@ -166,10 +170,15 @@ module Vagrant
         end
       end
 
+      # Track the generic location of each of the keys loaded so
+      # provenance can be reported without internal key names
+      locations = Hash[keys.map { |k| [k, k] }]
+
       # Add the sub-machine configuration to the loader and keys
       vm_config_key = "#{object_id}_machine_#{name}"
       @loader.set(vm_config_key, sub_machine.config_procs)
       keys << vm_config_key
+      locations[vm_config_key] = :machine
 
       # Load once so that we can get the proper box value
       config, config_warnings, config_errors = @loader.load(keys)
@@ -208,6 +217,7 @@ module Vagrant
                 "#{boxes.object_id}_#{box.name}_#{box.provider}".to_sym
               @loader.set(box_config_key, box_vagrantfile)
               local_keys.unshift(box_config_key)
+              locations[box_config_key] = :box
               config, config_warnings, config_errors = @loader.load(local_keys)
             elsif box_vagrantfile && config.vm.ignore_box_vagrantfile
               @logger.warn("Ignoring #{box.name} provided Vagrantfile inside box")
@@ -222,6 +232,7 @@ module Vagrant
             "#{object_id}_vm_#{name}_#{config.vm.box}_#{provider}".to_sym
           @loader.set(config_key, provider_overrides)
           local_keys << config_key
+          locations[config_key] = :provider
           config, config_warnings, config_errors = @loader.load(local_keys)
         end
 
@@ -254,6 +265,27 @@ module Vagrant
       config.vm.box = original_box
       config.vm.box_version = original_version
 
+      # The forwarded ports of a clone are the same as those of the
+      # machine it was cloned from, so they are corrected if they collide
+      if sub_machine.options[:clone_of]
+        config.vm.networks.each do |type, options|
+          options[:auto_correct] = true if type == :forwarded_port
+        end
+      end
+
+      provenance = {}
+      @loader.provenance.each do |setting, sources|
+        provenance[setting] = sources.map { |source| locations.fetch(source, source) }
+      end
+
+      # Settings inherited from a provider profile are attributed to the
+      # profile, since the provider itself does not set them
+      if provider
+        config.vm.inherited_provider_settings(provider).each do |setting, profile|
+          provenance["#{provider}.#{setting}"] = [:"profile:#{profile}"]
+        end
+      end
+
       return {
         box: box,
         provider_cls: provider_cls,
@@ -261,9 +293,31 @@ module Vagrant
         config: config,
         config_warnings: config_warnings,
         config_errors: config_errors,
+        provenance: provenance,
       }
     end
 
+    # Returns the locations that contributed to the final value of a
+    # setting for a single machine, in the order they were merged. The
+    # locations are the keys given to #initialize along with `:box`,
+    # `:machine` and `:provider` for the box Vagrantfile, sub-machine
+    # and provider override configurations. Settings of the provider
+    # are given as "provider.setting", and those inherited from a
+    # provider profile are reported as `:"profile:NAME"`.
+    #
+    # @param [String] key Setting name in the form of "namespace.attribute"
+    # @param [Symbol] name Name of the machine.
+    # @param [Symbol] provider The provider the machine should
+    #   be backed by (required for provider overrides).
+    # @param [BoxCollection] boxes BoxCollection to look up the
+    #   box Vagrantfile.
+    # @param [Pathname] data_path Machine data path
+    # @return [Array<Symbol>]
+    def config_provenance(key, name, provider, boxes, data_path=nil)
+      results = machine_config(name, provider, boxes, data_path)
+      results[:provenance].fetch(key.to_s, [])
+    end
+
     # Returns a list of the machines that are defined within this
     # Vagrantfile.
     #
@@ -4,6 +4,7 @@
 require 'optparse'
 
 require_relative 'download_mixins'
+require_relative 'progress_board'
 
 module VagrantPlugins
   module CommandBox
@@ -11,6 +12,21 @@ module VagrantPlugins
       class Add < Vagrant.plugin("2", :command)
         include DownloadMixins
 
+        # Number of boxes added at the same time when adding many boxes
+        DEFAULT_PARALLEL = 3
+
+        # Matches a rate limit, which is a number of bytes with an optional
+        # unit suffix
+        RATE_REGEXP = /\A(\d+)([kmg]?)\z/i
+
+        # Multipliers of the rate limit suffixes
+        RATE_UNITS = {
+          "" => 1,
+          "k" => 1024,
+          "m" => 1024 ** 2,
+          "g" => 1024 ** 3,
+        }.freeze
+
         def execute
           options = {
             architecture: :auto,
@@ -26,6 +42,10 @@ module VagrantPlugins
               options[:clean] = c
             end
 
+            o.on("--[no-]resume", "Resume a partial download of the box (default: true)") do |r|
+              options[:resume] = r
+            end
+
             o.on("-f", "--force", "Overwrite an existing box if it exists") do |f|
               options[:force] = f
             end
@@ -36,6 +56,10 @@ module VagrantPlugins
                 options[:location_trusted] = l
             end
 
+            o.on("--limit-rate RATE", String, "Maximum download rate in bytes per second, such as 500k or 2m") do |r|
+              options[:limit_rate] = r
+            end
+
             o.on("-a", "--architecture ARCH", String, "Architecture the box should satisfy") do |a|
               options[:architecture] = a
             end
@@ -61,18 +85,64 @@ module VagrantPlugins
               options[:checksum] = c
             end
 
-            o.on("--checksum-type TYPE", String, "Checksum type (md5, sha1, sha256)") do |c|
+            o.on("--checksum-type TYPE", String, "Checksum type (md5, sha1, sha256, sha384, sha512, minisign)") do |c|
               options[:checksum_type] = c.to_sym
             end
 
+            o.on("--checksum-key PATH", String, "Public key used to verify signature checksum types") do |c|
+              options[:checksum_key] = File.expand_path(c)
+            end
+
             o.on("--name BOX", String, "Name of the box") do |n|
               options[:name] = n
             end
+
+            o.separator ""
+            o.separator "Many boxes can be added at the same time by adding each argument as"
+            o.separator "a box, or by listing the boxes in a file with one box, and optionally"
+            o.separator "a version constraint, per line:"
+            o.separator ""
+
+            o.on("--force-many", "Add each argument as a separate box") do |m|
+              options[:many] = m
+            end
+
+            o.on("--from-file FILE", String, "Add the boxes listed in a file") do |f|
+              options[:from_file] = f
+            end
+
+            o.on("--parallel COUNT", Integer, "Maximum number of boxes added at the same time (default: #{DEFAULT_PARALLEL})") do |p|
+              options[:parallel] = p
+            end
           end
 
           # Parse the options
           argv = parse_options(opts)
           return if !argv
+
+          if (options[:limit_rate] && !RATE_REGEXP.match?(options[:limit_rate])) ||
+              (options[:parallel] && options[:parallel] < 1)
+            raise Vagrant::Errors::CLIInvalidUsage,
+              help: opts.help.chomp
+          end
+
+          if options[:many] || options[:from_file]
+            boxes = argv.map { |box| [box, options[:version]] }
+            if options[:from_file]
+              read_manifest(options[:from_file]).each do |box, version|
+                boxes << [box, version || options[:version]]
+              end
+            end
+
+            # The name and checksum can only apply to a single box
+            if boxes.empty? || options[:name] || options[:checksum]
+              raise Vagrant::Errors::CLIInvalidUsage,
+                help: opts.help.chomp
+            end
+
+            return add_many(boxes.uniq, options)
+          end
+
           if argv.empty? || argv.length > 2
             raise Vagrant::Errors::CLIInvalidUsage,
               help: opts.help.chomp
@@ -84,14 +154,122 @@ module VagrantPlugins
             url = argv[1]
           end
 
-          @env.action_runner.run(Vagrant::Action.action_box_add, {
+          @env.action_runner.run(Vagrant::Action.action_box_add,
+            action_env(url, options[:name], options[:version], options).merge(
+              box_download_limit_rate: options[:limit_rate],
+              ui: Vagrant::UI::Prefixed.new(@env.ui, "box"),
+            ))
+
+          # Success, exit status 0
+          0
+        end
+
+        protected
+
+        # Add many boxes. The boxes are downloaded, verified and unpacked
+        # by a pool of workers, and a box failing to be added does not stop
+        # the other boxes from being added. If a rate limit is given, it is
+        # shared by the workers.
+        #
+        # @param [Array<Array(String, String)>] boxes Box descriptor and
+        #   version constraint of each box
+        # @param [Hash] options
+        # @return [Integer] exit status
+        def add_many(boxes, options)
+          workers = [options[:parallel] || DEFAULT_PARALLEL, boxes.length].min
+          limit_rate = nil
+          if options[:limit_rate]
+            limit_rate = [parse_rate(options[:limit_rate]) / workers, 1].max
+          end
+
+          @env.ui.info(I18n.t("vagrant.commands.box.add_many.adding",
+            count: boxes.length, parallel: workers))
+
+          board = ProgressBoard.new(@env.ui)
+          queue = Queue.new
+          boxes.each_with_index { |box, i| queue << [i, *box] }
+          queue.close
+          errors = Array.new(boxes.length)
+
+          threads = Array.new(workers) do
+            Thread.new do
+              while (item = queue.pop)
+                i, url, version = item
+                ui = board.box_ui(url)
+                begin
+                  @env.action_runner.run(Vagrant::Action.action_box_add,
+                    action_env(url, nil, version, options).merge(
+                      box_download_limit_rate: limit_rate,
+                      ui: ui,
+                    ))
+                rescue StandardError => e
+                  @logger.error("Failed to add box #{url}: #{e.class}: #{e}")
+                  @logger.debug(e.backtrace.join("\n")) if e.backtrace
+                  errors[i] = e
+                ensure
+                  board.finish(url)
+                end
+              end
+            end
+          end
+          threads.each(&:join)
+
+          boxes.each_with_index do |(url, _), i|
+            if errors[i]
+              @env.ui.error(I18n.t("vagrant.commands.box.add_many.failed",
+                box: url, message: errors[i].message))
+            else
+              @env.ui.success(I18n.t("vagrant.commands.box.add_many.added",
+                box: url))
+            end
+          end
+
+          failed = errors.compact.length
+          @env.ui.info(I18n.t("vagrant.commands.box.add_many.summary",
+            added: boxes.length - failed, failed: failed))
+
+          failed > 0 ? 1 : 0
+        end
+
+        # Read the boxes listed in a file. Each line has a box descriptor
+        # and an optional version constraint. Blank lines and comments
+        # starting with `#` are ignored.
+        #
+        # @param [String] path Path to the file
+        # @return [Array<Array(String, String)>] box descriptor and version
+        #   constraint of each box
+        def read_manifest(path)
+          path = File.expand_path(path, @env.cwd)
+          if !File.file?(path)
+            raise Vagrant::Errors::BoxAddManifestNotFound, path: path
+          end
+
+          File.readlines(path).map do |line|
+            line = line.sub(/#.*/, "").strip
+            next if line.empty?
+
+            line.split(/\s+/, 2)
+          end.compact
+        end
+
+        # @param [String] rate Rate limit, such as 500k
+        # @return [Integer] rate limit in bytes
+        def parse_rate(rate)
+          match = RATE_REGEXP.match(rate)
+          match[1].to_i * RATE_UNITS[match[2].downcase]
+        end
+
+        # @return [Hash] environment of the box add action for a box
+        def action_env(url, name, version, options)
+          {
             box_url: url,
-            box_name: options[:name],
+            box_name: name,
             box_provider: options[:provider],
             box_architecture: options[:architecture],
-            box_version: options[:version],
+            box_version: version,
             box_checksum_type: options[:checksum_type],
             box_checksum: options[:checksum],
+            box_checksum_key: options[:checksum_key],
             box_clean: options[:clean],
             box_force: options[:force],
             box_download_ca_cert: options[:ca_cert],
@@ -99,11 +277,8 @@ module VagrantPlugins
             box_download_client_cert: options[:client_cert],
             box_download_insecure: options[:insecure],
             box_download_location_trusted: options[:location_trusted],
-            ui: Vagrant::UI::Prefixed.new(@env.ui, "box"),
-          })
-
-          # Success, exit status 0
-          0
+            box_download_resume: options[:resume],
+          }
         end
       end
     end
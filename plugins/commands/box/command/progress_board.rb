@@ -0,0 +1,159 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require "io/console"
+
+module VagrantPlugins
+  module CommandBox
+    # This shows the progress of several boxes which are added at the same
+    # time. Each box has a line on the board with its download progress,
+    # which is redrawn as the progress changes. Other messages are written
+    # above the board.
+    #
+    # The board is only drawn when the output is a terminal.
+    class ProgressBoard < Vagrant::UI::Interface
+      # Width of the progress bars
+      BAR_WIDTH = 20
+
+      # UI for a single box. Messages are written with the box as the
+      # prefix, and progress written while rewriting is shown on the board.
+      class BoxUI < Vagrant::UI::Prefixed
+        def initialize(board, name)
+          super(board, name)
+
+          @board = board
+          @name  = name
+        end
+
+        def rewriting
+          yield Status.new(@board, @name)
+        end
+      end
+
+      # Receives the progress of a box
+      class Status < Vagrant::UI::Interface
+        def initialize(board, name)
+          super()
+
+          @board = board
+          @name  = name
+        end
+
+        [:detail, :info, :output].each do |method|
+          define_method(method) do |message, **opts|
+            @board.update(@name, message)
+          end
+        end
+      end
+
+      # @param [Vagrant::UI::Interface] ui UI the board is written to
+      def initialize(ui)
+        super()
+
+        @ui    = ui
+        @lock  = Mutex.new
+        @lines = {}
+        @drawn = 0
+        @interactive = ui.is_a?(Vagrant::UI::Basic) &&
+          !ui.is_a?(Vagrant::UI::NonInteractive) &&
+          ui.stdout.respond_to?(:tty?) && ui.stdout.tty?
+      end
+
+      # Add a box to the board
+      #
+      # @param [String] name Name of the box
+      # @return [BoxUI] UI for the box
+      def box_ui(name)
+        @lock.synchronize do
+          @lines[name] = ""
+          redraw
+        end
+
+        BoxUI.new(self, name)
+      end
+
+      # Update the progress of a box
+      #
+      # @param [String] name Name of the box
+      # @param [String] status Progress of the box
+      def update(name, status)
+        @lock.synchronize do
+          if @lines.key?(name)
+            @lines[name] = status.to_s.strip
+            redraw
+          end
+        end
+      end
+
+      # Remove a box from the board
+      #
+      # @param [String] name Name of the box
+      def finish(name)
+        @lock.synchronize do
+          @lines.delete(name)
+          redraw
+        end
+      end
+
+      [:ask, :detail, :info, :warn, :error, :output, :success].each do |method|
+        define_method(method) do |message, **opts|
+          @lock.synchronize do
+            clear
+            begin
+              @ui.send(method, message, **opts)
+            ensure
+              draw
+            end
+          end
+        end
+      end
+
+      def machine(type, *data)
+        @ui.machine(type, *data)
+      end
+
+      def opts
+        @ui.opts
+      end
+
+      protected
+
+      def redraw
+        clear
+        draw
+      end
+
+      # Remove the board from the terminal
+      def clear
+        return if !@interactive || @drawn == 0
+
+        @ui.stdout.print("\e[#{@drawn}A\e[J")
+        @drawn = 0
+      end
+
+      # Write the board to the terminal
+      def draw
+        return if !@interactive || @lines.empty?
+
+        width = IO.console&.winsize&.last.to_i
+        @lines.each do |name, status|
+          line = "    #{name}: #{bar(status)}#{status}"
+          # Lines wrapping would move the board when it is redrawn
+          line = line[0, width - 1] if width > 1
+          @ui.stdout.puts(line)
+        end
+        @ui.stdout.flush
+        @drawn = @lines.size
+      end
+
+      # @return [String] progress bar for the status, if it has a percentage
+      def bar(status)
+        percent = status[/(\d+)%/, 1]
+        return "" if !percent
+
+        done = [percent.to_i, 100].min * BAR_WIDTH / 100
+        "[#{"=" * done}#{" " * (BAR_WIDTH - done)}] "
+      end
+    end
+  end
+end
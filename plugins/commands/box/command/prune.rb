@@ -3,14 +3,26 @@
 
 require 'optparse'
 
+require "vagrant/util/numeric"
+
 module VagrantPlugins
   module CommandBox
     module Command
       class Prune < Vagrant.plugin("2", :command)
+        # Units which can be used with `--older-than`
+        DURATION_UNITS = {
+          "s" => 1,
+          "m" => 60,
+          "h" => 60 * 60,
+          "d" => 60 * 60 * 24,
+          "w" => 60 * 60 * 24 * 7,
+        }.freeze
+
         def execute
           options = {}
           options[:force] = false
           options[:dry_run] = false
+          options[:keep_versions] = 1
 
           opts = OptionParser.new do |o|
             o.banner = "Usage: vagrant box prune [options]"
@@ -30,25 +42,48 @@ module VagrantPlugins
               options[:name] = name
             end
 
-            o.on("-f", "--force", "Destroy without confirmation even when box is in use.") do |f|
+            o.on("-f", "--force", "Destroy without confirmation.") do |f|
               options[:force] = f
             end
 
-            o.on("-k", "--keep-active-boxes", "When combined with `--force`, will keep boxes still actively in use.") do |k|
+            # Boxes in use are always kept, the flag is accepted so
+            # existing scripts continue to work
+            o.on("-k", "--keep-active-boxes", "Keep boxes still actively in use (always enabled).") do |k|
               options[:keep] = k
             end
+
+            o.on("--keep COUNT", Integer, "Number of the most recent versions of each box to keep (default: 1).") do |count|
+              options[:keep_versions] = count
+            end
+
+            o.on("--older-than DURATION", String, "Only remove versions added before this duration ago (e.g. 30d, 12h).") do |duration|
+              options[:older_than] = duration
+            end
           end
 
           # Parse the options
           argv = parse_options(opts)
           return if !argv
 
+          if options[:keep_versions] < 1
+            raise Vagrant::Errors::CLIInvalidUsage,
+              help: opts.help.chomp
+          end
+
+          if options[:older_than]
+            options[:older_than] = parse_duration(options[:older_than])
+            if !options[:older_than]
+              raise Vagrant::Errors::CLIInvalidUsage,
+                help: opts.help.chomp
+            end
+          end
+
           boxes = @env.boxes.all.sort
           if boxes.empty?
             return @env.ui.warn(I18n.t("vagrant.commands.box.no_installed_boxes"), prefix: false)
           end
 
-          delete_oldest_boxes(boxes, options[:provider], options[:force], options[:name], options[:dry_run], options[:keep])
+          delete_oldest_boxes(boxes, options)
 
           # Success, exit status 0
           0
@@ -56,39 +91,37 @@ module VagrantPlugins
 
         private
 
-        def delete_oldest_boxes(boxes, only_provider, skip_confirm, only_name, dry_run, keep_used_boxes)
+        def delete_oldest_boxes(boxes, options)
+          only_provider = options[:provider]
+          only_name = options[:name]
+
           # Find the longest box name
           longest_box = boxes.max_by { |x| x[0].length }
           longest_box_length = longest_box[0].length
 
-          # Hash map to keep track of newest versions
-          newest_boxes = Hash.new
-
-          # First find the newest version for every installed box
-          boxes.each do |name, version, provider|
+          # Group the installed versions of every box by name and provider
+          versions = Hash.new { |h, k| h[k] = Hash.new { |h2, k2| h2[k2] = [] } }
+          boxes.each do |name, version, provider, architecture|
             next if only_provider and only_provider != provider.to_s
             next if only_name and only_name != name
 
-            # Nested to make sure it works for boxes with different providers
-            if newest_boxes.has_key?(name)
-              if newest_boxes[name].has_key?(provider)
-                saved = Gem::Version.new(newest_boxes[name][provider])
-                current = Gem::Version.new(version)
-                if current > saved
-                  newest_boxes[name][provider] = version
-                end
-              else
-                newest_boxes[name][provider] = version
-              end
-            else
-              newest_boxes[name] = Hash.new
-              newest_boxes[name][provider] = version
+            versions[name][provider] << [version, architecture]
+          end
+
+          # Sort the versions from newest to oldest and determine the
+          # versions which will be kept
+          kept = {}
+          versions.each do |name, providers|
+            providers.each do |provider, entries|
+              entries.sort_by! { |version, _| Gem::Version.new(version) }.reverse!
+              kept_versions = entries.map(&:first).uniq.first(options[:keep_versions])
+              kept[[name, provider]] = kept_versions
             end
           end
 
           @env.ui.info("The following boxes will be kept...");
-          newest_boxes.each do |name, providers|
-            providers.each do |provider, version|
+          kept.each do |(name, provider), kept_versions|
+            kept_versions.each do |version|
               @env.ui.info("#{name.ljust(longest_box_length)} (#{provider}, #{version})")
 
               @env.ui.machine("box-name", name)
@@ -102,32 +135,78 @@ module VagrantPlugins
 
           # Track if we removed anything so the user can be informed
           removed_any_box = false
-          boxes.each do |name, version, provider|
-            next if !newest_boxes.has_key?(name) or !newest_boxes[name].has_key?(provider)
-
-            current = Gem::Version.new(version)
-            saved = Gem::Version.new(newest_boxes[name][provider])
-            if current < saved
-              removed_any_box = true
-
-              # Use the remove box action
-              if dry_run
-                @env.ui.info("Would remove #{name} #{provider} #{version}")
-              else
-                @env.action_runner.run(Vagrant::Action.action_box_remove, {
-                    box_name: name,
-                    box_provider: provider,
-                    box_version: version,
-                    force_confirm_box_remove: skip_confirm,
-                    keep_used_boxes: keep_used_boxes,
-                    box_remove_all_versions: false,
-                })
+          reclaimed = 0
+          versions.each do |name, providers|
+            providers.each do |provider, entries|
+              entries.each do |version, architecture|
+                next if kept[[name, provider]].include?(version)
+
+                box = @env.boxes.find(name, provider, version, architecture)
+                next if !box
+
+                if options[:older_than] && box.directory.mtime > Time.now - options[:older_than]
+                  @env.ui.info("Keeping #{name} #{provider} #{version} (added #{box.directory.mtime})")
+                  next
+                end
+
+                users = (box.in_use?(@env.machine_index) || []).find_all { |u| u.valid?(@env.home_path) }
+                # Boxes in use are never removed, even with --force, since
+                # the machines using them could not be reloaded
+                if !users.empty?
+                  @env.ui.info("Keeping #{name} #{provider} #{version} (in use by #{users.map(&:name).join(", ")})")
+                  next
+                end
+
+                removed_any_box = true
+                size = directory_size(box.directory)
+
+                # Use the remove box action
+                if options[:dry_run]
+                  @env.ui.info("Would remove #{name} #{provider} #{version}")
+                  reclaimed += size
+                else
+                  @env.action_runner.run(Vagrant::Action.action_box_remove, {
+                      box_name: name,
+                      box_provider: provider,
+                      box_version: version,
+                      box_architecture: architecture,
+                      force_confirm_box_remove: true,
+                      keep_used_boxes: true,
+                      box_remove_all_versions: false,
+                  })
+                  reclaimed += size if !box.directory.exist?
+                end
               end
             end
           end
 
           if !removed_any_box
             @env.ui.info("No old versions of boxes to remove...");
+          elsif options[:dry_run]
+            @env.ui.info("Would reclaim #{Vagrant::Util::Numeric.bytes_to_string(reclaimed)}")
+          else
+            @env.ui.info("Reclaimed #{Vagrant::Util::Numeric.bytes_to_string(reclaimed)}")
+          end
+        end
+
+        # Parse a duration such as "30d" into seconds
+        #
+        # @param [String] value
+        # @return [Integer, nil]
+        def parse_duration(value)
+          match = value.to_s.strip.match(/^(\d+)([smhdw])$/)
+          return if !match
+
+          match[1].to_i * DURATION_UNITS[match[2]]
+        end
+
+        # Total size of the files within a directory
+        #
+        # @param [Pathname] path
+        # @return [Integer] size in bytes
+        def directory_size(path)
+          Dir.glob(path.join("**", "*").to_s, File::FNM_DOTMATCH).sum do |file|
+            File.file?(file) ? File.size(file) : 0
           end
         end
       end
@@ -0,0 +1,127 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require "fileutils"
+require "optparse"
+
+require_relative "middleware/regenerate_machine_id"
+
+module VagrantPlugins
+  module CommandClone
+    class Command < Vagrant.plugin("2", :command)
+      # Files within the data directory of the source machine which are
+      # also valid for the clone, since it has the same disks
+      DATA_FILES = ["box_meta", "private_key"].freeze
+
+      def self.synopsis
+        "creates a new machine from the state of an existing machine"
+      end
+
+      def execute
+        options = {}
+
+        opts = OptionParser.new do |o|
+          o.banner = "Usage: vagrant clone [options] <source> <name>"
+          o.separator ""
+          o.separator "Options:"
+          o.separator ""
+
+          o.on("--linked", "Create a linked clone sharing the disks of the source") do |l|
+            options[:linked] = l
+          end
+        end
+
+        # Parse the options
+        argv = parse_options(opts)
+        return if !argv
+        if argv.length != 2 || argv[1] =~ /[\[\]\{\}\/]/
+          raise Vagrant::Errors::CLIInvalidUsage,
+            help: opts.help.chomp
+        end
+
+        source_name, name = argv
+        with_target_vms(source_name, single_target: true) do |source|
+          clone(source, name.to_sym, options)
+        end
+
+        # Success, exit status 0
+        0
+      end
+
+      protected
+
+      # Clone the machine
+      #
+      # @param [Vagrant::Machine] source
+      # @param [Symbol] name Name of the clone
+      # @param [Hash] options
+      def clone(source, name, options)
+        if source.state.id == Vagrant::MachineState::NOT_CREATED_ID
+          raise Vagrant::Errors::VMNotCreatedError
+        end
+
+        if !source.provider.capability?(:clone_machine)
+          raise Vagrant::Errors::CloneMachineUnsupported,
+            name: source.name.to_s,
+            provider: source.provider_name.to_s
+        end
+
+        # The source may be given by its ID from another environment
+        env = source.env
+        if clone_exists?(env, name, source.provider_name)
+          raise Vagrant::Errors::CloneMachineExists,
+            name: name.to_s,
+            source: source.name.to_s
+        end
+
+        env.register_clone(name, source.name)
+        begin
+          machine = env.machine(name, source.provider_name, true)
+
+          source.ui.info(I18n.t("vagrant.commands.clone.cloning", name: name.to_s))
+          machine.id = source.provider.capability(
+            :clone_machine, machine, !!options[:linked])
+        rescue Exception
+          env.unregister_clone(name)
+          raise
+        end
+
+        copy_data(source, machine)
+
+        source.ui.success(I18n.t("vagrant.commands.clone.cloned", name: name.to_s))
+      end
+
+      # @param [Vagrant::Environment] env
+      # @param [Symbol] name Name of the clone
+      # @param [Symbol] provider
+      # @return [Boolean] a machine with the name exists
+      def clone_exists?(env, name, provider)
+        return true if env.clones.key?(name)
+        return false if !env.machine_names.include?(name)
+
+        env.machine(name, provider).state.id != Vagrant::MachineState::NOT_CREATED_ID
+      end
+
+      # Copy the data of the source which is valid for the clone, and mark
+      # the clone so the identity of its guest is regenerated when it is
+      # first booted.
+      #
+      # @param [Vagrant::Machine] source
+      # @param [Vagrant::Machine] machine
+      def copy_data(source, machine)
+        DATA_FILES.each do |file|
+          path = source.data_dir.join(file)
+          FileUtils.cp(path, machine.data_dir.join(file), preserve: true) if path.file?
+        end
+
+        # The clone is provisioned like the source, so it is only
+        # provisioned again if the source was not provisioned
+        if source.data_dir.join("action_provision").file?
+          machine.data_dir.join("action_provision").write("1.5:#{machine.id}")
+        end
+
+        machine.data_dir.join(RegenerateMachineId::SENTINEL).write(Time.now.to_i.to_s)
+      end
+    end
+  end
+end
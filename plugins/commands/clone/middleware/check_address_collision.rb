@@ -0,0 +1,70 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require "log4r"
+
+module VagrantPlugins
+  module CommandClone
+    # Stops a clone from booting while a machine it shares its
+    # configuration with is running with the same static address, since
+    # static addresses of private and public networks can not be
+    # corrected like forwarded ports. The machines share their
+    # configuration if one was cloned from the other, or if both were
+    # cloned from the same machine.
+    class CheckAddressCollision
+      def initialize(app, env)
+        @app = app
+        @logger = Log4r::Logger.new("vagrant::clone::check_address_collision")
+      end
+
+      def call(env)
+        machine = env[:machine]
+        check(machine) if machine.state.id != :running
+
+        @app.call(env)
+      end
+
+      protected
+
+      # @param [Vagrant::Machine] machine
+      # @raise [Vagrant::Errors::CloneAddressCollision]
+      def check(machine)
+        addresses = static_addresses(machine)
+        return if addresses.empty?
+
+        related(machine).each do |name|
+          other = machine.env.machine(name, machine.provider_name)
+          next if other.state.id != :running
+
+          address = (static_addresses(other) & addresses).first
+          next if !address
+
+          raise Vagrant::Errors::CloneAddressCollision,
+            name: machine.name.to_s,
+            other: name.to_s,
+            address: address
+        end
+      end
+
+      # @param [Vagrant::Machine] machine
+      # @return [Array<Symbol>] names of the machines which share their
+      #   configuration with the machine
+      def related(machine)
+        clones = machine.env.clones
+        source = clones.fetch(machine.name, machine.name)
+        names = [source] + clones.select { |_, s| s == source }.keys
+        names - [machine.name]
+      end
+
+      # @param [Vagrant::Machine] machine
+      # @return [Array<String>] static IP and MAC addresses of the networks
+      def static_addresses(machine)
+        machine.config.vm.networks.flat_map do |type, options|
+          next [] if type != :private_network && type != :public_network
+
+          [options[:ip], options[:mac]].compact.map(&:to_s)
+        end
+      end
+    end
+  end
+end
@@ -0,0 +1,40 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require "log4r"
+
+module VagrantPlugins
+  module CommandClone
+    # Regenerates the machine ID of the guest the first time a clone is
+    # booted, since the guest of the clone has the same machine ID as the
+    # guest of the machine it was cloned from. Services such as DHCP
+    # clients identify the guest by its machine ID.
+    class RegenerateMachineId
+      # Name of the file within the data directory which marks that the
+      # guest of the clone has not been booted yet
+      SENTINEL = "action_clone".freeze
+
+      def initialize(app, env)
+        @app = app
+        @logger = Log4r::Logger.new("vagrant::clone::regenerate_machine_id")
+      end
+
+      def call(env)
+        @app.call(env)
+
+        machine = env[:machine]
+        sentinel = machine.data_dir.join(SENTINEL)
+        return if !sentinel.file? || !machine.communicate.ready?
+
+        if machine.guest.capability?(:regenerate_machine_id)
+          env[:ui].info(I18n.t("vagrant.commands.clone.regenerating_machine_id"))
+          machine.guest.capability(:regenerate_machine_id)
+        else
+          @logger.info("Guest can not regenerate its machine ID: #{machine.name}")
+        end
+
+        sentinel.delete
+      end
+    end
+  end
+end
@@ -0,0 +1,28 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require "log4r"
+
+module VagrantPlugins
+  module CommandClone
+    # Removes a clone from the project once it is destroyed, so it is no
+    # longer defined by the Vagrantfile.
+    class UnregisterClone
+      def initialize(app, env)
+        @app = app
+        @logger = Log4r::Logger.new("vagrant::clone::unregister_clone")
+      end
+
+      def call(env)
+        @app.call(env)
+
+        machine = env[:machine]
+        return if machine.state.id != Vagrant::MachineState::NOT_CREATED_ID
+        return if !machine.env.clones.key?(machine.name)
+
+        @logger.info("Removing destroyed clone: #{machine.name}")
+        machine.env.unregister_clone(machine.name)
+      end
+    end
+  end
+end
@@ -0,0 +1,36 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require "vagrant"
+
+module VagrantPlugins
+  module CommandClone
+    class Plugin < Vagrant.plugin("2")
+      name "clone command"
+      description <<-DESC
+      The `clone` command creates a new machine from the current
+      state of an existing machine.
+      DESC
+
+      command("clone") do
+        require File.expand_path("../command", __FILE__)
+        Command
+      end
+
+      action_hook(:check_address_collision, :machine_action_up) do |hook|
+        require_relative "middleware/check_address_collision"
+        hook.prepend(CheckAddressCollision)
+      end
+
+      action_hook(:regenerate_machine_id, :machine_action_up) do |hook|
+        require_relative "middleware/regenerate_machine_id"
+        hook.append(RegenerateMachineId)
+      end
+
+      action_hook(:unregister_clone, :machine_action_destroy) do |hook|
+        require_relative "middleware/unregister_clone"
+        hook.append(UnregisterClone)
+      end
+    end
+  end
+end
@@ -0,0 +1,101 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require 'optparse'
+
+module VagrantPlugins
+  module CommandConfig
+    class Command < Vagrant.plugin("2", :command)
+      def self.synopsis
+        "inspects the loaded configuration"
+      end
+
+      def execute
+        options = {}
+
+        opts = OptionParser.new do |o|
+          o.banner = "Usage: vagrant config --explain KEY [options] [name|id]"
+          o.separator ""
+          o.separator "Inspects the loaded Vagrantfile configuration"
+          o.separator ""
+          o.separator "Options:"
+          o.separator ""
+
+          o.on("--explain KEY", String, "Show where the value of KEY (e.g. vm.box_url) was set") do |k|
+            options[:explain] = k
+          end
+        end
+
+        # Parse the options
+        argv = parse_options(opts)
+        return if !argv
+
+        if !options[:explain]
+          raise Vagrant::Errors::CLIInvalidUsage,
+            help: opts.help.chomp
+        end
+
+        key = options[:explain]
+        namespace, attribute = key.split(".", 2)
+        if attribute.to_s.empty?
+          raise Vagrant::Errors::ConfigKeyInvalid, key: key
+        end
+
+        with_target_vms(argv) do |machine|
+          locations = machine.vagrantfile.config_provenance(
+            key, machine.name, machine.provider_name, @env.boxes, machine.data_dir)
+
+          if locations.empty?
+            machine.ui.info(I18n.t("vagrant.commands.config.not_set", key: key))
+            next
+          end
+
+          # Entries of hashes are given as "namespace.attribute.key"
+          attribute, entry = attribute.split(".", 2)
+          value = nil
+          instance = machine.config.__internal_state["keys"][namespace.to_sym]
+          # Settings of the provider are given as "provider.setting"
+          instance ||= machine.provider_config if namespace == machine.provider_name.to_s
+          if instance && attribute =~ /\A\w+\z/
+            value = instance.instance_variable_get(:"@#{attribute}")
+            if entry
+              value = value.is_a?(Hash) ? value.fetch(entry) { value[entry.to_sym] } : nil
+            end
+          end
+
+          names = locations.map { |l| location_name(l) }
+          location = names.first
+          if names.length > 1
+            location = I18n.t("vagrant.commands.config.multiple",
+              locations: names.join(", "))
+          end
+
+          machine.ui.info(I18n.t("vagrant.commands.config.explain",
+            key: key,
+            value: value.inspect,
+            location: location))
+          machine.ui.machine("config-provenance", key, *locations.map(&:to_s))
+        end
+
+        # Success, exit status 0
+        0
+      end
+
+      protected
+
+      # Returns a human readable name for a configuration location
+      #
+      # @param [Symbol] location Location as reported by the Vagrantfile
+      # @return [String]
+      def location_name(location)
+        type, name = location.to_s.split(":", 2)
+        if type == "profile" && name
+          return I18n.t("vagrant.commands.config.locations.profile", name: name)
+        end
+
+        I18n.t("vagrant.commands.config.locations.#{location}",
+          default: location.to_s)
+      end
+    end
+  end
+end
@@ -0,0 +1,20 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require "vagrant"
+
+module VagrantPlugins
+  module CommandConfig
+    class Plugin < Vagrant.plugin("2")
+      name "config command"
+      description <<-DESC
+      The `config` command inspects the loaded Vagrantfile configuration.
+      DESC
+
+      command("config") do
+        require File.expand_path("../command", __FILE__)
+        Command
+      end
+    end
+  end
+end
@@ -0,0 +1,75 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require 'json'
+require 'optparse'
+
+module VagrantPlugins
+  module CommandDebug
+    module Command
+      class ProviderState < Vagrant.plugin("2", :command)
+        # Supported output formats
+        FORMATS = ["text", "json"].freeze
+
+        def execute
+          options = {format: "text"}
+          opts = OptionParser.new do |o|
+            o.banner = "Usage: vagrant debug provider-state [options] [name|id]"
+            o.separator ""
+            o.separator "Shows the provider specific state stored for each machine."
+            o.separator ""
+            o.separator "Options:"
+            o.separator ""
+
+            o.on("--format FORMAT", String, "Output format (#{FORMATS.join(", ")})") do |f|
+              options[:format] = f.downcase
+            end
+          end
+
+          # Parse the options
+          argv = parse_options(opts)
+          return if !argv
+
+          if !FORMATS.include?(options[:format])
+            raise Vagrant::Errors::CLIInvalidUsage,
+              help: opts.help.chomp
+          end
+
+          result = []
+          with_target_vms(argv) do |machine|
+            state = machine.provider_state ? machine.provider_state.to_h : {}
+            if options[:format] == "json"
+              result << {
+                name: machine.name.to_s,
+                provider: machine.provider_name.to_s,
+                state: state,
+              }
+              next
+            end
+
+            if state.empty?
+              machine.ui.info(I18n.t("vagrant.commands.debug.provider_state.none"))
+              next
+            end
+
+            machine.ui.info(I18n.t("vagrant.commands.debug.provider_state.header",
+              provider: machine.provider_name.to_s))
+            state.keys.sort.each do |key|
+              machine.ui.detail(I18n.t("vagrant.commands.debug.provider_state.entry",
+                key: key,
+                type: state[key]["type"],
+                value: JSON.generate(state[key]["value"])))
+            end
+          end
+
+          if options[:format] == "json"
+            @env.ui.info(JSON.pretty_generate(result), prefix: false)
+          end
+
+          # Success, exit status 0
+          0
+        end
+      end
+    end
+  end
+end
@@ -0,0 +1,21 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require "vagrant"
+
+module VagrantPlugins
+  module CommandDebug
+    class Plugin < Vagrant.plugin("2")
+      name "debug command"
+      description <<-DESC
+      The `debug` command shows internal state of machines to help debug
+      Vagrant and its plugins.
+      DESC
+
+      command("debug", primary: false) do
+        require_relative "command/root"
+        Command::Root
+      end
+    end
+  end
+end
@@ -0,0 +1,95 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require "optparse"
+
+require_relative "diagnostics"
+
+module VagrantPlugins
+  module CommandDoctor
+    class Command < Vagrant.plugin("2", :command)
+      # Exit status for the most severe severity found
+      EXIT_STATUSES = {
+        info: 0,
+        warning: 1,
+        error: 2,
+      }.freeze
+
+      def self.synopsis
+        "diagnoses common problems with the Vagrant environment"
+      end
+
+      def execute
+        options = {}
+
+        opts = OptionParser.new do |o|
+          o.banner = "Usage: vagrant doctor [options]"
+          o.separator ""
+          o.separator "Options:"
+          o.separator ""
+
+          o.on("--fix", "Fix the problems which are safe to fix") do |f|
+            options[:fix] = f
+          end
+        end
+
+        # Parse the options
+        argv = parse_options(opts)
+        return if !argv
+        raise Vagrant::Errors::CLIInvalidUsage, help: opts.help.chomp if argv.length > 0
+
+        @env.ui.info(I18n.t("vagrant.commands.doctor.running"))
+        findings = Diagnostics.new(@env).run
+        findings = fix(findings) if options[:fix]
+
+        findings.each { |f| report(f) }
+
+        severity = Diagnostics.worst_severity(findings) || :info
+        if severity == :info
+          @env.ui.success(I18n.t("vagrant.commands.doctor.no_problems"))
+        elsif findings.any?(&:fixable?)
+          @env.ui.info(I18n.t("vagrant.commands.doctor.fixable",
+            count: findings.count(&:fixable?)))
+        end
+
+        EXIT_STATUSES[severity]
+      end
+
+      protected
+
+      # Fix the findings which are safe to fix
+      #
+      # @param [Array<Diagnostics::Finding>] findings
+      # @return [Array<Diagnostics::Finding>] findings which were not fixed
+      def fix(findings)
+        findings.reject do |finding|
+          next false if !finding.fixable?
+
+          finding.fix.call
+          @env.ui.success(I18n.t("vagrant.commands.doctor.fixed",
+            message: finding.message))
+          true
+        end
+      end
+
+      # @param [Diagnostics::Finding] finding
+      def report(finding)
+        message = I18n.t("vagrant.commands.doctor.severities.#{finding.severity}",
+          message: finding.message)
+        if !finding.suggestion.to_s.empty?
+          message += "\n" + I18n.t("vagrant.commands.doctor.suggestion",
+            suggestion: finding.suggestion)
+        end
+
+        case finding.severity
+        when :error
+          @env.ui.error(message)
+        when :warning
+          @env.ui.warn(message)
+        else
+          @env.ui.info(message)
+        end
+      end
+    end
+  end
+end
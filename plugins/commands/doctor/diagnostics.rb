@@ -0,0 +1,265 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require "log4r"
+
+module VagrantPlugins
+  module CommandDoctor
+    # This diagnoses common problems with the environment of the user,
+    # such as entries of the machine index for machines which no longer
+    # exist. Each check only reads the state of the environment. Problems
+    # which are safe to fix are found with a fix, which is run separately.
+    class Diagnostics
+      # Severities of findings, from the least to the most severe
+      SEVERITIES = [:info, :warning, :error].freeze
+
+      # A problem found by a check. The fix is a callable which fixes the
+      # problem, and is only set if fixing it is safe.
+      Finding = Struct.new(:severity, :message, :suggestion, :fix) do
+        def fixable?
+          !!fix
+        end
+      end
+
+      CHECKS = [
+        :check_data_dirs,
+        :check_plugins,
+        :check_providers,
+        :check_machine_index,
+      ].freeze
+
+      def initialize(env)
+        @env = env
+        @logger = Log4r::Logger.new("vagrant::command::doctor::diagnostics")
+        @usable = {}
+        @machine_ids = {}
+        @created_machine_ids = {}
+      end
+
+      # Run all the checks
+      #
+      # @return [Array<Finding>]
+      def run
+        CHECKS.flat_map do |name|
+          begin
+            send(name)
+          rescue Vagrant::Errors::VagrantError => e
+            @logger.debug("Check #{name} failed: #{e}")
+            [finding(:error, :check_failed, check: name.to_s.sub("check_", ""),
+              message: e.message)]
+          end
+        end
+      end
+
+      # @param [Array<Finding>] findings
+      # @return [Symbol, nil] the most severe severity of the findings
+      def self.worst_severity(findings)
+        findings.map(&:severity).max_by { |s| SEVERITIES.index(s) }
+      end
+
+      protected
+
+      # The data directories must be writable, and should be owned by the
+      # user so files created within them are also owned by the user.
+      #
+      # @return [Array<Finding>]
+      def check_data_dirs
+        paths = [@env.home_path, @env.data_dir, @env.boxes_path,
+          @env.tmp_path, @env.local_data_path]
+        paths.compact.uniq.map do |path|
+          next if !path.exist?
+
+          if !path.writable?
+            finding(:error, :data_dir_not_writable, path: path.to_s)
+          elsif !Vagrant::Util::Platform.windows? && !path.owned?
+            finding(:warning, :data_dir_not_owned, path: path.to_s)
+          end
+        end.compact
+      end
+
+      # The installed plugins must have their gems installed, with the
+      # versions recorded when they were installed.
+      #
+      # @return [Array<Finding>]
+      def check_plugins
+        manager = Vagrant::Plugin::Manager.instance
+        specs = manager.installed_specs.map { |spec| [spec.name, spec] }.to_h
+
+        manager.installed_plugins.map do |name, info|
+          spec = specs[name]
+          if !spec
+            next finding(:error, :plugin_missing, name: name)
+          end
+
+          recorded = info["installed_gem_version"]
+          next if !recorded || recorded == spec.version.to_s
+
+          finding(:warning, :plugin_version_drift, name: name,
+            recorded: recorded, found: spec.version.to_s)
+        end.compact
+      end
+
+      # At least one provider must be usable, and the default provider
+      # given by the user must be usable.
+      #
+      # @return [Array<Finding>]
+      def check_providers
+        findings = []
+        usable = providers.keys.select { |name| usable?(name) }
+        if usable.empty?
+          findings << finding(:error, :no_usable_providers)
+        else
+          findings << finding(:info, :usable_providers,
+            providers: usable.map(&:to_s).sort.join(", "))
+        end
+
+        default = ENV["VAGRANT_DEFAULT_PROVIDER"].to_s
+        if !default.empty? && !usable.include?(default.to_sym)
+          findings << finding(:error, :default_provider_unusable,
+            provider: default, reason: unusable_reason(default.to_sym))
+        end
+
+        findings
+      end
+
+      # Cross reference the machine index with the machines reported by
+      # the providers. Entries for machines which the provider reports do
+      # not exist are stale and are pruned by the fix. Entries for
+      # machines whose Vagrantfile directory was removed are only
+      # reported, since the machine may still exist. Machines created by
+      # Vagrant which are not in the index are orphans and must be
+      # destroyed manually.
+      #
+      # @return [Array<Finding>]
+      def check_machine_index
+        findings = []
+        indexed = Hash.new { |h, k| h[k] = [] }
+
+        @env.machine_index.each do |entry|
+          machine_id = entry_machine_id(entry)
+          indexed[entry.provider.to_s.to_sym] << machine_id if machine_id
+
+          opts = {name: entry.name.to_s, id: entry.id.to_s[0...7],
+            path: entry.vagrantfile_path.to_s}
+          if machine_missing?(entry, machine_id)
+            stale = finding(:warning, :stale_index_entry, **opts,
+              reason: I18n.t("vagrant.commands.doctor.reasons.machine_missing",
+                provider: entry.provider.to_s))
+            stale.fix = proc { prune_entry(entry) }
+            findings << stale
+          elsif !entry.vagrantfile_path || !entry.vagrantfile_path.directory?
+            findings << finding(:warning, :vagrantfile_missing, **opts)
+          end
+        end
+
+        providers.keys.each do |provider|
+          ids = created_machine_ids(provider)
+          next if !ids
+
+          (ids - indexed[provider]).each do |id|
+            findings << finding(:warning, :orphaned_machine,
+              provider: provider.to_s, id: id)
+          end
+        end
+
+        findings
+      end
+
+      # @return [Boolean] the provider confirms the machine of the entry
+      #   no longer exists
+      def machine_missing?(entry, machine_id)
+        return false if !machine_id
+
+        ids = machine_ids(entry.provider.to_s.to_sym)
+        !!ids && !ids.include?(machine_id)
+      end
+
+      # @return [String, nil] the provider specific ID of the machine of
+      #   the entry, read from its data directory
+      def entry_machine_id(entry)
+        return if !entry.local_data_path
+
+        path = entry.local_data_path.join(
+          "machines", entry.name.to_s, entry.provider.to_s, "id")
+        return if !path.file?
+
+        id = path.read.strip
+        id.empty? ? nil : id
+      end
+
+      # Remove the entry from the machine index
+      def prune_entry(entry)
+        deletable = @env.machine_index.get(entry.id)
+        @env.machine_index.delete(deletable) if deletable
+      end
+
+      # @return [Vagrant::Registry] the registered providers
+      def providers
+        @providers ||= Vagrant.plugin("2").manager.providers
+      end
+
+      # @return [Boolean] the provider is usable
+      def usable?(provider)
+        return @usable[provider] if @usable.key?(provider)
+
+        # Some providers raise an error even when not asked to
+        klass, _ = providers[provider]
+        @usable[provider] = begin
+          !!(klass && klass.usable?(false))
+        rescue Vagrant::Errors::VagrantError
+          false
+        end
+      end
+
+      # @return [String] the reason the provider is not usable
+      def unusable_reason(provider)
+        klass, _ = providers[provider]
+        return I18n.t("vagrant.commands.doctor.reasons.provider_unknown") if !klass
+
+        klass.usable?(true)
+        ""
+      rescue Vagrant::Errors::VagrantError => e
+        e.message
+      end
+
+      # @return [Array<String>, nil] the IDs of all the machines of the
+      #   provider, or nil if they are not known
+      def machine_ids(provider)
+        return @machine_ids[provider] if @machine_ids.key?(provider)
+
+        @machine_ids[provider] = nil
+        return if !usable?(provider)
+
+        klass, _ = providers[provider]
+        @machine_ids[provider] = klass.machine_ids
+      rescue Vagrant::Errors::VagrantError => e
+        @logger.debug("Failed to read the machines of #{provider}: #{e}")
+        nil
+      end
+
+      # @return [Array<String>, nil] the IDs of the machines created by
+      #   Vagrant with the provider, or nil if they are not known
+      def created_machine_ids(provider)
+        return @created_machine_ids[provider] if @created_machine_ids.key?(provider)
+
+        @created_machine_ids[provider] = nil
+        return if !usable?(provider)
+
+        klass, _ = providers[provider]
+        return if !klass.respond_to?(:created_machine_ids)
+
+        @created_machine_ids[provider] = klass.created_machine_ids(@env)
+      rescue Vagrant::Errors::VagrantError => e
+        @logger.debug("Failed to read the machines created by #{provider}: #{e}")
+        nil
+      end
+
+      def finding(severity, key, **opts)
+        Finding.new(severity,
+          I18n.t("vagrant.commands.doctor.findings.#{key}.message", **opts),
+          I18n.t("vagrant.commands.doctor.findings.#{key}.suggestion",
+            **opts, default: ""))
+      end
+    end
+  end
+end
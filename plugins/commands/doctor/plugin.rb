@@ -0,0 +1,21 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require "vagrant"
+
+module VagrantPlugins
+  module CommandDoctor
+    class Plugin < Vagrant.plugin("2")
+      name "doctor command"
+      description <<-DESC
+      The `doctor` command diagnoses common problems with the Vagrant
+      environment of the user, and fixes the ones which are safe to fix.
+      DESC
+
+      command("doctor") do
+        require File.expand_path("../command", __FILE__)
+        Command
+      end
+    end
+  end
+end
@@ -0,0 +1,180 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require "fileutils"
+require "json"
+require "optparse"
+require "tmpdir"
+
+module VagrantPlugins
+  module CommandExport
+    class Command < Vagrant.plugin("2", :command)
+      def self.synopsis
+        "exports a machine to a box"
+      end
+
+      def execute
+        options = {}
+
+        opts = OptionParser.new do |o|
+          o.banner = "Usage: vagrant export [options] [name|id]"
+          o.separator ""
+          o.separator "Options:"
+          o.separator ""
+
+          o.on("-o", "--output FILE", "File to write the box to, or - to write it to stdout") do |output|
+            options[:output] = output
+          end
+
+          o.on("-f", "--force", "Export a running machine without suspending it") do |f|
+            options[:force] = f
+          end
+        end
+
+        # Parse the options
+        argv = parse_options(opts)
+        return if !argv
+
+        with_target_vms(argv, single_target: true) do |machine|
+          export(machine, options)
+        end
+
+        # Success, exit status 0
+        0
+      end
+
+      protected
+
+      # Export the machine to a box
+      #
+      # @param [Vagrant::Machine] machine
+      # @param [Hash] options
+      def export(machine, options)
+        if machine.state.id == Vagrant::MachineState::NOT_CREATED_ID
+          raise Vagrant::Errors::VMNotCreatedError
+        end
+
+        if !machine.provider.capability?(:export_machine)
+          raise Vagrant::Errors::ExportMachineUnsupported,
+            name: machine.name.to_s,
+            provider: machine.provider_name.to_s
+        end
+
+        output = options[:output] || "#{machine.name}.box"
+        stream = output == "-"
+        if !stream
+          output = File.expand_path(output)
+          if File.exist?(output)
+            raise Vagrant::Errors::ExportOutputExists, path: output
+          end
+        end
+
+        directory = Dir.mktmpdir("vagrant-export-", @env.tmp_path.to_s)
+        begin
+          with_stdout(stream) do |stdout|
+            export_files(machine, directory, options)
+
+            machine.ui.info(I18n.t("vagrant.commands.export.compressing"))
+            if stream
+              compress(machine, directory, stdout)
+              machine.ui.success(I18n.t("vagrant.commands.export.exported_stdout"))
+            else
+              write_file(output) { |f| compress(machine, directory, f) }
+              machine.ui.success(I18n.t("vagrant.commands.export.exported", path: output))
+            end
+          end
+        ensure
+          FileUtils.rm_rf(directory)
+        end
+      end
+
+      # Write the box files of the machine to the directory. Running
+      # machines are suspended while they are exported unless forced.
+      #
+      # @param [Vagrant::Machine] machine
+      # @param [String] directory
+      # @param [Hash] options
+      def export_files(machine, directory, options)
+        suspended = false
+        if machine.state.id == :running && !options[:force]
+          machine.ui.info(I18n.t("vagrant.commands.export.pausing"))
+          begin
+            machine.action(:suspend)
+          rescue Vagrant::Errors::UnimplementedProviderAction
+            raise Vagrant::Errors::ExportMachineRunning,
+              name: machine.name.to_s
+          end
+          suspended = true
+        end
+
+        begin
+          machine.ui.info(I18n.t("vagrant.commands.export.exporting"))
+          machine.provider.capability(:export_machine, directory)
+        ensure
+          machine.action(:resume) if suspended
+        end
+
+        Vagrant::Action::General::Package.setup_private_key(machine, directory)
+
+        # The provider is needed to add the box
+        metadata = File.join(directory, "metadata.json")
+        if !File.exist?(metadata)
+          File.write(metadata, JSON.dump(provider: machine.provider_name))
+        end
+      end
+
+      # Write a gzipped tar archive of the files in the directory to
+      # the stream
+      #
+      # @param [Vagrant::Machine] machine
+      # @param [String] directory
+      # @param [IO] io
+      def compress(machine, directory, io)
+        files = Dir.children(directory).sort
+        IO.popen(["bsdtar", "-czf", "-", "-C", directory, *files], "rb") do |tar|
+          IO.copy_stream(tar, io)
+        end
+        io.flush
+
+        if !$?.success?
+          raise Vagrant::Errors::ExportArchiveFailed,
+            name: machine.name.to_s
+        end
+      rescue Errno::ENOENT
+        raise Vagrant::Errors::ExportArchiveFailed,
+          name: machine.name.to_s
+      end
+
+      # Open the file for writing, removing it if writing fails
+      #
+      # @param [String] path
+      def write_file(path)
+        File.open(path, "wb") { |f| yield f }
+      rescue Exception
+        FileUtils.rm_f(path)
+        raise
+      end
+
+      # When the box is written to stdout, everything else written to
+      # stdout is written to stderr instead so it does not end up within
+      # the box. The stream for the box is yielded.
+      #
+      # @param [Boolean] stream The box is written to stdout
+      def with_stdout(stream)
+        return yield $stdout if !stream
+
+        stdout = $stdout.dup
+        stdout.binmode
+        $stdout.flush
+        $stdout.reopen($stderr)
+        begin
+          yield stdout
+        ensure
+          $stdout.flush
+          $stdout.reopen(stdout)
+          stdout.close
+        end
+      end
+    end
+  end
+end
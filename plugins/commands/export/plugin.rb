@@ -0,0 +1,21 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require "vagrant"
+
+module VagrantPlugins
+  module CommandExport
+    class Plugin < Vagrant.plugin("2")
+      name "export command"
+      description <<-DESC
+      The `export` command exports a machine to a box which can be
+      added with `vagrant box add`.
+      DESC
+
+      command("export") do
+        require File.expand_path("../command", __FILE__)
+        Command
+      end
+    end
+  end
+end
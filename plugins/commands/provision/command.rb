@@ -15,12 +15,20 @@ module VagrantPlugins
         options[:provision_types] = nil
 
         opts = OptionParser.new do |o|
-          o.banner = "Usage: vagrant provision [vm-name] [--provision-with x,y,z]"
+          o.banner = "Usage: vagrant provision [vm-name] [--provision-with x,y,z] [--dry-run] [--force]"
 
           o.on("--provision-with x,y,z", Array,
                     "Enable only certain provisioners, by type or by name.") do |list|
             options[:provision_types] = list.map { |type| type.to_sym }
           end
+
+          o.on("--dry-run", "Show what the provisioners would do without running them") do |d|
+            options[:provision_dry_run] = d
+          end
+
+          o.on("-f", "--force", "Run provisioners even if they are unchanged since their last run") do |f|
+            options[:provision_force] = f
+          end
         end
 
         # Parse the options
@@ -5,14 +5,19 @@ require 'optparse'
 
 require "vagrant"
 
+require Vagrant.source_root.join("plugins/commands/up/resource_mixins")
 require Vagrant.source_root.join("plugins/commands/up/start_mixins")
 
+require_relative "scope_mixins"
+
 module VagrantPlugins
   module CommandReload
     class Command < Vagrant.plugin("2", :command)
       # We assume that the `up` plugin exists and that we'll have access
       # to this.
+      include VagrantPlugins::CommandUp::ResourceMixins
       include VagrantPlugins::CommandUp::StartMixins
+      include ScopeMixins
 
       def self.synopsis
         "restarts vagrant machine, loads new Vagrantfile configuration"
@@ -21,6 +26,7 @@ module VagrantPlugins
       def execute
         options = {}
         options[:provision_ignore_sentinel] = false
+        options[:reload_scopes] = []
 
         opts = OptionParser.new do |o|
           o.banner = "Usage: vagrant reload [vm-name]"
@@ -29,6 +35,12 @@ module VagrantPlugins
           o.on("-f", "--force", "Force shut down (equivalent of pulling power)") do |f|
             options[:force_halt] = f
           end
+          o.on("--only-synced-folders", "Only apply synced folder changes to the running machine") do |_|
+            options[:reload_scopes] << :synced_folders
+          end
+          o.on("--only-networks", "Only apply network changes to the running machine") do |_|
+            options[:reload_scopes] << :networks
+          end
         end
 
         # Parse the options
@@ -38,11 +50,33 @@ module VagrantPlugins
         # Validate the provisioners
         validate_provisioner_flags!(options, argv)
 
+        # Only parts of the configuration are applied to the running
+        # machine, so it can not be restarted or provisioned
+        if !options[:reload_scopes].empty? &&
+            (options[:force_halt] || options.key?(:provision_enabled))
+          raise Vagrant::Errors::CLIInvalidUsage,
+            help: opts.help.chomp
+        end
+
         @logger.debug("'reload' each target VM...")
         machines = []
         with_target_vms(argv) do |machine|
+          if !options[:reload_scopes].empty?
+            reload_scopes(machine, options[:reload_scopes])
+            next
+          end
+
           machines << machine
+
+          # If only the resources of the machine have changed, attempt
+          # to apply them without restarting the machine
+          if !options.key?(:provision_enabled) && !options[:force_halt]
+            next if modify_resources(machine)
+          end
+
           machine.action(:reload, options)
+          store_resources(machine)
+          store_networks(machine)
         end
 
         # Output the post-up messages that we have, if any
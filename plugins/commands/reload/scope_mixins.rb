@@ -0,0 +1,125 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require "json"
+
+module VagrantPlugins
+  module CommandReload
+    # This module applies only part of the configuration of a machine to
+    # the running machine, for example only the synced folders, instead
+    # of restarting the machine.
+    #
+    # Synced folders are applied with the `sync_folders` provider action
+    # which only mounts the folders which changed since they were last
+    # applied. Networks are applied with the `reload_networks` provider
+    # action, which receives the networks the machine was started with
+    # as `:networks_previous`. If the provider does not implement the
+    # action the machine must be restarted to apply network changes.
+    module ScopeMixins
+      # Name of the file within the machine data directory which stores
+      # the networks the machine was started with
+      NETWORKS_FILE = "networks".freeze
+
+      # Parts of the configuration which can be reloaded, in the order
+      # they are applied
+      SCOPES = [:networks, :synced_folders].freeze
+
+      # Record the configured networks of the machine
+      #
+      # @param [Vagrant::Machine] machine
+      def store_networks(machine)
+        return if machine.id.nil?
+
+        machine.data_dir.join(NETWORKS_FILE).write(
+          JSON.dump(configured_networks(machine)))
+      end
+
+      # Apply the given parts of the configuration to the running machine
+      #
+      # @param [Vagrant::Machine] machine
+      # @param [Array<Symbol>] scopes Parts of the configuration to apply
+      def reload_scopes(machine, scopes)
+        if machine.state.id != :running
+          raise Vagrant::Errors::ReloadNotRunning,
+            name: machine.name.to_s
+        end
+
+        SCOPES.each do |scope|
+          next if !scopes.include?(scope)
+
+          send("reload_#{scope}", machine)
+        end
+      end
+
+      # Mount and unmount the synced folders which changed since they
+      # were last applied to the running machine
+      #
+      # @param [Vagrant::Machine] machine
+      def reload_synced_folders(machine)
+        machine.ui.output(I18n.t("vagrant.commands.reload.scope.synced_folders"))
+        action_env = { synced_folders_reload: true }
+        begin
+          machine.action(:sync_folders, action_env)
+        rescue Vagrant::Errors::UnimplementedProviderAction
+          callable = Vagrant::Action::Builder.new
+          callable.use Vagrant::Action::Builtin::SyncedFolders
+          machine.action_raw(:sync_folders, callable, action_env)
+        end
+      end
+
+      # Apply network changes to the running machine
+      #
+      # @param [Vagrant::Machine] machine
+      def reload_networks(machine)
+        previous = stored_networks(machine)
+        if previous == configured_networks(machine)
+          machine.ui.info(I18n.t("vagrant.commands.reload.scope.networks_unchanged"))
+          return
+        end
+
+        machine.ui.output(I18n.t("vagrant.commands.reload.scope.networks"))
+        begin
+          machine.action(:reload_networks, networks_previous: previous)
+        rescue Vagrant::Errors::UnimplementedProviderAction
+          raise Vagrant::Errors::ReloadNetworksRebootRequired,
+            name: machine.name.to_s,
+            reason: I18n.t("vagrant.commands.reload.scope.networks_provider_unsupported",
+              provider: machine.provider_name.to_s)
+        end
+
+        store_networks(machine)
+      end
+
+      protected
+
+      # Forwarded ports are not included since they are not configured
+      # within the guest. Network IDs are not included since they are
+      # generated each time the configuration is loaded.
+      #
+      # @param [Vagrant::Machine] machine
+      # @return [Array] configured networks of the machine
+      def configured_networks(machine)
+        networks = machine.config.vm.networks.map do |type, options|
+          next if type == :forwarded_port
+
+          [type, options.reject { |key, _| key == :id }]
+        end.compact
+
+        JSON.parse(JSON.dump(networks))
+      end
+
+      # @param [Vagrant::Machine] machine
+      # @return [Array, nil] networks stored when the machine was started
+      def stored_networks(machine)
+        return if machine.id.nil?
+
+        path = machine.data_dir.join(NETWORKS_FILE)
+        return if !path.file?
+
+        JSON.parse(path.read)
+      rescue JSON::ParserError
+        nil
+      end
+    end
+  end
+end
@@ -3,6 +3,8 @@
 
 require 'optparse'
 
+require_relative "../tree_index"
+
 module VagrantPlugins
   module CommandSnapshot
     module Command
@@ -25,6 +27,7 @@ module VagrantPlugins
           end
 
           name = argv.pop
+          trees = TreeIndex.new(@env.local_data_path)
           with_target_vms(argv) do |vm|
             if !vm.provider.capability?(:snapshot_list)
               raise Vagrant::Errors::SnapshotNotSupported
@@ -34,6 +37,7 @@ module VagrantPlugins
 
             if snapshot_list.include? name
               vm.action(:snapshot_delete, snapshot_name: name)
+              trees.remove(name, vm.name)
             else
               raise Vagrant::Errors::SnapshotNotFound,
                 snapshot_name: name,
@@ -3,6 +3,8 @@
 
 require 'optparse'
 
+require_relative "../tree_index"
+
 module VagrantPlugins
   module CommandSnapshot
     module Command
@@ -11,13 +13,17 @@ module VagrantPlugins
           opts = OptionParser.new do |o|
             o.banner = "Usage: vagrant snapshot list [options] [vm-name]"
             o.separator ""
-            o.separator "List all snapshots taken for a machine."
+            o.separator "List all snapshots taken for a machine. Snapshots taken of"
+            o.separator "multiple machines with the same name are grouped by tree."
           end
 
           # Parse the options
           argv = parse_options(opts)
           return if !argv
 
+          index = TreeIndex.new(@env.local_data_path)
+          trees = Hash.new { |h, k| h[k] = [] }
+
           with_target_vms(argv) do |vm|
             if !vm.id
               vm.ui.info(I18n.t("vagrant.commands.common.vm_not_created"))
@@ -39,6 +45,20 @@ module VagrantPlugins
             snapshots.each do |snapshot|
               vm.ui.detail(snapshot, prefix: false)
             end
+
+            index.for_machine(vm.name).each do |tree, snapshot|
+              trees[tree] << vm.name.to_s if snapshots.include?(snapshot)
+            end
+          end
+
+          if !trees.empty?
+            @env.ui.output(I18n.t("vagrant.commands.snapshot.list.trees"))
+            trees.keys.sort.each do |tree|
+              @env.ui.detail(tree, prefix: false)
+              trees[tree].sort.each do |machine_name|
+                @env.ui.detail("  #{machine_name}", prefix: false)
+              end
+            end
           end
 
           # Success, exit status 0
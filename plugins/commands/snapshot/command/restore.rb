@@ -5,6 +5,8 @@ require 'optparse'
 
 require Vagrant.source_root.join("plugins/commands/up/start_mixins")
 
+require_relative "../tree_index"
+
 module VagrantPlugins
   module CommandSnapshot
     module Command
@@ -16,16 +18,25 @@ module VagrantPlugins
           options = {}
           options[:provision_ignore_sentinel] = false
           options[:snapshot_start] = true
+          options[:parallel] = true
 
           opts = OptionParser.new do |o|
             o.banner = "Usage: vagrant snapshot restore [options] [vm-name] <name>"
             o.separator ""
             build_start_options(o, options)
             o.separator "Restore a snapshot taken previously with snapshot save."
+            o.separator ""
+            o.separator "If no vm-name is given and the name is a snapshot tree, all"
+            o.separator "machines in the tree are restored together."
 
             o.on("--no-start", "Don't start the snapshot after the restore") do
               options[:snapshot_start] = false
             end
+
+            o.on("--[no-]parallel",
+                 "Restore snapshot trees in parallel if the provider supports it") do |parallel|
+              options[:parallel] = parallel
+            end
           end
 
           # Parse the options
@@ -42,6 +53,11 @@ module VagrantPlugins
           name = argv.pop
           options[:snapshot_name] = name
 
+          if argv.empty?
+            tree = TreeIndex.new(@env.local_data_path).get(name)
+            return restore_tree(name, tree, options) if tree
+          end
+
           with_target_vms(argv) do |vm|
             if !vm.provider.capability?(:snapshot_list)
               raise Vagrant::Errors::SnapshotNotSupported
@@ -61,6 +77,80 @@ module VagrantPlugins
           # Success, exit status 0
           0
         end
+
+        protected
+
+        # Restore all machines of a snapshot tree as a batch, so machines
+        # with a provider that supports parallelization are restored at the
+        # same time. All machines are restored even if a restore fails, and
+        # the result of each machine is reported once all restores are
+        # complete.
+        #
+        # @param [String] name Name of the tree
+        # @param [Hash] tree Machines of the tree
+        # @param [Hash] options Restore options
+        # @return [Integer] exit status
+        def restore_tree(name, tree, options)
+          results = {}
+          lock = Mutex.new
+          missing = tree.keys - @env.machine_names.map(&:to_s)
+          missing.each do |machine_name|
+            results[machine_name] = I18n.t(
+              "vagrant.commands.snapshot.restore.tree_machine_missing")
+          end
+
+          # Errors are returned by restore_machine instead of raised, so
+          # a failed restore does not cancel the restores of other machines
+          @env.batch(options[:parallel]) do |batch|
+            with_target_vms(tree.keys - missing) do |vm|
+              batch.custom(vm) do |machine|
+                error = restore_machine(machine, tree[machine.name.to_s], options)
+                lock.synchronize { results[machine.name.to_s] = error }
+              end
+            end
+          end
+
+          @env.ui.info(I18n.t("vagrant.commands.snapshot.restore.tree_summary",
+            name: name))
+          results.keys.sort.each do |machine_name|
+            if results[machine_name]
+              @env.ui.error(I18n.t("vagrant.commands.snapshot.restore.tree_machine_failed",
+                machine: machine_name, message: results[machine_name]))
+            else
+              @env.ui.success(I18n.t("vagrant.commands.snapshot.restore.tree_machine_restored",
+                machine: machine_name))
+            end
+          end
+
+          results.values.any? ? 1 : 0
+        end
+
+        # Restore the snapshot of a single machine of a tree
+        #
+        # @param [Vagrant::Machine] vm
+        # @param [Hash] info Snapshot information for the machine
+        # @param [Hash] options Restore options
+        # @return [String, nil] error message if the restore failed
+        def restore_machine(vm, info, options)
+          snapshot = info["snapshot"]
+          if !vm.provider.capability?(:snapshot_list)
+            raise Vagrant::Errors::SnapshotNotSupported
+          end
+
+          if !vm.provider.capability(:snapshot_list).include?(snapshot)
+            raise Vagrant::Errors::SnapshotNotFound,
+              snapshot_name: snapshot,
+              machine: vm.name.to_s
+          end
+
+          vm.action(:snapshot_restore, options.merge(snapshot_name: snapshot))
+          nil
+        rescue Vagrant::Errors::VagrantError => e
+          e.message
+        rescue StandardError => e
+          @logger.error("Failed to restore snapshot of #{vm.name}: #{e.class}: #{e}")
+          e.message
+        end
       end
     end
   end
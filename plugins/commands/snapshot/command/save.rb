@@ -3,6 +3,8 @@
 
 require 'optparse'
 
+require_relative "../tree_index"
+
 module VagrantPlugins
   module CommandSnapshot
     module Command
@@ -19,7 +21,10 @@ module VagrantPlugins
             o.separator "future to get back to this exact machine state."
             o.separator ""
             o.separator "If no vm-name is given, Vagrant will take a snapshot of"
-            o.separator "the entire environment with the same snapshot name."
+            o.separator "the entire environment with the same snapshot name. The"
+            o.separator "snapshots of a multi-machine environment are grouped as a"
+            o.separator "tree with this name, which can be restored together with"
+            o.separator "`vagrant snapshot restore <name>`."
             o.separator ""
             o.separator "Snapshots are useful for experimenting in a machine and being able"
             o.separator "to rollback quickly."
@@ -38,6 +43,11 @@ module VagrantPlugins
           end
 
           name = argv.pop
+          trees = TreeIndex.new(@env.local_data_path)
+
+          # Only snapshots of every machine of a multi-machine environment
+          # are recorded as a tree
+          record_tree = argv.empty? && @env.machine_names.length > 1
 
           with_target_vms(argv) do |vm|
             if !vm.provider.capability?(:snapshot_list)
@@ -64,6 +74,8 @@ module VagrantPlugins
             else
               raise Vagrant::Errors::SnapshotConflictFailed
             end
+
+            trees.add(name, vm, name) if record_tree
           end
 
           # Success, exit status 0
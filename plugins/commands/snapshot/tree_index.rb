@@ -0,0 +1,112 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require "json"
+
+require "log4r"
+
+module VagrantPlugins
+  module CommandSnapshot
+    # This class tracks snapshot trees for a project. A snapshot tree is
+    # a logical name for a set of snapshots taken across the machines of
+    # the project, mapping each machine to the snapshot taken by its
+    # provider. The index is stored within the local data path.
+    class TreeIndex
+      # Name of the file within the local data path the index is stored
+      FILENAME = "snapshot_trees.json".freeze
+
+      # Current version of the stored index
+      VERSION = 1
+
+      # @param [Pathname] data_path Local data path of the environment
+      def initialize(data_path)
+        @logger = Log4r::Logger.new("vagrant::command::snapshot::tree_index")
+        @path = data_path.join(FILENAME)
+        @trees = load
+      end
+
+      # @return [Array<String>] names of all trees
+      def names
+        @trees.keys.sort
+      end
+
+      # Get the machines of a tree
+      #
+      # @param [String] name Name of the tree
+      # @return [Hash<String, Hash>, nil] machine name to snapshot
+      #   information, or nil if the tree does not exist
+      def get(name)
+        @trees[name.to_s]
+      end
+
+      # Get the names of the trees which include a snapshot of a machine
+      #
+      # @param [String, Symbol] machine Name of the machine
+      # @return [Hash<String, String>] tree name to provider snapshot ID
+      def for_machine(machine)
+        result = {}
+        @trees.each do |name, machines|
+          info = machines[machine.to_s]
+          result[name] = info["snapshot"] if info
+        end
+        result
+      end
+
+      # Add a machine snapshot to a tree
+      #
+      # @param [String] name Name of the tree
+      # @param [Vagrant::Machine] machine Machine the snapshot was taken of
+      # @param [String] snapshot Provider snapshot ID
+      def add(name, machine, snapshot)
+        tree = (@trees[name.to_s] ||= {})
+        tree[machine.name.to_s] = {
+          "provider" => machine.provider_name.to_s,
+          "snapshot" => snapshot.to_s,
+        }
+        save
+      end
+
+      # Remove a machine from a tree. The tree is removed when it no
+      # longer includes any machines.
+      #
+      # @param [String] name Name of the tree
+      # @param [String, Symbol] machine Name of the machine
+      def remove(name, machine)
+        tree = @trees[name.to_s]
+        return if !tree
+
+        tree.delete(machine.to_s)
+        @trees.delete(name.to_s) if tree.empty?
+        save
+      end
+
+      protected
+
+      # Load the index from disk
+      #
+      # @return [Hash]
+      def load
+        return {} if !@path.file?
+
+        data = JSON.parse(@path.read)
+        if data["version"] != VERSION
+          @logger.warn("ignoring snapshot tree index with unknown version: #{data["version"]}")
+          return {}
+        end
+
+        data.fetch("trees", {})
+      rescue JSON::ParserError => e
+        @logger.warn("failed to parse snapshot tree index #{@path}: #{e}")
+        {}
+      end
+
+      # Write the index to disk
+      def save
+        @path.dirname.mkpath
+        tmp_path = @path.dirname.join(".#{FILENAME}.#{Process.pid}")
+        tmp_path.write(JSON.dump("version" => VERSION, "trees" => @trees))
+        File.rename(tmp_path, @path)
+      end
+    end
+  end
+end
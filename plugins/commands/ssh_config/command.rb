@@ -28,24 +28,39 @@ module VagrantPlugins
           o.separator "Options:"
           o.separator ""
 
-          o.on("--host NAME", "Name the host for the config") do |h|
+          o.on("--host NAME", "Name the host for the config, or prefix the names with --all") do |h|
             options[:host] = h
           end
+
+          o.on("--all", "Output the config for every machine in the project") do |a|
+            options[:all] = a
+          end
         end
 
         argv = parse_options(opts)
         return if !argv
+        raise Vagrant::Errors::CLIInvalidUsage, help: opts.help.chomp if options[:all] && !argv.empty?
 
         with_target_vms(argv) do |machine|
           ssh_info = machine.ssh_info
-          raise Vagrant::Errors::SSHNotReady if ssh_info.nil?
+          if ssh_info.nil?
+            raise Vagrant::Errors::SSHNotReady if !options[:all]
+
+            # Skip machines which are not running so the config can be
+            # generated for the rest of the project
+            machine.ui.warn(I18n.t("vagrant.commands.ssh_config.not_ready"), channel: :error)
+            next
+          end
 
           if Vagrant::Util::Platform.windows?
             ssh_info[:private_key_path] = convert_win_paths(ssh_info[:private_key_path])
           end
 
+          host_key = options[:host] || machine.name || "vagrant"
+          host_key = "#{options[:host]}-#{machine.name}" if options[:all] && options[:host]
+
           variables = {
-            host_key: options[:host] || machine.name || "vagrant",
+            host_key: host_key,
             ssh_host: ssh_info[:host],
             ssh_port: ssh_info[:port],
             ssh_user: ssh_info[:username],
@@ -55,7 +70,8 @@ module VagrantPlugins
             log_level: ssh_info[:log_level],
             forward_agent: ssh_info[:forward_agent],
             forward_x11:   ssh_info[:forward_x11],
-            proxy_command: ssh_info[:proxy_command],
+            proxy_command: ssh_info[:proxy_command] || proxy_jump_command(ssh_info[:proxy_jump]),
+            proxy_jump:    ssh_info[:proxy_jump].is_a?(String) ? ssh_info[:proxy_jump] : nil,
             ssh_command:   ssh_info[:ssh_command],
             forward_env:   ssh_info[:forward_env],
             config:        ssh_info[:config],
@@ -73,6 +89,32 @@ module VagrantPlugins
         # Success, exit status 0
         0
       end
+
+      protected
+
+      # Providers which connect to the machine through another host can
+      # provide the connection info of that host as `:proxy_jump`. A
+      # string is used as the ProxyJump destination, while a hash with
+      # the host, port, username and private keys of the jump host is
+      # converted to a ProxyCommand so the keys can be given.
+      #
+      # @param [Hash, String, nil] jump Connection info of the jump host
+      # @return [String, nil] proxy command connecting through the host
+      def proxy_jump_command(jump)
+        return if !jump.is_a?(Hash)
+
+        args = ["ssh", "-W", "%h:%p", "-q"]
+        args.concat(["-p", jump[:port].to_s]) if jump[:port]
+        Array(jump[:private_key_path]).each do |path|
+          if Vagrant::Util::Platform.windows?
+            path = Vagrant::Util::Platform.format_windows_path(path, :disable_unc)
+          end
+          args.concat(["-i", path.include?(" ") ? "\"#{path}\"" : path])
+        end
+        args.concat(["-o", "StrictHostKeyChecking=no", "-o", "UserKnownHostsFile=/dev/null"])
+        args << (jump[:username] ? "#{jump[:username]}@#{jump[:host]}" : jump[:host].to_s)
+        args.join(" ")
+      end
     end
   end
 end
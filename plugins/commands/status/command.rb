@@ -1,6 +1,7 @@
 # Copyright IBM Corp. 2010, 2025
 # SPDX-License-Identifier: BUSL-1.1
 
+require 'json'
 require 'optparse'
 
 module VagrantPlugins
@@ -10,27 +11,41 @@ module VagrantPlugins
         "outputs status of the vagrant machine"
       end
 
+      # Supported output formats
+      FORMATS = ["text", "json"].freeze
+
       def execute
+        options = {format: "text", long: false}
         opts = OptionParser.new do |o|
-          o.banner = "Usage: vagrant status [name|id]"
+          o.banner = "Usage: vagrant status [options] [name|id]"
+          o.separator ""
+          o.separator "Options:"
+          o.separator ""
+
+          o.on("--format FORMAT", String, "Output format (#{FORMATS.join(", ")})") do |f|
+            options[:format] = f.downcase
+          end
+
+          o.on("--long", "Include details read from running guests") do |l|
+            options[:long] = l
+          end
         end
 
         # Parse the options
         argv = parse_options(opts)
         return if !argv
 
-        max_name_length = 25
-        with_target_vms(argv) do |machine|
-          max_name_length = machine.name.length if machine.name.length > max_name_length
+        if !FORMATS.include?(options[:format])
+          raise Vagrant::Errors::CLIInvalidUsage,
+            help: opts.help.chomp
         end
 
-        state = nil
-        results = []
+        states = []
+        tools = {}
         with_target_vms(argv) do |machine|
-          state = machine.state if !state
           current_state = machine.state
-          results << "#{machine.name.to_s.ljust(max_name_length)} " +
-            "#{current_state.short_description} (#{machine.provider_name})"
+          states << [machine, current_state]
+          tools[machine] = guest_tools(machine) if options[:long]
 
           opts = { target: machine.name.to_s }
           @env.ui.machine("provider-name", machine.provider_name, opts)
@@ -39,9 +54,36 @@ module VagrantPlugins
           @env.ui.machine("state-human-long", current_state.long_description, opts)
         end
 
+        if options[:format] == "json"
+          output_json(states, options[:long] ? tools : nil)
+        else
+          output_text(states)
+          output_guest_tools(states, tools) if options[:long]
+        end
+
+        # Success, exit status 0
+        0
+      end
+
+      protected
+
+      # Output the machine states as a table
+      #
+      # @param [Array<Array(Vagrant::Machine, Vagrant::MachineState)>] states
+      def output_text(states)
+        max_name_length = 25
+        states.each do |machine, _|
+          max_name_length = machine.name.length if machine.name.length > max_name_length
+        end
+
+        results = states.map do |machine, current_state|
+          "#{machine.name.to_s.ljust(max_name_length)} " +
+            "#{current_state.short_description} (#{machine.provider_name})"
+        end
+
         message = nil
         if results.length == 1
-          message = state.long_description
+          message = states.first.last.long_description
         else
           message = I18n.t("vagrant.commands.status.listing")
         end
@@ -50,9 +92,89 @@ module VagrantPlugins
                             states: results.join("\n"),
                             message: message),
                      prefix: false)
+      end
 
-        # Success, exit status 0
-        0
+      # Output the guest tools versions of the machines
+      #
+      # @param [Array<Array(Vagrant::Machine, Vagrant::MachineState)>] states
+      # @param [Hash<Vagrant::Machine, Hash>] tools Result of {#guest_tools}
+      def output_guest_tools(states, tools)
+        max_name_length = 25
+        states.each do |machine, _|
+          max_name_length = machine.name.length if machine.name.length > max_name_length
+        end
+
+        results = states.map do |machine, _|
+          info = tools[machine]
+          key = "unknown"
+          if info
+            key = "match"
+            key = "mismatch" if info[:mismatch]
+            key = "missing" if info[:mismatch] == :missing
+          end
+
+          "#{machine.name.to_s.ljust(max_name_length)} " +
+            I18n.t("vagrant.commands.status.guest_tools.#{key}", **(info || {}))
+        end
+
+        @env.ui.info("", prefix: false)
+        @env.ui.info(I18n.t("vagrant.commands.status.guest_tools.header"), prefix: false)
+        @env.ui.info("", prefix: false)
+        @env.ui.info(results.join("\n"), prefix: false)
+      end
+
+      # Read the version of the guest tools installed in a running
+      # machine and the version expected by its provider
+      #
+      # @param [Vagrant::Machine] machine
+      # @return [Hash, nil] installed and expected versions and the
+      #   mismatch between them, or nil if they can not be read
+      def guest_tools(machine)
+        return if machine.id.nil? || !machine.communicate.ready?
+
+        installed, expected = Vagrant::Util::GuestTools.versions(machine)
+        return if expected.nil?
+
+        {
+          installed: installed,
+          expected: expected,
+          mismatch: Vagrant::Util::GuestTools.mismatch(installed, expected),
+        }
+      rescue Vagrant::Errors::VagrantError => e
+        @logger.debug("Failed to read guest tools version of #{machine.name}: #{e}")
+        nil
+      end
+
+      # Output the machine states as a JSON array
+      #
+      # @param [Array<Array(Vagrant::Machine, Vagrant::MachineState)>] states
+      # @param [Hash<Vagrant::Machine, Hash>, nil] tools Result of
+      #   {#guest_tools} for each machine, if requested
+      def output_json(states, tools=nil)
+        result = states.map do |machine, current_state|
+          {
+            name: machine.name.to_s,
+            provider: machine.provider_name.to_s,
+            state: current_state.id.to_s,
+            state_human_short: current_state.short_description,
+            state_human_long: current_state.long_description,
+            id: machine.id,
+            index_uuid: machine.index_uuid,
+            basis: @env.home_path.to_s,
+            project: @env.root_path.to_s,
+          }.tap do |r|
+            next if !tools
+
+            info = tools[machine] || {}
+            r[:guest_tools] = {
+              installed: info[:installed],
+              expected: info[:expected],
+              mismatch: info[:mismatch]&.to_s,
+            }
+          end
+        end
+
+        @env.ui.info(JSON.pretty_generate(result), prefix: false)
       end
     end
   end
@@ -4,12 +4,17 @@
 require 'optparse'
 require 'set'
 
+require File.expand_path("../precondition_check", __FILE__)
+require File.expand_path("../resource_mixins", __FILE__)
 require File.expand_path("../start_mixins", __FILE__)
+require Vagrant.source_root.join("plugins/commands/reload/scope_mixins")
 
 module VagrantPlugins
   module CommandUp
     class Command < Vagrant.plugin("2", :command)
+      include ResourceMixins
       include StartMixins
+      include VagrantPlugins::CommandReload::ScopeMixins
 
       def self.synopsis
         "starts and provisions the vagrant environment"
@@ -30,14 +35,29 @@ module VagrantPlugins
 
           build_start_options(o, options)
 
+          o.on("--check", "Check the machines can be brought up without",
+               "creating or changing anything") do |c|
+            options[:check] = c
+          end
+
           o.on("--[no-]destroy-on-error",
                "Destroy machine if any fatal error happens (default to true)") do |destroy|
             options[:destroy_on_error] = destroy
           end
 
-          o.on("--[no-]parallel",
-               "Enable or disable parallelism if provider supports it") do |parallel|
-            options[:parallel] = parallel
+          o.on("--[no-]parallel[=COUNT]", Integer,
+               "Enable or disable parallelism if provider supports it,",
+               "optionally limiting the number of machines started at",
+               "once (defaults to the number of CPUs)") do |parallel|
+            if parallel.is_a?(Integer)
+              if parallel < 1
+                raise Vagrant::Errors::CLIInvalidUsage,
+                  help: opts.help.chomp
+              end
+              options[:parallel_workers] = parallel
+              parallel = true
+            end
+            options[:parallel] = parallel != false
           end
 
           o.on("--provider PROVIDER", String,
@@ -45,6 +65,11 @@ module VagrantPlugins
             options[:provider] = provider
           end
 
+          o.on("--provider-order PROVIDERS", Array,
+               "Use the first usable provider of a comma separated list") do |order|
+            options[:provider_order] = order.map(&:strip).reject(&:empty?).map(&:to_sym)
+          end
+
           o.on("--[no-]install-provider",
                "If possible, install the provider if it isn't installed") do |p|
             options[:install_provider] = p
@@ -55,6 +80,11 @@ module VagrantPlugins
         argv = parse_options(opts)
         return if !argv
 
+        if options[:provider] && options[:provider_order]
+          raise Vagrant::Errors::CLIInvalidUsage,
+            help: opts.help.chomp
+        end
+
         # Validate the provisioners
         validate_provisioner_flags!(options, argv)
 
@@ -76,6 +106,8 @@ module VagrantPlugins
           names = nil if autostart && names.empty?
         end
 
+        return check_machines(names, options) if options[:check]
+
         # Build up the batch job of what we'll do
         machines = []
         if names
@@ -85,18 +117,21 @@ module VagrantPlugins
           # install_providers function if a user gives us a machine id instead
           # of the machines name.
           machine_names = []
-          with_target_vms(names, provider: options[:provider]){|m| machine_names << m.name }
+          with_target_vms(names, provider: options[:provider],
+            provider_order: options[:provider_order]) { |m| machine_names << m.name }
           options[:install_provider] = false if !(machine_names - names).empty?
 
           # If we're installing providers, then do that. We don't
           # parallelize this step because it is likely the same provider
           # anyways.
           if options[:install_provider]
-            install_providers(names, provider: options[:provider])
+            install_providers(names, provider: options[:provider],
+              provider_order: options[:provider_order])
           end
 
-          @env.batch(options[:parallel]) do |batch|
-            with_target_vms(names, provider: options[:provider]) do |machine|
+          @env.batch(options[:parallel], max_workers: options[:parallel_workers]) do |batch|
+            with_target_vms(names, provider: options[:provider],
+                            provider_order: options[:provider_order]) do |machine|
               @env.ui.info(I18n.t(
                 "vagrant.commands.up.upping",
                 name: machine.name,
@@ -114,6 +149,11 @@ module VagrantPlugins
           return 0
         end
 
+        machines.each do |m|
+          store_resources(m)
+          store_networks(m)
+        end
+
         # Output the post-up messages that we have, if any
         machines.each do |m|
           next if !m.config.vm.post_up_message
@@ -134,11 +174,55 @@ module VagrantPlugins
 
       protected
 
-      def install_providers(names, provider: nil)
+      # Check the machines can be brought up, reporting all the problems
+      # found instead of stopping at the first.
+      #
+      # @return [Integer] exit status
+      def check_machines(names, options)
+        if !names
+          @env.ui.info(I18n.t("vagrant.up_no_machines"))
+          return 0
+        end
+
+        checker = PreconditionCheck.new(@env)
+        failed = 0
+        names.each do |name|
+          results = {}
+          begin
+            with_target_vms([name], provider: options[:provider],
+                            provider_order: options[:provider_order]) do |machine|
+              results[machine.name.to_s] = [machine.ui, checker.check(machine)]
+            end
+          rescue Vagrant::Errors::VagrantError => e
+            results[name.to_s] = [Vagrant::UI::Prefixed.new(@env.ui, name.to_s), [e.message]]
+          end
+
+          results.each do |_, (ui, problems)|
+            if problems.empty?
+              ui.success(I18n.t("vagrant.commands.up.check.passed"))
+              next
+            end
+
+            failed += 1
+            ui.error(I18n.t("vagrant.commands.up.check.failed",
+              count: problems.length))
+            problems.each do |problem|
+              ui.error(problem.to_s.strip.gsub(/^/, "  "), prefix: false)
+            end
+          end
+        end
+
+        return 0 if failed == 0
+
+        @env.ui.error(I18n.t("vagrant.commands.up.check.summary", count: failed))
+        1
+      end
+
+      def install_providers(names, provider: nil, provider_order: nil)
         # First create a set of all the providers we need to check for.
         # Most likely this will be a set of one.
         providers = Set.new
-        with_target_vms(names, provider: provider) do |machine|
+        with_target_vms(names, provider: provider, provider_order: provider_order) do |machine|
           # Check if we have this machine in the index
           entry    = @env.machine_index.get(machine.name.to_s)
 
@@ -156,7 +240,8 @@ module VagrantPlugins
           p = provider
           p = entry.provider.to_sym if !p && entry
           p = @env.default_provider(
-            machine: machine.name.to_sym, check_usable: false) if !p
+            machine: machine.name.to_sym, check_usable: false,
+            provider_order: provider_order) if !p
 
           # Add it to the set
           providers.add(p)
@@ -0,0 +1,143 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require "log4r"
+
+require "vagrant/action/builtin/handle_forwarded_port_collisions"
+require "vagrant/action/builtin/mixin_synced_folders"
+require "vagrant/util/downloader"
+
+module VagrantPlugins
+  module CommandUp
+    # This checks that a machine can be brought up without bringing it up.
+    # Each check only reads the state of the machine and the host, so
+    # nothing is created, downloaded or modified.
+    class PreconditionCheck
+      include Vagrant::Action::Builtin::MixinSyncedFolders
+
+      def initialize(env)
+        @env = env
+        @logger = Log4r::Logger.new("vagrant::command::up::precondition_check")
+      end
+
+      # Check the machine
+      #
+      # @param [Vagrant::Machine] machine
+      # @return [Array<String>] the problems found, empty if the machine
+      #   can be brought up
+      def check(machine)
+        problems = []
+        [:check_config, :check_box, :check_synced_folders, :check_forwarded_ports].each do |name|
+          begin
+            problems.concat(send(name, machine))
+          rescue Vagrant::Errors::VagrantError => e
+            @logger.debug("Check #{name} failed for #{machine.name}: #{e}")
+            problems << e.message
+          end
+        end
+
+        problems
+      end
+
+      protected
+
+      # @return [Array<String>] errors of validating the configuration
+      def check_config(machine)
+        errors = machine.config.validate(machine) || {}
+        errors.flat_map do |namespace, messages|
+          messages.map { |message| "#{namespace}: #{message}" }
+        end
+      end
+
+      # The box is available if it is installed, or if a HEAD request of
+      # one of its URLs succeeds.
+      #
+      # @return [Array<String>]
+      def check_box(machine)
+        box_name = machine.config.vm.box
+        return [] if !box_name || machine.config.vm.clone || machine.id || machine.box
+
+        urls = box_urls(machine)
+        errors = urls.map do |url|
+          error = box_url_error(machine, url)
+          return [] if !error
+
+          error
+        end
+
+        [I18n.t("vagrant.commands.up.check.box_unavailable",
+          name: box_name, errors: errors.join("\n"))]
+      end
+
+      # The synced folders are loaded the same way as when they are
+      # enabled, which checks their implementations can be used with the
+      # host, such as an NFS server being installed for NFS folders.
+      #
+      # @return [Array<String>]
+      def check_synced_folders(machine)
+        synced_folders(machine)
+        []
+      end
+
+      # Forwarded ports which can not be corrected automatically must not
+      # be in use on the host. The ports of a running machine are not
+      # checked since they are used by the machine itself.
+      #
+      # @return [Array<String>]
+      def check_forwarded_ports(machine)
+        return [] if machine.state.id == :running
+
+        machine.config.vm.networks.map do |type, options|
+          next if type != :forwarded_port
+          next if options[:disabled] || options[:auto_correct]
+          next if options[:protocol] && options[:protocol] != "tcp"
+
+          host_port = options[:host]
+          next if !Vagrant::Action::Builtin::HandleForwardedPortCollisions.
+            port_check(machine, options[:host_ip], host_port)
+
+          I18n.t("vagrant.commands.up.check.port_in_use",
+            guest_port: options[:guest].to_s,
+            host_port: host_port.to_s)
+        end.compact
+      end
+
+      # @return [Array<String>] the URLs the box may be added from
+      def box_urls(machine)
+        Array(machine.config.vm.box_url || machine.config.vm.box).map do |url|
+          if url =~ /^[^\/]+\/[^\/]+$/ && !File.file?(url)
+            server = Vagrant.server_url(machine.config.vm.box_server_url)
+            raise Vagrant::Errors::BoxServerNotSet if !server
+
+            url = "#{server}/#{url}"
+          end
+
+          url
+        end
+      end
+
+      # @return [String, nil] the reason the box can not be added from the
+      #   URL, or nil if it can be
+      def box_url_error(machine, url)
+        if url !~ /^[a-z0-9]+:.*$/i || url.start_with?("file://")
+          path = File.expand_path(url.sub(/^file:\/\//, ""), machine.env.root_path)
+          return if File.file?(path)
+
+          return I18n.t("vagrant.commands.up.check.box_file_missing", url: url)
+        end
+
+        downloader_options = {
+          ca_cert: machine.config.vm.box_download_ca_cert,
+          ca_path: machine.config.vm.box_download_ca_path,
+          client_cert: machine.config.vm.box_download_client_cert,
+          insecure: machine.config.vm.box_download_insecure,
+          location_trusted: machine.config.vm.box_download_location_trusted,
+        }
+        Vagrant::Util::Downloader.new(url, File::NULL, downloader_options).head
+        nil
+      rescue Vagrant::Errors::DownloaderError => e
+        "#{url}: #{e.extra_data[:message].to_s.strip}"
+      end
+    end
+  end
+end
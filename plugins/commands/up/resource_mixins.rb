@@ -0,0 +1,168 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require "digest"
+require "json"
+
+module VagrantPlugins
+  module CommandUp
+    # This module records the resources a machine was started with so
+    # that resource changes can be applied to a running machine without
+    # restarting it.
+    #
+    # Providers which support modifying resources of a running machine
+    # implement the `resources` capability, which returns the configured
+    # resources of the machine (`:cpus` and `:memory`), and the
+    # `modify_resources` capability, which applies the given resources to
+    # the running machine. The guest must implement the `resources_hotplug`
+    # capability, which returns the resources that can be changed while
+    # the guest is running. Providers may implement the `resources_config`
+    # capability, which returns the provider settings other than the
+    # resources. Without it, any change of the provider settings requires
+    # a full reload.
+    module ResourceMixins
+      # Name of the file within the machine data directory which stores
+      # the resources the machine was started with
+      RESOURCES_FILE = "resources".freeze
+
+      # Record the configured resources of the machine
+      #
+      # @param [Vagrant::Machine] machine
+      def store_resources(machine)
+        return if machine.id.nil?
+
+        resources = configured_resources(machine)
+        path = machine.data_dir.join(RESOURCES_FILE)
+        if resources.nil?
+          path.delete if path.file?
+          return
+        end
+
+        path.write(JSON.dump(
+          "resources" => resources,
+          "config" => resources_config_digest(machine),
+        ))
+      end
+
+      # Apply resource changes to a running machine. Changes are only
+      # applied if the resources are the only configuration which has
+      # changed since the machine was started, and both the provider
+      # and guest support modifying the changed resources.
+      #
+      # @param [Vagrant::Machine] machine
+      # @return [Boolean] true if the changes were applied
+      def modify_resources(machine)
+        previous = stored_resources(machine)
+        return false if previous.nil?
+
+        resources = configured_resources(machine)
+        return false if resources.nil?
+        return false if previous["config"] != resources_config_digest(machine)
+
+        changes = resources.reject do |name, value|
+          previous.fetch("resources", {})[name.to_s] == value
+        end
+        return false if changes.empty?
+        return false if machine.state.id != :running
+
+        @logger.info("Resource changes for #{machine.name}: #{changes.inspect}")
+        if !machine.provider.capability?(:modify_resources)
+          machine.ui.info(I18n.t("vagrant.commands.reload.resources.provider_unsupported",
+            provider: machine.provider_name.to_s))
+          return false
+        end
+
+        machine.guest.detect! if !machine.guest.ready?
+        supported = []
+        if machine.guest.capability?(:resources_hotplug)
+          supported = Array(machine.guest.capability(:resources_hotplug)).map(&:to_sym)
+        end
+        unsupported = changes.keys - supported
+        if !unsupported.empty?
+          machine.ui.info(I18n.t("vagrant.commands.reload.resources.guest_unsupported",
+            resources: unsupported.join(", ")))
+          return false
+        end
+
+        if !machine.provider.capability(:modify_resources, changes)
+          machine.ui.info(I18n.t("vagrant.commands.reload.resources.provider_unsupported",
+            provider: machine.provider_name.to_s))
+          return false
+        end
+
+        store_resources(machine)
+        machine.ui.success(I18n.t("vagrant.commands.reload.resources.modified",
+          resources: changes.map { |name, value| "#{name}=#{value}" }.join(", ")))
+        true
+      end
+
+      protected
+
+      # @param [Vagrant::Machine] machine
+      # @return [Hash, nil] configured resources of the machine
+      def configured_resources(machine)
+        return if !machine.provider.capability?(:resources)
+
+        resources = machine.provider.capability(:resources)
+        return if resources.nil?
+
+        resources.reject { |_, value| value.nil? }.map { |name, value|
+          [name.to_sym, value]
+        }.to_h
+      end
+
+      # @param [Vagrant::Machine] machine
+      # @return [Hash, nil] resources stored when the machine was started
+      def stored_resources(machine)
+        return if machine.id.nil?
+
+        path = machine.data_dir.join(RESOURCES_FILE)
+        return if !path.file?
+
+        JSON.parse(path.read)
+      rescue JSON::ParserError
+        nil
+      end
+
+      # Digest of the machine configuration which requires a restart
+      # of the machine to apply.
+      #
+      # @param [Vagrant::Machine] machine
+      # @return [String]
+      def resources_config_digest(machine)
+        vm = machine.config.vm
+        provider_config = nil
+        if machine.provider.capability?(:resources_config)
+          provider_config = machine.provider.capability(:resources_config)
+        elsif machine.provider_config.respond_to?(:instance_variables_hash)
+          provider_config = machine.provider_config.instance_variables_hash
+        end
+
+        Digest::SHA256.hexdigest(JSON.dump(
+          "box" => vm.box,
+          "box_version" => vm.box_version,
+          "communicator" => vm.communicator,
+          "guest" => vm.guest,
+          "hostname" => vm.hostname,
+          "networks" => digest_networks(machine),
+          "synced_folders" => vm.synced_folders,
+          "provider" => provider_config,
+        ))
+      end
+
+      # Forwarded ports are not included since they are corrected when
+      # the machine is started. Network IDs are not included since they
+      # are generated each time the configuration is loaded.
+      #
+      # @param [Vagrant::Machine] machine
+      # @return [Array] networks of the machine
+      def digest_networks(machine)
+        machine.config.vm.networks.map do |type, options|
+          next if type == :forwarded_port
+
+          [type, options.reject { |key, _| key == :id }]
+        end.compact
+      end
+    end
+  end
+end
@@ -19,6 +19,7 @@ module VagrantPlugins
         parser.on("--[no-]provision", "Enable or disable provisioning") do |p|
           options[:provision_enabled] = p
           options[:provision_ignore_sentinel] = true
+          options[:provision_force] = p
         end
 
         parser.on("--provision-with x,y,z", Array,
@@ -26,6 +27,11 @@ module VagrantPlugins
           options[:provision_types] = list.map { |type| type.to_sym }
           options[:provision_enabled] = true
           options[:provision_ignore_sentinel] = true
+          options[:provision_force] = true
+        end
+
+        parser.on("--redetect-guest", "Detect the guest again instead of using cached results") do |r|
+          options[:guest_redetect] = r
         end
       end
 
@@ -24,6 +24,10 @@ module VagrantPlugins
           o.on("-p", "--ignore-provider", "Ignores provider config options") do |p|
             options[:ignore_provider] = p
           end
+
+          o.on("-s", "--strict", "Fail on warnings from provider and provisioner config checks") do |s|
+            options[:strict] = s
+          end
         end
 
         # Parse the options
@@ -37,8 +41,17 @@ module VagrantPlugins
         end
 
         # Validate the configuration of all machines
+        failures = 0
         with_target_vms() do |machine|
+          findings = config_findings(machine, options)
+          findings.each { |f| report_finding(machine, *f) }
+
           machine.action_raw(:config_validate, Vagrant::Action::Builtin::ConfigValidate, action_env)
+          failures += findings.count { |_, f| f.error? || options[:strict] }
+        end
+
+        if failures > 0
+          raise Vagrant::Errors::ConfigValidationFindings, count: failures
         end
 
         @env.ui.info(I18n.t("vagrant.commands.validate.success"))
@@ -51,6 +64,56 @@ module VagrantPlugins
 
       protected
 
+      # Checks the provider and provisioner configuration of the machine
+      # against the schemas declared by their plugins.
+      #
+      # @param [Vagrant::Machine] machine
+      # @param [Hash] options
+      # @return [Array(String, Vagrant::Plugin::V2::ConfigWarning)] name of
+      #   the section and each finding
+      def config_findings(machine, options)
+        findings = []
+
+        if !options[:ignore_provider] && machine.provider_config
+          section = "#{machine.provider_name} provider"
+          location = machine.config.vm.provider_locations(machine.provider_name).first
+          machine.provider_config.validate_config(machine).each do |f|
+            f.location ||= location
+            findings << [section, f]
+          end
+        end
+
+        machine.config.vm.provisioners.each do |p|
+          next if p.invalid?
+
+          section = "#{p.name || p.type} provisioner"
+          p.config.validate_config(machine).each do |f|
+            f.location ||= p.location
+            findings << [section, f]
+          end
+        end
+
+        findings
+      end
+
+      # @param [Vagrant::Machine] machine
+      # @param [String] section Name of the configuration section
+      # @param [Vagrant::Plugin::V2::ConfigWarning] finding
+      def report_finding(machine, section, finding)
+        key = finding.location ? "finding_location" : "finding"
+        message = I18n.t("vagrant.commands.validate.#{key}",
+          level: I18n.t("vagrant.commands.validate.level_#{finding.level}"),
+          section: section,
+          message: finding.message,
+          location: finding.location)
+
+        if finding.error?
+          machine.ui.error(message)
+        else
+          machine.ui.warn(message)
+        end
+      end
+
       # This method is required to bypass some of the provider checks that would
       # otherwise raise exceptions before Vagrant could load and validate a config.
       # It essentially ignores that there are no installed or usable prodivers so
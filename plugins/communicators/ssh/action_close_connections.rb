@@ -0,0 +1,21 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require_relative "connection_pool"
+
+module VagrantPlugins
+  module CommunicatorSSH
+    # This closes the pooled SSH connections when the environment is
+    # unloaded.
+    class ActionCloseConnections
+      def initialize(app, env)
+        @app = app
+      end
+
+      def call(env)
+        ConnectionPool.instance.close_all
+        @app.call(env)
+      end
+    end
+  end
+end
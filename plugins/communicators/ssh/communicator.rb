@@ -14,11 +14,15 @@ require 'net/ssh/proxy/command'
 require 'net/scp'
 
 require 'vagrant/util/ansi_escape_code_remover'
+require 'vagrant/util/busy'
+require 'vagrant/util/chunked_upload'
 require 'vagrant/util/file_mode'
 require 'vagrant/util/keypair'
 require 'vagrant/util/platform'
 require 'vagrant/util/retryable'
 
+require_relative "connection_pool"
+
 module VagrantPlugins
   module CommunicatorSSH
     # This class provides communication with the VM via SSH.
@@ -49,6 +53,9 @@ module VagrantPlugins
       include Vagrant::Util::ANSIEscapeCodeRemover
       include Vagrant::Util::Retryable
 
+      # @return [Vagrant::Machine] machine the communicator connects to
+      attr_reader :machine
+
       def self.match?(machine)
         # All machines are currently expected to have SSH.
         true
@@ -59,6 +66,7 @@ module VagrantPlugins
         @machine = machine
         @logger  = Log4r::Logger.new("vagrant::communication::ssh")
         @connection = nil
+        @connection_key = nil
         @inserted_key = false
       end
 
@@ -83,61 +91,88 @@ module VagrantPlugins
           end
 
           previous_messages = {}
-          while true
-            message  = nil
-            begin
+          attempt = 0
+          start_time = Time.now.to_f
+
+          # Stop retrying as soon as the user interrupts rather than
+          # waiting for the boot timeout to expire.
+          interrupted = false
+          int_callback = lambda { interrupted = true }
+          Vagrant::Util::Busy.busy(int_callback) do
+            while true
+              raise Vagrant::Errors::VagrantInterrupt if interrupted
+
+              message  = nil
               begin
-                connect(retries: 1)
-                return true if ready?
-              rescue Vagrant::Errors::VagrantError => e
-                @logger.info("SSH not ready: #{e.inspect}")
+                begin
+                  connect(retries: 1)
+                  return true if ready?
+                rescue Vagrant::Errors::VagrantError => e
+                  @logger.info("SSH not ready: #{e.inspect}")
+                  raise
+                end
+              rescue Vagrant::Errors::SSHConnectionTimeout
+                message = "Connection timeout."
+              rescue Vagrant::Errors::SSHDisconnected
+                message = "Remote connection disconnect."
+              rescue Vagrant::Errors::SSHConnectionRefused
+                message = "Connection refused."
+              rescue Vagrant::Errors::SSHConnectionReset
+                message = "Connection reset."
+              rescue Vagrant::Errors::SSHConnectionAborted
+                message = "Connection aborted."
+              rescue Vagrant::Errors::SSHHostDown
+                message = "Host appears down."
+              rescue Vagrant::Errors::SSHNoRoute
+                message = "Host unreachable."
+              rescue Vagrant::Errors::SSHAuthenticationFailed,
+                  Vagrant::Errors::NetSSHException
+                # The SSH server is accepting connections, so failures
+                # during the handshake or authentication will not be
+                # resolved by retrying.
+                raise
+              rescue Vagrant::Errors::SSHInvalidShell
+                raise
+              rescue Vagrant::Errors::SSHKeyTypeNotSupported
                 raise
+              rescue Vagrant::Errors::SSHKeyTypeNotSupportedByServer
+                raise
+              rescue Vagrant::Errors::SSHKeyBadOwner
+                raise
+              rescue Vagrant::Errors::SSHKeyBadPermissions
+                raise
+              rescue Vagrant::Errors::SSHInsertKeyUnsupported
+                raise
+              rescue Vagrant::Errors::VagrantError => e
+                # Ignore it, SSH is not ready, some other error.
               end
-            rescue Vagrant::Errors::SSHConnectionTimeout
-              message = "Connection timeout."
-            rescue Vagrant::Errors::SSHAuthenticationFailed
-              message = "Authentication failure."
-            rescue Vagrant::Errors::SSHDisconnected
-              message = "Remote connection disconnect."
-            rescue Vagrant::Errors::SSHConnectionRefused
-              message = "Connection refused."
-            rescue Vagrant::Errors::SSHConnectionReset
-              message = "Connection reset."
-            rescue Vagrant::Errors::SSHConnectionAborted
-              message = "Connection aborted."
-            rescue Vagrant::Errors::SSHHostDown
-              message = "Host appears down."
-            rescue Vagrant::Errors::SSHNoRoute
-              message = "Host unreachable."
-            rescue Vagrant::Errors::SSHInvalidShell
-              raise
-            rescue Vagrant::Errors::SSHKeyTypeNotSupported
-              raise
-            rescue Vagrant::Errors::SSHKeyTypeNotSupportedByServer
-              raise
-            rescue Vagrant::Errors::SSHKeyBadOwner
-              raise
-            rescue Vagrant::Errors::SSHKeyBadPermissions
-              raise
-            rescue Vagrant::Errors::SSHInsertKeyUnsupported
-              raise
-            rescue Vagrant::Errors::VagrantError => e
-              # Ignore it, SSH is not ready, some other error.
-            end
 
-            # If we have a message to show, then show it. We don't show
-            # repeated messages unless they've been repeating longer than
-            # 10 seconds.
-            if message
-              message_at   = Time.now.to_f
-              show_message = true
-              if previous_messages[message]
-                show_message = (message_at - previous_messages[message]) > 10.0
+              # If we have a message to show, then show it. We don't show
+              # repeated messages unless they've been repeating longer than
+              # 10 seconds.
+              if message
+                message_at   = Time.now.to_f
+                show_message = true
+                if previous_messages[message]
+                  show_message = (message_at - previous_messages[message]) > 10.0
+                end
+
+                if show_message
+                  @machine.ui.detail("Warning: #{message} Retrying...")
+                  previous_messages[message] = message_at
+                end
               end
 
-              if show_message
-                @machine.ui.detail("Warning: #{message} Retrying...")
-                previous_messages[message] = message_at
+              attempt += 1
+              delay = connect_retry_delay(ssh_info, attempt)
+              @logger.debug("SSH connection attempt #{attempt} failed after " \
+                "#{(Time.now.to_f - start_time).round(1)}s elapsed, " \
+                "retrying in #{delay}s (#{message || "not ready"})")
+
+              # Sleep in short intervals so an interrupt is noticed promptly
+              retry_at = Time.now.to_f + delay
+              while !interrupted && Time.now.to_f < retry_at
+                sleep([0.1, retry_at - Time.now.to_f].min)
               end
             end
           end
@@ -146,6 +181,24 @@ module VagrantPlugins
         return false
       end
 
+      # Number of seconds to wait before the next connection attempt
+      # while waiting for the machine to become ready. With the
+      # exponential backoff the configured delay is doubled after each
+      # attempt, up to the configured maximum delay.
+      #
+      # @param [Hash] ssh_info
+      # @param [Integer] attempt Number of failed attempts
+      # @return [Numeric]
+      def connect_retry_delay(ssh_info, attempt)
+        delay = ssh_info[:connect_retry_delay].to_f
+        if ssh_info[:connect_retry_backoff] == :exponential
+          delay *= 2 ** (attempt - 1)
+          max_delay = ssh_info[:connect_retry_max_delay]
+          delay = [delay, max_delay.to_f].min if max_delay
+        end
+        delay
+      end
+
       def ready?
         @logger.debug("Checking whether SSH is ready...")
 
@@ -268,8 +321,7 @@ module VagrantPlugins
 
           # Done, restart.
           @machine.ui.detail(I18n.t("vagrant.inserted_key"))
-          @connection.close
-          @connection = nil
+          close_connection
 
           return ready?
         end
@@ -349,7 +401,9 @@ module VagrantPlugins
         execute(command, opts) == 0
       end
 
-      def upload(from, to)
+      # @param [Boolean] verify Verify the checksum of uploaded files if
+      #   `config.vm.upload_checksum_type` is set
+      def upload(from, to, verify: true)
         @logger.debug("Uploading: #{from} to #{to}")
 
         if File.directory?(from)
@@ -362,6 +416,7 @@ module VagrantPlugins
           end
         end
 
+        chunked_upload = chunked_uploader
         scp_connect do |scp|
           uploader = lambda do |path, remote_dest=nil|
             if File.directory?(path)
@@ -385,11 +440,16 @@ module VagrantPlugins
               @logger.debug("Ensuring remote directory exists for destination upload")
               create_remote_directory(File.dirname(dest))
               @logger.debug("Uploading file #{path} to remote #{dest}")
-              upload_file = File.open(path, "rb")
-              begin
-                scp.upload!(upload_file, dest)
-              ensure
-                upload_file.close
+              if chunked_upload.chunked?(path)
+                chunked_upload.upload(path, dest)
+              else
+                upload_file = File.open(path, "rb")
+                begin
+                  scp.upload!(upload_file, dest)
+                ensure
+                  upload_file.close
+                end
+                chunked_upload.verify!(path, dest) if verify && chunked_upload.checksum?
               end
             end
           end
@@ -408,11 +468,33 @@ module VagrantPlugins
           to: to.to_s
       end
 
+      def chunked_upload?
+        true
+      end
+
+      def upload_chunk(from, to, offset)
+        chunk = "#{to}.chunk"
+        # Chunks are verified with the whole file once uploaded
+        upload(from, chunk, verify: false)
+        execute(chunked_upload_commands.write_chunk(chunk, to, offset))
+      end
+
+      def file_size(path)
+        size = capture_stdout(chunked_upload_commands.file_size(path))
+        size.empty? ? nil : size.to_i
+      end
+
+      def file_checksum(path, type)
+        checksum = capture_stdout(chunked_upload_commands.file_checksum(path, type))
+        checksum.split.first
+      end
+
+      def move_file(from, to)
+        execute(chunked_upload_commands.move_file(from, to))
+      end
+
       def reset!
-        if @connection
-          @connection.close
-          @connection = nil
-        end
+        close_connection
         @ssh_info_notification = true # suppress ssh info output
         wait_for_ready(5)
       end
@@ -426,29 +508,47 @@ module VagrantPlugins
 
       # Opens an SSH connection and yields it to a block.
       def connect(**opts)
-        if @connection && !@connection.closed?
-          # There is a chance that the socket is closed despite us checking
-          # 'closed?' above. To test this we need to send data through the
-          # socket.
-          #
-          # We wrap the check itself in a 5 second timeout because there
-          # are some cases where this will just hang.
-          begin
-            Timeout.timeout(5) do
-              @connection.exec!("")
+        # When keep alive is enabled, use a connection to the same target
+        # opened by another communicator if one is available.
+        if (@connection.nil? || @connection.closed?) && pool_connections?
+          ssh_info = @machine.ssh_info
+          if ssh_info
+            key = ConnectionPool.key_for(ssh_info)
+            pooled = connection_pool.get(key)
+            if pooled
+              @logger.debug("Using pooled SSH connection.")
+              @connection = pooled
+              @connection_key = key
+              @connection_ssh_info = ssh_info
             end
-          rescue Exception => e
-            @logger.info("Connection errored, not re-using. Will reconnect.")
-            @logger.debug(e.inspect)
-            @connection = nil
           end
+        end
+
+        if @connection && !@connection.closed?
+          with_connection_lock do
+            # There is a chance that the socket is closed despite us checking
+            # 'closed?' above. To test this we need to send data through the
+            # socket.
+            #
+            # We wrap the check itself in a 5 second timeout because there
+            # are some cases where this will just hang.
+            begin
+              Timeout.timeout(5) do
+                @connection.exec!("")
+              end
+            rescue Exception => e
+              @logger.info("Connection errored, not re-using. Will reconnect.")
+              @logger.debug(e.inspect)
+              close_connection
+            end
 
-          # If the @connection is still around, then it is valid,
-          # and we use it.
-          if @connection
-            @logger.debug("Re-using SSH connection.")
-            return yield @connection if block_given?
-            return
+            # If the @connection is still around, then it is valid,
+            # and we use it.
+            if @connection
+              @logger.debug("Re-using SSH connection.")
+              return yield @connection if block_given?
+              return
+            end
           end
         end
 
@@ -488,7 +588,12 @@ module VagrantPlugins
           timeout = 60
 
           @logger.info("Attempting SSH connection...")
-          connection = retryable(tries: opts[:retries], on: SSH_RETRY_EXCEPTIONS, sleep: opts[:retry_delay]) do
+          retry_opts = {tries: opts[:retries], on: SSH_RETRY_EXCEPTIONS, sleep: opts[:retry_delay]}
+          if ssh_info[:connect_retry_backoff] == :exponential
+            retry_opts[:backoff] = 2
+            retry_opts[:max_sleep] = ssh_info[:connect_retry_max_delay]
+          end
+          connection = retryable(retry_opts) do
             Timeout.timeout(timeout) do
               begin
                 # This logger will get the Net-SSH log data for us.
@@ -581,10 +686,46 @@ module VagrantPlugins
 
         @connection          = connection
         @connection_ssh_info = ssh_info
+        @connection_key      = ConnectionPool.key_for(ssh_info)
+        connection_pool.set(@connection_key, connection) if pool_connections?
 
         # Yield the connection that is ready to be used and
         # return the value of the block
-        return yield connection if block_given?
+        return with_connection_lock { yield connection } if block_given?
+      end
+
+      # @return [Boolean] connections are shared with other communicators
+      #   for the same target
+      def pool_connections?
+        !!machine_config_ssh.connection_pool
+      end
+
+      # @return [ConnectionPool]
+      def connection_pool
+        ConnectionPool.instance
+      end
+
+      # Run the block while holding the lock for the connection. Pooled
+      # connections are locked by target so communicators sharing a
+      # connection do not use it at the same time.
+      def with_connection_lock(&block)
+        key = pool_connections? && @connection_key ? @connection_key : self
+        connection_pool.synchronize(key, &block)
+      end
+
+      # Close the current connection and remove it from the pool
+      def close_connection
+        return if !@connection
+
+        if pool_connections? && @connection_key
+          connection_pool.remove(@connection_key, @connection)
+        end
+        begin
+          @connection.close if !@connection.closed?
+        rescue StandardError => e
+          @logger.debug("Error closing SSH connection: #{e.class}: #{e}")
+        end
+        @connection = nil
       end
 
       # The shell wrapper command used in shell_execute defined by
@@ -816,6 +957,28 @@ module VagrantPlugins
         execute("mkdir -p \"#{dir}\"")
       end
 
+      # @return [Vagrant::Util::ChunkedUpload] uploader of large files
+      def chunked_uploader
+        Vagrant::Util::ChunkedUpload.new(self,
+          chunk_size: @machine.config.vm.upload_chunk_size,
+          checksum_type: @machine.config.vm.upload_checksum_type)
+      end
+
+      # @return [Module] commands implementing the primitives of chunked
+      #   uploads for the shell of the guest
+      def chunked_upload_commands
+        Vagrant::Util::ChunkedUpload::PosixCommands
+      end
+
+      # @return [String] the stripped standard output of the command
+      def capture_stdout(command)
+        output = ""
+        execute(command, error_check: false) do |type, data|
+          output << data if type == :stdout
+        end
+        output.strip
+      end
+
       def machine_config_ssh
         @machine.config.ssh
       end
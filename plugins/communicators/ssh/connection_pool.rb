@@ -0,0 +1,151 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require "monitor"
+require "thread"
+
+require "log4r"
+
+module VagrantPlugins
+  module CommunicatorSSH
+    # This class stores SSH connections so they can be shared by all the
+    # communicators for a target within a single Vagrant process. Each
+    # target also has a lock which must be held while its connection is
+    # in use since a Net::SSH session can not be used by multiple threads
+    # at once. A connection which is replaced while the lock of its target
+    # is held is only closed once the lock is released.
+    class ConnectionPool
+      @@instance = nil
+      @@instance_lock = Mutex.new
+
+      # @return [ConnectionPool] the pool shared by the process
+      def self.instance
+        @@instance_lock.synchronize do
+          @@instance ||= new
+        end
+      end
+
+      # Key identifying the target of a connection
+      #
+      # @param [Hash] ssh_info
+      # @return [Array]
+      def self.key_for(ssh_info)
+        [
+          ssh_info[:host],
+          ssh_info[:port],
+          ssh_info[:username],
+          Array(ssh_info[:private_key_path]).sort,
+          ssh_info[:proxy_command],
+        ]
+      end
+
+      def initialize
+        @logger = Log4r::Logger.new("vagrant::communication::ssh::connection_pool")
+        @lock = Mutex.new
+        @connections = {}
+        @locks = {}
+        @replaced = {}
+      end
+
+      # Get the connection for a target
+      #
+      # @param [Array] key Target key
+      # @return [Net::SSH::Connection::Session, nil] connection, or nil if
+      #   there is no open connection for the target
+      def get(key)
+        @lock.synchronize do
+          connection = @connections[key]
+          if connection && connection.closed?
+            @logger.debug("removing closed connection from pool")
+            @connections.delete(key)
+            connection = nil
+          end
+          connection
+        end
+      end
+
+      # Store the connection for a target. Any existing connection for the
+      # target is closed, once it is no longer in use if the lock of the
+      # target is held.
+      #
+      # @param [Array] key Target key
+      # @param [Net::SSH::Connection::Session] connection
+      def set(key, connection)
+        previous = @lock.synchronize do
+          replaced = @connections[key]
+          @connections[key] = connection
+          replaced = nil if replaced.equal?(connection)
+
+          if replaced && @locks[key] && @locks[key].mon_locked?
+            @logger.debug("closing replaced connection once it is no longer in use")
+            (@replaced[key] ||= []) << replaced
+            replaced = nil
+          end
+          replaced
+        end
+        close_connection(previous) if previous
+      end
+
+      # Remove the connection for a target. The connection is closed if
+      # it is still open. If a connection is given it is only removed if
+      # it is the connection stored for the target.
+      #
+      # @param [Array] key Target key
+      # @param [Net::SSH::Connection::Session] connection
+      def remove(key, connection=nil)
+        removed = @lock.synchronize do
+          if connection.nil? || @connections[key].equal?(connection)
+            @connections.delete(key)
+          end
+        end
+        close_connection(removed) if removed
+      end
+
+      # Run the block while holding the lock for a target. The lock is
+      # reentrant so nested use of the connection by the same thread
+      # does not deadlock.
+      #
+      # @param [Array] key Target key
+      # @return [Object] result of the block
+      def synchronize(key)
+        monitor = @lock.synchronize { @locks[key] ||= Monitor.new }
+        outermost = !monitor.mon_owned?
+        monitor.synchronize do
+          begin
+            yield
+          ensure
+            close_replaced(key) if outermost
+          end
+        end
+      end
+
+      # Close all the connections in the pool
+      def close_all
+        connections = @lock.synchronize do
+          replaced = @replaced.values.flatten
+          @replaced.clear
+          @connections.values.tap { @connections.clear } + replaced
+        end
+        @logger.info("closing #{connections.size} pooled SSH connection(s)") if !connections.empty?
+        connections.each { |c| close_connection(c) }
+      end
+
+      protected
+
+      # Close the connections of the target which were replaced while
+      # they were in use
+      #
+      # @param [Array] key Target key
+      def close_replaced(key)
+        replaced = @lock.synchronize { @replaced.delete(key) }
+        Array(replaced).each { |c| close_connection(c) }
+      end
+
+      def close_connection(connection)
+        connection.close if !connection.closed?
+      rescue StandardError => e
+        @logger.debug("error closing SSH connection: #{e.class}: #{e}")
+      end
+    end
+  end
+end
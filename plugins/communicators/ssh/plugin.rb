@@ -17,6 +17,11 @@ module VagrantPlugins
         require File.expand_path("../communicator", __FILE__)
         Communicator
       end
+
+      action_hook(:ssh_connection_pool, :environment_unload) do |hook|
+        require_relative "action_close_connections"
+        hook.append(ActionCloseConnections)
+      end
     end
   end
 end
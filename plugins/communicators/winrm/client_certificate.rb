@@ -0,0 +1,113 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require "fileutils"
+require "openssl"
+require "securerandom"
+
+require "log4r"
+
+require "vagrant/util/powershell"
+
+require_relative "errors"
+
+module VagrantPlugins
+  module CommunicatorWinRM
+    # Resolves the client certificate used to authenticate with the
+    # certificate transport. The certificate is either read from the
+    # configured files, or exported by its thumbprint from the certificate
+    # store of the current user on Windows hosts.
+    class ClientCertificate
+      # @return [String] path of the PEM encoded certificate
+      attr_reader :cert_path
+
+      # @return [String] path of the PEM encoded private key
+      attr_reader :key_path
+
+      # @return [String, nil] passphrase of the private key
+      attr_reader :key_pass
+
+      # @param [Config] config
+      # @param [Pathname] root_path Path the configured files are relative to
+      # @param [Pathname] data_dir Directory certificates exported from the
+      #   certificate store are written to
+      def initialize(config, root_path:, data_dir:)
+        @config = config
+        @root_path = root_path
+        @data_dir = data_dir
+        @logger = Log4r::Logger.new("vagrant::communication::winrm::client_certificate")
+      end
+
+      # @return [Boolean] true if a client certificate is configured
+      def self.configured?(config)
+        !!(config.client_cert || config.client_cert_thumbprint)
+      end
+
+      # Thumbprint of a certificate, as shown by the Windows certificate store
+      #
+      # @param [OpenSSL::X509::Certificate] cert
+      # @return [String]
+      def self.thumbprint(cert)
+        OpenSSL::Digest::SHA1.hexdigest(cert.to_der).upcase
+      end
+
+      # @return [self]
+      def resolve
+        if @config.client_cert
+          @cert_path = File.expand_path(@config.client_cert, @root_path)
+          @key_path = File.expand_path(@config.client_key, @root_path)
+          @key_pass = @config.client_key_pass
+          verify_thumbprint! if @config.client_cert_thumbprint
+        else
+          export!
+        end
+
+        self
+      end
+
+      protected
+
+      def verify_thumbprint!
+        cert = OpenSSL::X509::Certificate.new(File.read(@cert_path))
+        return if self.class.thumbprint(cert) == @config.client_cert_thumbprint
+
+        raise Errors::ClientCertificateMismatch,
+          thumbprint: @config.client_cert_thumbprint,
+          path: @cert_path
+      end
+
+      # Export the certificate and its private key from the certificate
+      # store. The private key is written encrypted with a passphrase
+      # only kept in memory.
+      def export!
+        thumbprint = @config.client_cert_thumbprint
+        dir = File.join(@data_dir.to_s, "winrm")
+        FileUtils.mkdir_p(dir)
+        pfx_path = File.join(dir, "client.pfx")
+        pass = SecureRandom.hex(16)
+
+        @logger.info("Exporting client certificate #{thumbprint} from the certificate store")
+        result = Vagrant::Util::PowerShell.execute_cmd(
+          "$cert = Get-Item -Path 'Cert:\\CurrentUser\\My\\#{thumbprint}' -ErrorAction Stop; " \
+          "[IO.File]::WriteAllBytes('#{pfx_path.gsub("'", "''")}', $cert.Export('Pfx', '#{pass}'))")
+        if result.nil? || !File.file?(pfx_path)
+          raise Errors::ClientCertificateNotFound, thumbprint: thumbprint
+        end
+
+        pkcs12 = OpenSSL::PKCS12.new(File.binread(pfx_path), pass)
+        @cert_path = File.join(dir, "client.pem")
+        @key_path = File.join(dir, "client.key")
+        @key_pass = pass
+        File.write(@cert_path, pkcs12.certificate.to_pem)
+        File.open(@key_path, "w", 0600) do |f|
+          f.write(pkcs12.key.export(OpenSSL::Cipher.new("aes-256-cbc"), pass))
+        end
+      rescue OpenSSL::PKCS12::PKCS12Error => e
+        @logger.debug("Failed to read the exported certificate: #{e}")
+        raise Errors::ClientCertificateNotFound, thumbprint: thumbprint
+      ensure
+        File.delete(pfx_path) if pfx_path && File.exist?(pfx_path)
+      end
+    end
+  end
+end
@@ -5,6 +5,8 @@ require "log4r"
 require "tempfile"
 require "timeout"
 
+require "vagrant/util/chunked_upload"
+
 require_relative "helper"
 require_relative "shell"
 require_relative "command_filter"
@@ -76,6 +78,8 @@ module VagrantPlugins
               message = "Host appears down."
             rescue Errors::NoRoute
               message = "Host unreachable."
+            rescue Errors::TransportNegotiationFailed
+              message = "Transport negotiation failed."
             rescue Errors::TransientError => e
               # Any other retryable errors
               message = e.message
@@ -177,7 +181,42 @@ module VagrantPlugins
 
       def upload(from, to)
         @logger.info("Uploading: #{from} to #{to}")
-        shell.upload(from, to)
+
+        # Only single files uploaded to a file path are uploaded in chunks
+        # or verified
+        chunked_upload = chunked_uploader
+        single_file = from.is_a?(String) && File.file?(from) && to !~ /[\\\/]$/
+        if single_file && chunked_upload.chunked?(from)
+          return chunked_upload.upload(from, to)
+        end
+
+        result = shell.upload(from, to)
+        chunked_upload.verify!(from, to) if single_file && chunked_upload.checksum?
+        result
+      end
+
+      def chunked_upload?
+        true
+      end
+
+      def upload_chunk(from, to, offset)
+        chunk = "#{to}.chunk"
+        shell.upload(from, chunk)
+        execute(chunked_upload_commands.write_chunk(chunk, to, offset))
+      end
+
+      def file_size(path)
+        size = capture_stdout(chunked_upload_commands.file_size(path))
+        size.empty? ? nil : size.to_i
+      end
+
+      def file_checksum(path, type)
+        checksum = capture_stdout(chunked_upload_commands.file_checksum(path, type))
+        checksum.empty? ? nil : checksum.downcase
+      end
+
+      def move_file(from, to)
+        execute(chunked_upload_commands.move_file(from, to))
       end
 
       def download(from, to)
@@ -195,10 +234,34 @@ module VagrantPlugins
         WinRMShell.new(
           winrm_info[:host],
           winrm_info[:port],
-          @machine.config.winrm
+          @machine.config.winrm,
+          root_path: @machine.env.root_path,
+          data_dir: @machine.data_dir
         )
       end
 
+      # @return [Vagrant::Util::ChunkedUpload] uploader of large files
+      def chunked_uploader
+        Vagrant::Util::ChunkedUpload.new(self,
+          chunk_size: @machine.config.vm.upload_chunk_size,
+          checksum_type: @machine.config.vm.upload_checksum_type)
+      end
+
+      # @return [Module] commands implementing the primitives of chunked
+      #   uploads
+      def chunked_upload_commands
+        Vagrant::Util::ChunkedUpload::PowerShellCommands
+      end
+
+      # @return [String] the stripped standard output of the command
+      def capture_stdout(command)
+        output = ""
+        execute(command, error_check: false) do |type, data|
+          output << data if type == :stdout
+        end
+        output.strip
+      end
+
       # Handles the raw WinRM shell result and converts it to a
       # standard Vagrant communicator result
       def execution_output(output, opts)
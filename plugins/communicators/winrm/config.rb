@@ -4,6 +4,9 @@
 module VagrantPlugins
   module CommunicatorWinRM
     class Config < Vagrant.plugin("2", :config)
+      # Transports which can be used to connect to WinRM
+      VALID_TRANSPORTS = [:negotiate, :ntlm, :plaintext, :ssl].freeze
+
       attr_accessor :username
       attr_accessor :password
       attr_accessor :host
@@ -14,6 +17,10 @@ module VagrantPlugins
       attr_accessor :timeout
       attr_accessor :transport
       attr_accessor :ssl_peer_verification
+      attr_accessor :client_cert
+      attr_accessor :client_key
+      attr_accessor :client_key_pass
+      attr_accessor :client_cert_thumbprint
       attr_accessor :execution_time_limit
       attr_accessor :basic_auth_only
       attr_accessor :codepage
@@ -29,6 +36,10 @@ module VagrantPlugins
         @timeout                = UNSET_VALUE
         @transport              = UNSET_VALUE
         @ssl_peer_verification  = UNSET_VALUE
+        @client_cert            = UNSET_VALUE
+        @client_key             = UNSET_VALUE
+        @client_key_pass        = UNSET_VALUE
+        @client_cert_thumbprint = UNSET_VALUE
         @execution_time_limit   = UNSET_VALUE
         @basic_auth_only        = UNSET_VALUE
         @codepage               = UNSET_VALUE
@@ -38,6 +49,7 @@ module VagrantPlugins
         @username = "vagrant"   if @username == UNSET_VALUE
         @password = "vagrant"   if @password == UNSET_VALUE
         @transport = :negotiate if @transport == UNSET_VALUE
+        @transport = @transport.to_sym if @transport.is_a?(String)
         @host = nil           if @host == UNSET_VALUE
         is_ssl = @transport == :ssl
         @port = (is_ssl ? 5986 : 5985)       if @port == UNSET_VALUE
@@ -46,6 +58,12 @@ module VagrantPlugins
         @retry_delay = 2       if @retry_delay == UNSET_VALUE
         @timeout = 1800        if @timeout == UNSET_VALUE
         @ssl_peer_verification = true if @ssl_peer_verification == UNSET_VALUE
+        @client_cert = nil     if @client_cert == UNSET_VALUE
+        @client_key = nil      if @client_key == UNSET_VALUE
+        @client_key_pass = nil if @client_key_pass == UNSET_VALUE
+        @client_cert_thumbprint = nil if @client_cert_thumbprint == UNSET_VALUE
+        # Thumbprints are shown with spaces by some Windows tools
+        @client_cert_thumbprint = @client_cert_thumbprint.to_s.gsub(/[\s:]/, "").upcase if @client_cert_thumbprint
         @execution_time_limit = "PT2H"   if @execution_time_limit == UNSET_VALUE
         @basic_auth_only = false    if @basic_auth_only == UNSET_VALUE
         @codepage = nil        if @codepage == UNSET_VALUE
@@ -68,6 +86,31 @@ module VagrantPlugins
         unless @basic_auth_only == true || @basic_auth_only == false
           errors << "winrm.basic_auth_only must be a boolean."
         end
+        if !VALID_TRANSPORTS.include?(@transport)
+          errors << "winrm.transport must be one of: #{VALID_TRANSPORTS.join(", ")}."
+        end
+
+        if @client_cert || @client_key || @client_cert_thumbprint
+          if @transport != :ssl
+            errors << "winrm.client_cert requires winrm.transport to be :ssl."
+          end
+          if (@client_cert || @client_key) && (@client_cert.nil? || @client_key.nil?)
+            errors << "winrm.client_cert and winrm.client_key must both be set."
+          end
+          if @client_cert_thumbprint && @client_cert_thumbprint !~ /\A\h{40}\z/
+            errors << "winrm.client_cert_thumbprint must be a SHA1 thumbprint."
+          end
+          if @client_cert_thumbprint && !@client_cert && !Vagrant::Util::Platform.windows?
+            errors << "winrm.client_cert_thumbprint requires winrm.client_cert unless the host is Windows."
+          end
+
+          [[:client_cert, @client_cert], [:client_key, @client_key]].each do |name, path|
+            next if path.nil?
+            if !File.file?(File.expand_path(path, machine.env.root_path))
+              errors << "winrm.#{name} file does not exist: #{path}"
+            end
+          end
+        end
 
         { "WinRM" => errors }
       end
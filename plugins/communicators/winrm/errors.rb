@@ -44,6 +44,18 @@ module VagrantPlugins
         error_key(:ssl_error)
       end
 
+      class SSLUntrustedCertificate < WinRMError
+        error_key(:ssl_untrusted_certificate)
+      end
+
+      class ClientCertificateMismatch < WinRMError
+        error_key(:client_certificate_mismatch)
+      end
+
+      class ClientCertificateNotFound < WinRMError
+        error_key(:client_certificate_not_found)
+      end
+
       class ConnectionTimeout < TransientError
         error_key(:connection_timeout)
       end
@@ -67,6 +79,10 @@ module VagrantPlugins
       class NoRoute < TransientError
         error_key(:no_route)
       end
+
+      class TransportNegotiationFailed < TransientError
+        error_key(:transport_negotiation_failed)
+      end
     end
   end
 end
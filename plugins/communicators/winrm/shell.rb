@@ -15,6 +15,8 @@ end
 require "winrm-elevated"
 require "winrm-fs"
 
+require_relative "client_certificate"
+
 module VagrantPlugins
   module CommunicatorWinRM
     class WinRMShell
@@ -24,6 +26,15 @@ module VagrantPlugins
       # after a hostname update
       INVALID_USERID_EXITCODE = -2147024809
 
+      # Messages of SSL errors raised when the WinRM listener in the
+      # guest closes the connection during the handshake, which happens
+      # while the listener is still starting
+      SSL_NEGOTIATION_ERRORS = [
+        "SSL_connect SYSCALL",
+        "unexpected eof while reading",
+        "connection reset",
+      ].freeze
+
       # These are the exceptions that we retry because they represent
       # errors that are generally fixed from a retry and don't
       # necessarily represent immediate failure cases.
@@ -49,7 +60,15 @@ module VagrantPlugins
       attr_reader :execution_time_limit
       attr_reader :config
 
-      def initialize(host, port, config)
+      # @param [String] host
+      # @param [Integer] port
+      # @param [Config] config
+      # @param [Hash] opts
+      # @option opts [Pathname] :root_path Path client certificate paths
+      #   are relative to
+      # @option opts [Pathname] :data_dir Directory client certificates
+      #   exported from the certificate store are written to
+      def initialize(host, port, config, **opts)
         @logger = Log4r::Logger.new("vagrant::communication::winrmshell")
         @logger.debug("initializing WinRMShell")
 
@@ -58,6 +77,8 @@ module VagrantPlugins
         @username              = config.username
         @password              = config.password
         @execution_time_limit  = config.execution_time_limit
+        @root_path             = opts[:root_path]
+        @data_dir              = opts[:data_dir]
         @config                = config
       end
 
@@ -173,11 +194,31 @@ module VagrantPlugins
               endpoint: endpoint,
               message: exception.message
         when WinRM::WinRMHTTPTransportError
+          # HTTP.sys returns this while the WinRM service in the guest
+          # is still starting and has not registered its listener
+          if exception.status_code.to_i == 503
+            raise Errors::TransportNegotiationFailed,
+              transport: @config.transport,
+              message: exception.message
+          end
+
           raise Errors::ExecutionError,
             shell: shell,
             command: command,
             message: exception.message
         when OpenSSL::SSL::SSLError
+          if exception.message.include?("certificate verify failed")
+            raise Errors::SSLUntrustedCertificate,
+              endpoint: endpoint,
+              message: exception.message
+          end
+
+          if SSL_NEGOTIATION_ERRORS.any? { |m| exception.message.downcase.include?(m.downcase) }
+            raise Errors::TransportNegotiationFailed,
+              transport: @config.transport,
+              message: exception.message
+          end
+
           raise Errors::SSLError, message: exception.message
         when HTTPClient::TimeoutError
           raise Errors::ConnectionTimeout, message: exception.message
@@ -227,16 +268,16 @@ module VagrantPlugins
         case @config.transport.to_sym
         when :ssl
           "https://#{@host}:#{@port}/wsman"
-        when :plaintext, :negotiate
+        when :plaintext, :negotiate, :ntlm
           "http://#{@host}:#{@port}/wsman"
         else
-          raise Errors::WinRMInvalidTransport, transport: @config.transport
+          raise Errors::InvalidTransport, transport: @config.transport
         end
       end
 
       def endpoint_options
-        { endpoint: endpoint,
-          transport: @config.transport,
+        options = { endpoint: endpoint,
+          transport: winrm_transport,
           operation_timeout: @config.timeout,
           user: @username,
           password: @password,
@@ -246,6 +287,30 @@ module VagrantPlugins
           no_ssl_peer_verification: !@config.ssl_peer_verification,
           retry_delay: @config.retry_delay,
           retry_limit: @config.max_tries }
+
+        if ClientCertificate.configured?(@config)
+          options[:client_cert] = client_certificate.cert_path
+          options[:client_key] = client_certificate.key_path
+          options[:key_pass] = client_certificate.key_pass if client_certificate.key_pass
+        end
+
+        options
+      end
+
+      # The WinRM library provides NTLM authentication using the
+      # negotiate transport, and client certificate authentication over
+      # SSL using the certificate transport
+      def winrm_transport
+        transport = @config.transport.to_sym
+        return :negotiate if transport == :ntlm
+        return :certificate if transport == :ssl && ClientCertificate.configured?(@config)
+        transport
+      end
+
+      # @return [ClientCertificate]
+      def client_certificate
+        @client_certificate ||= ClientCertificate.new(@config,
+          root_path: @root_path, data_dir: @data_dir).resolve
       end
 
       def elevated_username
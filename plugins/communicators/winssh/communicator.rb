@@ -157,7 +157,7 @@ module VagrantPlugins
       # even when uploading to a directory where I did not have write
       # privileges. I believe this is because Windows SSH sessions are started
       # in an elevated process.
-      def upload(from, to)
+      def upload(from, to, verify: true)
         to = Vagrant::Util::Platform.unix_windows_path(to)
         @logger.debug("Uploading: #{from} to #{to}")
 
@@ -171,6 +171,7 @@ module VagrantPlugins
           end
         end
 
+        chunked_upload = chunked_uploader
         sftp_connect do |sftp|
           uploader = lambda do |path, remote_dest=nil|
             if File.directory?(path)
@@ -193,11 +194,16 @@ module VagrantPlugins
               @logger.debug("Ensuring remote directory exists for destination upload")
               sftp.mkdir(File.dirname(dest))
               @logger.debug("Uploading file #{path} to remote #{dest}")
-              upload_file = File.open(path, "rb")
-              begin
-                sftp.upload!(upload_file, dest)
-              ensure
-                upload_file.close
+              if chunked_upload.chunked?(path)
+                chunked_upload.upload(path, dest)
+              else
+                upload_file = File.open(path, "rb")
+                begin
+                  sftp.upload!(upload_file, dest)
+                ensure
+                  upload_file.close
+                end
+                chunked_upload.verify!(path, dest) if verify && chunked_upload.checksum?
               end
             end
           end
@@ -205,6 +211,11 @@ module VagrantPlugins
         end
       end
 
+      # Commands of chunked uploads are run with PowerShell
+      def chunked_upload_commands
+        Vagrant::Util::ChunkedUpload::PowerShellCommands
+      end
+
       # Opens an SFTP connection and yields it so that you can download and
       # upload files. SFTP works more reliably than SCP on Windows due to
       # issues with shell quoting and escaping.
@@ -11,6 +11,7 @@ module VagrantPlugins
       class ConfigureNetworks
         include Vagrant::Util
         extend Vagrant::Util::GuestInspection::Linux
+        extend Vagrant::Util::GuestNetworks::Linux
         extend Vagrant::Util::Retryable
 
         NETPLAN_DEFAULT_VERSION = 2
@@ -43,15 +44,7 @@ module VagrantPlugins
                 if network[:type].to_s == "dhcp"
                   entry["dhcp4"] = true
                 else
-                  mask = network[:netmask]
-                  if mask && IPAddr.new(network[:ip]).ipv4?
-                    begin
-                      mask = IPAddr.new(mask).to_i.to_s(2).count("1")
-                    rescue IPAddr::Error
-                      # ignore and use given value
-                    end
-                  end
-                  entry["addresses"] = [[network[:ip], mask].compact.join("/")]
+                  entry["addresses"] = [network_address(network)]
                 end
                 if network[:gateway]
                   entry["gateway4"] = network[:gateway]
@@ -96,28 +89,7 @@ module VagrantPlugins
         def self.configure_networkd(machine, interfaces, comm, networks)
           networks.each do |network|
             dev_name = interfaces[network[:interface]]
-            net_conf = []
-            net_conf << "[Match]"
-            net_conf << "Name=#{dev_name}"
-            net_conf << "[Network]"
-            if network[:type].to_s == "dhcp"
-              net_conf << "DHCP=yes"
-            else
-              mask = network[:netmask]
-              if mask && IPAddr.new(network[:ip]).ipv4?
-                begin
-                  mask = IPAddr.new(mask).to_i.to_s(2).count("1")
-                rescue IPAddr::Error
-                  # ignore and use given value
-                end
-              end
-              address = [network[:ip], mask].compact.join("/")
-              net_conf << "DHCP=no"
-              net_conf << "Address=#{address}"
-              net_conf << "Gateway=#{network[:gateway]}" if network[:gateway]
-            end
-
-            remote_path = upload_tmp_file(comm, net_conf.join("\n"))
+            remote_path = upload_tmp_file(comm, networkd_unit(dev_name, network))
             dest_path = "#{NETWORKD_DIRECTORY}/50-vagrant-#{dev_name}.network"
             comm.sudo(["mkdir -p #{NETWORKD_DIRECTORY}",
               "mv -f '#{remote_path}' '#{dest_path}'",
@@ -0,0 +1,34 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require "log4r"
+
+module VagrantPlugins
+  module GuestLinux
+    module Cap
+      class ConfigureNetworks
+        extend Vagrant::Util::GuestInspection::Linux
+        extend Vagrant::Util::GuestNetworks::Linux
+
+        @@logger = Log4r::Logger.new("vagrant::guest::linux::configure_networks")
+
+        # Configure the guest networks using the network configurator
+        # active within the guest. Guests with a specific implementation
+        # of this capability take precedence over this one.
+        def self.configure_networks(machine, networks)
+          configurator = network_configurator(machine.communicate)
+          @@logger.debug("detected network configurator: #{configurator.inspect}")
+
+          case configurator
+          when :network_manager
+            configure_network_manager(machine, networks)
+          when :networkd
+            configure_networkd(machine, networks)
+          else
+            machine.ui.warn(I18n.t("vagrant.guests.linux.configure_networks_unsupported"))
+          end
+        end
+      end
+    end
+  end
+end
@@ -0,0 +1,41 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+module VagrantPlugins
+  module GuestLinux
+    module Cap
+      class GuestToolsVersion
+        # Commands which output the version of the guest tools for a
+        # provider, in the order they are tried. The VirtualBox provider
+        # reads the version of the Guest Additions itself.
+        VMWARE_COMMANDS = [
+          "vmware-toolbox-cmd -v",
+        ].freeze
+
+        # Returns the version of the guest tools of the provider which
+        # are installed in the guest, such as open-vm-tools.
+        #
+        # @param [Vagrant::Machine] machine
+        # @param [Symbol] provider Name of the provider
+        # @return [String, nil] version, or nil if the tools are not installed
+        def self.guest_tools_version(machine, provider)
+          commands = []
+          commands = VMWARE_COMMANDS if provider.to_s.start_with?("vmware")
+
+          commands.each do |command|
+            output = ""
+            result = machine.communicate.sudo(command, error_check: false) do |type, data|
+              output << data if type == :stdout
+            end
+            next if result != 0
+
+            match = output.match(/(\d+\.\d+(\.\d+)?)/)
+            return match[1] if match
+          end
+
+          nil
+        end
+      end
+    end
+  end
+end
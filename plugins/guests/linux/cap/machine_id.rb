@@ -0,0 +1,34 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+module VagrantPlugins
+  module GuestLinux
+    module Cap
+      class MachineId
+        # Replace the machine ID of the guest with a new random ID. The
+        # D-Bus machine ID is a link to, or a copy of, /etc/machine-id so
+        # both are replaced.
+        #
+        # @param [Vagrant::Machine] machine
+        def self.regenerate_machine_id(machine)
+          machine.communicate.sudo(<<-EOH.gsub(/^ {12}/, ""))
+            if command -v systemd-machine-id-setup >/dev/null 2>&1; then
+              rm -f /etc/machine-id
+              systemd-machine-id-setup
+            elif command -v dbus-uuidgen >/dev/null 2>&1; then
+              rm -f /etc/machine-id
+              dbus-uuidgen --ensure=/etc/machine-id
+            fi
+            if [ -f /var/lib/dbus/machine-id ] && [ ! -L /var/lib/dbus/machine-id ]; then
+              cp /etc/machine-id /var/lib/dbus/machine-id
+            fi
+            # DHCP clients of systemd-networkd identify the guest by its machine ID
+            if command -v systemctl >/dev/null 2>&1; then
+              systemctl try-restart systemd-networkd || true
+            fi
+          EOH
+        end
+      end
+    end
+  end
+end
@@ -79,6 +79,9 @@ SCRIPT
             end
           end
 
+          if options[:readonly]
+            verify_readonly_mount(machine, expanded_guest_path)
+          end
           emit_upstart_notification(machine, expanded_guest_path)
         end
 
@@ -0,0 +1,84 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require "shellwords"
+
+require_relative "../../../synced_folders/unix_mount_helpers"
+
+module VagrantPlugins
+  module GuestLinux
+    module Cap
+      class MountVirtiofsSharedFolder
+        extend SyncedFolder::UnixMountHelpers
+
+        # Mounts a virtiofs folder on linux guest
+        #
+        # @param [Machine] machine
+        # @param [String] tag of the virtiofs device
+        # @param [String] path of mount on guest
+        # @param [Hash] hash of mount options
+        def self.mount_virtiofs_shared_folder(machine, tag, guestpath, options)
+          expanded_guest_path = machine.guest.capability(
+            :shell_expand_guest_path, guestpath)
+          guest_path = Shellwords.escape(expanded_guest_path)
+
+          @@logger.debug("Mounting #{tag} (#{options[:hostpath]} to #{guestpath})")
+
+          if mounted?(machine, guest_path)
+            @@logger.info("Skipping mount: #{guest_path} is already mounted")
+            return
+          end
+
+          mount_options = Array(options[:mount_options]).dup
+          case options[:virtiofs_dax]
+          when true
+            mount_options << "dax"
+          when String, Symbol
+            mount_options << "dax=#{options[:virtiofs_dax]}"
+          end
+          mount_options << "ro" if options[:readonly] && !mount_options.include?("ro")
+
+          mount_command = "mount -t virtiofs"
+          mount_command << " -o #{mount_options.join(",")}" if !mount_options.empty?
+          mount_command << " #{Shellwords.escape(tag)} #{guest_path}"
+
+          # Create the guest path if it doesn't exist
+          machine.communicate.sudo("mkdir -p #{guest_path}")
+
+          # Attempt to mount the folder. We retry here a few times because
+          # the device may not be ready right after boot.
+          retryable(on: Vagrant::Errors::LinuxMountFailed, tries: 3, sleep: 2) do
+            stderr = ""
+            status = machine.communicate.sudo(mount_command, error_check: false) do |type, data|
+              stderr << data if type == :stderr
+            end
+            if status != 0
+              raise Vagrant::Errors::LinuxMountFailed,
+                command: mount_command,
+                output: stderr
+            end
+          end
+
+          verify_readonly_mount(machine, expanded_guest_path) if options[:readonly]
+          emit_upstart_notification(machine, guest_path)
+        end
+
+        def self.unmount_virtiofs_shared_folder(machine, guestpath, options)
+          expanded_guest_path = machine.guest.capability(
+            :shell_expand_guest_path, guestpath)
+          guest_path = Shellwords.escape(expanded_guest_path)
+          return if !mounted?(machine, guest_path)
+
+          result = machine.communicate.sudo("umount #{guest_path}", error_check: false)
+          if result == 0
+            machine.communicate.sudo("rmdir #{guest_path}", error_check: false)
+          end
+        end
+
+        def self.mounted?(machine, guest_path)
+          machine.communicate.test("mountpoint -q #{guest_path}")
+        end
+      end
+    end
+  end
+end
@@ -61,7 +61,11 @@ module VagrantPlugins
 
           # Chown the directory to the proper user. We skip this if the
           # mount options contained a readonly flag, because it won't work.
-          if !options[:mount_options] || !options[:mount_options].include?("ro")
+          readonly = options[:readonly] ||
+            (options[:mount_options] && options[:mount_options].include?("ro"))
+          if readonly
+            verify_readonly_mount(machine, guestpath) if options[:readonly]
+          else
             chown_command = "chown #{mount_uid}:#{mount_gid} #{guest_path}"
             machine.communicate.sudo(chown_command)
           end
@@ -29,6 +29,7 @@ module VagrantPlugins
             if opts[:mount_options]
               mount_opts = mount_opts + opts[:mount_options].dup
             end
+            mount_opts << "ro" if opts[:readonly] && !mount_opts.include?("ro")
             mount_opts = mount_opts.join(",")
 
             machine.communicate.sudo("mkdir -p #{guest_path}")
@@ -42,6 +43,7 @@ module VagrantPlugins
               )
             end
 
+            verify_readonly_mount(machine, opts[:guestpath]) if opts[:readonly]
             emit_upstart_notification(machine, guest_path)
           end
         end
@@ -0,0 +1,25 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+module VagrantPlugins
+  module GuestLinux
+    module Cap
+      class ResourcesHotplug
+        # Returns the resources which can be added to the guest while
+        # it is running. Hot-plugged memory is only usable if the kernel
+        # brings new memory blocks online automatically.
+        #
+        # @return [Array<Symbol>]
+        def self.resources_hotplug(machine)
+          comm = machine.communicate
+          result = []
+          result << :cpus if comm.test("ls /sys/devices/system/cpu/cpu*/online")
+          if comm.test("grep -q '^online' /sys/devices/system/memory/auto_online_blocks")
+            result << :memory
+          end
+          result
+        end
+      end
+    end
+  end
+end
@@ -0,0 +1,56 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require "vagrant/util/guest_inspection"
+
+module VagrantPlugins
+  module GuestLinux
+    module Cap
+      class SyncTime
+        extend Vagrant::Util::GuestInspection::Linux
+
+        # Offset of the system clock reported by `chronyc tracking`
+        CHRONY_OFFSET = /^System time\s*:\s*(.+)$/
+
+        # Offset of the system clock reported by `timedatectl timesync-status`
+        TIMESYNCD_OFFSET = /^\s*Offset:\s*(.+)$/
+
+        # Synchronize the clock of the guest with the time service in use,
+        # or with the hardware clock if no time service is in use.
+        #
+        # @param [Vagrant::Machine] machine
+        # @return [Hash] offsets of the clock `:before` and `:after`
+        #   synchronizing, when reported by the time service
+        def self.sync_time(machine)
+          comm = machine.communicate
+
+          if comm.test("chronyc tracking", sudo: true)
+            before = offset(comm, "chronyc tracking", CHRONY_OFFSET)
+            comm.sudo("chronyc makestep")
+            after = offset(comm, "chronyc tracking", CHRONY_OFFSET)
+          elsif systemd?(comm) && systemd_controlled?(comm, "systemd-timesyncd")
+            # Restarting the service synchronizes the clock right away. The
+            # offset is only reported by recent versions of systemd.
+            before = offset(comm, "timedatectl timesync-status", TIMESYNCD_OFFSET)
+            comm.sudo("systemctl restart systemd-timesyncd")
+          else
+            comm.sudo("hwclock --hctosys")
+          end
+
+          {before: before, after: after}
+        end
+
+        # @return [String, nil] the offset matched in the output of the command
+        def self.offset(comm, command, pattern)
+          output = ""
+          comm.execute(command, sudo: true, error_check: false) do |type, data|
+            output << data if type == :stdout
+          end
+
+          match = output.match(pattern)
+          match[1].strip if match
+        end
+      end
+    end
+  end
+end
@@ -24,6 +24,11 @@ module VagrantPlugins
         Cap::ChooseAddressableIPAddr
       end
 
+      guest_capability(:linux, :configure_networks) do
+        require_relative "cap/configure_networks"
+        Cap::ConfigureNetworks
+      end
+
       guest_capability(:linux, :create_tmp_path) do
         require_relative "cap/file_system"
         Cap::FileSystem
@@ -39,6 +44,11 @@ module VagrantPlugins
         Cap::FileSystem
       end
 
+      guest_capability(:linux, :guest_tools_version) do
+        require_relative "cap/guest_tools_version"
+        Cap::GuestToolsVersion
+      end
+
       guest_capability(:linux, :halt) do
         require_relative "cap/halt"
         Cap::Halt
@@ -69,6 +79,11 @@ module VagrantPlugins
         Cap::MountVirtualBoxSharedFolder
       end
 
+      guest_capability(:linux, :mount_virtiofs_shared_folder) do
+        require_relative "cap/mount_virtiofs_shared_folder"
+        Cap::MountVirtiofsSharedFolder
+      end
+
       guest_capability(:linux, :persist_mount_shared_folder) do
         require_relative "cap/persist_mount_shared_folder"
         Cap::PersistMountSharedFolder
@@ -105,11 +120,21 @@ module VagrantPlugins
         Cap::Reboot
       end
 
+      guest_capability(:linux, :regenerate_machine_id) do
+        require_relative "cap/machine_id"
+        Cap::MachineId
+      end
+
       guest_capability(:linux, :remove_public_key) do
         require_relative "cap/public_key"
         Cap::PublicKey
       end
 
+      guest_capability(:linux, :resources_hotplug) do
+        require_relative "cap/resources_hotplug"
+        Cap::ResourcesHotplug
+      end
+
       guest_capability(:linux, :rsync_installed) do
         require_relative "cap/rsync"
         Cap::RSync
@@ -130,10 +155,20 @@ module VagrantPlugins
         Cap::RSync
       end
 
+      guest_capability(:linux, :sync_time) do
+        require_relative "cap/sync_time"
+        Cap::SyncTime
+      end
+
       guest_capability(:linux, :unmount_virtualbox_shared_folder) do
         require_relative "cap/mount_virtualbox_shared_folder"
         Cap::MountVirtualBoxSharedFolder
       end
+
+      guest_capability(:linux, :unmount_virtiofs_shared_folder) do
+        require_relative "cap/mount_virtiofs_shared_folder"
+        Cap::MountVirtiofsSharedFolder
+      end
     end
   end
 end
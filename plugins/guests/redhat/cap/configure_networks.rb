@@ -21,10 +21,15 @@ module VagrantPlugins
           network_scripts_dir = machine.guest.capability(:network_scripts_dir)
           @logger.debug("guest network scripts directory: #{network_scripts_dir}")
 
+          # Boxes which replace the default network service with
+          # systemd-networkd ignore both the network scripts and the
+          # NetworkManager connections, so configure networkd directly.
           # The legacy configuration will handle rhel/centos pre-10
           # versions. The newer versions have a different path for
           # network configuration files.
-          if network_scripts_dir.end_with?("network-scripts")
+          if network_configurator(machine.communicate) == :networkd
+            configure_networkd(machine, networks)
+          elsif network_scripts_dir.end_with?("network-scripts")
             configure_networks_legacy(machine, networks)
           else
             # Recent versions use Network Manager
@@ -0,0 +1,43 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+module VagrantPlugins
+  module GuestWindows
+    module Cap
+      module SyncTime
+        # Offset of the clock reported by `w32tm /query /status /verbose`
+        OFFSET = /^Phase Offset:\s*(.+)$/
+
+        # Synchronize the clock of the guest with the Windows Time service.
+        #
+        # @param [Vagrant::Machine] machine
+        # @return [Hash] offsets of the clock `:before` and `:after`
+        #   synchronizing, when reported by the Windows Time service
+        def self.sync_time(machine)
+          comm = machine.communicate
+          comm.execute("Start-Service -Name w32time")
+
+          before = offset(comm)
+          comm.execute(<<-EOH.gsub(/^ {12}/, ""))
+            w32tm /resync /force
+            if ($LASTEXITCODE -ne 0) { exit $LASTEXITCODE }
+          EOH
+          after = offset(comm)
+
+          {before: before, after: after}
+        end
+
+        # @return [String, nil] the offset reported by the Windows Time service
+        def self.offset(comm)
+          output = ""
+          comm.execute("w32tm /query /status /verbose", error_check: false) do |type, data|
+            output << data if type == :stdout
+          end
+
+          match = output.match(OFFSET)
+          match[1].strip if match
+        end
+      end
+    end
+  end
+end
@@ -102,6 +102,11 @@ module VagrantPlugins
         Cap::PublicKey
       end
 
+      guest_capability(:windows, :sync_time) do
+        require_relative "cap/sync_time"
+        Cap::SyncTime
+      end
+
       protected
 
       def self.init!
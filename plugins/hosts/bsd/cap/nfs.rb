@@ -12,14 +12,134 @@ module VagrantPlugins
     module Cap
       class NFS
         def self.nfs_export(environment, ui, id, ips, folders)
-          nfs_exports_template = environment.host.capability(:nfs_exports_template)
+          nfs_export_batch(environment, ui, [[id, ips, folders]])
+        end
+
+        # Export the folders of several machines in a single pass. The
+        # blocks of all the machines are written at once, and nfsd is
+        # updated once for all of them.
+        #
+        # @param [Array<Array(String, Array<String>, Hash)>] exports ID,
+        #   IPs and folders of each machine
+        def self.nfs_export_batch(environment, ui, exports)
           nfs_restart_command  = environment.host.capability(:nfs_restart_command)
           nfs_status_command  = environment.host.capability(:nfs_status_command)
           nfs_update_command  = environment.host.capability(:nfs_update_command)
-          logger = Log4r::Logger.new("vagrant::hosts::bsd")
 
           nfs_checkexports! if File.file?("/etc/exports")
 
+          blocks = exports.map do |id, ips, folders|
+            [id, nfs_export_output(environment, id, ips, folders)]
+          end
+
+          nfs_exports_lock do
+            editor = Vagrant::Util::StringBlockEditor.new(nfs_exports_content)
+
+            # If the exports of the machines are unchanged there is nothing
+            # to do, and no need for sudo to check the status of nfsd
+            changed = blocks.reject do |id, output|
+              nfs_export_lines(editor.get("#{Process.uid} #{id}")) == nfs_export_lines(output)
+            end
+            if changed.empty?
+              ui.info I18n.t("vagrant.hosts.bsd.nfs_export_unchanged")
+              return
+            end
+
+            # The sleep ensures that the output is truly flushed before any `sudo`
+            # commands are issued.
+            ui.info I18n.t("vagrant.hosts.bsd.nfs_export")
+            sleep 0.5
+
+            # Only use "sudo" if we can't write to /etc/exports directly
+            sudo_command = ""
+            sudo_command = "sudo " if !File.writable?("/etc/exports")
+
+            changed.each do |id, output|
+              # First, clean up the old entry
+              nfs_cleanup(id)
+
+              # Output the rendered template into the exports
+              output.split("\n").each do |line|
+                line = Vagrant::Util::ShellQuote.escape(line, "'")
+                system(
+                  "echo '#{line}' | " +
+                  "#{sudo_command}/usr/bin/tee -a /etc/exports >/dev/null")
+              end
+            end
+
+            # Check if nfsd is running, and update or restart depending on the result
+            if nfs_running?(nfs_status_command)
+              system(*nfs_update_command)
+            else
+              system(*nfs_restart_command)
+            end
+          end
+        end
+
+        def self.nfs_exports_template(environment)
+          "nfs/exports_bsd"
+        end
+
+        def self.nfs_installed(environment)
+          !!Vagrant::Util::Which.which("nfsd")
+        end
+
+        def self.nfs_prune(environment, ui, valid_ids)
+          return if !File.exist?("/etc/exports")
+
+          logger = Log4r::Logger.new("vagrant::hosts::bsd")
+          logger.info("Pruning invalid NFS entries...")
+
+          output = false
+          user = Process.uid
+
+          nfs_exports_lock do
+            File.read("/etc/exports").lines.each do |line|
+              if id = line[/^# VAGRANT-BEGIN:( #{user})? ([\.\/A-Za-z0-9\-_:]+?)$/, 2]
+                if valid_ids.include?(id)
+                  logger.debug("Valid ID: #{id}")
+                else
+                  if !output
+                    # We want to warn the user but we only want to output once
+                    ui.info I18n.t("vagrant.hosts.bsd.nfs_prune")
+                    output = true
+                  end
+
+                  logger.info("Invalid ID, pruning: #{id}")
+                  nfs_cleanup(id)
+                end
+              end
+            end
+          end
+        rescue Errno::EACCES
+          raise Vagrant::Errors::NFSCantReadExports
+        end
+
+        def self.nfs_running?(check_command)
+          Vagrant::Util::Subprocess.execute(*check_command).exit_code == 0
+        end
+
+        def self.nfs_restart_command(environment)
+          ["sudo", "nfsd", "restart"]
+        end
+
+        def self.nfs_update_command(environment)
+          ["sudo", "nfsd", "update"]
+        end
+
+        def self.nfs_status_command(environment)
+          ["sudo", "nfsd", "status"]
+        end
+
+        protected
+
+        # Render the exports of the folders of a machine
+        #
+        # @return [String]
+        def self.nfs_export_output(environment, id, ips, folders)
+          nfs_exports_template = environment.host.capability(:nfs_exports_template)
+          logger = Log4r::Logger.new("vagrant::hosts::bsd")
+
           # We need to build up mapping of directories that are enclosed
           # within each other because the exports file has to have subdirectories
           # of an exported directory on the same line. e.g.:
@@ -93,95 +213,48 @@ module VagrantPlugins
             logger.info("NFS OPTS: #{opts.inspect}")
           end
 
-          output = Vagrant::Util::TemplateRenderer.render(nfs_exports_template,
+          Vagrant::Util::TemplateRenderer.render(nfs_exports_template,
                                            uuid: id,
                                            ips: ips,
                                            folders: dirmap,
                                            user: Process.uid)
+        end
 
-          # The sleep ensures that the output is truly flushed before any `sudo`
-          # commands are issued.
-          ui.info I18n.t("vagrant.hosts.bsd.nfs_export")
-          sleep 0.5
-
-          # First, clean up the old entry
-          nfs_cleanup(id)
-
-          # Only use "sudo" if we can't write to /etc/exports directly
-          sudo_command = ""
-          sudo_command = "sudo " if !File.writable?("/etc/exports")
-
-          # Output the rendered template into the exports
-          output.split("\n").each do |line|
-            line = Vagrant::Util::ShellQuote.escape(line, "'")
-            system(
-              "echo '#{line}' | " +
-              "#{sudo_command}/usr/bin/tee -a /etc/exports >/dev/null")
+        # Export lines of a block of the exports file, without comments
+        # and blank lines
+        #
+        # @param [String, nil] content
+        # @return [Array<String>]
+        def self.nfs_export_lines(content)
+          content.to_s.lines.map(&:strip).reject do |line|
+            line.empty? || line.start_with?("#")
           end
+        end
 
-          # Check if nfsd is running, and update or restart depending on the result
-          if nfs_running?(nfs_status_command)
-            system(*nfs_update_command)
-          else
-            system(*nfs_restart_command)
+        # Runs the block while holding the lock for editing the exports
+        # file, so concurrent Vagrant processes do not overwrite each
+        # others changes
+        def self.nfs_exports_lock
+          nfs_exports_lock_path.dirname.mkpath
+          File.open(nfs_exports_lock_path, File::RDONLY | File::CREAT, 0644) do |lock|
+            lock.flock(File::LOCK_EX)
+            yield
           end
         end
 
-        def self.nfs_exports_template(environment)
-          "nfs/exports_bsd"
+        # @return [Pathname] path of the lock for editing the exports file
+        def self.nfs_exports_lock_path
+          Vagrant.user_data_path.join("nfs-exports.lock")
         end
 
-        def self.nfs_installed(environment)
-          !!Vagrant::Util::Which.which("nfsd")
-        end
-
-        def self.nfs_prune(environment, ui, valid_ids)
-          return if !File.exist?("/etc/exports")
-
-          logger = Log4r::Logger.new("vagrant::hosts::bsd")
-          logger.info("Pruning invalid NFS entries...")
-
-          output = false
-          user = Process.uid
+        def self.nfs_exports_content
+          return "" if !File.exist?("/etc/exports")
 
-          File.read("/etc/exports").lines.each do |line|
-            if id = line[/^# VAGRANT-BEGIN:( #{user})? ([\.\/A-Za-z0-9\-_:]+?)$/, 2]
-              if valid_ids.include?(id)
-                logger.debug("Valid ID: #{id}")
-              else
-                if !output
-                  # We want to warn the user but we only want to output once
-                  ui.info I18n.t("vagrant.hosts.bsd.nfs_prune")
-                  output = true
-                end
-
-                logger.info("Invalid ID, pruning: #{id}")
-                nfs_cleanup(id)
-              end
-            end
-          end
+          File.read("/etc/exports")
         rescue Errno::EACCES
           raise Vagrant::Errors::NFSCantReadExports
         end
 
-        def self.nfs_running?(check_command)
-          Vagrant::Util::Subprocess.execute(*check_command).exit_code == 0
-        end
-
-        def self.nfs_restart_command(environment)
-          ["sudo", "nfsd", "restart"]
-        end
-
-        def self.nfs_update_command(environment)
-          ["sudo", "nfsd", "update"]
-        end
-
-        def self.nfs_status_command(environment)
-          ["sudo", "nfsd", "status"]
-        end
-
-        protected
-
         def self.nfs_cleanup(id)
           return if !File.exist?("/etc/exports")
 
@@ -14,11 +14,26 @@ module VagrantPlugins
         Host
       end
 
+      host_capability("bsd", "hosts_file_remove") do
+        require_relative "cap/hosts"
+        Cap::Hosts
+      end
+
+      host_capability("bsd", "hosts_file_update") do
+        require_relative "cap/hosts"
+        Cap::Hosts
+      end
+
       host_capability("bsd", "nfs_export") do
         require_relative "cap/nfs"
         Cap::NFS
       end
 
+      host_capability("bsd", "nfs_export_batch") do
+        require_relative "cap/nfs"
+        Cap::NFS
+      end
+
       host_capability("bsd", "nfs_exports_template") do
         require_relative "cap/nfs"
         Cap::NFS
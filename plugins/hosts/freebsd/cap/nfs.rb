@@ -11,14 +11,20 @@ module VagrantPlugins
     module Cap
       class NFS
         def self.nfs_export(environment, ui, id, ips, folders)
-          folders.each do |folder_name, folder_values|
-            if folder_values[:hostpath] =~ /\s+/
-              raise Vagrant::Errors::VagrantError,
-                _key: :freebsd_nfs_whitespace
+          nfs_export_batch(environment, ui, [[id, ips, folders]])
+        end
+
+        def self.nfs_export_batch(environment, ui, exports)
+          exports.each do |_, _, folders|
+            folders.each do |folder_name, folder_values|
+              if folder_values[:hostpath] =~ /\s+/
+                raise Vagrant::Errors::VagrantError,
+                  _key: :freebsd_nfs_whitespace
+              end
             end
           end
 
-          HostBSD::Cap::NFS.nfs_export(environment, ui, id, ips, folders)
+          HostBSD::Cap::NFS.nfs_export_batch(environment, ui, exports)
         end
 
         def self.nfs_exports_template(environment)
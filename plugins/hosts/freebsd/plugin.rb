@@ -19,6 +19,11 @@ module VagrantPlugins
         Cap::NFS
       end
 
+      host_capability("freebsd", "nfs_export_batch") do
+        require_relative "cap/nfs"
+        Cap::NFS
+      end
+
       # BSD-specific helpers
       host_capability("freebsd", "nfs_exports_template") do
         require_relative "cap/nfs"
@@ -0,0 +1,14 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require "vagrant/util/hosts_file"
+
+module VagrantPlugins
+  module HostLinux
+    module Cap
+      class Hosts
+        extend Vagrant::Util::HostsFile::Capability
+      end
+    end
+  end
+end
@@ -63,31 +63,59 @@ module VagrantPlugins
         end
 
         def self.nfs_export(env, ui, id, ips, folders)
+          nfs_export_batch(env, ui, [[id, ips, folders]])
+        end
+
+        # Export the folders of several machines in a single pass. The
+        # blocks of all the machines are written at once, and the exports
+        # are applied once for all of them.
+        #
+        # @param [Array<Array(String, Array<String>, Hash)>] exports ID,
+        #   IPs and folders of each machine
+        def self.nfs_export_batch(env, ui, exports)
           # Get some values we need before we do anything
           nfs_apply_command = env.host.capability(:nfs_apply_command)
           nfs_check_command = env.host.capability(:nfs_check_command)
           nfs_start_command = env.host.capability(:nfs_start_command)
 
-          nfs_opts_setup(folders)
-          folders = folder_dupe_check(folders)
-          ips = ips.uniq
-          output = Vagrant::Util::TemplateRenderer.render('nfs/exports_linux',
-                                           uuid: id,
-                                           ips: ips,
-                                           folders: folders,
-                                           user: Process.uid)
+          blocks = exports.map do |id, ips, folders|
+            nfs_opts_setup(folders)
+            folders = folder_dupe_check(folders)
+            output = Vagrant::Util::TemplateRenderer.render('nfs/exports_linux',
+                                             uuid: id,
+                                             ips: ips.uniq,
+                                             folders: folders,
+                                             user: Process.uid)
+            ["#{Process.uid} #{id}", output]
+          end
 
-          ui.info I18n.t("vagrant.hosts.linux.nfs_export")
-          sleep 0.5
+          nfs_exports_lock do
+            editor = Vagrant::Util::StringBlockEditor.new(nfs_exports_content)
+            running = nfs_running?(nfs_check_command)
 
-          nfs_cleanup("#{Process.uid} #{id}")
-          output = nfs_exports_content + output
-          nfs_write_exports(output)
+            # If the exports of the machines are unchanged and already
+            # applied there is nothing to do, and no need for sudo
+            changed = blocks.reject do |key, output|
+              nfs_export_lines(editor.get(key)) == nfs_export_lines(output)
+            end
+            if running && changed.empty?
+              ui.info I18n.t("vagrant.hosts.linux.nfs_export_unchanged")
+              return true
+            end
 
-          if nfs_running?(nfs_check_command)
-            Vagrant::Util::Subprocess.execute("sudo", *Shellwords.split(nfs_apply_command)).exit_code == 0
-          else
-            Vagrant::Util::Subprocess.execute("sudo", *Shellwords.split(nfs_start_command)).exit_code == 0
+            ui.info I18n.t("vagrant.hosts.linux.nfs_export")
+            sleep 0.5
+
+            # Replace the blocks of these machines, leaving the blocks of
+            # other machines in place, and apply all the exports at once
+            changed.each { |key, _| editor.delete(key) }
+            nfs_write_exports(editor.value + changed.map { |_, output| output.chomp + "\n" }.join)
+
+            if running
+              Vagrant::Util::Subprocess.execute("sudo", *Shellwords.split(nfs_apply_command)).exit_code == 0
+            else
+              Vagrant::Util::Subprocess.execute("sudo", *Shellwords.split(nfs_start_command)).exit_code == 0
+            end
           end
         end
 
@@ -124,7 +152,7 @@ module VagrantPlugins
           logger.debug("NFS export IDs to be removed: #{remove_ids}")
           if !remove_ids.empty?
             ui.info I18n.t("vagrant.hosts.linux.nfs_prune")
-            nfs_cleanup(remove_ids)
+            nfs_exports_lock { nfs_cleanup(remove_ids) }
           end
         end
 
@@ -161,6 +189,33 @@ module VagrantPlugins
           return_folders
         end
 
+        # Export lines of a block of the exports file, without comments
+        # and blank lines
+        #
+        # @param [String, nil] content
+        # @return [Array<String>]
+        def self.nfs_export_lines(content)
+          content.to_s.lines.map(&:strip).reject do |line|
+            line.empty? || line.start_with?("#")
+          end
+        end
+
+        # Runs the block while holding the lock for editing the exports
+        # file, so concurrent Vagrant processes do not overwrite each
+        # others changes
+        def self.nfs_exports_lock
+          nfs_exports_lock_path.dirname.mkpath
+          File.open(nfs_exports_lock_path, File::RDONLY | File::CREAT, 0644) do |lock|
+            lock.flock(File::LOCK_EX)
+            yield
+          end
+        end
+
+        # @return [Pathname] path of the lock for editing the exports file
+        def self.nfs_exports_lock_path
+          Vagrant.user_data_path.join("nfs-exports.lock")
+        end
+
         def self.nfs_cleanup(remove_ids)
           return if !File.exist?(NFS_EXPORTS_PATH)
 
@@ -175,7 +230,7 @@ module VagrantPlugins
         end
 
         def self.nfs_write_exports(new_exports_content)
-          if(nfs_exports_content != new_exports_content.strip)
+          if(nfs_exports_content.strip != new_exports_content.strip)
             begin
               exports_path = Pathname.new(NFS_EXPORTS_PATH)
 
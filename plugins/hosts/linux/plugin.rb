@@ -24,11 +24,26 @@ module VagrantPlugins
         Cap::FsISO
       end
 
+      host_capability("linux", "hosts_file_remove") do
+        require_relative "cap/hosts"
+        Cap::Hosts
+      end
+
+      host_capability("linux", "hosts_file_update") do
+        require_relative "cap/hosts"
+        Cap::Hosts
+      end
+
       host_capability("linux", "nfs_export") do
         require_relative "cap/nfs"
         Cap::NFS
       end
 
+      host_capability("linux", "nfs_export_batch") do
+        require_relative "cap/nfs"
+        Cap::NFS
+      end
+
       host_capability("linux", "nfs_installed") do
         require_relative "cap/nfs"
         Cap::NFS
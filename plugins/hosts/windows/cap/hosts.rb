@@ -0,0 +1,24 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require "vagrant/util/hosts_file"
+
+module VagrantPlugins
+  module HostWindows
+    module Cap
+      class Hosts
+        extend Vagrant::Util::HostsFile::Capability
+
+        # @return [String] path to the hosts file
+        def self.hosts_file_path
+          File.join(ENV.fetch("SystemRoot", "C:\\Windows"), "System32", "drivers", "etc", "hosts")
+        end
+
+        # The hosts file can only be modified from an administrator shell
+        def self.hosts_file_sudo?
+          false
+        end
+      end
+    end
+  end
+end
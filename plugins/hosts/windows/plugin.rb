@@ -29,6 +29,16 @@ module VagrantPlugins
         Cap::ProviderInstallVirtualBox
       end
 
+      host_capability("windows", "hosts_file_remove") do
+        require_relative "cap/hosts"
+        Cap::Hosts
+      end
+
+      host_capability("windows", "hosts_file_update") do
+        require_relative "cap/hosts"
+        Cap::Hosts
+      end
+
       host_capability("windows", "nfs_installed") do
         require_relative "cap/nfs"
         Cap::NFS
@@ -13,6 +13,7 @@ module VagrantPlugins
       attr_accessor :forward_env
       attr_accessor :guest_port
       attr_accessor :keep_alive
+      attr_accessor :connection_pool
       attr_accessor :shell
       attr_accessor :proxy_command
       attr_accessor :ssh_command
@@ -30,6 +31,7 @@ module VagrantPlugins
         @forward_env             = UNSET_VALUE
         @guest_port              = UNSET_VALUE
         @keep_alive              = UNSET_VALUE
+        @connection_pool         = UNSET_VALUE
         @proxy_command           = UNSET_VALUE
         @ssh_command             = UNSET_VALUE
         @pty                     = UNSET_VALUE
@@ -54,6 +56,7 @@ module VagrantPlugins
         @forward_env   = false if @forward_env == UNSET_VALUE
         @guest_port = 22 if @guest_port == UNSET_VALUE
         @keep_alive = true if @keep_alive == UNSET_VALUE
+        @connection_pool = false if @connection_pool == UNSET_VALUE
         @proxy_command = nil if @proxy_command == UNSET_VALUE
         @ssh_command = nil if @ssh_command == UNSET_VALUE
         @pty        = false if @pty == UNSET_VALUE
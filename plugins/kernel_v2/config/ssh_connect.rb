@@ -6,13 +6,17 @@ module VagrantPlugins
     class SSHConnectConfig < Vagrant.plugin("2", :config)
       DEFAULT_SSH_CONNECT_RETRIES = 5
       DEFAULT_SSH_CONNECT_RETRY_DELAY = 2
+      DEFAULT_SSH_CONNECT_RETRY_MAX_DELAY = 30
       DEFAULT_SSH_CONNECT_TIMEOUT = 15
+      VALID_SSH_CONNECT_RETRY_BACKOFFS = [:fixed, :exponential].freeze
 
       attr_accessor :host
       attr_accessor :port
       attr_accessor :config
       attr_accessor :connect_retries
       attr_accessor :connect_retry_delay
+      attr_accessor :connect_retry_backoff
+      attr_accessor :connect_retry_max_delay
       attr_accessor :connect_timeout
       attr_accessor :private_key_path
       attr_accessor :username
@@ -34,6 +38,8 @@ module VagrantPlugins
         @config           = UNSET_VALUE
         @connect_retries  = UNSET_VALUE
         @connect_retry_delay = UNSET_VALUE
+        @connect_retry_backoff = UNSET_VALUE
+        @connect_retry_max_delay = UNSET_VALUE
         @connect_timeout  = UNSET_VALUE
         @private_key_path = UNSET_VALUE
         @username         = UNSET_VALUE
@@ -69,6 +75,8 @@ module VagrantPlugins
         @connect_timeout  = DEFAULT_SSH_CONNECT_TIMEOUT if @connect_timeout == UNSET_VALUE
         @connect_retries  = DEFAULT_SSH_CONNECT_RETRIES if @connect_retries == UNSET_VALUE
         @connect_retry_delay = DEFAULT_SSH_CONNECT_RETRY_DELAY if @connect_retry_delay == UNSET_VALUE
+        @connect_retry_backoff = :fixed if @connect_retry_backoff == UNSET_VALUE
+        @connect_retry_max_delay = DEFAULT_SSH_CONNECT_RETRY_MAX_DELAY if @connect_retry_max_delay == UNSET_VALUE
 
         if @private_key_path && !@private_key_path.is_a?(Array)
           @private_key_path = [@private_key_path]
@@ -111,6 +119,10 @@ module VagrantPlugins
         if @key_type
           @key_type = @key_type.to_sym
         end
+
+        if @connect_retry_backoff.is_a?(String)
+          @connect_retry_backoff = @connect_retry_backoff.to_sym
+        end
       end
 
       # NOTE: This is _not_ a valid config validation method, since it
@@ -179,6 +191,26 @@ module VagrantPlugins
           )
         end
 
+        if !VALID_SSH_CONNECT_RETRY_BACKOFFS.include?(@connect_retry_backoff)
+          errors << I18n.t(
+            "vagrant.config.ssh.connect_retry_backoff_invalid",
+            given: @connect_retry_backoff.to_s,
+            supported: VALID_SSH_CONNECT_RETRY_BACKOFFS.join(", ")
+          )
+        end
+
+        if !@connect_retry_max_delay.is_a?(Numeric)
+          errors << I18n.t(
+            "vagrant.config.ssh.connect_retry_max_delay_invalid_type",
+            given: @connect_retry_max_delay.class.name
+          )
+        elsif @connect_retry_max_delay < 0
+          errors << I18n.t(
+            "vagrant.config.ssh.connect_retry_max_delay_invalid_value",
+            given: @connect_retry_max_delay.to_s
+          )
+        end
+
         if @key_type != :auto && !Vagrant::Util::Keypair.valid_type?(@key_type)
           errors << I18n.t(
             "vagrant.config.ssh.connect_invalid_key_type",
@@ -9,6 +9,7 @@ module VagrantPlugins
       attr_accessor :host
       attr_accessor :sensitive
       attr_accessor :plugins
+      attr_accessor :deterministic_ids
 
       VALID_PLUGIN_KEYS = ["sources", "version", "entry_point"].map(&:freeze).freeze
       INVALID_PLUGIN_FORMAT = :invalid_plugin_format
@@ -17,12 +18,15 @@ module VagrantPlugins
         @host = UNSET_VALUE
         @sensitive = UNSET_VALUE
         @plugins = UNSET_VALUE
+        @deterministic_ids = UNSET_VALUE
       end
 
       def finalize!
         @host = :detect if @host == UNSET_VALUE
         @host = @host.to_sym if @host
         @sensitive = nil if @sensitive == UNSET_VALUE
+        @deterministic_ids = false if @deterministic_ids == UNSET_VALUE
+        @deterministic_ids = !!@deterministic_ids
         if @plugins == UNSET_VALUE
           @plugins = {}
         else
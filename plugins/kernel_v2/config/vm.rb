@@ -41,6 +41,7 @@ module VagrantPlugins
       attr_accessor :box_download_ca_cert
       attr_accessor :box_download_ca_path
       attr_accessor :box_download_checksum
+      attr_accessor :box_download_checksum_key
       attr_accessor :box_download_checksum_type
       attr_accessor :box_download_client_cert
       attr_accessor :box_download_disable_ssl_revoke_best_effort
@@ -49,10 +50,18 @@ module VagrantPlugins
       attr_accessor :box_download_options
       attr_accessor :cloud_init_first_boot_only
       attr_accessor :communicator
+      attr_accessor :depends_on
+      attr_accessor :environment
+      attr_accessor :graceful_halt_command
       attr_accessor :graceful_halt_timeout
       attr_accessor :guest
+      attr_accessor :guest_cache
       attr_accessor :hostname
       attr_accessor :post_up_message
+      attr_accessor :provider_order
+      attr_accessor :sync_time
+      attr_accessor :upload_checksum_type
+      attr_accessor :upload_chunk_size
       attr_accessor :usable_port_range
       attr_reader :provisioners
       attr_reader :disks
@@ -77,6 +86,7 @@ module VagrantPlugins
         @box_download_ca_cert          = UNSET_VALUE
         @box_download_ca_path          = UNSET_VALUE
         @box_download_checksum         = UNSET_VALUE
+        @box_download_checksum_key     = UNSET_VALUE
         @box_download_checksum_type    = UNSET_VALUE
         @box_download_client_cert      = UNSET_VALUE
         @box_download_disable_ssl_revoke_best_effort = UNSET_VALUE
@@ -90,10 +100,18 @@ module VagrantPlugins
         @clone                         = UNSET_VALUE
         @cloud_init_first_boot_only    = UNSET_VALUE
         @communicator                  = UNSET_VALUE
+        @depends_on                    = UNSET_VALUE
+        @environment                   = UNSET_VALUE
+        @graceful_halt_command         = UNSET_VALUE
         @graceful_halt_timeout         = UNSET_VALUE
         @guest                         = UNSET_VALUE
+        @guest_cache                   = UNSET_VALUE
         @hostname                      = UNSET_VALUE
         @post_up_message               = UNSET_VALUE
+        @provider_order                = UNSET_VALUE
+        @sync_time                     = UNSET_VALUE
+        @upload_checksum_type          = UNSET_VALUE
+        @upload_chunk_size             = UNSET_VALUE
         @provisioners                  = []
         @disks                         = []
         @cloud_init_configs            = []
@@ -106,8 +124,12 @@ module VagrantPlugins
         @__finalized                   = false
         @__networks                    = {}
         @__providers                   = {}
+        @__provider_inherited_settings = {}
         @__provider_order              = []
         @__provider_overrides          = {}
+        @__provider_profile_overrides  = {}
+        @__provider_profile_refs       = {}
+        @__provider_profiles           = {}
         @__synced_folders              = {}
       end
 
@@ -120,6 +142,14 @@ module VagrantPlugins
       # Custom merge method since some keys here are merged differently.
       def merge(other)
         super.tap do |result|
+          # Merge the environment variables so a variable set by the other
+          # configuration only replaces the same variable of this one.
+          other_environment = other.instance_variable_get(:@environment)
+          if @environment.is_a?(Hash) && other_environment.is_a?(Hash)
+            result.environment = stringify_keys(@environment).merge(
+              stringify_keys(other_environment))
+          end
+
           other_networks = other.instance_variable_get(:@__networks)
 
           result.instance_variable_set(:@__networks, @__networks.merge(other_networks))
@@ -214,6 +244,26 @@ module VagrantPlugins
             new_overrides[key] += blocks
           end
 
+          # Merge the provider profiles the same way as the providers so a
+          # profile can be defined in one Vagrantfile and used in another.
+          # The profile a provider uses is replaced by later configs.
+          other_profiles = other.instance_variable_get(:@__provider_profiles)
+          new_profiles   = @__provider_profiles.dup
+          other_profiles.each do |key, blocks|
+            new_profiles[key] ||= []
+            new_profiles[key] += blocks
+          end
+
+          other_profile_overrides = other.instance_variable_get(:@__provider_profile_overrides)
+          new_profile_overrides   = @__provider_profile_overrides.dup
+          other_profile_overrides.each do |key, blocks|
+            new_profile_overrides[key] ||= []
+            new_profile_overrides[key] += blocks
+          end
+
+          new_profile_refs = @__provider_profile_refs.merge(
+            other.instance_variable_get(:@__provider_profile_refs))
+
           # Merge provisioners. First we deal with overrides and making
           # sure the ordering is good there. Then we merge them.
           new_provs   = []
@@ -256,6 +306,9 @@ module VagrantPlugins
           result.instance_variable_set(:@__providers, new_providers)
           result.instance_variable_set(:@__provider_order, new_order)
           result.instance_variable_set(:@__provider_overrides, new_overrides)
+          result.instance_variable_set(:@__provider_profile_overrides, new_profile_overrides)
+          result.instance_variable_set(:@__provider_profile_refs, new_profile_refs)
+          result.instance_variable_set(:@__provider_profiles, new_profiles)
           result.instance_variable_set(:@__synced_folders, new_folders)
         end
       end
@@ -362,7 +415,11 @@ module VagrantPlugins
       # Configures a provider for this VM.
       #
       # @param [Symbol] name The name of the provider.
-      def provider(name, &block)
+      # @param [Hash] options
+      # @option options [String] :profile Name of the provider profile
+      #   whose settings the provider inherits. Settings of the provider
+      #   itself take precedence over the settings of the profile.
+      def provider(name, **options, &block)
         name = name.to_sym
         @__providers[name] ||= []
         @__provider_overrides[name] ||= []
@@ -370,6 +427,8 @@ module VagrantPlugins
         # Add the provider to the ordering list
         @__provider_order << name
 
+        @__provider_profile_refs[name] = options[:profile].to_s if options[:profile]
+
         if block_given?
           @__providers[name] << block if block_given?
 
@@ -381,6 +440,26 @@ module VagrantPlugins
         end
       end
 
+      # Defines a provider profile. A profile holds provider settings
+      # which are shared by the providers referencing it with the
+      # `profile` option of {#provider}. Like provider blocks, the block
+      # may take a second argument to override the VM configuration.
+      #
+      # @param [String] name The name of the profile.
+      def provider_profile(name, &block)
+        name = name.to_s
+        @__provider_profiles[name] ||= []
+        @__provider_profile_overrides[name] ||= []
+
+        if block_given?
+          @__provider_profiles[name] << block
+
+          if block.arity == 2
+            @__provider_profile_overrides[name] << block.curry[Vagrant::Config::V2::DummyConfig.new]
+          end
+        end
+      end
+
       def provision(name, **options, &block)
         type = name
         if options.key?(:type)
@@ -421,6 +500,8 @@ module VagrantPlugins
           options.key?(:preserve_order)
         prov.run = options.delete(:run) if options.key?(:run)
         prov.communicator_required = options.delete(:communicator_required) if options.key?(:communicator_required)
+        prov.skip_unchanged = options.delete(:skip_unchanged) if options.key?(:skip_unchanged)
+        prov.location ||= _caller_location
 
         prov.add_config(**options, &block)
         nil
@@ -430,6 +511,58 @@ module VagrantPlugins
         @__defined_vms
       end
 
+      # Returns the locations of the configuration blocks of a provider.
+      #
+      # @param [Symbol] name Name of the provider.
+      # @return [Array<String>] path and line of each block
+      def provider_locations(name)
+        Array(@__providers[name.to_sym]).map(&:source_location).compact.map do |path, line|
+          "#{path}:#{line}"
+        end
+      end
+
+      # Returns the profile used by a provider.
+      #
+      # @param [Symbol] name Name of the provider.
+      # @return [String, nil]
+      def provider_profile_name(name)
+        @__provider_profile_refs[name.to_sym]
+      end
+
+      # Returns the settings of a provider which were inherited from its
+      # profile because the provider does not set them itself.
+      #
+      # @param [Symbol] name Name of the provider.
+      # @return [Hash<String, String>] profile name keyed by setting name
+      def inherited_provider_settings(name)
+        @__provider_inherited_settings.fetch(name.to_sym, {})
+      end
+
+      # Returns the settings set by the configuration blocks of the
+      # providers, keyed by "provider.setting". Settings inherited from
+      # profiles are not included. This is used to track where the
+      # settings of providers were set.
+      #
+      # @return [Hash<String, Object>]
+      def provider_defined_values
+        {}.tap do |values|
+          @__providers.each do |name, blocks|
+            next if blocks.empty?
+
+            begin
+              config = compile_provider_blocks(name, blocks)
+            rescue Exception
+              # Errors are reported when the configuration is finalized
+              next
+            end
+
+            defined_settings(config).each do |setting, value|
+              values["#{name}.#{setting}"] = value
+            end
+          end
+        end
+      end
+
       # This returns the keys of the sub-vms in the order they were
       # defined.
       def defined_vm_keys
@@ -455,6 +588,36 @@ module VagrantPlugins
         @__defined_vms[name].config_procs << [options[:config_version], block] if block
       end
 
+      # Define a machine cloned from another machine. The clone uses the
+      # configuration of the machine it was cloned from, along with any
+      # configuration defined for the clone itself.
+      #
+      # @param [Symbol] name Name of the clone
+      # @param [Symbol] source Name of the machine it was cloned from
+      def define_clone(name, source)
+        name = name.to_sym
+        source = source.to_sym
+        source_vm = @__defined_vms[source]
+        return if !source_vm
+
+        # A single machine is the primary machine, which must remain so
+        # once it has a clone
+        if @__defined_vms.values.none? { |subvm| subvm.options[:primary] }
+          source_vm.options[:primary] = true
+        end
+
+        clone_vm = source_vm.dup
+        clone_vm.options.delete(:primary)
+        if @__defined_vms[name]
+          clone_vm.options.merge!(@__defined_vms[name].options)
+          clone_vm.config_procs.concat(@__defined_vms[name].config_procs)
+        end
+        clone_vm.options[:clone_of] = source
+
+        @__defined_vm_keys << name if !@__defined_vm_keys.include?(name)
+        @__defined_vms[name] = clone_vm
+      end
+
       # Stores disk config options from Vagrantfile
       #
       # @param [Symbol] type
@@ -527,6 +690,7 @@ module VagrantPlugins
         @box_download_ca_cert = nil if @box_download_ca_cert == UNSET_VALUE
         @box_download_ca_path = nil if @box_download_ca_path == UNSET_VALUE
         @box_download_checksum = nil if @box_download_checksum == UNSET_VALUE
+        @box_download_checksum_key = nil if @box_download_checksum_key == UNSET_VALUE
         @box_download_checksum_type = nil if @box_download_checksum_type == UNSET_VALUE
         @box_download_client_cert = nil if @box_download_client_cert == UNSET_VALUE
         @box_download_disable_ssl_revoke_best_effort = false if @box_download_disable_ssl_revoke_best_effort == UNSET_VALUE
@@ -540,11 +704,27 @@ module VagrantPlugins
         @clone = nil if @clone == UNSET_VALUE
         @cloud_init_first_boot_only = @cloud_init_first_boot_only == UNSET_VALUE ? true : !!@cloud_init_first_boot_only
         @communicator = nil if @communicator == UNSET_VALUE
+        @depends_on = [] if @depends_on == UNSET_VALUE
+        @depends_on = Array(@depends_on).map(&:to_s)
+        @environment = {} if @environment == UNSET_VALUE
+        @environment = stringify_keys(@environment) if @environment.is_a?(Hash)
+        @graceful_halt_command = nil if @graceful_halt_command == UNSET_VALUE
         @graceful_halt_timeout = 60 if @graceful_halt_timeout == UNSET_VALUE
         @guest = nil if @guest == UNSET_VALUE
+        @guest_cache = false if @guest_cache == UNSET_VALUE
         @hostname = nil if @hostname == UNSET_VALUE
         @hostname = @hostname.to_s if @hostname
         @post_up_message = "" if @post_up_message == UNSET_VALUE
+        @provider_order = [] if @provider_order == UNSET_VALUE
+        @provider_order = @provider_order.split(",") if @provider_order.is_a?(String)
+        @provider_order = Array(@provider_order).map { |p| p.to_s.strip }.
+          reject(&:empty?).map(&:to_sym).uniq
+        @sync_time = true if @sync_time == UNSET_VALUE
+        @upload_checksum_type = nil if @upload_checksum_type == UNSET_VALUE
+        @upload_checksum_type = @upload_checksum_type.to_sym if @upload_checksum_type
+        if @upload_chunk_size == UNSET_VALUE
+          @upload_chunk_size = Vagrant::Util::ChunkedUpload::DEFAULT_CHUNK_SIZE
+        end
 
         if @usable_port_range == UNSET_VALUE
           @usable_port_range = (2200..2250)
@@ -608,6 +788,7 @@ module VagrantPlugins
           if type == :forwarded_port
             opts[:guest] = opts[:guest].to_i if opts[:guest]
             opts[:host] = opts[:host].to_i if opts[:host]
+            opts[:auto_correct] = true if opts[:host_range] && !opts.key?(:auto_correct)
           end
         end
 
@@ -617,25 +798,26 @@ module VagrantPlugins
 
           name = name.to_sym
 
+          # The settings of the profile are merged under the settings of
+          # the provider, regardless of where either was defined
+          profile_blocks = Array(@__provider_profiles[@__provider_profile_refs[name]])
 
           # If we don't have any configuration blocks, then ignore it
-          next if blocks.empty?
-
-          # Find the configuration class for this provider
-          config_class = Vagrant.plugin("2").manager.provider_configs[name]
-          config_class ||= Vagrant::Config::V2::DummyConfig
-
-          l = Log4r::Logger.new(self.class.name.downcase)
-          l.info("config class lookup for provider #{name.inspect} gave us base class: #{config_class}")
-
-          # Load it up
-          config = config_class.new
+          next if blocks.empty? && profile_blocks.empty?
 
           begin
-            blocks.each do |b|
-              new_config = config_class.new
-              b.call(new_config, Vagrant::Config::V2::DummyConfig.new)
-              config = config.merge(new_config)
+            config = compile_provider_blocks(name, blocks)
+
+            if !profile_blocks.empty?
+              profile_config = compile_provider_blocks(name, profile_blocks)
+              explicit = defined_settings(config)
+              @__provider_inherited_settings[name] = {}
+              defined_settings(profile_config).each_key do |setting|
+                next if explicit.key?(setting)
+                @__provider_inherited_settings[name][setting] = @__provider_profile_refs[name]
+              end
+
+              config = profile_config.merge(config)
             end
           rescue Exception => e
             @logger.error("Vagrantfile load error: #{e.message}")
@@ -725,7 +907,9 @@ module VagrantPlugins
       # @param [Symbol] name Name of the provider
       # @return [Array<Proc>]
       def get_provider_overrides(name)
-        (@__provider_overrides[name] || []).map do |p|
+        # Overrides of the profile are applied before those of the provider
+        profile_overrides = Array(@__provider_profile_overrides[@__provider_profile_refs[name]])
+        (profile_overrides + (@__provider_overrides[name] || [])).map do |p|
           ["2", p]
         end
       end
@@ -769,6 +953,18 @@ module VagrantPlugins
         errors << I18n.t("vagrant.config.vm.hostname_invalid_characters", name: machine.name) if \
           @hostname && @hostname !~ /^[a-z0-9][-.a-z0-9]*$/i
 
+        if !@depends_on.empty?
+          machine_names = machine.env.machine_names.map(&:to_s)
+          @depends_on.each do |dep|
+            if dep == machine.name.to_s
+              errors << I18n.t("vagrant.config.vm.depends_on_self", name: machine.name)
+            elsif !machine_names.include?(dep)
+              errors << I18n.t("vagrant.config.vm.depends_on_not_found",
+                name: machine.name, dependency: dep)
+            end
+          end
+        end
+
         if @box_version
           @box_version.to_s.split(",").each do |v|
             begin
@@ -810,6 +1006,16 @@ module VagrantPlugins
           end
         end
 
+        if box_download_checksum_key
+          path = Pathname.new(box_download_checksum_key).
+            expand_path(machine.env.root_path)
+          if !path.file?
+            errors << I18n.t(
+              "vagrant.config.vm.box_download_checksum_key_not_found",
+              path: box_download_checksum_key)
+          end
+        end
+
         if !box_download_options.is_a?(Hash)
           errors <<  I18n.t("vagrant.config.vm.box_download_options_type", type: box_download_options.class.to_s)
         end
@@ -917,6 +1123,16 @@ module VagrantPlugins
             if !port_range.include?(options[:host]) || !port_range.include?(options[:guest])
               errors << I18n.t("vagrant.config.vm.network_fp_invalid_port")
             end
+
+            if options[:host_range]
+              range = options[:host_range]
+              valid = (range.is_a?(Range) || range.is_a?(Array)) &&
+                range.first && range.all? { |port| port.is_a?(Integer) && port_range.include?(port) }
+              if !valid
+                errors << I18n.t("vagrant.config.vm.network_fp_host_range_invalid",
+                                host: options[:host].to_s)
+              end
+            end
           end
 
           if type == :private_network
@@ -968,6 +1184,13 @@ module VagrantPlugins
           errors.concat error if !error.empty?
         end
 
+        @__provider_profile_refs.each do |provider, profile|
+          if !@__provider_profiles.key?(profile)
+            errors << I18n.t("vagrant.config.vm.provider_profile_missing",
+              provider: provider, profile: profile)
+          end
+        end
+
         # We're done with VM level errors so prepare the section
         errors = { "vm" => errors }
 
@@ -1054,12 +1277,104 @@ module VagrantPlugins
           )
         end
 
+        if @graceful_halt_command && !@graceful_halt_command.is_a?(String)
+          errors["vm"] << I18n.t("vagrant.config.vm.config_type",
+            option: "graceful_halt_command", given: @graceful_halt_command.class, required: "String"
+          )
+        end
+
+        if ![TrueClass, FalseClass].include?(@guest_cache.class)
+          errors["vm"] << I18n.t("vagrant.config.vm.config_type",
+            option: "guest_cache", given: @guest_cache.class, required: "Boolean"
+          )
+        end
+
+        if ![TrueClass, FalseClass].include?(@sync_time.class)
+          errors["vm"] << I18n.t("vagrant.config.vm.config_type",
+            option: "sync_time", given: @sync_time.class, required: "Boolean"
+          )
+        end
+
+        if !@upload_chunk_size.is_a?(Integer) || @upload_chunk_size < 1
+          errors["vm"] << I18n.t("vagrant.config.vm.upload_chunk_size_invalid",
+            given: @upload_chunk_size.inspect)
+        end
+
+        if @upload_checksum_type &&
+            !Vagrant::Util::ChunkedUpload::CHECKSUM_TYPES.key?(@upload_checksum_type)
+          errors["vm"] << I18n.t("vagrant.config.vm.upload_checksum_type_invalid",
+            given: @upload_checksum_type.to_s,
+            types: Vagrant::Util::ChunkedUpload::CHECKSUM_TYPES.keys.join(", "))
+        end
+
+        if !@environment.is_a?(Hash)
+          errors["vm"] << I18n.t("vagrant.config.vm.config_type",
+            option: "environment", given: @environment.class, required: "Hash"
+          )
+        else
+          @environment.each_key do |name|
+            if name !~ /\A[A-Za-z_][A-Za-z0-9_]*\z/
+              errors["vm"] << I18n.t("vagrant.config.vm.environment_invalid_name",
+                name: name)
+            end
+          end
+        end
+
         errors
       end
 
       def __providers
         @__provider_order
       end
+
+      protected
+
+      # Merges the configuration blocks of a provider into a new
+      # configuration instance of the provider.
+      #
+      # @param [Symbol] name Name of the provider.
+      # @param [Array<Proc>] blocks
+      # @return [Vagrant::Plugin::V2::Config]
+      def compile_provider_blocks(name, blocks)
+        # Find the configuration class for this provider
+        config_class = Vagrant.plugin("2").manager.provider_configs[name]
+        config_class ||= Vagrant::Config::V2::DummyConfig
+
+        l = Log4r::Logger.new(self.class.name.downcase)
+        l.info("config class lookup for provider #{name.inspect} gave us base class: #{config_class}")
+
+        # Load it up
+        config = config_class.new
+        blocks.each do |b|
+          new_config = config_class.new
+          b.call(new_config, Vagrant::Config::V2::DummyConfig.new)
+          config = config.merge(new_config)
+        end
+
+        config
+      end
+
+      # @return [Hash<String, Object>] the settings of the provider
+      #   configuration which differ from its defaults, keyed by name
+      def defined_settings(config)
+        defaults = config.class.new
+        {}.tap do |settings|
+          config.instance_variables.each do |key|
+            next if key.to_s.start_with?("@__")
+
+            value = config.instance_variable_get(key)
+            next if value == UNSET_VALUE
+            next if value == defaults.instance_variable_get(key)
+
+            settings[key.to_s[1..-1]] = value
+          end
+        end
+      end
+
+      # @return [Hash] the hash with the keys converted to strings
+      def stringify_keys(hash)
+        Hash[hash.map { |k, v| [k.to_s, v] }]
+      end
     end
   end
 end
@@ -65,6 +65,17 @@ module VagrantPlugins
       # @return [Boolean]
       attr_accessor :communicator_required
 
+      # Boolean, when true the provisioner is skipped if its fingerprint
+      # is unchanged since it last ran successfully.
+      #
+      # @return [Boolean]
+      attr_accessor :skip_unchanged
+
+      # The path and line where the provisioner was defined
+      #
+      # @return [String]
+      attr_accessor :location
+
       def initialize(name, type, **options)
         @logger = Log4r::Logger.new("vagrant::config::vm::provisioner")
         @logger.debug("Provisioner defined: #{name}")
@@ -79,6 +90,8 @@ module VagrantPlugins
         @before  = options[:before]
         @after   = options[:after]
         @communicator_required = options.fetch(:communicator_required, true)
+        @skip_unchanged = options.fetch(:skip_unchanged, false)
+        @location = nil
 
         # Attempt to find the provisioner...
         if !Vagrant.plugin("2").manager.provisioners[type]
@@ -131,6 +144,10 @@ module VagrantPlugins
           errors << I18n.t("vagrant.provisioners.base.wrong_type", opt: "communicator_required", type: "boolean")
         end
 
+        if ![TrueClass, FalseClass].include?(@skip_unchanged.class)
+          errors << I18n.t("vagrant.provisioners.base.wrong_type", opt: "skip_unchanged", type: "boolean")
+        end
+
         if @before && @after
           errors << I18n.t("vagrant.provisioners.base.both_before_after_set")
         end
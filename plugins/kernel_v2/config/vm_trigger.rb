@@ -77,6 +77,18 @@ module VagrantPlugins
       # @return [Integer, Array]
       attr_accessor :exit_codes
 
+      # If set, will only run trigger if the condition is true when the
+      # trigger fires.
+      #
+      # @return [String]
+      attr_accessor :only_if
+
+      # If set, will not run trigger if the condition is true when the
+      # trigger fires.
+      #
+      # @return [String]
+      attr_accessor :unless
+
       # If set to true, trigger will halt Vagrant immediately and exit 0
       # Can also be configured to have a custom exit code
       #
@@ -112,6 +124,8 @@ module VagrantPlugins
         @run = UNSET_VALUE
         @run_remote = UNSET_VALUE
         @exit_codes = UNSET_VALUE
+        @only_if = UNSET_VALUE
+        @unless = UNSET_VALUE
         @abort = UNSET_VALUE
         @ruby = UNSET_VALUE
         @type = UNSET_VALUE
@@ -150,6 +164,8 @@ module VagrantPlugins
         @run_remote = nil if @run_remote == UNSET_VALUE
         @only_on = nil if @only_on == UNSET_VALUE
         @exit_codes = DEFAULT_EXIT_CODE if @exit_codes == UNSET_VALUE
+        @only_if = nil if @only_if == UNSET_VALUE
+        @unless = nil if @unless == UNSET_VALUE
         @abort = nil if @abort == UNSET_VALUE
         @type = :action if @type == UNSET_VALUE
 
@@ -273,6 +289,23 @@ module VagrantPlugins
           end
         end
 
+        { only_if: @only_if, unless: @unless }.each do |option, condition|
+          next if condition.nil?
+
+          if !condition.is_a?(String)
+            errors << I18n.t("vagrant.config.triggers.condition_bad_type",
+                             option: option, cmd: @command)
+            next
+          end
+
+          begin
+            Vagrant::Plugin::V2::TriggerCondition.parse(condition)
+          rescue Vagrant::Errors::TriggersConditionInvalid => e
+            errors << I18n.t("vagrant.config.triggers.condition_invalid",
+                             option: option, cmd: @command, error: e.extra_data[:error])
+          end
+        end
+
         if @abort && !@abort.is_a?(Integer)
           errors << I18n.t("vagrant.config.triggers.abort_bad_type", cmd: @command)
         elsif @abort == false
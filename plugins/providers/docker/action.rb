@@ -157,6 +157,7 @@ module VagrantPlugins
                   b4.use EnvSet, force_halt: true
                   b4.use action_halt
                   b4.use HostMachineSyncFoldersDisable
+                  b4.use HostsFileRemove
                   b4.use Destroy
                   b4.use DestroyNetwork
                   b4.use DestroyBuildImage
@@ -269,6 +270,7 @@ module VagrantPlugins
                   b3.use WaitForCommunicator
                 end
               end
+              b2.use HostsFileUpdate
             else
               # We're in a run command, so we do things a bit differently.
               b2.use Create
@@ -1,6 +1,9 @@
 # Copyright IBM Corp. 2010, 2025
 # SPDX-License-Identifier: BUSL-1.1
 
+require "digest/sha2"
+require "json"
+
 require "log4r"
 
 require "vagrant/util/ansi_escape_code_remover"
@@ -8,9 +11,15 @@ require "vagrant/util/ansi_escape_code_remover"
 module VagrantPlugins
   module DockerProvider
     module Action
+      # This builds the image of the container from a Dockerfile. Images
+      # built from a build directory are rebuilt when the hash of the
+      # build context, which is stored in the provider state, changes.
       class Build
         include Vagrant::Util::ANSIEscapeCodeRemover
 
+        # Key of the build context hash in the provider state
+        BUILD_HASH_KEY = "docker_build_hash".freeze
+
         def initialize(app, env)
           @app = app
           @logger = Log4r::Logger.new("vagrant::docker::build")
@@ -40,10 +49,21 @@ module VagrantPlugins
             image = nil
           end
 
+          # The context of git repositories can not be hashed so they are
+          # only rebuilt when requested
+          build_hash = nil
+          if machine.provider_config.build_dir
+            build_hash = context_hash(machine)
+            if image && machine.provider_state.get(BUILD_HASH_KEY) != build_hash
+              machine.ui.output(I18n.t("docker_provider.build_context_changed"))
+              image = nil
+            end
+          end
+
           # If we have no image or we're rebuilding, we rebuild
           if !image || env[:build_rebuild]
             # Build it
-            args = machine.provider_config.build_args.clone
+            args = build_args(machine)
             if machine.provider_config.dockerfile
               dockerfile      = machine.provider_config.dockerfile
               dockerfile_path = build_dir ? File.join(build_dir, dockerfile) : dockerfile
@@ -69,11 +89,21 @@ module VagrantPlugins
               end
             end
 
-            image = machine.provider.driver.build(
-              build_dir || git_repo,
-              extra_args: args) do |type, data|
-              data = remove_ansi_escape_codes(data.chomp).chomp
-              env[:ui].detail(data) if data != ""
+            begin
+              image = machine.provider.driver.build(
+                build_dir || git_repo,
+                extra_args: args) do |type, data|
+                data = remove_ansi_escape_codes(data.chomp).chomp
+                env[:ui].detail(data) if data != ""
+              end
+            rescue Vagrant::Errors::VagrantError
+              # Don't leave a machine which was being created for the
+              # first time in the preparing state
+              if machine.id == "preparing"
+                @logger.info("Build failed, removing preparing state")
+                machine.id = nil
+              end
+              raise
             end
 
             # Output the final image
@@ -84,6 +114,7 @@ module VagrantPlugins
               f.binmode
               f.write("#{image}\n")
             end
+            machine.provider_state.set(BUILD_HASH_KEY, build_hash) if build_hash
           else
             machine.ui.output(I18n.t("docker_provider.already_built"))
           end
@@ -93,6 +124,109 @@ module VagrantPlugins
 
           @app.call(env)
         end
+
+        protected
+
+        # @return [Array<String>] arguments for `docker build`
+        def build_args(machine)
+          config = machine.provider_config
+          args = []
+          if config.build_args.is_a?(Hash)
+            config.build_args.each do |key, value|
+              args.push("--build-arg").push("#{key}=#{value}")
+            end
+          else
+            args.concat(config.build_args)
+          end
+
+          config.build_opts.each do |key, value|
+            flag = "--#{key.to_s.tr("_", "-")}"
+            case value
+            when true
+              args.push(flag)
+            when false, nil
+              next
+            when Array
+              value.each { |v| args.push(flag).push(v.to_s) }
+            else
+              args.push(flag).push(value.to_s)
+            end
+          end
+
+          args.push("--tag").push(image_tag(machine))
+        end
+
+        # The tag is the same for every build of the machine so that each
+        # build replaces the image of the previous build.
+        #
+        # @return [String] tag for the built image
+        def image_tag(machine)
+          project = Digest::SHA256.hexdigest(machine.env.root_path.to_s)[0, 8]
+          name = machine.name.to_s.downcase.gsub(/[^a-z0-9_.-]+/, "-")
+          "vagrant-#{project}-#{name}:latest"
+        end
+
+        # Hash the files in the build context which are not excluded by the
+        # `.dockerignore` file, along with the build settings.
+        #
+        # @return [String]
+        def context_hash(machine)
+          config = machine.provider_config
+          root = File.expand_path(config.build_dir, machine.env.root_path)
+          ignore = dockerignore(root)
+
+          digest = Digest::SHA256.new
+          digest << JSON.dump([config.dockerfile, config.build_args, config.build_opts])
+          files = Dir.glob(File.join(root, "**", "*"), File::FNM_DOTMATCH).sort
+          files.each do |path|
+            next if !File.file?(path)
+
+            relative = path[(root.length + 1)..-1]
+            next if ignored?(relative, ignore)
+
+            digest << "#{relative}\0#{Digest::SHA256.file(path).hexdigest}\0"
+          end
+
+          if config.dockerfile
+            dockerfile = File.expand_path(config.dockerfile, root)
+            digest << Digest::SHA256.file(dockerfile).hexdigest if File.file?(dockerfile)
+          end
+
+          digest.hexdigest
+        end
+
+        # @return [Array<Array(Boolean, String)>] exclusion patterns, and
+        #   whether each pattern is an exception
+        def dockerignore(root)
+          path = File.join(root, ".dockerignore")
+          return [] if !File.file?(path)
+
+          File.readlines(path).map(&:strip).map do |line|
+            next if line.empty? || line.start_with?("#")
+
+            exception = line.start_with?("!")
+            pattern = line.sub(/\A!/, "").sub(%r{\A/+}, "").chomp("/")
+            [exception, pattern]
+          end.compact
+        end
+
+        # The last matching pattern decides if the file is excluded. A
+        # pattern matching a directory excludes the files within it.
+        #
+        # @return [Boolean] file is excluded from the build context
+        def ignored?(relative, patterns)
+          flags = File::FNM_PATHNAME | File::FNM_DOTMATCH | File::FNM_EXTGLOB
+          parts = relative.split("/")
+          candidates = (1..parts.length).map { |i| parts[0, i].join("/") }
+
+          patterns.inject(false) do |ignored, (exception, pattern)|
+            if candidates.any? { |c| File.fnmatch(pattern, c, flags) }
+              !exception
+            else
+              ignored
+            end
+          end
+        end
       end
     end
   end
@@ -61,7 +61,11 @@ module VagrantPlugins
 
           if env[:machine_action] != :run_command
             # For regular "ups" create it and get the CID
-            cid = @driver.create(params)
+            cid = Vagrant::Util::Tracing.span("vagrant.provider.create",
+              Vagrant::Util::Tracing.machine_attributes(
+                @machine, env[:machine_action])) do
+              @driver.create(params)
+            end
             env[:ui].detail(" \n"+I18n.t(
               "docker_provider.created", id: cid[0...16]))
             @machine.id = cid
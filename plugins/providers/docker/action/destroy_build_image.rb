@@ -42,6 +42,7 @@ module VagrantPlugins
               # Its okay
             end
           end
+          machine.provider_state.delete(Build::BUILD_HASH_KEY)
 
           @app.call(env)
         end
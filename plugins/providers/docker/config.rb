@@ -10,10 +10,11 @@ module VagrantPlugins
     class Config < Vagrant.plugin("2", :config)
       attr_accessor :image, :cmd, :ports, :volumes, :privileged
 
-      # Additional arguments to pass to `docker build` when creating
-      # an image using the build dir setting.
+      # Arguments for building an image using the build dir setting. A
+      # hash sets build arguments, which are passed with `--build-arg`,
+      # while an array is passed to `docker build` as is.
       #
-      # @return [Array<String>]
+      # @return [Array<String>, Hash]
       attr_accessor :build_args
 
       # The directory with a Dockerfile to build and use as the basis
@@ -23,6 +24,14 @@ module VagrantPlugins
       # @return [String]
       attr_accessor :build_dir
 
+      # Options for `docker build`, such as `no_cache`, `pull`, `target`
+      # or `cache_from`. Option names have underscores replaced with dashes,
+      # options set to true are passed as flags, and options set to an
+      # array are passed once for each value.
+      #
+      # @return [Hash]
+      attr_accessor :build_opts
+
       # The URL for a git repository with a Dockerfile to build and use
       # as the basis for this container. If this is set, neither "image"
       # nor "build_dir" should be set.
@@ -160,6 +169,7 @@ module VagrantPlugins
       def initialize
         @build_args = []
         @build_dir  = UNSET_VALUE
+        @build_opts = UNSET_VALUE
         @git_repo   = UNSET_VALUE
         @cmd        = UNSET_VALUE
         @compose    = UNSET_VALUE
@@ -251,6 +261,7 @@ module VagrantPlugins
       def finalize!
         @build_args = [] if @build_args == UNSET_VALUE
         @build_dir  = nil if @build_dir == UNSET_VALUE
+        @build_opts = {} if @build_opts == UNSET_VALUE
         @git_repo   = nil if @git_repo == UNSET_VALUE
         @cmd        = [] if @cmd == UNSET_VALUE
         @compose    = false if @compose == UNSET_VALUE
@@ -315,6 +326,14 @@ module VagrantPlugins
           end
         end
 
+        if !@build_args.is_a?(Array) && !@build_args.is_a?(Hash)
+          errors << I18n.t("docker_provider.errors.config.build_args_invalid")
+        end
+
+        if !@build_opts.is_a?(Hash)
+          errors << I18n.t("docker_provider.errors.config.build_opts_hash")
+        end
+
         # Comparison logic taken directly from docker's urlutil.go
         if @git_repo && !( @git_repo =~ /^http(?:s)?:\/\/.*.git(?:#.+)?$/ || @git_repo =~ /^git(?:hub\.com|@|:\/\/)/)
           errors << I18n.t("docker_provider.errors.config.git_repo_invalid")
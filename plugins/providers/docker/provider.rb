@@ -168,10 +168,26 @@ module VagrantPlugins
         # here and we let Vagrant core deal with it ;)
         return nil if port_info.nil? || port_info.empty?
 
-        {
+        info = {
           host: port_info['HostIp'],
           port: port_info['HostPort']
         }
+
+        # The container is only reachable from the host VM, so connections
+        # from outside of Vagrant must jump through it
+        if host_vm?
+          host_info = host_vm.ssh_info
+          if host_info
+            info[:proxy_jump] = {
+              host: host_info[:host],
+              port: host_info[:port],
+              username: host_info[:username],
+              private_key_path: host_info[:private_key_path],
+            }
+          end
+        end
+
+        info
       end
 
       def state
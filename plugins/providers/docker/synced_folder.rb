@@ -18,16 +18,24 @@ module VagrantPlugins
         true
       end
 
+      # Volumes are bind mounts, so the owner and mode of the files are
+      # the same as on the host
+      def unsupported_options(machine)
+        [:owner, :group, :mode, :mount_options]
+      end
+
       def prepare(machine, folders, _opts)
         folders.each do |id, data|
           next if data[:ignore]
 
           host_path  = data[:hostpath]
           guest_path = data[:guestpath]
-          # Append consistency option if it exists, otherwise let it nil out
-          consistency = data[:docker_consistency]
-          consistency &&= ":" + consistency
-          machine.provider_config.volumes << "#{host_path}:#{guest_path}#{consistency}"
+          # Append the read-only and consistency options if they exist
+          options = []
+          options << "ro" if data[:readonly]
+          options << data[:docker_consistency] if data[:docker_consistency]
+          options = options.empty? ? "" : ":#{options.join(",")}"
+          machine.provider_config.volumes << "#{host_path}:#{guest_path}#{options}"
         end
       end
     end
@@ -42,6 +42,7 @@ module VagrantPlugins
 
               b2.use ConfigValidate
               b2.use ProvisionerCleanup, :before
+              b2.use HostsFileRemove
               b2.use StopInstance
               b2.use DeleteVM
               b2.use SyncedFolderCleanup
@@ -124,6 +125,7 @@ module VagrantPlugins
             b1.use ResumeVM
             b1.use WaitForIPAddress
             b1.use WaitForCommunicator, [:running]
+            b1.use SyncTime
           end
         end
       end
@@ -162,6 +164,7 @@ module VagrantPlugins
                 b3.use CloudInitWait
                 b3.use SyncedFolders
                 b3.use SetHostname
+                b3.use HostsFileUpdate
               end
             end
           end
@@ -277,7 +280,7 @@ module VagrantPlugins
             end
 
             b2.use action_start
-
+            b2.use SyncTime
           end
         end
       end
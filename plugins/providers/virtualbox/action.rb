@@ -43,6 +43,7 @@ module VagrantPlugins
       autoload :PrepareNFSSettings, File.expand_path("../action/prepare_nfs_settings", __FILE__)
       autoload :PrepareNFSValidIds, File.expand_path("../action/prepare_nfs_valid_ids", __FILE__)
       autoload :PrepareForwardedPortCollisionParams, File.expand_path("../action/prepare_forwarded_port_collision_params", __FILE__)
+      autoload :ReloadNetworks, File.expand_path("../action/reload_networks", __FILE__)
       autoload :Resume, File.expand_path("../action/resume", __FILE__)
       autoload :SaneDefaults, File.expand_path("../action/sane_defaults", __FILE__)
       autoload :SetDefaultNICType, File.expand_path("../action/set_default_nic_type", __FILE__)
@@ -81,6 +82,7 @@ module VagrantPlugins
           b.use NetworkFixIPv6
           b.use ForwardPorts
           b.use SetHostname
+          b.use HostsFileUpdate
           b.use SaneDefaults
           b.use CloudInitSetup
           b.use CleanupDisks
@@ -113,6 +115,7 @@ module VagrantPlugins
                 b3.use CheckAccessible
                 b3.use EnvSet, force_halt: env2[:force_halt]
                 b3.use action_halt
+                b3.use HostsFileRemove
                 b3.use Destroy
                 b3.use CleanMachineFolder
                 b3.use DestroyUnusedNetworkInterfaces
@@ -220,6 +223,19 @@ module VagrantPlugins
         end
       end
 
+      # This action applies the networks of the configuration to the
+      # running machine without restarting it.
+      def self.action_reload_networks
+        Vagrant::Action::Builder.new.tap do |b|
+          b.use CheckVirtualbox
+          b.use ConfigValidate
+          b.use CheckCreated
+          b.use CheckAccessible
+          b.use CheckRunning
+          b.use ReloadNetworks
+        end
+      end
+
       # This is the action that is primarily responsible for resuming
       # suspended machines.
       def self.action_resume
@@ -234,6 +250,7 @@ module VagrantPlugins
               b2.use Resume
               b2.use Provision
               b2.use WaitForCommunicator, [:restoring, :running]
+              b2.use SyncTime
             else
               b2.use MessageNotCreated
             end
@@ -277,6 +294,7 @@ module VagrantPlugins
             b2.use Call, IsEnvSet, :snapshot_start do |env2, b3|
               if env2[:result]
                 b3.use action_start
+                b3.use SyncTime
               end
             end
           end
@@ -20,11 +20,13 @@ module VagrantPlugins
         def clone(env)
           # Do the actual clone
           env[:ui].info I18n.t("vagrant.actions.vm.clone.creating")
-          env[:machine].id = env[:machine].provider.driver.clonevm(
-            env[:clone_id], env[:clone_snapshot]) do |progress|
-            env[:ui].rewriting do |ui|
-              ui.clear_line
-              ui.report_progress(progress, 100, false)
+          env[:machine].id = trace_create(env) do
+            env[:machine].provider.driver.clonevm(
+              env[:clone_id], env[:clone_snapshot]) do |progress|
+              env[:ui].rewriting do |ui|
+                ui.clear_line
+                ui.report_progress(progress, 100, false)
+              end
             end
           end
 
@@ -55,10 +57,12 @@ module VagrantPlugins
 
           # Import the virtual machine
           ovf_file = env[:machine].box.directory.join("box.ovf").to_s
-          id = env[:machine].provider.driver.import(ovf_file) do |progress|
-            env[:ui].rewriting do |ui|
-              ui.clear_line
-              ui.report_progress(progress, 100, false)
+          id = trace_create(env) do
+            env[:machine].provider.driver.import(ovf_file) do |progress|
+              env[:ui].rewriting do |ui|
+                ui.clear_line
+                ui.report_progress(progress, 100, false)
+              end
             end
           end
 
@@ -104,6 +108,15 @@ module VagrantPlugins
             env[:action_runner].run(Action.action_destroy, destroy_env)
           end
         end
+
+        protected
+
+        # Run the block within a span for creating the machine
+        def trace_create(env, &block)
+          Vagrant::Util::Tracing.span("vagrant.provider.create",
+            Vagrant::Util::Tracing.machine_attributes(
+              env[:machine], env[:machine_action]), &block)
+        end
       end
     end
   end
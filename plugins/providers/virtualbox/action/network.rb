@@ -147,7 +147,7 @@ module VagrantPlugins
               ))
             end
 
-            env[:machine].provider.driver.enable_adapters(adapters)
+            enable_adapters(env, adapters)
           end
 
           # Continue the middleware chain.
@@ -167,6 +167,13 @@ module VagrantPlugins
           end
         end
 
+        # Enable the adapters of the VM, which must not be running
+        #
+        # @param [Array<Hash>] adapters
+        def enable_adapters(env, adapters)
+          env[:machine].provider.driver.enable_adapters(adapters)
+        end
+
         def bridged_config(options)
           return {
             auto_config:                     true,
@@ -0,0 +1,71 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require_relative "network"
+
+module VagrantPlugins
+  module ProviderVirtualBox
+    module Action
+      # This middleware applies the networks of the configuration to the
+      # running VM. The adapters of a running VM can not be enabled, so
+      # only the attachment of adapters which are already enabled can be
+      # changed, after which the networks are configured within the guest.
+      class ReloadNetworks < Network
+        # Types of adapters which can be attached to a running VM, and the
+        # key of the device they are attached to
+        ATTACHMENTS = {
+          bridged: :bridge,
+          hostonly: :hostonly,
+          intnet: :intnet,
+          nat: nil,
+        }.freeze
+
+        def initialize(app, env)
+          super
+          @logger = Log4r::Logger.new("vagrant::plugins::virtualbox::reload_networks")
+        end
+
+        # Attach each adapter of the running VM with `controlvm`. All
+        # adapters are checked before any is attached, so nothing is
+        # changed if one of them requires a reboot.
+        #
+        # @param [Array<Hash>] adapters
+        def enable_adapters(env, adapters)
+          machine = env[:machine]
+          current = machine.provider.driver.read_network_interfaces
+
+          attachments = adapters.map do |adapter|
+            slot = adapter[:adapter]
+            nic = current[slot]
+            if !nic || nic[:type] == :none
+              raise Vagrant::Errors::ReloadNetworksRebootRequired,
+                name: machine.name.to_s,
+                reason: I18n.t("vagrant.virtualbox.reload_networks.adapter_disabled",
+                  adapter: slot.to_s)
+            end
+
+            type = adapter[:type].to_sym
+            if !ATTACHMENTS.key?(type)
+              raise Vagrant::Errors::ReloadNetworksRebootRequired,
+                name: machine.name.to_s,
+                reason: I18n.t("vagrant.virtualbox.reload_networks.type_unsupported",
+                  adapter: slot.to_s, type: type.to_s)
+            end
+
+            device_key = ATTACHMENTS[type]
+            device = device_key ? adapter[device_key] : nil
+            next if nic[:type] == type && (!device_key || nic[device_key] == device)
+
+            [slot, type, device]
+          end.compact
+
+          attachments.each do |slot, type, device|
+            @logger.info("Attaching adapter #{slot} to #{type} #{device}")
+            machine.provider.driver.execute_command(
+              ["controlvm", machine.id, "nic#{slot}", type.to_s, device].compact)
+          end
+        end
+      end
+    end
+  end
+end
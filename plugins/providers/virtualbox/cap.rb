@@ -21,6 +21,32 @@ module VagrantPlugins
         end
       end
 
+      # Returns the version of the Guest Additions which should be
+      # installed in the guest. This is the version of VirtualBox.
+      #
+      # @return [String, nil] version, or nil if guest additions are
+      #   not checked
+      def self.guest_tools_version(machine)
+        return nil if !machine.provider_config.check_guest_additions
+
+        machine.provider.driver.version
+      end
+
+      # Returns the version of the Guest Additions installed in the
+      # guest, as reported by the Guest Additions to VirtualBox.
+      #
+      # @return [String, nil] version, or nil if no guest additions are
+      #   detected
+      def self.guest_tools_installed_version(machine)
+        machine.provider.driver.read_guest_additions_version
+      end
+
+      # Sends an ACPI shutdown signal to the machine so the guest shuts
+      # down when it can not be halted with the communicator.
+      def self.acpi_shutdown(machine)
+        machine.provider.driver.acpi_shutdown
+      end
+
       # Reads the network interface card MAC addresses and returns them.
       #
       # @return [Hash<String, String>] Adapter => MAC address
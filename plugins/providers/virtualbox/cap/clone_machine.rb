@@ -0,0 +1,33 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+module VagrantPlugins
+  module ProviderVirtualBox
+    module Cap
+      module CloneMachine
+        # Clone the machine to a new VirtualBox machine. VirtualBox
+        # generates new MAC addresses for the network adapters of the
+        # clone, and the clone is renamed when it is first booted.
+        #
+        # A linked clone is created from a new snapshot of the machine so
+        # it shares the disks of the machine as they are now.
+        #
+        # @param [Vagrant::Machine] machine
+        # @param [Vagrant::Machine] target The clone
+        # @param [Boolean] linked Create a linked clone
+        # @return [String] ID of the clone
+        def self.clone_machine(machine, target, linked)
+          driver = machine.provider.driver
+
+          snapshot = nil
+          if linked
+            snapshot = "vagrant-clone-#{target.name}-#{Time.now.to_i}"
+            driver.create_snapshot(machine.id, snapshot)
+          end
+
+          driver.clonevm(machine.id, snapshot)
+        end
+      end
+    end
+  end
+end
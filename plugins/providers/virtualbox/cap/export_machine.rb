@@ -0,0 +1,48 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require "vagrant/util/platform"
+require "vagrant/util/template_renderer"
+
+module VagrantPlugins
+  module ProviderVirtualBox
+    module Cap
+      module ExportMachine
+        # Export the machine as the files of a VirtualBox box. The machine
+        # must be powered off or saved, since VirtualBox can not export a
+        # running machine.
+        #
+        # @param [Vagrant::Machine] machine
+        # @param [String] directory Directory to write the box files to
+        def self.export_machine(machine, directory)
+          if machine.state.id == :running
+            raise Vagrant::Errors::VirtualBoxExportRunning,
+              name: machine.name.to_s
+          end
+
+          ovf_path = File.join(directory, "box.ovf")
+
+          # If we're within WSL, we should use the correct path rather than
+          # the mnt path. GH-9059
+          if Vagrant::Util::Platform.wsl?
+            ovf_path = Vagrant::Util::Platform.wsl_to_windows_path(ovf_path)
+          end
+
+          machine.provider.driver.export(ovf_path) do |progress|
+            machine.ui.rewriting do |ui|
+              ui.clear_line
+              ui.report_progress(progress.percent, 100, false)
+            end
+          end
+          machine.ui.clear_line
+
+          # The box Vagrantfile contains the MAC address so machines
+          # created from the box have networking configured
+          File.write(File.join(directory, "Vagrantfile"),
+            Vagrant::Util::TemplateRenderer.render("package_Vagrantfile",
+              base_mac: machine.provider.driver.read_mac_address))
+        end
+      end
+    end
+  end
+end
@@ -18,13 +18,18 @@ module VagrantPlugins
         # @param [String] path of mount on guest
         # @param [Hash] hash of mount options 
         def self.mount_options(machine, name, guest_path, options)
-          mount_options = options.fetch(:mount_options, [])
+          mount_options = options.fetch(:mount_options, []).dup
           detected_ids = detect_owner_group_ids(machine, guest_path, mount_options, options)
           mount_uid = detected_ids[:uid]
           mount_gid = detected_ids[:gid]
 
           mount_options << "uid=#{mount_uid}"
           mount_options << "gid=#{mount_gid}"
+          if options[:mode]
+            mount_options << "dmode=#{options[:mode]}"
+            mount_options << "fmode=#{options[:mode]}"
+          end
+          mount_options << "ro" if options[:readonly] && !mount_options.include?("ro")
           mount_options << "_netdev"
           mount_options = mount_options.join(',')
           return mount_options, mount_uid, mount_gid
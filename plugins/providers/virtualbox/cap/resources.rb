@@ -0,0 +1,109 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+module VagrantPlugins
+  module ProviderVirtualBox
+    module Cap
+      module Resources
+        # Flags of `modifyvm` which set the resources of the machine
+        RESOURCE_FLAGS = ["--cpus", "--memory"].freeze
+
+        # Matches the CPUs listed in the machine readable VM info, such as
+        # `cpu1="attached"`, when CPU hot-plug is enabled
+        PLUGGED_CPU = /^cpu(\d+)="?([^"\n]*)"?$/
+
+        # Returns the CPU and memory configured for the machine using
+        # the `cpus` and `memory` provider settings.
+        #
+        # @return [Hash] configured resources
+        def self.resources(machine)
+          result = {}
+          machine.provider_config.customizations.each do |event, command|
+            next if event != "pre-boot" || command[0] != "modifyvm"
+
+            command.each_with_index do |arg, i|
+              case arg
+              when "--cpus"
+                result[:cpus] = command[i + 1].to_i
+              when "--memory"
+                result[:memory] = command[i + 1].to_i
+              end
+            end
+          end
+          result
+        end
+
+        # Returns the provider settings, other than the resources, which
+        # require the machine to be restarted when they change.
+        #
+        # @return [Hash] provider settings
+        def self.resources_config(machine)
+          config = machine.provider_config.instance_variables_hash.reject do |name, _|
+            name.start_with?("_") || name == "logger"
+          end
+
+          config["customizations"] = Array(config["customizations"]).map { |event, command|
+            if event == "pre-boot" && command[0] == "modifyvm"
+              command = command.dup
+              RESOURCE_FLAGS.each do |flag|
+                while (i = command.index(flag))
+                  command.slice!(i, 2)
+                end
+              end
+              next if command.length <= 2
+            end
+
+            [event, command]
+          }.compact
+          config.sort.to_h
+        end
+
+        # Modify the resources of the running machine. VirtualBox can only
+        # add and remove CPUs of a running machine, and only if CPU hot-plug
+        # is enabled for the machine (`--cpuhotplug on`). With CPU hot-plug
+        # the `cpus` setting of the machine is the maximum number of CPUs,
+        # so CPUs can only be plugged up to the number the machine was
+        # started with. The boot CPU is never unplugged.
+        #
+        # @param [Hash] resources Resources to modify
+        # @return [Boolean] true if the resources were modified
+        def self.modify_resources(machine, resources)
+          return false if resources.keys != [:cpus]
+
+          info = machine.provider.driver.execute_command(
+            ["showvminfo", machine.id, "--machinereadable"])
+          return false if info !~ /^cpuhotplug="on"$/
+
+          max = info[/^cpus=(\d+)$/, 1].to_i
+          plugged = plugged_cpus(info)
+          wanted = resources[:cpus].to_i
+          return false if plugged.nil? || wanted < 1 || wanted > max
+
+          if wanted > plugged.length
+            ((0...max).to_a - plugged).first(wanted - plugged.length).each do |id|
+              machine.provider.driver.execute_command(
+                ["controlvm", machine.id, "plugcpu", id.to_s])
+            end
+          else
+            (plugged - [0]).last(plugged.length - wanted).reverse_each do |id|
+              machine.provider.driver.execute_command(
+                ["controlvm", machine.id, "unplugcpu", id.to_s])
+            end
+          end
+          true
+        end
+
+        # @param [String] info Machine readable VM info
+        # @return [Array<Integer>, nil] sorted IDs of the plugged CPUs, or
+        #   nil if the VM info does not list them
+        def self.plugged_cpus(info)
+          cpus = info.scan(PLUGGED_CPU)
+          return if cpus.empty?
+
+          cpus.reject { |_, state| state == "off" || state == "detached" }.
+            map { |id, _| id.to_i }.sort
+        end
+      end
+    end
+  end
+end
@@ -74,6 +74,20 @@ module VagrantPlugins
       # @return [Hash]
       attr_reader :network_adapters
 
+      def self.schema
+        {
+          auto_nat_dns_proxy: [:boolean],
+          check_guest_additions: [:boolean],
+          default_nic_type: [String, Symbol],
+          destroy_unused_network_interfaces: [:boolean],
+          gui: [:boolean],
+          linked_clone: [:boolean],
+          linked_clone_snapshot: [String],
+          name: [String],
+          functional_vboxsf: [:boolean],
+        }
+      end
+
       def initialize
         @auto_nat_dns_proxy = UNSET_VALUE
         @check_guest_additions = UNSET_VALUE
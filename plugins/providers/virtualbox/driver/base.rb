@@ -97,6 +97,10 @@ module VagrantPlugins
           @logger.info("VBoxManage path: #{@vboxmanage_path}")
         end
 
+        # Sends an ACPI shutdown signal to the virtual machine.
+        def acpi_shutdown
+        end
+
         # Clears the forwarded ports that have been set on the virtual machine.
         def clear_forwarded_ports
         end
@@ -104,6 +104,7 @@ module VagrantPlugins
         end
 
         def_delegators :@driver,
+          :acpi_shutdown,
           :attach_disk,
           :clear_forwarded_ports,
           :clear_shared_folders,
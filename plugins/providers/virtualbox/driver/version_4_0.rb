@@ -176,6 +176,10 @@ module VagrantPlugins
           execute("controlvm", @uuid, "poweroff")
         end
 
+        def acpi_shutdown
+          execute("controlvm", @uuid, "acpipowerbutton")
+        end
+
         def import(ovf)
           ovf = Vagrant::Util::Platform.cygwin_windows_path(ovf)
 
@@ -485,6 +489,7 @@ module VagrantPlugins
               "--hostpath",
               folder[:hostpath]]
             args << "--transient" if folder.key?(:transient) && folder[:transient]
+            args << "--readonly" if folder[:readonly]
             execute("sharedfolder", "add", @uuid, *args)
           end
         end
@@ -283,6 +283,10 @@ module VagrantPlugins
           execute("controlvm", @uuid, "poweroff")
         end
 
+        def acpi_shutdown
+          execute("controlvm", @uuid, "acpipowerbutton")
+        end
+
         def import(ovf)
           ovf = Vagrant::Util::Platform.cygwin_windows_path(ovf)
 
@@ -643,6 +647,7 @@ module VagrantPlugins
               "--hostpath",
               hostpath]
             args << "--transient" if folder.key?(:transient) && folder[:transient]
+            args << "--readonly" if folder[:readonly]
 
             if folder[:SharedFoldersEnableSymlinksCreate]
               # Enable symlinks on the shared folder
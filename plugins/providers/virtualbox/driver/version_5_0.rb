@@ -419,6 +419,10 @@ module VagrantPlugins
           execute("controlvm", @uuid, "poweroff", retryable: true)
         end
 
+        def acpi_shutdown
+          execute("controlvm", @uuid, "acpipowerbutton", retryable: true)
+        end
+
         def import(ovf)
           ovf = Vagrant::Util::Platform.windows_path(ovf)
 
@@ -804,6 +808,7 @@ module VagrantPlugins
                     "--hostpath",
                     hostpath]
             args << "--transient" if folder.key?(:transient) && folder[:transient]
+            args << "--readonly" if folder[:readonly]
 
             args << "--automount" if folder.key?(:automount) && folder[:automount]
 
@@ -27,11 +27,26 @@ module VagrantPlugins
         SyncedFolder
       end
 
+      provider_capability(:virtualbox, :acpi_shutdown) do
+        require_relative "cap"
+        Cap
+      end
+
       provider_capability(:virtualbox, :forwarded_ports) do
         require_relative "cap"
         Cap
       end
 
+      provider_capability(:virtualbox, :guest_tools_version) do
+        require_relative "cap"
+        Cap
+      end
+
+      provider_capability(:virtualbox, :guest_tools_installed_version) do
+        require_relative "cap"
+        Cap
+      end
+
       provider_capability(:virtualbox, :nic_mac_addresses) do
         require_relative "cap"
         Cap
@@ -67,11 +82,36 @@ module VagrantPlugins
         Cap::ValidateDiskExt
       end
 
+      provider_capability(:virtualbox, :clone_machine) do
+        require_relative "cap/clone_machine"
+        Cap::CloneMachine
+      end
+
+      provider_capability(:virtualbox, :export_machine) do
+        require_relative "cap/export_machine"
+        Cap::ExportMachine
+      end
+
       provider_capability(:virtualbox, :snapshot_list) do
         require_relative "cap"
         Cap
       end
 
+      provider_capability(:virtualbox, :resources) do
+        require_relative "cap/resources"
+        Cap::Resources
+      end
+
+      provider_capability(:virtualbox, :modify_resources) do
+        require_relative "cap/resources"
+        Cap::Resources
+      end
+
+      provider_capability(:virtualbox, :resources_config) do
+        require_relative "cap/resources"
+        Cap::Resources
+      end
+
       synced_folder_capability(:virtualbox, "mount_options") do
         require_relative "cap/mount_options"
         Cap::MountOptions
@@ -32,6 +32,24 @@ module VagrantPlugins
         return false
       end
 
+      def self.machine_ids
+        Driver::Meta.new.read_vms.values
+      end
+
+      # The VMs created by Vagrant are named by the SetName action with
+      # the time they were created and a random number as a suffix. The
+      # masters of linked clones are named the same way, and are recorded
+      # in the directories of their boxes.
+      def self.created_machine_ids(env)
+        masters = Dir.glob(env.boxes_path.join("**", "master_id").to_s).map do |path|
+          File.read(path).strip
+        end
+
+        Driver::Meta.new.read_vms.map do |name, uuid|
+          uuid if name =~ /_\d+_\d+$/ && !masters.include?(uuid)
+        end.compact
+      end
+
       def initialize(machine)
         @logger  = Log4r::Logger.new("vagrant::provider::virtualbox")
         @machine = machine
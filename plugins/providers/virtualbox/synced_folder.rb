@@ -17,8 +17,15 @@ module VagrantPlugins
         machine.provider_config.functional_vboxsf
       end
 
+      def hot_mount?(machine)
+        true
+      end
+
       def prepare(machine, folders, _opts)
-        share_folders(machine, folders, false)
+        # Permanent shared folders can not be added to a running VM, so
+        # they are added as transient folders until the VM is restarted
+        running = driver(machine).read_state == :running
+        share_folders(machine, folders, false, as_transient: running)
       end
 
       def enable(machine, folders, _opts)
@@ -99,8 +106,9 @@ module VagrantPlugins
       # The transient parameter determines if we're FORCING transient
       # or not. If this is false, then any shared folders will be
       # shared as non-transient unless they've specifically asked for
-      # transient.
-      def share_folders(machine, folders, transient)
+      # transient. If as_transient is true, the non-transient folders
+      # are shared as transient folders.
+      def share_folders(machine, folders, transient, as_transient: false)
         defs = []
         warn_user_symlink = false
 
@@ -127,9 +135,10 @@ module VagrantPlugins
             defs << {
               name: os_friendly_id(id),
               hostpath: hostpath.to_s,
-              transient: transient,
+              transient: transient || as_transient,
               SharedFoldersEnableSymlinksCreate: enable_symlink_create,
-              automount: !!data[:automount]
+              automount: !!data[:automount],
+              readonly: !!data[:readonly]
             }
           end
         end
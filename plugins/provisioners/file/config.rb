@@ -9,6 +9,13 @@ module VagrantPlugins
       attr_accessor :source
       attr_accessor :destination
 
+      def self.schema
+        {
+          source: [String, Pathname],
+          destination: [String],
+        }
+      end
+
       def validate(machine)
         errors = _detected_errors
         if !source
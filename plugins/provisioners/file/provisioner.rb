@@ -1,6 +1,9 @@
 # Copyright IBM Corp. 2010, 2025
 # SPDX-License-Identifier: BUSL-1.1
 
+require "digest/sha2"
+require "json"
+
 module VagrantPlugins
   module FileUpload
     class Provisioner < Vagrant.plugin("2", :provisioner)
@@ -31,6 +34,31 @@ module VagrantPlugins
         end
       end
 
+      def plan
+        source = File.expand_path(config.source, @machine.env.cwd)
+        [I18n.t("vagrant.actions.vm.provision.file.locations",
+          src: source, dst: config.destination)]
+      end
+
+      # The fingerprint covers the configuration of the provisioner and
+      # the modification time and size of each file in the source.
+      def fingerprint
+        source = File.expand_path(config.source, @machine.env.cwd)
+        return if !File.exist?(source)
+
+        files = [source]
+        if File.directory?(source)
+          files += Dir.glob(File.join(source, "**", "*"), File::FNM_DOTMATCH).
+            reject { |path| File.basename(path) == "." || File.basename(path) == ".." }.sort
+        end
+
+        entries = files.map do |path|
+          stat = File.stat(path)
+          [path[source.length..-1], stat.directory? ? nil : stat.size, stat.mtime.to_f]
+        end
+        Digest::SHA256.hexdigest(JSON.dump([config.destination, entries, config_values]))
+      end
+
       private
 
       # Expand the guest path if the guest has the capability
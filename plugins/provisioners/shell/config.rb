@@ -26,6 +26,30 @@ module VagrantPlugins
       attr_accessor :reboot
       attr_accessor :reset
 
+      def self.schema
+        {
+          inline: [String],
+          path: [String, Pathname],
+          md5: [String],
+          sha1: [String],
+          sha256: [String],
+          sha384: [String],
+          sha512: [String],
+          env: [Hash],
+          upload_path: [String],
+          args: [String, Integer, Array],
+          privileged: [:boolean],
+          binary: [:boolean],
+          keep_color: [:boolean],
+          name: [String, Symbol],
+          sensitive: [:boolean],
+          powershell_args: [String],
+          powershell_elevated_interactive: [:boolean],
+          reboot: [:boolean],
+          reset: [:boolean],
+        }
+      end
+
       def initialize
         @args                  = UNSET_VALUE
         @inline                = UNSET_VALUE
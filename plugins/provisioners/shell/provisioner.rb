@@ -1,7 +1,10 @@
 # Copyright IBM Corp. 2010, 2025
 # SPDX-License-Identifier: BUSL-1.1
 
+require "digest/sha2"
+require "json"
 require "pathname"
+require "shellwords"
 require "tempfile"
 
 require "vagrant/util/downloader"
@@ -17,7 +20,22 @@ module VagrantPlugins
 
       CMD_WINDOWS_SHELL_EXT = ".bat".freeze
 
+      # @return [Integer, nil] exit code of the script from the last run
+      attr_reader :exit_code
+
+      # @return [String, nil] combined stdout and stderr of the script
+      #   from the last run
+      attr_reader :output
+
+      # Set to false so the script exiting with a non-zero exit code does
+      # not raise an error. The caller is then responsible for checking
+      # {#exit_code}.
+      attr_writer :error_check
+
       def provision
+        @exit_code = nil
+        @output = ""
+
         args = ""
         if config.args.is_a?(String)
           args = " #{config.args.to_s}"
@@ -46,6 +64,68 @@ module VagrantPlugins
         end
       end
 
+      def plan
+        steps = []
+        if config.path || config.inline
+          if config.remote?
+            steps << I18n.t("vagrant.provisioners.shell.plan_download",
+              url: config.path)
+          end
+
+          if config.path
+            steps << I18n.t("vagrant.provisioners.shell.plan_path",
+              path: config.path.to_s, upload_path: upload_path)
+          else
+            steps << I18n.t("vagrant.provisioners.shell.plan_inline",
+              length: config.inline.to_s.bytesize, upload_path: upload_path)
+          end
+
+          args = ""
+          if config.args.is_a?(String)
+            args = " #{config.args}"
+          elsif config.args.is_a?(Array)
+            args = " #{config.args.map { |a| quote_and_escape(a) }.join(" ")}"
+          end
+          key = config.privileged ? "plan_run_privileged" : "plan_run"
+          steps << I18n.t("vagrant.provisioners.shell.#{key}",
+            upload_path: upload_path, args: args)
+        end
+
+        if config.reboot
+          steps << I18n.t("vagrant.provisioners.shell.plan_reboot")
+        elsif config.reset
+          steps << I18n.t("vagrant.provisioners.shell.plan_reset")
+        end
+        steps
+      end
+
+      # The fingerprint covers the script and the whole configuration of
+      # the provisioner. Remote scripts are only fingerprinted when a
+      # checksum is configured since the script would otherwise need to
+      # be downloaded.
+      def fingerprint
+        if config.remote?
+          checksum = [:sha512, :sha384, :sha256, :sha1, :md5].map do |type|
+            value = config.send(type)
+            "#{type}:#{value}" if value
+          end.compact.first
+          return if checksum.nil?
+
+          script = "#{config.path}\n#{checksum}"
+        elsif config.path
+          path = Pathname.new(config.path).expand_path(@machine.env.root_path)
+          return if !path.file?
+
+          script = path.binread
+        elsif config.inline
+          script = config.inline
+        else
+          return
+        end
+
+        Digest::SHA256.hexdigest(JSON.dump([Digest::SHA256.hexdigest(script), config.args, config_values]))
+      end
+
       def upload_path
         if !defined?(@_upload_path)
           case @machine.config.vm.guest
@@ -89,6 +169,7 @@ module VagrantPlugins
           options = {}
           options[:color] = color if !config.keep_color
 
+          @output << data if @output
           @machine.ui.detail(data.chomp, **options)
         end
       end
@@ -96,7 +177,8 @@ module VagrantPlugins
       # This is the provision method called if SSH is what is running
       # on the remote end, which assumes a POSIX-style host.
       def provision_ssh(args)
-        env = config.env.map { |k,v| "#{k}=#{quote_and_escape(v.to_s)}" }
+        env = machine_environment { |v| Shellwords.escape(v) }.map { |k,v| "#{k}=#{v}" }
+        env += config.env.map { |k,v| "#{k}=#{quote_and_escape(v.to_s)}" }
         env = env.join(" ")
 
         command =  "chmod +x '#{upload_path}'"
@@ -133,9 +215,10 @@ module VagrantPlugins
             # Execute it with sudo
             outputs, handler = build_outputs
             begin
-              comm.execute(
+              @exit_code = comm.execute(
                 command,
                 sudo: config.privileged,
+                error_check: @error_check != false,
                 error_key: :ssh_bad_exit_status_muted,
                 &handler
               )
@@ -152,7 +235,9 @@ module VagrantPlugins
         with_script_file do |path|
           # Upload the script to the machine
           @machine.communicate.tap do |comm|
-            env = config.env.map{|k,v| comm.generate_environment_export(k, v)}.join(';')
+            env = machine_environment.merge(config.env).map do |k,v|
+              comm.generate_environment_export(k.to_s, v.to_s)
+            end.join(';')
 
             remote_ext = get_windows_ext(path)
             remote_path = add_extension(upload_path, remote_ext)
@@ -192,9 +277,10 @@ module VagrantPlugins
             # Execute it with sudo
             begin
               outputs, handler = build_outputs
-              comm.execute(
+              @exit_code = comm.execute(
                 command,
                 shell: :powershell,
+                error_check: @error_check != false,
                 error_key: :ssh_bad_exit_status_muted,
                 &handler
               )
@@ -222,7 +308,8 @@ module VagrantPlugins
             comm.upload(path.to_s, winrm_upload_path)
 
             # Build the environment
-            env = config.env.map { |k,v| "$env:#{k} = #{quote_and_escape(v.to_s)}" }
+            env = machine_environment { |v| "'#{v.gsub("'", "''")}'" }.map { |k,v| "$env:#{k} = #{v}" }
+            env += config.env.map { |k,v| "$env:#{k} = #{quote_and_escape(v.to_s)}" }
             env = env.join("; ")
 
             # Calculate the path that we'll be executing
@@ -265,9 +352,10 @@ module VagrantPlugins
             # Execute it with sudo
             begin
               outputs, handler = build_outputs
-              comm.sudo(command,
+              @exit_code = comm.sudo(command,
                 elevated: config.privileged,
                 interactive: config.powershell_elevated_interactive,
+                error_check: @error_check != false,
                 &handler
               )
             ensure
@@ -277,6 +365,31 @@ module VagrantPlugins
         end
       end
 
+      # The environment variables of the machine which are not overridden
+      # by the environment of the provisioner. When a block is given, the
+      # values are escaped with it. Secrets are registered as sensitive
+      # once escaped too, so the escaped values are also removed from the
+      # output and logs.
+      #
+      # @yieldparam [String] value Value of the variable
+      # @yieldreturn [String] the escaped value
+      # @return [Hash]
+      def machine_environment
+        environment = @machine.config.vm.environment || {}
+        names = config.env.keys.map(&:to_s)
+        environment = environment.reject { |name, _| names.include?(name.to_s) }
+        return environment if !block_given?
+
+        environment.map do |name, value|
+          value = value.to_s
+          escaped = yield value
+          if Vagrant::Util::CredentialScrubber.sensitive_strings.include?(value)
+            Vagrant::Util::CredentialScrubber.sensitive(escaped)
+          end
+          [name, escaped]
+        end.to_h
+      end
+
       # Quote and escape strings for shell execution, thanks to Capistrano.
       def quote_and_escape(text, quote = '"')
         "#{quote}#{text.gsub(/#{quote}/) { |m| "#{m}\\#{m}#{m}" }}#{quote}"
@@ -0,0 +1,254 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require "digest"
+require "net/http"
+require "openssl"
+require "rexml/document"
+require "uri"
+
+require_relative "errors"
+
+module VagrantPlugins
+  module S3Push
+    # A minimal client of the S3 API supporting the requests needed to
+    # upload objects, including multipart uploads. Requests are signed
+    # with AWS Signature Version 4 so any S3 compatible object store can
+    # be used.
+    class Client
+      # Hash of an empty request body
+      EMPTY_SHA256 = Digest::SHA256.hexdigest("").freeze
+
+      attr_reader :bucket
+      attr_reader :region
+
+      # @param [String] bucket Name of the bucket
+      # @param [String] region Region of the bucket
+      # @param [Credentials::Credential] credential
+      # @param [String] endpoint URL of the object store, AWS if not set
+      # @param [Boolean] path_style Address the bucket in the path of the
+      #   URL instead of the host
+      def initialize(bucket:, region:, credential:, endpoint: nil, path_style: false)
+        @bucket = bucket
+        @region = region
+        @credential = credential
+        @endpoint = URI.parse(endpoint || "https://s3.#{region}.amazonaws.com")
+        # Buckets with dots in their name do not match the certificate of
+        # the virtual hosts of AWS
+        @path_style = path_style || (!endpoint && bucket.include?("."))
+        @http = nil
+        @logger = Log4r::Logger.new("vagrant::pushes::s3::client")
+      end
+
+      # Upload a file as a single object.
+      #
+      # @param [String] key Key of the object
+      # @param [String] path Path of the file
+      # @param [String] sha256 SHA256 hex digest of the file
+      def put_object(key, path, sha256)
+        File.open(path, "rb") do |file|
+          request("PUT", key, body: file, sha256: sha256, headers: {
+            "content-length" => File.size(path).to_s,
+            "content-type" => "application/octet-stream",
+          })
+        end
+        nil
+      end
+
+      # @param [String] key Key of the object
+      # @return [String] ID of the multipart upload
+      def create_multipart_upload(key)
+        response = request("POST", key, query: {"uploads" => ""})
+        upload_id = xml_text(response.body, "UploadId")
+        if upload_id.to_s.empty?
+          raise Errors::RequestFailed, method: "POST", key: key,
+            status: response.code, message: "no upload ID returned"
+        end
+        upload_id
+      end
+
+      # @param [String] key Key of the object
+      # @param [String] upload_id ID of the multipart upload
+      # @param [Integer] number Number of the part, starting at 1
+      # @param [String] data Data of the part
+      # @return [String] ETag of the part
+      def upload_part(key, upload_id, number, data)
+        response = request("PUT", key, body: data,
+          query: {"partNumber" => number.to_s, "uploadId" => upload_id},
+          headers: {"content-type" => "application/octet-stream"})
+        response["etag"]
+      end
+
+      # @param [String] key Key of the object
+      # @param [String] upload_id ID of the multipart upload
+      # @param [Array<String>] etags ETags of the parts, in order
+      def complete_multipart_upload(key, upload_id, etags)
+        parts = etags.each_with_index.map do |etag, i|
+          "<Part><PartNumber>#{i + 1}</PartNumber>" \
+            "<ETag>#{etag.to_s.encode(xml: :text)}</ETag></Part>"
+        end
+        body = "<CompleteMultipartUpload>#{parts.join}</CompleteMultipartUpload>"
+
+        response = request("POST", key, body: body,
+          query: {"uploadId" => upload_id},
+          headers: {"content-type" => "application/xml"})
+
+        # Completing an upload can fail after the response has started,
+        # in which case the error is in the body of a successful response
+        error!("POST", key, response) if xml_text(response.body, "Code")
+        nil
+      end
+
+      # @param [String] key Key of the object
+      # @param [String] upload_id ID of the multipart upload
+      def abort_multipart_upload(key, upload_id)
+        request("DELETE", key, query: {"uploadId" => upload_id})
+        nil
+      end
+
+      # Close the connection to the object store. A new connection is
+      # opened by the next request.
+      def close
+        @http.finish if @http && @http.started?
+      rescue IOError
+      ensure
+        @http = nil
+      end
+
+      # Sign the request with AWS Signature Version 4.
+      #
+      # @param [String] method HTTP method
+      # @param [String] path Encoded path of the request
+      # @param [String] query Encoded query of the request, sorted by name
+      # @param [Hash<String, String>] headers Headers of the request, with
+      #   lowercase names
+      # @param [String] sha256 SHA256 hex digest of the body
+      # @param [Time] time Time of the request
+      # @return [Hash<String, String>] the headers with the signature
+      def sign(method, path, query, headers, sha256, time=Time.now)
+        time = time.utc
+        amz_date = time.strftime("%Y%m%dT%H%M%SZ")
+        date = time.strftime("%Y%m%d")
+
+        headers = headers.merge(
+          "host" => host,
+          "x-amz-content-sha256" => sha256,
+          "x-amz-date" => amz_date,
+        )
+        headers["x-amz-security-token"] = @credential.session_token if @credential.session_token
+
+        names = headers.keys.sort
+        canonical_request = [
+          method,
+          path,
+          query,
+          names.map { |name| "#{name}:#{headers[name].to_s.strip}\n" }.join,
+          names.join(";"),
+          sha256,
+        ].join("\n")
+
+        scope = "#{date}/#{@region}/s3/aws4_request"
+        string_to_sign = [
+          "AWS4-HMAC-SHA256",
+          amz_date,
+          scope,
+          Digest::SHA256.hexdigest(canonical_request),
+        ].join("\n")
+
+        key = [date, @region, "s3", "aws4_request"].inject("AWS4#{@credential.secret_access_key}") do |k, v|
+          OpenSSL::HMAC.digest("SHA256", k, v)
+        end
+        signature = OpenSSL::HMAC.hexdigest("SHA256", key, string_to_sign)
+
+        headers.merge("authorization" => "AWS4-HMAC-SHA256 " \
+          "Credential=#{@credential.access_key_id}/#{scope}, " \
+          "SignedHeaders=#{names.join(";")}, Signature=#{signature}")
+      end
+
+      # @return [String] encoded path of the object
+      def path(key)
+        encoded = key.split("/", -1).map { |part| self.class.uri_encode(part) }.join("/")
+        if @path_style
+          "#{@endpoint.path.chomp("/")}/#{self.class.uri_encode(@bucket)}/#{encoded}"
+        else
+          "/#{encoded}"
+        end
+      end
+
+      # @return [String] value of the host header
+      def host
+        host = hostname
+        host += ":#{@endpoint.port}" if @endpoint.port != @endpoint.default_port
+        host
+      end
+
+      # Encode the value as required by AWS Signature Version 4
+      #
+      # @param [String] value
+      # @return [String]
+      def self.uri_encode(value)
+        value.to_s.b.gsub(/[^A-Za-z0-9\-._~]/n) { |c| "%%%02X" % c.ord }
+      end
+
+      protected
+
+      # @return [String] name of the host serving the bucket
+      def hostname
+        @path_style ? @endpoint.host : "#{@bucket}.#{@endpoint.host}"
+      end
+
+      # @return [Net::HTTPResponse] the successful response
+      # @raise [Errors::RequestFailed]
+      def request(method, key, query: {}, body: nil, sha256: nil, headers: {})
+        query = query.sort.map do |name, value|
+          "#{self.class.uri_encode(name)}=#{self.class.uri_encode(value)}"
+        end.join("&")
+        sha256 ||= body.is_a?(String) ? Digest::SHA256.hexdigest(body) : EMPTY_SHA256
+
+        request_path = path(key)
+        request_path += "?#{query}" if !query.empty?
+        @logger.debug("#{method} #{request_path}")
+
+        request = Net::HTTPGenericRequest.new(method, !body.nil?, true, request_path,
+          sign(method, path(key), query, headers, sha256))
+        if body.respond_to?(:read)
+          request.body_stream = body
+        else
+          request.body = body
+        end
+
+        response = http.request(request)
+        error!(method, key, response) if !response.is_a?(Net::HTTPSuccess)
+        response
+      end
+
+      # @raise [Errors::RequestFailed]
+      def error!(method, key, response)
+        message = xml_text(response.body, "Message") || xml_text(response.body, "Code") ||
+          response.message
+        raise Errors::RequestFailed, method: method, key: key,
+          status: response.code, message: message
+      end
+
+      # @return [String, nil] text of the first element with the name
+      def xml_text(body, name)
+        return if body.to_s.empty?
+
+        element = REXML::XPath.first(REXML::Document.new(body), "//*[local-name()='#{name}']")
+        element && element.text
+      rescue REXML::ParseException
+        nil
+      end
+
+      # @return [Net::HTTP] the started connection to the object store
+      def http
+        if !@http
+          @http = Net::HTTP.new(hostname, @endpoint.port)
+          @http.use_ssl = @endpoint.scheme == "https"
+        end
+        @http.start if !@http.started?
+        @http
+      end
+    end
+  end
+end
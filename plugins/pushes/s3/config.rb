@@ -0,0 +1,178 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+module VagrantPlugins
+  module S3Push
+    class Config < Vagrant.plugin("2", :config)
+      # Smallest size of the parts of multipart uploads allowed by S3
+      MINIMUM_PART_SIZE = 5 * 1024 * 1024
+
+      # The name of the bucket to upload the files to.
+      # @return [String]
+      attr_accessor :bucket
+
+      # The prefix of the keys of the uploaded objects (default is none).
+      # @return [String]
+      attr_accessor :prefix
+
+      # The region of the bucket. By default the region is read from the
+      # environment or the AWS configuration file, or is us-east-1.
+      # @return [String]
+      attr_accessor :region
+
+      # The URL of an S3 compatible object store to use instead of AWS.
+      # @return [String]
+      attr_accessor :endpoint
+
+      # Address the bucket in the path of the URL instead of the host. By
+      # default this is true when an endpoint is set.
+      # @return [true, false]
+      attr_accessor :force_path_style
+
+      # The profile of the AWS configuration files to read credentials
+      # from. By default this is AWS_PROFILE or "default".
+      # @return [String]
+      attr_accessor :profile
+
+      # Credentials to use instead of those found by the AWS credential
+      # chain.
+      # @return [String]
+      attr_accessor :access_key_id
+      attr_accessor :secret_access_key
+      attr_accessor :session_token
+
+      # Lists of files to include/exclude in what is uploaded. Exclude is
+      # always the last run filter, so if a file is matched in both include
+      # and exclude, it will be excluded. All the files are included if no
+      # include is given.
+      #
+      # The value of the array elements should be a simple file glob relative
+      # to the directory being uploaded.
+      # @return [Array<String>]
+      attr_accessor :includes
+      attr_accessor :excludes
+
+      # The base directory with file contents to upload. By default this
+      # is the same directory as the Vagrantfile.
+      # @return [String]
+      attr_accessor :dir
+
+      # Files larger than this size, in bytes, are uploaded in parts
+      # (default is 8 MiB).
+      # @return [Integer]
+      attr_accessor :multipart_threshold
+
+      # The size of the parts of multipart uploads, in bytes (default is
+      # 8 MiB).
+      # @return [Integer]
+      attr_accessor :multipart_chunk_size
+
+      def initialize
+        @bucket = UNSET_VALUE
+        @prefix = UNSET_VALUE
+        @region = UNSET_VALUE
+        @endpoint = UNSET_VALUE
+        @force_path_style = UNSET_VALUE
+        @profile = UNSET_VALUE
+        @access_key_id = UNSET_VALUE
+        @secret_access_key = UNSET_VALUE
+        @session_token = UNSET_VALUE
+        @multipart_threshold = UNSET_VALUE
+        @multipart_chunk_size = UNSET_VALUE
+
+        @includes = []
+        @excludes = []
+
+        @dir = UNSET_VALUE
+      end
+
+      def merge(other)
+        super.tap do |result|
+          result.includes = self.includes.dup.concat(other.includes).uniq
+          result.excludes = self.excludes.dup.concat(other.excludes).uniq
+        end
+      end
+
+      def finalize!
+        @bucket = nil if @bucket == UNSET_VALUE
+        @prefix = "" if @prefix == UNSET_VALUE
+        @prefix = @prefix.to_s.sub(/\A\/+/, "").sub(/\/+\z/, "")
+        @region = nil if @region == UNSET_VALUE
+        @endpoint = nil if @endpoint == UNSET_VALUE
+        @force_path_style = !!@endpoint if @force_path_style == UNSET_VALUE
+        @profile = nil if @profile == UNSET_VALUE
+        @access_key_id = nil if @access_key_id == UNSET_VALUE
+        @secret_access_key = nil if @secret_access_key == UNSET_VALUE
+        @session_token = nil if @session_token == UNSET_VALUE
+        @multipart_threshold = 8 * 1024 * 1024 if @multipart_threshold == UNSET_VALUE
+        @multipart_chunk_size = 8 * 1024 * 1024 if @multipart_chunk_size == UNSET_VALUE
+        @dir = "." if @dir == UNSET_VALUE
+
+        if @secret_access_key
+          Vagrant::Util::CredentialScrubber.sensitive(@secret_access_key.to_s)
+        end
+        if @session_token
+          Vagrant::Util::CredentialScrubber.sensitive(@session_token.to_s)
+        end
+      end
+
+      def validate(machine)
+        errors = _detected_errors
+
+        if missing?(@bucket)
+          errors << I18n.t("s3_push.errors.missing_attribute",
+            attribute: "bucket",
+          )
+        end
+
+        if missing?(@dir)
+          errors << I18n.t("s3_push.errors.missing_attribute",
+            attribute: "dir",
+          )
+        end
+
+        if missing?(@access_key_id) != missing?(@secret_access_key)
+          errors << I18n.t("s3_push.errors.incomplete_credentials")
+        end
+
+        [:multipart_threshold, :multipart_chunk_size].each do |attribute|
+          value = instance_variable_get(:"@#{attribute}")
+          if !value.is_a?(Integer) || value <= 0
+            errors << I18n.t("s3_push.errors.invalid_size",
+              attribute: attribute, given: value.inspect)
+          end
+        end
+
+        if @multipart_chunk_size.is_a?(Integer) && @multipart_chunk_size < MINIMUM_PART_SIZE &&
+            @multipart_chunk_size > 0
+          errors << I18n.t("s3_push.errors.chunk_size_too_small",
+            minimum: MINIMUM_PART_SIZE)
+        end
+
+        { "S3 push" => errors }
+      end
+
+      # Add the filepath to the list of includes
+      # @param [String] filepath
+      def include(filepath)
+        @includes << filepath
+      end
+      alias_method :include=, :include
+
+      # Add the filepath to the list of excludes
+      # @param [String] filepath
+      def exclude(filepath)
+        @excludes << filepath
+      end
+      alias_method :exclude=, :exclude
+
+      private
+
+      # Determine if the given string is "missing" (blank)
+      # @return [true, false]
+      def missing?(obj)
+        obj.to_s.strip.empty?
+      end
+    end
+  end
+end
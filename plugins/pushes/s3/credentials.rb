@@ -0,0 +1,220 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require "json"
+require "net/http"
+require "uri"
+
+require_relative "errors"
+
+module VagrantPlugins
+  module S3Push
+    # Resolves the credentials and region to use with the standard AWS
+    # credential chain. Credentials are looked up, in order, in:
+    #
+    #   * the push configuration
+    #   * the AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and
+    #     AWS_SESSION_TOKEN environment variables
+    #   * the profile of the shared credentials and config files
+    #   * the container credentials endpoint (ECS)
+    #   * the instance metadata service (EC2)
+    class Credentials
+      Credential = Struct.new(:access_key_id, :secret_access_key, :session_token, :source)
+
+      # Host of the container credentials endpoint
+      CONTAINER_CREDENTIALS_HOST = "169.254.170.2".freeze
+
+      # Host of the instance metadata service
+      INSTANCE_METADATA_HOST = "169.254.169.254".freeze
+
+      # Seconds to wait for the metadata endpoints
+      METADATA_TIMEOUT = 1
+
+      # Errors of requests to the metadata endpoints, which are not
+      # available outside of AWS
+      METADATA_ERRORS = [
+        IOError,
+        JSON::ParserError,
+        Net::HTTPBadResponse,
+        SocketError,
+        SystemCallError,
+        Timeout::Error,
+      ].freeze
+
+      # Region used when none is configured
+      DEFAULT_REGION = "us-east-1".freeze
+
+      # @param [Config] config Push configuration
+      # @param [Hash] env Environment variables
+      def initialize(config, env=ENV)
+        @config = config
+        @env = env
+        @logger = Log4r::Logger.new("vagrant::pushes::s3::credentials")
+      end
+
+      # @return [Credential]
+      # @raise [Errors::CredentialsMissing]
+      def resolve
+        credential = from_config || from_env || from_shared_files ||
+          from_container || from_instance_metadata
+        raise Errors::CredentialsMissing, profile: profile if !credential
+
+        @logger.info("Using AWS credentials from #{credential.source}")
+        [credential.secret_access_key, credential.session_token].compact.each do |secret|
+          Vagrant::Util::CredentialScrubber.sensitive(secret)
+        end
+        credential
+      end
+
+      # @return [String] the region of the bucket
+      def region
+        @config.region || @env["AWS_REGION"] || @env["AWS_DEFAULT_REGION"] ||
+          profile_settings(config_file, "profile #{profile}")["region"] ||
+          DEFAULT_REGION
+      end
+
+      # @return [String] the profile of the shared files to use
+      def profile
+        @config.profile || @env["AWS_PROFILE"] || "default"
+      end
+
+      # Parses an INI file of the AWS CLI
+      #
+      # @param [String] path
+      # @return [Hash<String, Hash<String, String>>] settings keyed by section
+      def self.parse_ini(path)
+        sections = {}
+        current = nil
+        File.readlines(path).each do |line|
+          line = line.strip
+          next if line.empty? || line.start_with?("#", ";")
+
+          if line =~ /\A\[(.+)\]\z/
+            current = sections[$1.strip] ||= {}
+          elsif current && line.include?("=")
+            key, value = line.split("=", 2)
+            current[key.strip] = value.strip
+          end
+        end
+        sections
+      end
+
+      protected
+
+      def from_config
+        return if !@config.access_key_id || !@config.secret_access_key
+
+        build(@config.access_key_id, @config.secret_access_key,
+          @config.session_token, "the Vagrantfile")
+      end
+
+      def from_env
+        build(@env["AWS_ACCESS_KEY_ID"], @env["AWS_SECRET_ACCESS_KEY"],
+          @env["AWS_SESSION_TOKEN"], "the environment")
+      end
+
+      def from_shared_files
+        # The credentials file takes precedence over the config file
+        [
+          [credentials_file, profile],
+          [config_file, "profile #{profile}"],
+        ].each do |path, section|
+          settings = profile_settings(path, section)
+          credential = build(settings["aws_access_key_id"],
+            settings["aws_secret_access_key"], settings["aws_session_token"],
+            "the profile '#{profile}' of #{path}")
+          return credential if credential
+        end
+
+        nil
+      end
+
+      def from_container
+        uri = nil
+        headers = {}
+        if @env["AWS_CONTAINER_CREDENTIALS_RELATIVE_URI"]
+          uri = URI.parse("http://#{CONTAINER_CREDENTIALS_HOST}" \
+            "#{@env["AWS_CONTAINER_CREDENTIALS_RELATIVE_URI"]}")
+        elsif @env["AWS_CONTAINER_CREDENTIALS_FULL_URI"]
+          uri = URI.parse(@env["AWS_CONTAINER_CREDENTIALS_FULL_URI"])
+          token = @env["AWS_CONTAINER_AUTHORIZATION_TOKEN"]
+          headers["Authorization"] = token if token
+        end
+        return if !uri
+
+        response = metadata_request(Net::HTTP::Get.new(uri, headers), uri)
+        from_metadata(response, "the container credentials endpoint")
+      rescue *METADATA_ERRORS, URI::InvalidURIError => e
+        @logger.debug("Failed to read the container credentials: #{e}")
+        nil
+      end
+
+      def from_instance_metadata
+        return if @env["AWS_EC2_METADATA_DISABLED"].to_s.downcase == "true"
+
+        base = URI.parse("http://#{INSTANCE_METADATA_HOST}")
+        token = metadata_request(Net::HTTP::Put.new("/latest/api/token",
+          "X-aws-ec2-metadata-token-ttl-seconds" => "21600"), base)
+        return if !token
+
+        headers = {"X-aws-ec2-metadata-token" => token}
+        path = "/latest/meta-data/iam/security-credentials/"
+        role = metadata_request(Net::HTTP::Get.new(path, headers), base)
+        return if role.to_s.strip.empty?
+
+        response = metadata_request(
+          Net::HTTP::Get.new("#{path}#{role.lines.first.strip}", headers), base)
+        from_metadata(response, "the instance metadata")
+      rescue *METADATA_ERRORS => e
+        @logger.debug("Failed to read the instance metadata credentials: #{e}")
+        nil
+      end
+
+      # @return [String, nil] body of the successful response
+      def metadata_request(request, uri)
+        # Proxies are not used, as the endpoints are link local
+        http = Net::HTTP.new(uri.host, uri.port, nil)
+        http.use_ssl = uri.scheme == "https"
+        http.open_timeout = METADATA_TIMEOUT
+        http.read_timeout = METADATA_TIMEOUT
+        response = http.request(request)
+        response.body if response.is_a?(Net::HTTPSuccess)
+      end
+
+      def from_metadata(body, source)
+        return if !body
+
+        data = JSON.parse(body)
+        build(data["AccessKeyId"], data["SecretAccessKey"], data["Token"], source)
+      end
+
+      # @return [Hash<String, String>] settings of the section of the file
+      def profile_settings(path, section)
+        return {} if !File.file?(path)
+
+        sections = self.class.parse_ini(path)
+        # The default profile has no prefix in the config file
+        sections[section] || (section == "profile default" && sections["default"]) || {}
+      end
+
+      def credentials_file
+        @env["AWS_SHARED_CREDENTIALS_FILE"] || File.join(home, ".aws", "credentials")
+      end
+
+      def config_file
+        @env["AWS_CONFIG_FILE"] || File.join(home, ".aws", "config")
+      end
+
+      def home
+        @env["HOME"] || @env["USERPROFILE"] || Dir.home
+      end
+
+      def build(access_key_id, secret_access_key, session_token, source)
+        return if access_key_id.to_s.empty? || secret_access_key.to_s.empty?
+
+        session_token = nil if session_token.to_s.empty?
+        Credential.new(access_key_id, secret_access_key, session_token, source)
+      end
+    end
+  end
+end
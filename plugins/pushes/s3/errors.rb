@@ -0,0 +1,24 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+module VagrantPlugins
+  module S3Push
+    module Errors
+      class Error < Vagrant::Errors::VagrantError
+        error_namespace("s3_push.errors")
+      end
+
+      class CredentialsMissing < Error
+        error_key(:credentials_missing)
+      end
+
+      class RequestFailed < Error
+        error_key(:request_failed)
+      end
+
+      class UploadFailed < Error
+        error_key(:upload_failed)
+      end
+    end
+  end
+end
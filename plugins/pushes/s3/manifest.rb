@@ -0,0 +1,85 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require "json"
+require "pathname"
+require "securerandom"
+
+module VagrantPlugins
+  module S3Push
+    # The manifest records the hash of the content of each object uploaded
+    # by the previous pushes, so only the files that changed since are
+    # uploaded. The manifest is replaced atomically when saved, so it is
+    # never left partially written.
+    class Manifest
+      VERSION = 1
+
+      attr_reader :path
+
+      # @param [Pathname] path Path of the manifest file
+      def initialize(path)
+        @path = Pathname.new(path)
+        @logger = Log4r::Logger.new("vagrant::pushes::s3::manifest")
+        @objects = load
+      end
+
+      # @param [String] key Key of the object
+      # @return [Hash, nil] the "sha256" and "size" of the object
+      def [](key)
+        @objects[key]
+      end
+
+      # @param [String] key Key of the object
+      # @param [String] sha256 SHA256 hex digest of the content
+      # @param [Integer] size Size of the content
+      def record(key, sha256, size)
+        @objects[key] = {"sha256" => sha256, "size" => size}
+      end
+
+      # @param [String] key Key of the object
+      def delete(key)
+        @objects.delete(key)
+      end
+
+      # @return [Array<String>] keys of the recorded objects
+      def keys
+        @objects.keys
+      end
+
+      # @param [String] key Key of the object
+      # @param [String] sha256 SHA256 hex digest of the content
+      # @return [Boolean] the content differs from the recorded object
+      def changed?(key, sha256)
+        !@objects.key?(key) || @objects[key]["sha256"] != sha256
+      end
+
+      def save
+        @path.dirname.mkpath
+        tmp = @path.dirname.join(".#{@path.basename}.#{SecureRandom.hex(4)}")
+        begin
+          tmp.write(JSON.pretty_generate("version" => VERSION, "objects" => @objects))
+          File.rename(tmp, @path)
+        ensure
+          tmp.delete if tmp.exist?
+        end
+      end
+
+      protected
+
+      def load
+        return {} if !@path.file?
+
+        data = JSON.parse(@path.read)
+        if !data.is_a?(Hash) || data["version"] != VERSION || !data["objects"].is_a?(Hash)
+          @logger.warn("Ignoring manifest with unknown format: #{@path}")
+          return {}
+        end
+        data["objects"]
+      rescue JSON::ParserError => e
+        # All the files are uploaded again
+        @logger.warn("Ignoring invalid manifest #{@path}: #{e}")
+        {}
+      end
+    end
+  end
+end
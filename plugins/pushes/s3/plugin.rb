@@ -0,0 +1,36 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require "vagrant"
+
+module VagrantPlugins
+  module S3Push
+    class Plugin < Vagrant.plugin("2")
+      name "s3"
+      description <<-DESC
+      Deploy to an S3 compatible object store.
+      DESC
+
+      config(:s3, :push) do
+        require File.expand_path("../config", __FILE__)
+        init!
+        Config
+      end
+
+      push(:s3) do
+        require File.expand_path("../push", __FILE__)
+        init!
+        Push
+      end
+
+      protected
+
+      def self.init!
+        return if defined?(@_init)
+        I18n.load_path << File.expand_path("../locales/en.yml", __FILE__)
+        I18n.reload!
+        @_init = true
+      end
+    end
+  end
+end
@@ -0,0 +1,213 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require "digest"
+require "openssl"
+require "pathname"
+
+require_relative "client"
+require_relative "credentials"
+require_relative "errors"
+require_relative "manifest"
+
+module VagrantPlugins
+  module S3Push
+    class Push < Vagrant.plugin("2", :push)
+      DEFAULT_EXCLUDES = %w(.git .hg .svn .vagrant).freeze
+
+      # Largest number of parts of a multipart upload allowed by S3
+      MAXIMUM_PARTS = 10_000
+
+      # Errors uploading an object, after which the other objects are
+      # still uploaded
+      UPLOAD_ERRORS = [
+        Errors::RequestFailed,
+        IOError,
+        Net::HTTPBadResponse,
+        OpenSSL::SSL::SSLError,
+        SocketError,
+        SystemCallError,
+        Timeout::Error,
+      ].freeze
+
+      def initialize(*)
+        super
+        @logger = Log4r::Logger.new("vagrant::pushes::s3")
+      end
+
+      def push
+        files = all_files
+        manifest = Manifest.new(manifest_path)
+
+        # Objects of files that no longer exist are forgotten
+        (manifest.keys - files.keys).each { |key| manifest.delete(key) }
+
+        hashes = {}
+        changed = files.select do |key, path|
+          hashes[key] = Digest::SHA256.file(path).hexdigest
+          manifest.changed?(key, hashes[key])
+        end
+
+        if changed.empty?
+          manifest.save
+          env.ui.info(I18n.t("s3_push.up_to_date",
+            total: files.length, bucket: config.bucket))
+          return
+        end
+
+        env.ui.info(I18n.t("s3_push.uploading",
+          count: changed.length, total: files.length, bucket: config.bucket))
+
+        failures = {}
+        client = self.client
+        begin
+          changed.each do |key, path|
+            env.ui.detail(I18n.t("s3_push.uploading_file",
+              path: relative_path_for(path, base_dir), key: key))
+
+            begin
+              upload(client, key, path, hashes[key])
+              manifest.record(key, hashes[key], File.size(path))
+            rescue *UPLOAD_ERRORS => e
+              @logger.error("Failed to upload #{path} to #{key}: #{e}")
+              failures[key] = e.message
+              # The connection may be unusable after an error
+              client.close
+            end
+          end
+        ensure
+          client.close
+          # Only the objects that were uploaded are recorded, so the
+          # objects that failed are uploaded by the next push
+          manifest.save
+        end
+
+        if !failures.empty?
+          raise Errors::UploadFailed,
+            count: failures.length,
+            total: changed.length,
+            bucket: config.bucket,
+            objects: failures.map { |key, message| "  #{key}: #{message}" }.join("\n")
+        end
+
+        env.ui.success(I18n.t("s3_push.uploaded",
+          count: changed.length, bucket: config.bucket))
+      end
+
+      # Upload the file, in parts if it is larger than the multipart
+      # threshold.
+      #
+      # @param [Client] client
+      # @param [String] key Key of the object
+      # @param [String] path Path of the file
+      # @param [String] sha256 SHA256 hex digest of the file
+      def upload(client, key, path, sha256)
+        size = File.size(path)
+        if size <= config.multipart_threshold
+          client.put_object(key, path, sha256)
+          return
+        end
+
+        part_size = [config.multipart_chunk_size, (size.to_f / MAXIMUM_PARTS).ceil].max
+        upload_id = client.create_multipart_upload(key)
+        begin
+          etags = []
+          File.open(path, "rb") do |file|
+            while (data = file.read(part_size))
+              etags << client.upload_part(key, upload_id, etags.length + 1, data)
+            end
+          end
+          client.complete_multipart_upload(key, upload_id, etags)
+        rescue Exception
+          # Remove the parts which were uploaded, as they are stored
+          # until the upload is completed or aborted
+          begin
+            client.abort_multipart_upload(key, upload_id)
+          rescue *UPLOAD_ERRORS => e
+            @logger.warn("Failed to abort multipart upload of #{key}: #{e}")
+          end
+          raise
+        end
+      end
+
+      # @return [Client]
+      def client
+        credentials = Credentials.new(config)
+        Client.new(
+          bucket: config.bucket,
+          region: credentials.region,
+          credential: credentials.resolve,
+          endpoint: config.endpoint,
+          path_style: config.force_path_style,
+        )
+      end
+
+      # The files that should be pushed by this push, keyed by the key of
+      # their object. This method only returns **files**, not folders!
+      # @return [Hash<String, String>]
+      def all_files
+        includes = config.includes.empty? ? ["**/*"] : config.includes
+        paths = includes.flat_map do |pattern|
+          Dir.glob(pattern, File::FNM_DOTMATCH, base: base_dir)
+        end
+
+        excludes = Array(config.excludes) + DEFAULT_EXCLUDES
+        paths = paths.map { |p| Pathname.new(p).cleanpath.to_s }.uniq.sort
+        paths.reject! { |p| excluded?(p, excludes) }
+        paths.select! { |p| File.file?(File.join(base_dir, p)) }
+
+        Hash[paths.map { |p| [object_key(p), File.join(base_dir, p)] }]
+      end
+
+      # A path is excluded if a pattern matches it or one of its parent
+      # directories. Patterns without a slash also match the name of
+      # files and directories at any depth.
+      #
+      # @param [String] path Path relative to the base directory
+      # @param [Array<String>] excludes Patterns to exclude
+      # @return [Boolean]
+      def excluded?(path, excludes)
+        parts = path.split("/")
+        candidates = (1..parts.length).map { |i| parts[0, i].join("/") }
+
+        excludes.any? do |pattern|
+          candidates.any? { |c| File.fnmatch?(pattern, c, File::FNM_DOTMATCH) } ||
+            (!pattern.include?("/") &&
+              parts.any? { |part| File.fnmatch?(pattern, part, File::FNM_DOTMATCH) })
+        end
+      end
+
+      # @param [String] path Path relative to the base directory
+      # @return [String] key of the object of the file
+      def object_key(path)
+        config.prefix.empty? ? path : "#{config.prefix}/#{path}"
+      end
+
+      # The manifest of the objects uploaded by the previous pushes to the
+      # same bucket and prefix.
+      # @return [Pathname]
+      def manifest_path
+        id = Digest::SHA1.hexdigest(
+          [config.endpoint, config.bucket, config.prefix].join("|"))
+        env.local_data_path.join("push", "s3", "#{id}.json")
+      end
+
+      # The relative path from the given `parent`. If files exist on another
+      # device, this will probably blow up.
+      # @return [String]
+      def relative_path_for(path, parent)
+        Pathname.new(path).relative_path_from(Pathname.new(parent)).to_s
+      rescue ArgumentError
+        return path
+      end
+
+      def base_dir
+        if Pathname.new(config.dir).absolute?
+          config.dir
+        else
+          File.join(File.expand_path(env.root_path), config.dir)
+        end
+      end
+    end
+  end
+end
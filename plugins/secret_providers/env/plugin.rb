@@ -0,0 +1,20 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require "vagrant"
+
+module VagrantPlugins
+  module SecretEnv
+    class Plugin < Vagrant.plugin("2")
+      name "env secret provider"
+      description <<-DESC
+      Reads secrets from environment variables.
+      DESC
+
+      secret_provider(:env) do
+        require File.expand_path("../provider", __FILE__)
+        Provider
+      end
+    end
+  end
+end
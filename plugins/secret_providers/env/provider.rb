@@ -0,0 +1,17 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+module VagrantPlugins
+  module SecretEnv
+    # Reads secrets from environment variables. The path of the
+    # reference is the name of the variable, such as "env:TOKEN".
+    class Provider < Vagrant.plugin("2", :secret_provider)
+      def resolve(path, field)
+        value = ENV[path]
+        return if value.nil? || value.empty?
+
+        value
+      end
+    end
+  end
+end
@@ -0,0 +1,20 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require "vagrant"
+
+module VagrantPlugins
+  module SecretVault
+    class Plugin < Vagrant.plugin("2")
+      name "vault secret provider"
+      description <<-DESC
+      Reads secrets from HashiCorp Vault.
+      DESC
+
+      secret_provider(:vault) do
+        require File.expand_path("../provider", __FILE__)
+        Provider
+      end
+    end
+  end
+end
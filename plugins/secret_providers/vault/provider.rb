@@ -0,0 +1,103 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require "json"
+require "net/http"
+require "openssl"
+require "uri"
+
+require "log4r"
+
+module VagrantPlugins
+  module SecretVault
+    # Reads secrets from HashiCorp Vault. The path of the reference is
+    # the API path of the secret, and the field is the key within the
+    # secret data, which defaults to "value":
+    #
+    #     vault:secret/data/app#token
+    #
+    # Both version 1 and version 2 of the KV secrets engine are supported.
+    # The server and credentials are configured with the same environment
+    # variables as the Vault CLI: VAULT_ADDR, VAULT_TOKEN, VAULT_NAMESPACE,
+    # VAULT_CACERT and VAULT_SKIP_VERIFY. If VAULT_TOKEN is not set, the
+    # token stored by `vault login` is used.
+    class Provider < Vagrant.plugin("2", :secret_provider)
+      DEFAULT_ADDRESS = "https://127.0.0.1:8200".freeze
+
+      DEFAULT_FIELD = "value".freeze
+
+      def initialize
+        @logger = Log4r::Logger.new("vagrant::secret::vault")
+      end
+
+      def resolve(path, field)
+        data = read(path)
+        return if data.nil?
+
+        # Version 2 of the KV secrets engine nests the secret data
+        if data["data"].is_a?(Hash) && data.key?("metadata")
+          data = data["data"]
+        end
+
+        value = data[field || DEFAULT_FIELD]
+        value.is_a?(String) || value.nil? ? value : JSON.dump(value)
+      end
+
+      protected
+
+      # @return [Hash, nil] data of the secret, or nil if the secret does
+      #   not exist
+      def read(path)
+        uri = URI.join("#{address.chomp("/")}/", "v1/#{path.sub(%r{\A/+}, "")}")
+        request = Net::HTTP::Get.new(uri)
+        request["X-Vault-Token"] = token
+        request["X-Vault-Namespace"] = ENV["VAULT_NAMESPACE"] if ENV["VAULT_NAMESPACE"]
+
+        @logger.debug("GET #{uri}")
+        response = Net::HTTP.start(uri.host, uri.port, **http_options(uri)) do |http|
+          http.request(request)
+        end
+
+        return if response.code == "404"
+        if response.code != "200"
+          raise "Vault returned #{response.code} #{response.message} for #{path}"
+        end
+
+        body = JSON.parse(response.body)
+        body["data"]
+      rescue JSON::ParserError
+        raise "Vault returned an invalid response for #{path}"
+      end
+
+      # @return [String]
+      def address
+        value = ENV["VAULT_ADDR"].to_s
+        value.empty? ? DEFAULT_ADDRESS : value
+      end
+
+      # @return [String]
+      def token
+        value = ENV["VAULT_TOKEN"].to_s
+        if value.empty?
+          path = File.expand_path("~/.vault-token")
+          value = File.read(path).strip if File.file?(path)
+        end
+        if value.to_s.empty?
+          raise "No Vault token found. Set VAULT_TOKEN or log in with `vault login`"
+        end
+
+        value
+      end
+
+      # @return [Hash]
+      def http_options(uri)
+        opts = { use_ssl: uri.scheme == "https" }
+        opts[:ca_file] = ENV["VAULT_CACERT"] if ENV["VAULT_CACERT"]
+        if !["", "0", "false"].include?(ENV["VAULT_SKIP_VERIFY"].to_s.downcase)
+          opts[:verify_mode] = OpenSSL::SSL::VERIFY_NONE
+        end
+        opts
+      end
+    end
+  end
+end
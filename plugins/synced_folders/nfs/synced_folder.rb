@@ -21,6 +21,13 @@ module VagrantPlugins
     class SyncedFolder < Vagrant.plugin("2", :synced_folder)
       @@lock = Mutex.new
 
+      # Exports waiting for the lock, keyed by the environment and the
+      # ID of the machine, and the errors of exports which failed while
+      # exported by another machine
+      @@pending_lock = Mutex.new
+      @@pending = {}
+      @@failed = {}
+
       def initialize(*args)
         super
 
@@ -40,6 +47,12 @@ module VagrantPlugins
         raise Vagrant::Errors::NFSNotSupported
       end
 
+      # The owner of the files within NFS folders is mapped by the host
+      # with the `map_uid` and `map_gid` options instead
+      def unsupported_options(machine)
+        [:owner, :group, :mode]
+      end
+
       def prepare(machine, folders, opts)
         # Nothing is necessary to do before VM boot.
       end
@@ -77,24 +90,7 @@ module VagrantPlugins
         end
 
         # Update the exports when there are actually exports [GH-4148]
-        if !export_folders.empty?
-          # Export the folders. We do this with a class-wide lock because
-          # NFS exporting often requires sudo privilege and we don't want
-          # overlapping input requests. [GH-2680]
-          @@lock.synchronize do
-            begin
-              machine.env.lock("nfs-export") do
-                machine.ui.info I18n.t("vagrant.actions.vm.nfs.exporting")
-                machine.env.host.capability(
-                  :nfs_export,
-                  machine.ui, machine.id, machine_ip, export_folders)
-              end
-            rescue Vagrant::Errors::EnvironmentLockedError
-              sleep 1
-              retry
-            end
-          end
-        end
+        export(machine, machine_ip, export_folders) if !export_folders.empty?
 
         # Mount
         machine.ui.info I18n.t("vagrant.actions.vm.nfs.mounting")
@@ -134,6 +130,59 @@ module VagrantPlugins
 
       protected
 
+      # Export the folders. We do this with a class-wide lock because
+      # NFS exporting often requires sudo privilege and we don't want
+      # overlapping input requests. [GH-2680]
+      #
+      # If the host can export the folders of several machines at once,
+      # the exports of the machines which are waiting for the lock, such
+      # as when machines are brought up in parallel, are exported in one
+      # pass so the host only applies the exports once.
+      def export(machine, ips, folders)
+        host = machine.env.host
+        batch = host.capability?(:nfs_export_batch)
+        key = [machine.env.object_id, machine.id]
+        @@pending_lock.synchronize { @@pending[key] = [ips, folders] } if batch
+
+        @@lock.synchronize do
+          exports = {key => [ips, folders]}
+          if batch
+            exports = @@pending_lock.synchronize do
+              mine = @@pending.select { |k, _| k[0] == key[0] }
+              mine.each_key { |k| @@pending.delete(k) }
+              mine
+            end
+          end
+
+          if !exports.empty?
+            begin
+              machine.env.lock("nfs-export") do
+                machine.ui.info I18n.t("vagrant.actions.vm.nfs.exporting")
+                if batch
+                  host.capability(:nfs_export_batch, machine.ui,
+                    exports.map { |(_, id), (i, f)| [id, i, f] })
+                else
+                  host.capability(:nfs_export, machine.ui, machine.id, ips, folders)
+                end
+              end
+            rescue Vagrant::Errors::EnvironmentLockedError
+              sleep 1
+              retry
+            rescue StandardError => e
+              # Other machines of the batch raise the error once they
+              # get the lock
+              @@pending_lock.synchronize do
+                exports.each_key { |k| @@failed[k] = e if k != key }
+              end
+              raise
+            end
+          end
+
+          error = @@pending_lock.synchronize { @@failed.delete(key) }
+          raise error if error
+        end
+      end
+
       def prepare_folder(machine, opts)
         opts[:map_uid] = prepare_permission(machine, :uid, opts)
         opts[:map_gid] = prepare_permission(machine, :gid, opts)
@@ -104,6 +104,7 @@ module VagrantPlugins
             if ssh_info
               machine.ui.info(I18n.t("vagrant.rsync_auto_initial"))
               folders.each do |id, folder_opts|
+                RsyncHelper.rsync_back(machine, ssh_info, folder_opts) if folder_opts[:auto_back]
                 RsyncHelper.rsync_single(machine, ssh_info, folder_opts)
               end
             end
@@ -120,6 +121,7 @@ module VagrantPlugins
                 id: id,
                 machine: machine,
                 opts:    folder_opts,
+                auto_back: !!folder_opts[:auto_back],
               }
 
               if folder_opts[:exclude]
@@ -218,6 +220,11 @@ module VagrantPlugins
               ssh_info = opts[:machine].ssh_info
               begin
                 start = Time.now
+                # Pull changes made in the guest first so they are
+                # not removed when syncing to the guest
+                if opts[:auto_back]
+                  RsyncHelper.rsync_back(opts[:machine], ssh_info, opts[:opts])
+                end
                 RsyncHelper.rsync_single(opts[:machine], ssh_info, opts[:opts])
                 finish = Time.now
                 @logger.info("Time spent in rsync: #{finish-start} (in seconds)")
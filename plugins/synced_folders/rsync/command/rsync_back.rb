@@ -0,0 +1,115 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require 'optparse'
+
+require "vagrant/action/builtin/mixin_synced_folders"
+
+require_relative "../helper"
+
+module VagrantPlugins
+  module SyncedFolderRSync
+    module Command
+      class RsyncBack < Vagrant.plugin("2", :command)
+        include Vagrant::Action::Builtin::MixinSyncedFolders
+
+        def self.synopsis
+          "syncs rsync synced folders from remote machine"
+        end
+
+        def execute
+          options = {}
+          opts = OptionParser.new do |o|
+            o.banner = "Usage: vagrant rsync-back [options] [vm-name]"
+            o.separator ""
+            o.separator "This command syncs any synced folders with type 'rsync' from"
+            o.separator "the guest back to the host. Files are never deleted from the host."
+            o.separator ""
+            o.separator "Options:"
+            o.separator ""
+            o.on("--guest-path PATH", String, "Only sync the folder at this guest path") do |path|
+              options[:guestpath] = path
+            end
+            o.on("--[no-]update", "Skip files that are newer on the host (default: true)") do |update|
+              options[:update] = update
+            end
+          end
+
+          # Parse the options and return if we don't have any target.
+          argv = parse_options(opts)
+          return if !argv
+
+          # Go through each machine and perform the rsync
+          error = false
+          with_target_vms(argv) do |machine|
+            if machine.provider.capability?(:proxy_machine)
+              proxy = machine.provider.capability(:proxy_machine)
+              if proxy
+                machine.ui.warn(I18n.t(
+                  "vagrant.rsync_proxy_machine",
+                  name: machine.name.to_s,
+                  provider: machine.provider_name.to_s))
+
+                machine = proxy
+              end
+            end
+
+            if !machine.communicate.ready?
+              machine.ui.error(I18n.t("vagrant.rsync_communicator_not_ready"))
+              error = true
+              next
+            end
+
+            # Determine the rsync synced folders for this machine
+            folders = synced_folders(machine, cached: true)[:rsync] || {}
+            if options[:guestpath]
+              folders = select_folder(machine, folders, options[:guestpath])
+            end
+            next if folders.empty?
+
+            # Get the SSH info for this machine so we can access it
+            ssh_info = machine.ssh_info
+
+            # Sync them!
+            folders.each do |id, folder_opts|
+              if options.key?(:update)
+                folder_opts = folder_opts.merge(update: options[:update])
+              end
+              RsyncHelper.rsync_back(machine, ssh_info, folder_opts)
+            end
+          end
+
+          return error ? 1 : 0
+        end
+
+        protected
+
+        # Find the rsync synced folder for the guest path. An error is
+        # raised if the folder is disabled or is not an rsync folder.
+        #
+        # @param [Vagrant::Machine] machine
+        # @param [Hash] folders rsync synced folders
+        # @param [String] guestpath
+        # @return [Hash] folders matching the guest path
+        def select_folder(machine, folders, guestpath)
+          configured = machine.config.vm.synced_folders.values.find do |data|
+            data[:guestpath] == guestpath
+          end
+          if configured && configured[:disabled]
+            raise Vagrant::Errors::RSyncBackFolderDisabled,
+              guestpath: guestpath
+          end
+
+          folders = folders.select { |_, data| data[:guestpath] == guestpath }
+          if folders.empty?
+            raise Vagrant::Errors::RSyncBackFolderNotFound,
+              guestpath: guestpath,
+              name: machine.name.to_s
+          end
+
+          folders
+        end
+      end
+    end
+  end
+end
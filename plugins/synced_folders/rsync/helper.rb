@@ -6,9 +6,12 @@ require "ipaddr"
 require "shellwords"
 require "tmpdir"
 
+require "vagrant/util/busy"
 require "vagrant/util/platform"
 require "vagrant/util/subprocess"
 
+require_relative "progress"
+
 module VagrantPlugins
   module SyncedFolderRSync
     # This is a helper that abstracts out the functionality of rsyncing
@@ -17,6 +20,10 @@ module VagrantPlugins
       # rsync version requirement to support chown argument
       RSYNC_CHOWN_REQUIREMENT = Gem::Requirement.new(">= 3.1.0").freeze
 
+      # rsync version requirement to support reporting the progress of the
+      # whole transfer
+      RSYNC_PROGRESS_REQUIREMENT = Gem::Requirement.new(">= 3.1.0").freeze
+
       # This converts an rsync exclude pattern to a regular expression
       # we can send to Listen.
       #
@@ -44,7 +51,35 @@ module VagrantPlugins
         Regexp.new(exclude)
       end
 
-      def self.rsync_single(machine, ssh_info, opts)
+      # Rsync the folder from the guest back to the host. The same
+      # options used to sync the folder to the guest are used, but files
+      # are never deleted from the host and, unless the `update` option
+      # is false, files which are newer on the host are not replaced.
+      #
+      # @param [Vagrant::Machine] machine The remote machine
+      # @param [Hash] ssh_info SSH information for the machine
+      # @param [Hash] opts Synced folder options
+      def self.rsync_back(machine, ssh_info, opts)
+        if opts[:disabled]
+          raise Vagrant::Errors::RSyncBackFolderDisabled,
+            guestpath: opts[:guestpath]
+        end
+
+        # Ensure the host directory exists to receive the files
+        FileUtils.mkdir_p(File.expand_path(opts[:hostpath], machine.env.root_path))
+
+        rsync_single(machine, ssh_info, opts, reverse: true)
+      end
+
+      # Rsync the folder to the guest.
+      #
+      # @param [Vagrant::Machine] machine The remote machine
+      # @param [Hash] ssh_info SSH information for the machine
+      # @param [Hash] opts Synced folder options
+      # @param [Boolean] reverse Rsync the folder from the guest to the host
+      # @param [Boolean] progress Report the progress of the transfer, unless
+      #   the `progress` option of the folder is false
+      def self.rsync_single(machine, ssh_info, opts, reverse: false, progress: false)
         # Folder info
         guestpath = opts[:guestpath]
         hostpath  = opts[:hostpath]
@@ -140,6 +175,27 @@ module VagrantPlugins
         args = Array(opts[:args]).dup if opts[:args]
         args ||= ["--verbose", "--archive", "--delete", "-z", "--copy-links"]
 
+        if reverse
+          # Never delete files from the host when syncing back
+          args.reject! { |arg| arg.start_with?("--delete") }
+
+          # Don't replace files that are newer on the host
+          if opts.fetch(:update, true) && !args.include?("--update") && !args.include?("-u")
+            args << "--update"
+          end
+        end
+
+        # Limit the bandwidth used by rsync
+        if opts[:bwlimit] && !args.any? { |arg| arg.start_with?("--bwlimit") }
+          args << "--bwlimit=#{opts[:bwlimit]}"
+        end
+
+        # Report the progress of the whole transfer. The output isn't
+        # parsed when it is shown with the verbose option.
+        progress = progress && opts.fetch(:progress, true) &&
+          !opts.include?(:verbose) && rsync_progress_support?
+        args << "--info=progress2" if progress
+
         # On Windows, we have to set a default chmod flag to avoid permission issues
         if Vagrant::Util::Platform.windows? && !args.any? { |arg| arg.start_with?("--chmod=") }
           # Ensures that all non-masked bits get enabled
@@ -150,7 +206,14 @@ module VagrantPlugins
           args << "--no-perms" if args.include?("--archive") || args.include?("-a")
         end
 
-        if opts[:rsync_ownership] && rsync_chown_support?(machine)
+        # The mode of the folder and whether it is read-only are set by the
+        # permissions of the files copied to the guest
+        if !reverse
+          args << "--chmod=D#{opts[:mode]},F#{opts[:mode]}" if opts[:mode]
+          args << "--chmod=ugo-w" if opts[:readonly]
+        end
+
+        if !reverse && opts[:rsync_ownership] && rsync_chown_support?(machine)
           # Allow rsync to map ownership
           args << "--chown=#{opts[:owner]}:#{opts[:group]}"
           # Notify rsync post capability not to chown
@@ -182,21 +245,31 @@ module VagrantPlugins
         end
 
         # Build up the actual command to execute
+        source = hostpath
+        destination = "#{username}@#{host}:#{guestpath}"
+        if reverse
+          # The guest path must end with a "/" to avoid creating a
+          # nested directory on the host
+          source = destination
+          source += "/" if !source.end_with?("/")
+          destination = hostpath
+        end
+
         command = [
           "rsync",
           args,
           "-e", rsh.flatten.join(" "),
           excludes.map { |e| ["--exclude", e] },
-          hostpath,
-          "#{username}@#{host}:#{guestpath}",
+          source,
+          destination,
         ].flatten
 
         # The working directory should be the root path
         command_opts = {}
         command_opts[:workdir] = machine.env.root_path.to_s
 
-        machine.ui.info(I18n.t(
-          "vagrant.rsync_folder", guestpath: guestpath, hostpath: hostpath))
+        message = reverse ? "vagrant.rsync_back_folder" : "vagrant.rsync_folder"
+        machine.ui.info(I18n.t(message, guestpath: guestpath, hostpath: hostpath))
         if excludes.length > 1
           machine.ui.info(I18n.t(
             "vagrant.rsync_folder_excludes", excludes: excludes.inspect))
@@ -206,18 +279,26 @@ module VagrantPlugins
         end
 
         # If we have tasks to do before rsyncing, do those.
-        if machine.guest.capability?(:rsync_pre)
+        if !reverse && machine.guest.capability?(:rsync_pre)
           machine.guest.capability(:rsync_pre, opts)
         end
 
         if opts.include?(:verbose)
           command_opts[:notify] = [:stdout, :stderr]
-          r = Vagrant::Util::Subprocess.execute(*(command + [command_opts])) {
+          r = execute_rsync(command, command_opts) {
             |io_name,data| data.each_line { |line|
               machine.ui.info("rsync[#{io_name}] -> #{line}") }
           }
+        elsif progress
+          command_opts[:notify] = [:stdout]
+          reporter = RsyncProgress.new(machine.ui)
+          begin
+            r = execute_rsync(command, command_opts) { |_, data| reporter.update(data) }
+          ensure
+            reporter.finish
+          end
         else
-          r = Vagrant::Util::Subprocess.execute(*(command + [command_opts]))
+          r = execute_rsync(command, command_opts)
         end
 
         if r.exit_code != 0
@@ -229,7 +310,7 @@ module VagrantPlugins
         end
 
         # If we have tasks to do after rsyncing, do those.
-        if machine.guest.capability?(:rsync_post)
+        if !reverse && machine.guest.capability?(:rsync_post)
           begin
             machine.guest.capability(:rsync_post, opts)
           rescue Vagrant::Errors::VagrantError => err
@@ -243,6 +324,37 @@ module VagrantPlugins
         FileUtils.remove_entry_secure(controlpath, true) if controlpath
       end
 
+      # Run rsync. When Vagrant is interrupted the interrupt is raised
+      # within the thread running rsync, which stops the rsync process
+      # instead of waiting for the transfer to complete.
+      #
+      # @return [Vagrant::Util::Subprocess::Result]
+      def self.execute_rsync(command, command_opts, &block)
+        thread = Thread.current
+        running = true
+        int_callback = lambda do
+          # Raising within another thread can't happen in a trap context
+          Thread.new { thread.raise(Vagrant::Errors::VagrantInterrupt) if running }
+        end
+
+        Vagrant::Util::Busy.busy(int_callback) do
+          begin
+            Vagrant::Util::Subprocess.execute(*(command + [command_opts]), &block)
+          ensure
+            running = false
+          end
+        end
+      end
+
+      # Check if the local rsync version supports reporting the progress
+      # of the whole transfer
+      #
+      # @return [Boolean]
+      def self.rsync_progress_support?
+        version = local_rsync_version
+        !!version && RSYNC_PROGRESS_REQUIREMENT.satisfied_by?(Gem::Version.new(version))
+      end
+
       # Check if rsync versions support using chown option
       #
       # @param [Vagrant::Machine] machine The remote machine
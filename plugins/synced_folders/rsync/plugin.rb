@@ -22,6 +22,11 @@ module VagrantPlugins
         Command::RsyncAuto
       end
 
+      command("rsync-back", primary: false) do
+        require_relative "command/rsync_back"
+        Command::RsyncBack
+      end
+
       synced_folder("rsync", 5) do
         require_relative "synced_folder"
         SyncedFolder
@@ -0,0 +1,62 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+module VagrantPlugins
+  module SyncedFolderRSync
+    # This reports the progress of rsync to the UI. It parses the output
+    # of rsync when run with `--info=progress2`, which reports the progress
+    # of the whole transfer instead of each file:
+    #
+    #     1,238,099  42%   13.47MB/s    0:00:05 (xfr#13, to-chk=52/80)
+    #
+    # The columns are the bytes transferred, the percent complete, the
+    # rate and the estimated time remaining.
+    class RsyncProgress
+      PROGRESS_REGEXP = /^\s*([\d,.]+[KMGT]?)\s+(\d+)%\s+(\S+\/s)\s+(\d+:\d{2}:\d{2})/.freeze
+
+      # @param [Vagrant::UI::Interface] ui UI to report the progress to
+      def initialize(ui)
+        @ui = ui
+        @buffer = ""
+        @reported = false
+      end
+
+      # Parse output of rsync. Lines which are not progress reports, such
+      # as the names of files when run with `--verbose`, are ignored.
+      #
+      # @param [String] data Output of rsync
+      def update(data)
+        @buffer << data
+
+        # Progress reports are ended with a carriage return and are only
+        # ended with a newline once the transfer completes
+        while (index = @buffer.index(/[\r\n]/))
+          line = @buffer.slice!(0, index + 1)
+          report(line)
+        end
+      end
+
+      # Remove the progress from the UI once the transfer is complete
+      def finish
+        @ui.clear_line if @reported
+        @reported = false
+      end
+
+      protected
+
+      def report(line)
+        match = PROGRESS_REGEXP.match(line)
+        return if !match
+
+        output = I18n.t("vagrant.rsync_progress",
+          transferred: match[1], percent: match[2],
+          rate: match[3], remaining: match[4])
+        @ui.rewriting do |ui|
+          ui.clear_line
+          ui.detail(output, new_line: false)
+        end
+        @reported = true
+      end
+    end
+  end
+end
@@ -26,6 +26,15 @@ module VagrantPlugins
         raise Vagrant::Errors::RSyncNotFound
       end
 
+      def hot_mount?(machine)
+        true
+      end
+
+      # The folders are copied to the guest instead of being mounted
+      def unsupported_options(machine)
+        [:mount_options]
+      end
+
       def prepare(machine, folders, opts)
         # Nothing is necessary to do before VM boot.
       end
@@ -48,7 +57,7 @@ module VagrantPlugins
         end
 
         folders.each do |id, folder_opts|
-          RsyncHelper.rsync_single(machine, ssh_info, folder_opts)
+          RsyncHelper.rsync_single(machine, ssh_info, folder_opts, progress: true)
         end
       end
 
@@ -34,6 +34,11 @@ module VagrantPlugins
           mnt_opts << "credentials=/etc/smb_creds_#{options[:smb_id]}"
           mnt_opts << "uid=#{mount_uid}"
           mnt_opts << "gid=#{mount_gid}"
+          if options[:mode]
+            mnt_opts << "dir_mode=#{options[:mode]}"
+            mnt_opts << "file_mode=#{options[:mode]}"
+          end
+          mnt_opts << "ro" if options[:readonly] && !mount_options.include?("ro")
           if !ENV['VAGRANT_DISABLE_SMBMFSYMLINKS']
             mnt_opts << "mfsymlinks"
           end
@@ -103,6 +103,50 @@ module VagrantPlugins
           EOH
       end
 
+      # Verify the folder mounted at the guest path is read-only. The
+      # options of the mount are read from /proc/mounts since some file
+      # systems ignore the "ro" option instead of failing to mount.
+      #
+      # @param [Machine] machine
+      # @param [String] guest_path Path of the mount
+      def verify_readonly_mount(machine, guest_path)
+        mounts = ""
+        machine.communicate.execute("cat /proc/mounts", error_check: false) do |type, data|
+          mounts << data if type == :stdout
+        end
+
+        path = normalize_mount_path(guest_path)
+        # The last entry takes precedence if folders are mounted over
+        # each other
+        options = nil
+        mounts.each_line do |line|
+          _, mount_path, _, mount_options = line.split(" ")
+          next if !mount_options
+
+          options = mount_options if normalize_mount_path(decode_mount_path(mount_path)) == path
+        end
+        return if options && options.split(",").include?("ro")
+
+        raise Vagrant::Errors::SyncedFolderReadonlyFailed,
+          guestpath: guest_path
+      end
+
+      # Decode the octal escapes of spaces and other special characters
+      # within paths of /proc/mounts
+      #
+      # @param [String] path
+      # @return [String]
+      def decode_mount_path(path)
+        path.b.gsub(/\\([0-7]{3})/n) { $1.to_i(8).chr }.force_encoding(Encoding::UTF_8)
+      end
+
+      # @param [String] path
+      # @return [String] path without trailing slashes
+      def normalize_mount_path(path)
+        path = path.to_s.sub(/\/+\z/, "")
+        path.empty? ? "/" : path
+      end
+
       def merge_mount_options(base, overrides)
         base = base.join(",").split(",")
         overrides = overrides.join(",").split(",")
@@ -0,0 +1,38 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require "log4r"
+
+module VagrantPlugins
+  module SyncedFolderVirtiofs
+    # This unmounts the virtiofs folders before the machine is halted
+    # gracefully, so the guest doesn't hang on the folders while it is
+    # shutting down.
+    class ActionUnmount
+      include Vagrant::Action::Builtin::MixinSyncedFolders
+
+      def initialize(app, env)
+        @app    = app
+        @logger = Log4r::Logger.new("vagrant::synced_folders::virtiofs")
+      end
+
+      def call(env)
+        machine = env[:machine]
+        folders = synced_folders(machine, cached: true)[:virtiofs] || {}
+        folders = folders.select { |_, data| data[:guestpath] }
+
+        if !folders.empty? && !env[:force_halt] && machine.communicate.ready? &&
+            machine.guest.capability?(:unmount_virtiofs_shared_folder)
+          env[:ui].info(I18n.t("vagrant_sf_virtiofs.unmounting"))
+          folders.each do |id, data|
+            @logger.info("Unmounting virtiofs folder #{id}: #{data[:guestpath]}")
+            machine.guest.capability(:unmount_virtiofs_shared_folder,
+              data[:guestpath], data)
+          end
+        end
+
+        @app.call(env)
+      end
+    end
+  end
+end
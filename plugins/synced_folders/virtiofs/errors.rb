@@ -0,0 +1,25 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+module VagrantPlugins
+  module SyncedFolderVirtiofs
+    module Errors
+      # A convenient superclass for all our errors.
+      class VirtiofsError < Vagrant::Errors::VagrantError
+        error_namespace("vagrant_sf_virtiofs.errors")
+      end
+
+      class GuestUnsupported < VirtiofsError
+        error_key(:guest_unsupported)
+      end
+
+      class OptionInvalid < VirtiofsError
+        error_key(:option_invalid)
+      end
+
+      class ProviderUnsupported < VirtiofsError
+        error_key(:provider_unsupported)
+      end
+    end
+  end
+end
@@ -0,0 +1,58 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require "vagrant"
+
+module VagrantPlugins
+  Vagrant::Util::Experimental.guard_with(:virtiofs) do
+    module SyncedFolderVirtiofs
+      autoload :Errors, File.expand_path("../errors", __FILE__)
+
+      # This plugin implements virtiofs synced folders. It requires the
+      # provider to attach the virtiofs devices to the machine, which is
+      # done with the following provider capabilities:
+      #
+      #   * `virtiofs_attach(machine, folders)` - Attach a virtiofs device
+      #     for each folder before the machine boots. The tag of the device
+      #     is the `:virtiofs_tag` of the folder, and the `:virtiofs_cache`
+      #     and `:virtiofs_dax` options configure the device.
+      #   * `virtiofs_detach(machine, folders)` - Optional. Detach the
+      #     virtiofs devices of the folders.
+      #
+      # The guest mounts the folders with the `mount_virtiofs_shared_folder`
+      # and `unmount_virtiofs_shared_folder` guest capabilities.
+      class Plugin < Vagrant.plugin("2")
+        name "virtiofs synced folders"
+        description <<-EOF
+        The virtiofs synced folders plugin enables you to share folders with
+        machines running on hypervisors which support virtiofs.
+        EOF
+
+        # This is preferred over the default synced folders of the providers,
+        # and is only usable if both the provider and the guest support it.
+        synced_folder("virtiofs", 15) do
+          require_relative "synced_folder"
+          init!
+          SyncedFolder
+        end
+
+        action_hook("virtiofs_unmount", :machine_action_halt) do |hook|
+          require_relative "action_unmount"
+          hook.before(
+            Vagrant::Action::Builtin::GracefulHalt,
+            ActionUnmount)
+        end
+
+        protected
+
+        def self.init!
+          return if defined?(@_init)
+          I18n.load_path << File.expand_path(
+            "templates/locales/synced_folder_virtiofs.yml", Vagrant.source_root)
+          I18n.reload!
+          @_init = true
+        end
+      end
+    end
+  end
+end
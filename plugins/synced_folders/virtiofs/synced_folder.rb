@@ -0,0 +1,159 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require "digest"
+
+require "log4r"
+
+require_relative "errors"
+
+module VagrantPlugins
+  module SyncedFolderVirtiofs
+    class SyncedFolder < Vagrant.plugin("2", :synced_folder)
+      # Cache modes of the virtiofs device
+      CACHE_MODES = ["always", "auto", "metadata", "never"].freeze
+
+      # DAX modes of the mount. `true` enables DAX with the default mode
+      # of the guest, and `false` disables it.
+      DAX_MODES = [true, false, "always", "inode", "never"].freeze
+
+      # Maximum length of a virtiofs tag
+      TAG_MAX_LENGTH = 36
+
+      def initialize(*args)
+        super
+
+        @logger = Log4r::Logger.new("vagrant::synced_folders::virtiofs")
+      end
+
+      def usable?(machine, raise_error=false)
+        if !machine.provider.capability?(:virtiofs_attach)
+          return false if !raise_error
+          raise Errors::ProviderUnsupported,
+            provider: machine.provider_name.to_s
+        end
+
+        return true if guest_capability?(machine, :mount_virtiofs_shared_folder)
+        return false if !raise_error
+        raise Errors::GuestUnsupported
+      end
+
+      # The owner and mode of the files are the same as on the host
+      def unsupported_options(machine)
+        [:owner, :group, :mode]
+      end
+
+      def prepare(machine, folders, _opts)
+        folders.each do |id, data|
+          validate_options(id, data)
+          data[:virtiofs_tag] ||= tag(id)
+        end
+
+        machine.ui.output(I18n.t("vagrant_sf_virtiofs.preparing"))
+        machine.provider.capability(:virtiofs_attach, folders)
+      end
+
+      def enable(machine, folders, _opts)
+        # Make sure that this machine knows this dance
+        if !machine.guest.capability?(:mount_virtiofs_shared_folder)
+          raise Vagrant::Errors::GuestCapabilityNotFound,
+            cap: "mount_virtiofs_shared_folder",
+            guest: machine.guest.name.to_s
+        end
+
+        # short guestpaths first, so we don't step on ourselves
+        folders = folders.sort_by do |_, data|
+          data[:guestpath] ? data[:guestpath].length : 10000
+        end
+
+        machine.ui.output(I18n.t("vagrant_sf_virtiofs.mounting"))
+        folders.each do |id, data|
+          if !data[:guestpath]
+            machine.ui.detail(I18n.t("vagrant_sf_virtiofs.nomount_entry",
+              hostpath: data[:hostpath]))
+            next
+          end
+
+          machine.ui.detail(I18n.t("vagrant_sf_virtiofs.mounting_entry",
+            guestpath: data[:guestpath],
+            hostpath: data[:hostpath]))
+          machine.guest.capability(:mount_virtiofs_shared_folder,
+            data[:virtiofs_tag] || tag(id), data[:guestpath], data)
+        end
+      end
+
+      def disable(machine, folders, _opts)
+        if machine.guest.capability?(:unmount_virtiofs_shared_folder)
+          folders.each do |_, data|
+            next if !data[:guestpath]
+
+            machine.guest.capability(:unmount_virtiofs_shared_folder,
+              data[:guestpath], data)
+          end
+        end
+
+        if machine.provider.capability?(:virtiofs_detach)
+          machine.provider.capability(:virtiofs_detach, folders)
+        end
+      end
+
+      protected
+
+      # Check if the guest supports a capability. Before the machine can
+      # be reached, the guest set with `config.vm.guest` is checked since
+      # the guest can not be detected yet.
+      #
+      # @return [Boolean]
+      def guest_capability?(machine, cap)
+        return machine.guest.capability?(cap) if machine.communicate.ready?
+
+        name = machine.config.vm.guest
+        manager = Vagrant.plugin("2").manager
+        guests = manager.guests
+        capabilities = manager.guest_capabilities
+        while name
+          name = name.to_sym
+          return true if capabilities[name].key?(cap)
+          return false if !guests.key?(name)
+
+          _, name = guests.get(name)
+        end
+
+        false
+      rescue Vagrant::Errors::MachineGuestNotReady
+        false
+      end
+
+      # The tag of the virtiofs device of a folder, which must be unique
+      # for the machine. The tag is derived from a digest of the ID since
+      # IDs are paths which may be longer than a tag.
+      #
+      # @param [String] id ID of the folder
+      # @return [String]
+      def tag(id)
+        prefix = "vagrant-"
+        prefix + Digest::SHA256.hexdigest(id.to_s)[0, TAG_MAX_LENGTH - prefix.length]
+      end
+
+      def validate_options(id, data)
+        if data.key?(:virtiofs_cache) && !CACHE_MODES.include?(data[:virtiofs_cache].to_s)
+          raise Errors::OptionInvalid,
+            id: id,
+            option: "virtiofs_cache",
+            value: data[:virtiofs_cache].to_s,
+            valid: CACHE_MODES.join(", ")
+        end
+
+        dax = data[:virtiofs_dax]
+        dax = dax.to_s if dax.is_a?(Symbol)
+        if data.key?(:virtiofs_dax) && !DAX_MODES.include?(dax)
+          raise Errors::OptionInvalid,
+            id: id,
+            option: "virtiofs_dax",
+            value: data[:virtiofs_dax].to_s,
+            valid: DAX_MODES.map(&:to_s).join(", ")
+        end
+      end
+    end
+  end
+end
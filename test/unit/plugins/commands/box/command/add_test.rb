@@ -79,4 +79,95 @@ describe VagrantPlugins::CommandBox::Command::Add do
       subject.execute
     end
   end
+
+  context "with limit rate flag" do
+    let(:argv) { ["foo", "--limit-rate", "2m"] }
+
+    it "executes the runner with the rate limit" do
+      expect(action_runner).to receive(:run) do |_, opts|
+        expect(opts[:box_download_limit_rate]).to eq("2m")
+      end
+
+      subject.execute
+    end
+
+    context "with an invalid rate" do
+      let(:argv) { ["foo", "--limit-rate", "fast"] }
+
+      it "shows help" do
+        expect { subject.execute }.
+          to raise_error(Vagrant::Errors::CLIInvalidUsage)
+      end
+    end
+  end
+
+  context "with force many flag" do
+    let(:argv) { ["--force-many", "foo", "bar", "baz"] }
+
+    it "adds each box" do
+      urls = []
+      expect(action_runner).to receive(:run).exactly(3).times do |_, opts|
+        expect(opts[:box_name]).to be_nil
+        urls << opts[:box_url]
+      end
+
+      expect(subject.execute).to eq(0)
+      expect(urls).to contain_exactly("foo", "bar", "baz")
+    end
+
+    it "splits the rate limit between the downloads" do
+      argv.concat(["--limit-rate", "3k", "--parallel", "3"])
+      expect(action_runner).to receive(:run).exactly(3).times do |_, opts|
+        expect(opts[:box_download_limit_rate]).to eq(1024)
+      end
+
+      subject.execute
+    end
+
+    it "adds the other boxes when one fails" do
+      urls = []
+      expect(action_runner).to receive(:run).exactly(3).times do |_, opts|
+        urls << opts[:box_url]
+        raise Vagrant::Errors::BoxNotFound, name: "bar" if opts[:box_url] == "bar"
+      end
+
+      expect(subject.execute).to eq(1)
+      expect(urls).to contain_exactly("foo", "bar", "baz")
+    end
+
+    it "shows help with a box name" do
+      argv.concat(["--name", "box"])
+
+      expect { subject.execute }.
+        to raise_error(Vagrant::Errors::CLIInvalidUsage)
+    end
+
+    it "shows help with an invalid parallel count" do
+      argv.concat(["--parallel", "0"])
+
+      expect { subject.execute }.
+        to raise_error(Vagrant::Errors::CLIInvalidUsage)
+    end
+  end
+
+  context "with from file flag" do
+    let(:manifest) { iso_env.cwd.join("boxes.txt") }
+    let(:argv) { ["--from-file", manifest.to_s] }
+
+    it "adds the boxes listed in the file" do
+      manifest.write("# boxes\nfoo ~> 1.0\n\nbar # latest\n")
+      boxes = {}
+      expect(action_runner).to receive(:run).twice do |_, opts|
+        boxes[opts[:box_url]] = opts[:box_version]
+      end
+
+      expect(subject.execute).to eq(0)
+      expect(boxes).to eq("foo" => "~> 1.0", "bar" => nil)
+    end
+
+    it "raises an error when the file does not exist" do
+      expect { subject.execute }.
+        to raise_error(Vagrant::Errors::BoxAddManifestNotFound)
+    end
+  end
 end
@@ -189,5 +189,110 @@ describe VagrantPlugins::CommandBox::Command::Prune do
         expect(output).to include("Removing box 'foobox' (v1.0) with provider 'virtualbox'...")
       end
     end
+
+    context "with --keep" do
+      let(:argv) { ["--keep", "2"] }
+
+      it "keeps the most recent versions" do
+        iso_env.box3("foobox", "1.0", :virtualbox)
+        iso_env.box3("foobox", "1.1", :virtualbox)
+        iso_env.box3("foobox", "1.2", :virtualbox)
+
+        output = ""
+        allow(iso_vagrant_env.ui).to receive(:info) do |data|
+          output << "\n" + data
+        end
+
+        expect(subject.execute).to eq(0)
+        expect(iso_vagrant_env.boxes.all.map { |b| b[1] }).to eq(["1.1", "1.2"])
+        expect(output).to include("foobox (virtualbox, 1.2)")
+        expect(output).to include("foobox (virtualbox, 1.1)")
+        expect(output).to include("Removing box 'foobox' (v1.0) with provider 'virtualbox'...")
+        expect(output).to include("Reclaimed")
+      end
+
+      context "with an invalid count" do
+        let(:argv) { ["--keep", "0"] }
+
+        it "shows help" do
+          iso_env.box3("foobox", "1.0", :virtualbox)
+          expect { subject.execute }.
+            to raise_error(Vagrant::Errors::CLIInvalidUsage)
+        end
+      end
+    end
+
+    context "with --older-than" do
+      let(:argv) { ["--older-than", "30d"] }
+
+      it "only removes versions older than the duration" do
+        old_dir = iso_env.box3("foobox", "1.0", :virtualbox)
+        iso_env.box3("foobox", "1.1", :virtualbox)
+        iso_env.box3("foobox", "1.2", :virtualbox)
+        old_time = Time.now - (60 * 60 * 24 * 31)
+        File.utime(old_time, old_time, old_dir.to_s)
+
+        output = ""
+        allow(iso_vagrant_env.ui).to receive(:info) do |data|
+          output << "\n" + data
+        end
+
+        expect(subject.execute).to eq(0)
+        expect(iso_vagrant_env.boxes.all.map { |b| b[1] }).to eq(["1.1", "1.2"])
+        expect(output).to include("Keeping foobox virtualbox 1.1")
+      end
+
+      context "with an invalid duration" do
+        let(:argv) { ["--older-than", "soon"] }
+
+        it "shows help" do
+          expect { subject.execute }.
+            to raise_error(Vagrant::Errors::CLIInvalidUsage)
+        end
+      end
+    end
+
+    context "with a box in use" do
+      before do
+        iso_env.box3("foobox", "1.0", :virtualbox)
+        iso_env.box3("foobox", "1.1", :virtualbox)
+        iso_vagrant_env.machine_index.set(new_entry("foo", "foobox", "virtualbox", "1.0"))
+        allow_any_instance_of(entry_klass).to receive(:valid?).and_return(true)
+      end
+
+      it "does not remove the box" do
+        output = ""
+        allow(iso_vagrant_env.ui).to receive(:info) do |data|
+          output << "\n" + data
+        end
+
+        expect(subject.execute).to eq(0)
+        expect(iso_vagrant_env.boxes.all.count).to eq(2)
+        expect(output).to include("Keeping foobox virtualbox 1.0 (in use by foo)")
+      end
+
+      context "with --force" do
+        let(:argv) { ["--force"] }
+
+        it "does not remove the box" do
+          expect(subject.execute).to eq(0)
+          expect(iso_vagrant_env.boxes.all.count).to eq(2)
+        end
+      end
+    end
+
+    context "with --dry-run and reclaimable boxes" do
+      let(:argv) { ["--dry-run"] }
+
+      it "reports the space which would be reclaimed" do
+        iso_env.box3("foobox", "1.0", :virtualbox)
+        iso_env.box3("foobox", "1.1", :virtualbox)
+
+        allow(iso_vagrant_env.ui).to receive(:info)
+        expect(iso_vagrant_env.ui).to receive(:info).with(/Would reclaim/)
+        expect(subject.execute).to eq(0)
+        expect(iso_vagrant_env.boxes.all.count).to eq(2)
+      end
+    end
   end
 end
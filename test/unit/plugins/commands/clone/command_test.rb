@@ -0,0 +1,129 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require File.expand_path("../../../../base", __FILE__)
+require Vagrant.source_root.join("plugins/commands/clone/command")
+
+describe VagrantPlugins::CommandClone::Command do
+  include_context "unit"
+
+  let(:argv) { ["default", "web2"] }
+  let(:vagrantfile_content) { "" }
+  let(:iso_env) do
+    env = isolated_environment
+    env.vagrantfile(vagrantfile_content)
+    env.create_vagrant_env
+  end
+  let(:state) { double("state", id: :poweroff) }
+  let(:provider) { double("provider") }
+  let(:source) { iso_env.machine(:default, :dummy) }
+
+  subject { described_class.new(argv, iso_env) }
+
+  before do
+    allow(subject).to receive(:with_target_vms) { |&block| block.call source }
+    allow(source).to receive(:state).and_return(state)
+    allow(source).to receive(:provider).and_return(provider)
+    allow(provider).to receive(:capability?).with(:clone_machine).and_return(true)
+    allow(provider).to receive(:capability).
+      with(:clone_machine, anything, anything).and_return("CLONE_ID")
+  end
+
+  it "clones the machine" do
+    expect(provider).to receive(:capability).
+      with(:clone_machine, kind_of(Vagrant::Machine), false).and_return("CLONE_ID")
+
+    expect(subject.execute).to eq(0)
+    expect(iso_env.clones).to eq(web2: :default)
+    expect(iso_env.machine(:web2, :dummy).id).to eq("CLONE_ID")
+  end
+
+  it "registers the clone in the machine index" do
+    subject.execute
+
+    machine = iso_env.machine(:web2, :dummy)
+    expect(machine.index_uuid).not_to be_nil
+    expect(machine.index_uuid).not_to eq(source.index_uuid)
+  end
+
+  it "copies the private key of the source" do
+    source.data_dir.join("private_key").write("KEY")
+
+    subject.execute
+    expect(iso_env.machine(:web2, :dummy).data_dir.join("private_key").read).to eq("KEY")
+  end
+
+  it "marks the clone as provisioned if the source is provisioned" do
+    source.data_dir.join("action_provision").write("1.5:SOURCE_ID")
+
+    subject.execute
+    expect(iso_env.machine(:web2, :dummy).data_dir.join("action_provision").read).
+      to eq("1.5:CLONE_ID")
+  end
+
+  it "marks the clone so its machine ID is regenerated" do
+    subject.execute
+    expect(iso_env.machine(:web2, :dummy).data_dir.join("action_clone")).to be_file
+  end
+
+  context "with --linked" do
+    let(:argv) { ["default", "web2", "--linked"] }
+
+    it "creates a linked clone" do
+      expect(provider).to receive(:capability).
+        with(:clone_machine, anything, true).and_return("CLONE_ID")
+
+      subject.execute
+    end
+  end
+
+  context "when cloning fails" do
+    before do
+      allow(provider).to receive(:capability).
+        with(:clone_machine, anything, anything).and_raise(Vagrant::Errors::VagrantError)
+    end
+
+    it "removes the clone" do
+      expect { subject.execute }.to raise_error(Vagrant::Errors::VagrantError)
+      expect(iso_env.clones).to be_empty
+    end
+  end
+
+  context "when the clone exists" do
+    before { iso_env.register_clone(:web2, :default) }
+
+    it "raises an error" do
+      expect { subject.execute }.
+        to raise_error(Vagrant::Errors::CloneMachineExists)
+    end
+  end
+
+  context "when the source is not created" do
+    let(:state) { double("state", id: Vagrant::MachineState::NOT_CREATED_ID) }
+
+    it "raises an error" do
+      expect { subject.execute }.
+        to raise_error(Vagrant::Errors::VMNotCreatedError)
+    end
+  end
+
+  context "when the provider can not clone machines" do
+    before do
+      allow(provider).to receive(:capability?).with(:clone_machine).and_return(false)
+    end
+
+    it "raises an error" do
+      expect { subject.execute }.
+        to raise_error(Vagrant::Errors::CloneMachineUnsupported)
+    end
+  end
+
+  context "without the name of the clone" do
+    let(:argv) { ["default"] }
+
+    it "raises an error" do
+      expect { subject.execute }.
+        to raise_error(Vagrant::Errors::CLIInvalidUsage)
+    end
+  end
+end
@@ -0,0 +1,92 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require File.expand_path("../../../../../base", __FILE__)
+require Vagrant.source_root.join("plugins/commands/clone/middleware/check_address_collision")
+
+describe VagrantPlugins::CommandClone::CheckAddressCollision do
+  include_context "unit"
+
+  let(:app) { double("app", call: nil) }
+  let(:clones) { {web2: :web} }
+  let(:vagrant_env) { double("vagrant_env", clones: clones) }
+  let(:networks) { [[:private_network, {ip: "192.168.56.10"}]] }
+  let(:source_networks) { [[:private_network, {ip: "192.168.56.10"}]] }
+  let(:source_state) { double("source_state", id: :running) }
+  let(:source) do
+    double("source", state: source_state,
+      config: double("source_config", vm: double("source_vm", networks: source_networks)))
+  end
+  let(:machine) do
+    double("machine", name: :web2, env: vagrant_env, provider_name: :virtualbox,
+      state: double("state", id: :poweroff),
+      config: double("config", vm: double("vm", networks: networks)))
+  end
+  let(:env) { {machine: machine} }
+
+  subject { described_class.new(app, env) }
+
+  before do
+    allow(vagrant_env).to receive(:machine).with(:web, :virtualbox).and_return(source)
+  end
+
+  describe "#call" do
+    it "raises an error if the source is running with the same address" do
+      expect(app).not_to receive(:call)
+      expect { subject.call(env) }.
+        to raise_error(Vagrant::Errors::CloneAddressCollision)
+    end
+
+    it "raises an error if another clone is running with the same address" do
+      clones[:web3] = :web
+      allow(source_state).to receive(:id).and_return(:poweroff)
+      allow(vagrant_env).to receive(:machine).with(:web3, :virtualbox).
+        and_return(double("web3", state: double("web3_state", id: :running),
+          config: double("web3_config", vm: double("web3_vm", networks: networks))))
+
+      expect { subject.call(env) }.
+        to raise_error(Vagrant::Errors::CloneAddressCollision)
+    end
+
+    it "raises an error when booting the source while a clone uses its address" do
+      allow(machine).to receive(:name).and_return(:web)
+      allow(vagrant_env).to receive(:machine).with(:web2, :virtualbox).and_return(source)
+
+      expect { subject.call(env) }.
+        to raise_error(Vagrant::Errors::CloneAddressCollision)
+    end
+
+    it "continues if the source is not running" do
+      allow(source_state).to receive(:id).and_return(:poweroff)
+      expect(app).to receive(:call).with(env)
+
+      subject.call(env)
+    end
+
+    it "continues if the clone uses a different address" do
+      networks[0][1][:ip] = "192.168.56.11"
+      expect(app).to receive(:call).with(env)
+
+      subject.call(env)
+    end
+
+    it "continues if the machine is already running" do
+      allow(machine).to receive(:state).and_return(double("state", id: :running))
+      expect(vagrant_env).not_to receive(:machine)
+      expect(app).to receive(:call).with(env)
+
+      subject.call(env)
+    end
+
+    context "when the machine is not related to a clone" do
+      let(:clones) { {} }
+
+      it "continues" do
+        expect(vagrant_env).not_to receive(:machine)
+        expect(app).to receive(:call).with(env)
+
+        subject.call(env)
+      end
+    end
+  end
+end
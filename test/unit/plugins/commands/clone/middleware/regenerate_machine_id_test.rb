@@ -0,0 +1,73 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require File.expand_path("../../../../../base", __FILE__)
+require Vagrant.source_root.join("plugins/commands/clone/middleware/regenerate_machine_id")
+
+describe VagrantPlugins::CommandClone::RegenerateMachineId do
+  include_context "unit"
+
+  let(:app) { double("app", call: nil) }
+  let(:data_dir) { Pathname.new(Dir.mktmpdir("vagrant-test-clone")) }
+  let(:sentinel) { data_dir.join("action_clone") }
+  let(:ui) { Vagrant::UI::Silent.new }
+  let(:communicator) { double("communicator", ready?: true) }
+  let(:guest) { double("guest") }
+  let(:machine) do
+    double("machine", name: :web2, data_dir: data_dir,
+      communicate: communicator, guest: guest)
+  end
+  let(:env) { {machine: machine, ui: ui} }
+
+  subject { described_class.new(app, env) }
+
+  before do
+    allow(guest).to receive(:capability?).with(:regenerate_machine_id).and_return(true)
+  end
+
+  after { FileUtils.rm_rf(data_dir) }
+
+  describe "#call" do
+    context "when the machine is a clone which was not booted" do
+      before { sentinel.write("0") }
+
+      it "regenerates the machine ID after booting" do
+        expect(app).to receive(:call).with(env).ordered
+        expect(guest).to receive(:capability).with(:regenerate_machine_id).ordered
+
+        subject.call(env)
+      end
+
+      it "removes the sentinel" do
+        allow(guest).to receive(:capability)
+
+        subject.call(env)
+        expect(sentinel).not_to be_file
+      end
+
+      it "removes the sentinel if the guest can not regenerate its machine ID" do
+        allow(guest).to receive(:capability?).with(:regenerate_machine_id).and_return(false)
+        expect(guest).not_to receive(:capability)
+
+        subject.call(env)
+        expect(sentinel).not_to be_file
+      end
+
+      it "does nothing if the machine is not ready" do
+        allow(communicator).to receive(:ready?).and_return(false)
+        expect(guest).not_to receive(:capability)
+
+        subject.call(env)
+        expect(sentinel).to be_file
+      end
+    end
+
+    context "when the machine is not a clone" do
+      it "does not regenerate the machine ID" do
+        expect(guest).not_to receive(:capability)
+
+        subject.call(env)
+      end
+    end
+  end
+end
@@ -0,0 +1,40 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require File.expand_path("../../../../../base", __FILE__)
+require Vagrant.source_root.join("plugins/commands/clone/middleware/unregister_clone")
+
+describe VagrantPlugins::CommandClone::UnregisterClone do
+  include_context "unit"
+
+  let(:app) { double("app", call: nil) }
+  let(:vagrant_env) { double("vagrant_env", clones: {web2: :web}) }
+  let(:state) { double("state", id: Vagrant::MachineState::NOT_CREATED_ID) }
+  let(:machine) { double("machine", name: :web2, env: vagrant_env, state: state) }
+  let(:env) { {machine: machine} }
+
+  subject { described_class.new(app, env) }
+
+  describe "#call" do
+    it "removes the destroyed clone" do
+      expect(app).to receive(:call).with(env).ordered
+      expect(vagrant_env).to receive(:unregister_clone).with(:web2).ordered
+
+      subject.call(env)
+    end
+
+    it "does not remove the clone if it was not destroyed" do
+      allow(state).to receive(:id).and_return(:running)
+      expect(vagrant_env).not_to receive(:unregister_clone)
+
+      subject.call(env)
+    end
+
+    it "does not remove machines which are not clones" do
+      allow(machine).to receive(:name).and_return(:web)
+      expect(vagrant_env).not_to receive(:unregister_clone)
+
+      subject.call(env)
+    end
+  end
+end
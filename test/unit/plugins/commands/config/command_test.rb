@@ -0,0 +1,153 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require File.expand_path("../../../../base", __FILE__)
+
+require Vagrant.source_root.join("plugins/commands/config/command")
+
+describe VagrantPlugins::CommandConfig::Command do
+  include_context "unit"
+  include_context "command plugin helpers"
+
+  let(:vagrantfile_content) do
+    <<-VF
+    Vagrant.configure("2") do |config|
+      config.vm.box = "hashicorp/precise64"
+      config.vm.provision "shell", inline: "echo root"
+      config.vm.environment = { "ROLE" => "base", "ZONE" => "a" }
+
+      config.vm.define "default" do |vm|
+        vm.vm.box_url = "http://example.com/default.box"
+        vm.vm.provision "shell", inline: "echo default"
+        vm.vm.environment = { "ROLE" => "web" }
+      end
+    end
+    VF
+  end
+
+  let(:iso_env) do
+    env = isolated_environment
+    env.vagrantfile(vagrantfile_content)
+    env.create_vagrant_env
+  end
+
+  let(:machine) { iso_env.machine(iso_env.machine_names[0], :dummy) }
+  let(:argv) { ["--explain", key] }
+  let(:key) { "vm.box" }
+
+  subject { described_class.new(argv, iso_env) }
+
+  before do
+    allow(subject).to receive(:with_target_vms) { |&block| block.call machine }
+  end
+
+  describe "#execute" do
+    context "without a key" do
+      let(:argv) { [] }
+
+      it "raises an invalid usage error" do
+        expect { subject.execute }.
+          to raise_error(Vagrant::Errors::CLIInvalidUsage)
+      end
+    end
+
+    context "with a key missing the namespace" do
+      let(:key) { "box" }
+
+      it "raises an invalid key error" do
+        expect { subject.execute }.
+          to raise_error(Vagrant::Errors::ConfigKeyInvalid)
+      end
+    end
+
+    context "with a key set in the project Vagrantfile" do
+      it "reports the project Vagrantfile" do
+        expect(machine.ui).to receive(:info) { |message|
+          expect(message).to include("vm.box = \"hashicorp/precise64\"")
+          expect(message).to include("Set by: project Vagrantfile")
+        }
+
+        expect(subject.execute).to eq(0)
+      end
+    end
+
+    context "with a key set in the machine definition" do
+      let(:key) { "vm.box_url" }
+
+      it "reports the machine definition" do
+        expect(machine.ui).to receive(:info) { |message|
+          expect(message).to include("Set by: machine definition")
+        }
+
+        expect(subject.execute).to eq(0)
+      end
+    end
+
+    context "with a key combined from multiple locations" do
+      let(:key) { "vm.provisioners" }
+
+      it "reports multiple locations" do
+        expect(machine.ui).to receive(:info) { |message|
+          expect(message).to include("Set by: multiple (project Vagrantfile, machine definition)")
+        }
+
+        expect(subject.execute).to eq(0)
+      end
+    end
+
+    context "with an entry of a hash overridden in the machine definition" do
+      let(:key) { "vm.environment.ROLE" }
+
+      it "reports the machine definition" do
+        expect(machine.ui).to receive(:info) { |message|
+          expect(message).to include("vm.environment.ROLE = \"web\"")
+          expect(message).to include("Set by: machine definition")
+        }
+
+        expect(subject.execute).to eq(0)
+      end
+    end
+
+    context "with an entry of a hash only set in the project Vagrantfile" do
+      let(:key) { "vm.environment.ZONE" }
+
+      it "reports the project Vagrantfile" do
+        expect(machine.ui).to receive(:info) { |message|
+          expect(message).to include("vm.environment.ZONE = \"a\"")
+          expect(message).to include("Set by: project Vagrantfile")
+        }
+
+        expect(subject.execute).to eq(0)
+      end
+    end
+
+    context "with a setting inherited from a provider profile" do
+      let(:key) { "dummy.memory" }
+
+      before do
+        allow(machine.vagrantfile).to receive(:config_provenance).
+          and_return([:"profile:big"])
+      end
+
+      it "reports the provider profile" do
+        expect(machine.ui).to receive(:info) { |message|
+          expect(message).to include("Set by: provider profile 'big'")
+        }
+
+        expect(subject.execute).to eq(0)
+      end
+    end
+
+    context "with a key that is not set" do
+      let(:key) { "ssh.port" }
+
+      it "reports the key is not set" do
+        expect(machine.ui).to receive(:info) { |message|
+          expect(message).to include("ssh.port is not set")
+        }
+
+        expect(subject.execute).to eq(0)
+      end
+    end
+  end
+end
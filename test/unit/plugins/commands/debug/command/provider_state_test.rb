@@ -0,0 +1,65 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require File.expand_path("../../../../../base", __FILE__)
+
+require Vagrant.source_root.join("plugins/commands/debug/command/provider_state")
+
+describe VagrantPlugins::CommandDebug::Command::ProviderState do
+  include_context "unit"
+
+  let(:iso_env) do
+    # We have to create a Vagrantfile so there is a root path
+    env = isolated_environment
+    env.vagrantfile("")
+    env.create_vagrant_env
+  end
+
+  let(:machine) { iso_env.machine(iso_env.machine_names[0], :dummy) }
+  let(:argv) { [] }
+
+  subject { described_class.new(argv, iso_env) }
+
+  before do
+    allow(subject).to receive(:with_target_vms) { |&block| block.call(machine) }
+  end
+
+  describe "execute" do
+    it "shows a message when no state is stored" do
+      expect(machine.ui).to receive(:info).with(/No provider state/)
+      expect(subject.execute).to eq(0)
+    end
+
+    it "shows the stored state" do
+      machine.provider_state.set("volume_id", "vol-1")
+      expect(machine.ui).to receive(:detail).with('volume_id (string): "vol-1"')
+      expect(subject.execute).to eq(0)
+    end
+
+    context "with --format=json" do
+      let(:argv) { ["--format=json"] }
+
+      it "outputs the stored state" do
+        machine.provider_state.set("volume_id", "vol-1")
+        output = nil
+        allow(iso_env.ui).to receive(:info) { |message, _| output = message }
+
+        expect(subject.execute).to eq(0)
+        expect(JSON.parse(output)).to eq([{
+          "name" => "default",
+          "provider" => "dummy",
+          "state" => { "volume_id" => { "type" => "string", "value" => "vol-1" } },
+        }])
+      end
+    end
+
+    context "with an invalid format" do
+      let(:argv) { ["--format", "yaml"] }
+
+      it "shows help" do
+        expect { subject.execute }.
+          to raise_error(Vagrant::Errors::CLIInvalidUsage)
+      end
+    end
+  end
+end
@@ -0,0 +1,88 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require File.expand_path("../../../../base", __FILE__)
+require Vagrant.source_root.join("plugins/commands/doctor/command")
+
+describe VagrantPlugins::CommandDoctor::Command do
+  include_context "unit"
+
+  let(:argv) { [] }
+  let(:iso_env) do
+    env = isolated_environment
+    env.vagrantfile("")
+    env.create_vagrant_env
+  end
+  let(:finding_klass) { VagrantPlugins::CommandDoctor::Diagnostics::Finding }
+  let(:diagnostics) { double("diagnostics", run: findings) }
+  let(:findings) { [finding_klass.new(:info, "Usable providers: dummy", "")] }
+
+  subject { described_class.new(argv, iso_env) }
+
+  before do
+    allow(VagrantPlugins::CommandDoctor::Diagnostics).to receive(:new).
+      with(iso_env).and_return(diagnostics)
+  end
+
+  it "succeeds without problems" do
+    expect(iso_env.ui).to receive(:success).with(/No problems/)
+    expect(subject.execute).to eq(0)
+  end
+
+  context "with a warning" do
+    let(:fix) { double("fix") }
+    let(:findings) do
+      [finding_klass.new(:warning, "stale entry", "run --fix", fix)]
+    end
+
+    it "exits with the status of the warning" do
+      expect(iso_env.ui).to receive(:warn).with(/stale entry.*run --fix/m)
+      expect(subject.execute).to eq(1)
+    end
+
+    it "does not fix the problem" do
+      expect(fix).not_to receive(:call)
+      subject.execute
+    end
+
+    context "with --fix" do
+      let(:argv) { ["--fix"] }
+
+      it "fixes the problem" do
+        expect(fix).to receive(:call)
+        expect(subject.execute).to eq(0)
+      end
+    end
+  end
+
+  context "with an error" do
+    let(:findings) do
+      [
+        finding_klass.new(:warning, "orphaned machine", ""),
+        finding_klass.new(:error, "not writable", ""),
+      ]
+    end
+
+    it "exits with the status of the error" do
+      expect(iso_env.ui).to receive(:error).with(/not writable/)
+      expect(subject.execute).to eq(2)
+    end
+
+    context "with --fix" do
+      let(:argv) { ["--fix"] }
+
+      it "does not fix problems without a safe fix" do
+        expect(subject.execute).to eq(2)
+      end
+    end
+  end
+
+  context "with arguments" do
+    let(:argv) { ["default"] }
+
+    it "raises an error" do
+      expect { subject.execute }.
+        to raise_error(Vagrant::Errors::CLIInvalidUsage)
+    end
+  end
+end
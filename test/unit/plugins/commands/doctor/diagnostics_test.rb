@@ -0,0 +1,175 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require File.expand_path("../../../../base", __FILE__)
+require Vagrant.source_root.join("plugins/commands/doctor/diagnostics")
+
+describe VagrantPlugins::CommandDoctor::Diagnostics do
+  include_context "unit"
+
+  let(:iso_env) do
+    env = isolated_environment
+    env.vagrantfile("")
+    env.create_vagrant_env
+  end
+  let(:machine) { iso_env.machine(:default, :dummy) }
+  let(:provider_class) do
+    double("provider_class", usable?: true, machine_ids: machine_ids,
+      created_machine_ids: created_machine_ids)
+  end
+  let(:machine_ids) { ["MACHINE_ID"] }
+  let(:created_machine_ids) { machine_ids }
+  let(:providers) do
+    Vagrant::Registry.new.tap do |registry|
+      registry.register(:dummy) { [provider_class, {}] }
+    end
+  end
+  let(:plugin_manager) do
+    double("plugin_manager", installed_plugins: installed_plugins,
+      installed_specs: installed_specs)
+  end
+  let(:installed_plugins) { {} }
+  let(:installed_specs) { [] }
+
+  subject { described_class.new(iso_env) }
+
+  before do
+    allow(subject).to receive(:providers).and_return(providers)
+    allow(Vagrant::Plugin::Manager).to receive(:instance).and_return(plugin_manager)
+    machine.id = "MACHINE_ID"
+  end
+
+  def messages(findings, severity)
+    findings.select { |f| f.severity == severity }.map(&:message)
+  end
+
+  it "finds no problems" do
+    findings = subject.run
+    expect(findings.map(&:severity)).to eq([:info])
+    expect(findings.first.message).to include("dummy")
+  end
+
+  context "when the provider reports the machine does not exist" do
+    let(:machine_ids) { [] }
+
+    it "finds the stale index entry" do
+      findings = subject.run
+      stale = findings.detect(&:fixable?)
+      expect(stale.severity).to eq(:warning)
+      expect(stale.message).to include("'default'")
+    end
+
+    it "prunes the entry when fixed" do
+      subject.run.detect(&:fixable?).fix.call
+
+      expect(iso_env.machine_index.include?(machine.index_uuid)).to be(false)
+    end
+  end
+
+  context "when the provider reports a machine not in the index" do
+    let(:machine_ids) { ["MACHINE_ID", "ORPHAN_ID"] }
+
+    it "finds the orphaned machine" do
+      findings = subject.run
+      expect(messages(findings, :warning)).to include(/ORPHAN_ID/)
+      expect(findings.none?(&:fixable?)).to be(true)
+    end
+  end
+
+  context "when the machine was not created by Vagrant" do
+    let(:machine_ids) { ["MACHINE_ID", "OTHER_ID"] }
+    let(:created_machine_ids) { [] }
+
+    it "does not find stale entries or orphans" do
+      expect(subject.run.map(&:severity)).to eq([:info])
+    end
+  end
+
+  context "when the provider can not report its machines" do
+    let(:machine_ids) { nil }
+
+    it "does not find stale entries" do
+      expect(subject.run.map(&:severity)).to eq([:info])
+    end
+  end
+
+  context "when the Vagrantfile directory of an entry is removed" do
+    before do
+      entry = iso_env.machine_index.get(machine.index_uuid)
+      entry.vagrantfile_path = "/does/not/exist"
+      iso_env.machine_index.release(iso_env.machine_index.set(entry))
+    end
+
+    it "does not offer to prune the entry of a machine which exists" do
+      findings = subject.run
+      expect(messages(findings, :warning)).to include(/no longer exists/)
+      expect(findings.none?(&:fixable?)).to be(true)
+    end
+
+    context "when the provider reports the machine does not exist" do
+      let(:machine_ids) { [] }
+
+      it "finds the stale index entry" do
+        expect(subject.run.any?(&:fixable?)).to be(true)
+      end
+    end
+  end
+
+  context "when no provider is usable" do
+    before { allow(provider_class).to receive(:usable?).and_return(false) }
+
+    it "finds an error" do
+      expect(messages(subject.run, :error)).to include(/No usable providers/)
+    end
+  end
+
+  context "when the default provider is not usable" do
+    before do
+      allow(ENV).to receive(:[]).and_call_original
+      allow(ENV).to receive(:[]).with("VAGRANT_DEFAULT_PROVIDER").and_return("unknown")
+    end
+
+    it "finds an error" do
+      expect(messages(subject.run, :error)).to include(/'unknown'/)
+    end
+  end
+
+  context "with plugins" do
+    let(:installed_plugins) { {"vagrant-example" => {"installed_gem_version" => "1.0.0"}} }
+    let(:spec) { Gem::Specification.new("vagrant-example", "1.0.0") }
+    let(:installed_specs) { [spec] }
+
+    it "finds no problems" do
+      expect(subject.run.map(&:severity)).to eq([:info])
+    end
+
+    context "when the version of the plugin differs" do
+      let(:spec) { Gem::Specification.new("vagrant-example", "1.2.0") }
+
+      it "finds the drift" do
+        expect(messages(subject.run, :warning)).to include(/1\.0\.0.*1\.2\.0/m)
+      end
+    end
+
+    context "when the gem of the plugin is missing" do
+      let(:installed_specs) { [] }
+
+      it "finds an error" do
+        expect(messages(subject.run, :error)).to include(/vagrant-example/)
+      end
+    end
+  end
+
+  describe ".worst_severity" do
+    let(:finding_klass) { described_class::Finding }
+
+    it "returns the most severe severity" do
+      findings = [:warning, :error, :info].map { |s| finding_klass.new(s, "") }
+      expect(described_class.worst_severity(findings)).to eq(:error)
+    end
+
+    it "returns nil without findings" do
+      expect(described_class.worst_severity([])).to be_nil
+    end
+  end
+end
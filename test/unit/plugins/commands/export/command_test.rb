@@ -0,0 +1,146 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require File.expand_path("../../../../base", __FILE__)
+require Vagrant.source_root.join("plugins/commands/export/command")
+
+describe VagrantPlugins::CommandExport::Command do
+  include_context "unit"
+
+  let(:argv) { [] }
+  let(:iso_env) do
+    env = isolated_environment
+    env.vagrantfile("")
+    env.create_vagrant_env
+  end
+  let(:output_dir) { Dir.mktmpdir("vagrant-test-export") }
+  let(:output) { File.join(output_dir, "out.box") }
+  let(:state) { double("state", id: :poweroff) }
+  let(:provider) { double("provider") }
+  let(:machine) { iso_env.machine(iso_env.machine_names[0], :dummy) }
+
+  subject { described_class.new(argv, iso_env) }
+
+  before do
+    allow(subject).to receive(:with_target_vms) { |&block| block.call machine }
+    allow(machine).to receive(:state).and_return(state)
+    allow(machine).to receive(:provider).and_return(provider)
+    allow(provider).to receive(:capability?).with(:export_machine).and_return(true)
+    allow(provider).to receive(:capability).with(:export_machine, anything) do |_, dir|
+      File.write(File.join(dir, "box.img"), "disk")
+    end
+    allow(subject).to receive(:compress) { |_, _, io| io.write("box") }
+  end
+
+  after { FileUtils.rm_rf(output_dir) }
+
+  context "with an output file" do
+    let(:argv) { ["--output", output] }
+
+    it "writes the box to the file" do
+      expect(subject.execute).to eq(0)
+      expect(File.read(output)).to eq("box")
+    end
+
+    it "adds the metadata of the box" do
+      expect(subject).to receive(:compress) do |_, dir, io|
+        metadata = JSON.parse(File.read(File.join(dir, "metadata.json")))
+        expect(metadata).to eq("provider" => "dummy")
+        expect(File.read(File.join(dir, "box.img"))).to eq("disk")
+      end
+
+      subject.execute
+    end
+
+    it "removes the exported files" do
+      dir = nil
+      expect(subject).to receive(:compress) { |_, d, _| dir = d }
+
+      subject.execute
+      expect(File.exist?(dir)).to be(false)
+    end
+
+    it "raises an error if the file exists" do
+      File.write(output, "")
+      expect { subject.execute }.
+        to raise_error(Vagrant::Errors::ExportOutputExists)
+    end
+
+    it "removes the file if compressing fails" do
+      expect(subject).to receive(:compress).
+        and_raise(Vagrant::Errors::ExportArchiveFailed, name: "default")
+
+      expect { subject.execute }.
+        to raise_error(Vagrant::Errors::ExportArchiveFailed)
+      expect(File.exist?(output)).to be(false)
+    end
+
+    context "when the machine is running" do
+      let(:state) { double("state", id: :running) }
+
+      it "suspends the machine while exporting it" do
+        expect(machine).to receive(:action).with(:suspend).ordered
+        expect(provider).to receive(:capability).with(:export_machine, anything).ordered
+        expect(machine).to receive(:action).with(:resume).ordered
+
+        subject.execute
+      end
+
+      it "resumes the machine if exporting fails" do
+        expect(machine).to receive(:action).with(:suspend)
+        expect(provider).to receive(:capability).and_raise("failed")
+        expect(machine).to receive(:action).with(:resume)
+
+        expect { subject.execute }.to raise_error("failed")
+      end
+
+      it "raises an error if the machine can not be suspended" do
+        expect(machine).to receive(:action).with(:suspend).
+          and_raise(Vagrant::Errors::UnimplementedProviderAction, action: :suspend, provider: "dummy")
+
+        expect { subject.execute }.
+          to raise_error(Vagrant::Errors::ExportMachineRunning)
+      end
+
+      context "when forced" do
+        let(:argv) { ["--output", output, "--force"] }
+
+        it "does not suspend the machine" do
+          expect(machine).not_to receive(:action)
+
+          subject.execute
+        end
+      end
+    end
+  end
+
+  context "with stdout as the output" do
+    let(:argv) { ["--output", "-"] }
+
+    it "writes the box to stdout" do
+      expect(subject).to receive(:with_stdout).with(true).and_yield(StringIO.new)
+
+      expect(subject.execute).to eq(0)
+    end
+  end
+
+  context "when the machine is not created" do
+    let(:state) { double("state", id: Vagrant::MachineState::NOT_CREATED_ID) }
+
+    it "raises an error" do
+      expect { subject.execute }.
+        to raise_error(Vagrant::Errors::VMNotCreatedError)
+    end
+  end
+
+  context "when the provider can not export machines" do
+    before do
+      allow(provider).to receive(:capability?).with(:export_machine).and_return(false)
+    end
+
+    it "raises an error" do
+      expect { subject.execute }.
+        to raise_error(Vagrant::Errors::ExportMachineUnsupported)
+    end
+  end
+end
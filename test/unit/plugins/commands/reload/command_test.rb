@@ -99,4 +99,165 @@ describe VagrantPlugins::CommandReload::Command do
       expect(subject.execute).to eq(0)
     end
   end
+
+  context "with resource changes" do
+    let(:resources) { {cpus: 1} }
+    let(:hotplug) { [:cpus] }
+    let(:provider_modified) { true }
+    let(:provider_settings) { {"gui" => false} }
+
+    before do
+      machine.id = "foo"
+      allow(machine.provider).to receive(:capability?).with(:resources_config).and_return(true)
+      allow(machine.provider).to receive(:capability).with(:resources_config) { provider_settings }
+      allow(machine.provider).to receive(:capability?).with(:resources).and_return(true)
+      allow(machine.provider).to receive(:capability?).with(:modify_resources).and_return(true)
+      allow(machine.provider).to receive(:capability).with(:resources) { resources }
+      allow(machine.provider).to receive(:capability).with(:modify_resources, anything) { provider_modified }
+      allow(machine).to receive(:state).and_return(double("state", id: :running))
+      allow(machine.guest).to receive(:ready?).and_return(true)
+      allow(machine.guest).to receive(:capability?).with(:resources_hotplug).and_return(true)
+      allow(machine.guest).to receive(:capability).with(:resources_hotplug) { hotplug }
+
+      subject.store_resources(machine)
+      resources[:cpus] = 2
+    end
+
+    it "should modify the resources without reloading" do
+      expect(machine.provider).to receive(:capability).with(:modify_resources, cpus: 2)
+      expect(machine).not_to receive(:action)
+      expect(subject.execute).to eq(0)
+    end
+
+    it "should reload when nothing has changed" do
+      resources[:cpus] = 1
+      expect(machine.provider).not_to receive(:capability).with(:modify_resources, anything)
+      expect(machine).to receive(:action).with(:reload, anything)
+      expect(subject.execute).to eq(0)
+    end
+
+    it "should reload when other configuration has changed" do
+      machine.config.vm.hostname = "changed"
+      expect(machine).to receive(:action).with(:reload, anything)
+      expect(subject.execute).to eq(0)
+    end
+
+    it "should reload when other provider settings have changed" do
+      provider_settings["gui"] = true
+      expect(machine.provider).not_to receive(:capability).with(:modify_resources, anything)
+      expect(machine).to receive(:action).with(:reload, anything)
+      expect(subject.execute).to eq(0)
+    end
+
+    context "with a network" do
+      let(:isolated) { isolated_environment }
+      let(:iso_env) do
+        isolated.vagrantfile(vagrantfile_content)
+        isolated.create_vagrant_env
+      end
+      let(:vagrantfile_content) do
+        "Vagrant.configure(2) { |c| c.vm.network :private_network, ip: '192.168.56.10' }"
+      end
+
+      it "should modify the resources without reloading" do
+        expect(machine.provider).to receive(:capability).with(:modify_resources, cpus: 2)
+        expect(machine).not_to receive(:action)
+        expect(subject.execute).to eq(0)
+      end
+
+      it "should have the same digest when the Vagrantfile is loaded again" do
+        reloaded = isolated.create_vagrant_env.machine(machine.name, :dummy)
+        allow(reloaded.provider).to receive(:capability?).with(:resources_config).and_return(true)
+        allow(reloaded.provider).to receive(:capability).with(:resources_config) { provider_settings }
+
+        expect(subject.send(:resources_config_digest, reloaded)).
+          to eq(subject.send(:resources_config_digest, machine))
+      end
+    end
+
+    context "when the guest does not support the change" do
+      let(:hotplug) { [] }
+
+      it "should reload with a message" do
+        expect(machine.ui).to receive(:info).with(/guest does not support/)
+        expect(machine).to receive(:action).with(:reload, anything)
+        expect(subject.execute).to eq(0)
+      end
+    end
+
+    context "when the provider cannot modify the resources" do
+      let(:provider_modified) { false }
+
+      it "should reload with a message" do
+        expect(machine.ui).to receive(:info).with(/does not support changing/)
+        expect(machine).to receive(:action).with(:reload, anything)
+        expect(subject.execute).to eq(0)
+      end
+    end
+  end
+
+  context "with --only-synced-folders" do
+    let(:argv) { ["--only-synced-folders"] }
+    let(:state) { :running }
+
+    before do
+      allow(machine).to receive(:state).and_return(double("state", id: state))
+    end
+
+    it "should sync the folders without reloading" do
+      expect(machine).to receive(:action).with(:sync_folders, synced_folders_reload: true)
+      expect(machine).not_to receive(:action).with(:reload, anything)
+      expect(subject.execute).to eq(0)
+    end
+
+    context "when the machine is not running" do
+      let(:state) { :poweroff }
+
+      it "should raise an error" do
+        expect(machine).not_to receive(:action)
+        expect { subject.execute }.to raise_error(Vagrant::Errors::ReloadNotRunning)
+      end
+    end
+
+    context "with the provision flag" do
+      let(:argv) { ["--only-synced-folders", "--provision"] }
+
+      it "should raise an error" do
+        expect { subject.execute }.to raise_error(Vagrant::Errors::CLIInvalidUsage)
+      end
+    end
+  end
+
+  context "with --only-networks" do
+    let(:argv) { ["--only-networks"] }
+
+    before do
+      machine.id = "foo"
+      allow(machine).to receive(:state).and_return(double("state", id: :running))
+      subject.store_networks(machine)
+    end
+
+    it "should do nothing when the networks have not changed" do
+      expect(machine).not_to receive(:action)
+      expect(subject.execute).to eq(0)
+    end
+
+    context "when the networks have changed" do
+      before do
+        machine.config.vm.network "private_network", ip: "192.168.56.10"
+      end
+
+      it "should reload the networks" do
+        expect(machine).to receive(:action).with(:reload_networks, networks_previous: [])
+        expect(subject.execute).to eq(0)
+      end
+
+      it "should raise an error if the provider can not reload the networks" do
+        expect(machine).to receive(:action).with(:reload_networks, anything).
+          and_raise(Vagrant::Errors::UnimplementedProviderAction.new(
+            action: :reload_networks, provider: "dummy"))
+        expect { subject.execute }.to raise_error(Vagrant::Errors::ReloadNetworksRebootRequired)
+      end
+    end
+  end
 end
@@ -81,6 +81,21 @@ describe VagrantPlugins::CommandSnapshot::Command::List do
         expect(iso_env.ui).to receive(:detail).with(/baz/, anything)
         expect(subject.execute).to eq(0)
       end
+
+      it "groups snapshots by tree" do
+        machine.id = "foo"
+        VagrantPlugins::CommandSnapshot::TreeIndex.new(iso_env.local_data_path).
+          add("foo", machine, "foo")
+
+        allow(machine.provider).to receive(:capability).with(:snapshot_list).
+          and_return(["foo", "bar"])
+
+        allow(iso_env.ui).to receive(:output).and_call_original
+        allow(iso_env.ui).to receive(:detail).and_call_original
+        expect(iso_env.ui).to receive(:output).with(/Snapshot trees/).and_call_original
+        expect(iso_env.ui).to receive(:detail).with("  default", anything).and_call_original
+        expect(subject.execute).to eq(0)
+      end
     end
   end
 end
@@ -119,6 +119,50 @@ describe VagrantPlugins::CommandSnapshot::Command::Restore do
       subject.execute
     end
 
+    context "with a snapshot tree" do
+      let(:argv) { ["tree"] }
+      let(:trees) { VagrantPlugins::CommandSnapshot::TreeIndex.new(iso_env.local_data_path) }
+
+      before do
+        machine.id = "foo"
+        trees.add("tree", machine, "tree")
+        allow(machine.provider).to receive(:capability).with(:snapshot_list).
+          and_return(["tree"])
+        allow(machine).to receive(:provider_options).and_return(parallel: true)
+      end
+
+      it "restores the machines of the tree" do
+        expect(subject).to receive(:with_target_vms).with(["default"]) { |&block| block.call machine }
+        expect(machine).to receive(:action) do |name, opts|
+          expect(name).to eq(:snapshot_restore)
+          expect(opts[:snapshot_name]).to eq("tree")
+        end
+        expect(subject.execute).to eq(0)
+      end
+
+      it "reports failures instead of raising" do
+        expect(machine).to receive(:action).
+          and_raise(Vagrant::Errors::SnapshotNotSupported)
+        expect(iso_env.ui).to receive(:error).with(/default: failed/)
+        expect(subject.execute).to eq(1)
+      end
+
+      it "reports machines missing the snapshot" do
+        allow(machine.provider).to receive(:capability).with(:snapshot_list).
+          and_return([])
+        expect(machine).not_to receive(:action)
+        expect(iso_env.ui).to receive(:error).with(/default: failed/)
+        expect(subject.execute).to eq(1)
+      end
+
+      it "restores a single machine when a vm name is given" do
+        argv.unshift("default")
+        expect(machine).to receive(:action).with(:snapshot_restore, hash_including(snapshot_name: "tree"))
+        expect(iso_env.ui).not_to receive(:success)
+        expect(subject.execute).to eq(0)
+      end
+    end
+
     context "when --no-start flag is provided" do
       let(:argv) { [snapshot_name, "--no-start"] }
 
@@ -69,6 +69,53 @@ describe VagrantPlugins::CommandSnapshot::Command::Save do
         expect(subject.execute).to eq(0)
       end
 
+      it "does not record a snapshot tree for a single machine" do
+        machine.id = "foo"
+        allow(machine).to receive(:action)
+
+        subject.execute
+        trees = VagrantPlugins::CommandSnapshot::TreeIndex.new(iso_env.local_data_path)
+        expect(trees.get("test")).to be_nil
+      end
+
+      context "in a multi-machine environment" do
+        let(:iso_env) do
+          env = isolated_environment
+          env.vagrantfile(<<-VF)
+            Vagrant.configure("2") do |config|
+              config.vm.define "web"
+              config.vm.define "db"
+            end
+          VF
+          env.create_vagrant_env
+        end
+
+        let(:db) { iso_env.machine(:db, :dummy) }
+
+        before do
+          [machine, db].each do |vm|
+            vm.id = "foo"
+            allow(vm).to receive(:action)
+            allow(vm.provider).to receive(:capability).with(:snapshot_list).and_return([])
+            allow(vm.provider).to receive(:capability?).with(:snapshot_list).and_return(true)
+          end
+          allow(subject).to receive(:with_target_vms) { |&block| [machine, db].each(&block) }
+        end
+
+        it "records the snapshots in the snapshot tree" do
+          subject.execute
+          trees = VagrantPlugins::CommandSnapshot::TreeIndex.new(iso_env.local_data_path)
+          expect(trees.get("test").keys.sort).to eq(["db", "web"])
+        end
+
+        it "does not record a snapshot tree when a vm name is given" do
+          argv.unshift("web")
+          subject.execute
+          trees = VagrantPlugins::CommandSnapshot::TreeIndex.new(iso_env.local_data_path)
+          expect(trees.get("test")).to be_nil
+        end
+      end
+
       it "doesn't snapshot a non-existent machine" do
         machine.id = nil
 
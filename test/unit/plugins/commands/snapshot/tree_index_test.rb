@@ -0,0 +1,50 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require File.expand_path("../../../../base", __FILE__)
+
+require Vagrant.source_root.join("plugins/commands/snapshot/tree_index")
+
+describe VagrantPlugins::CommandSnapshot::TreeIndex do
+  include_context "unit"
+
+  let(:data_path) { temporary_dir }
+  let(:web) { double("web", name: :web, provider_name: :virtualbox) }
+  let(:db) { double("db", name: :db, provider_name: :virtualbox) }
+
+  subject { described_class.new(data_path) }
+
+  it "should be empty by default" do
+    expect(subject.names).to be_empty
+    expect(subject.get("tree")).to be_nil
+  end
+
+  it "should add machines to a tree" do
+    subject.add("tree", web, "tree")
+    subject.add("tree", db, "tree")
+    expect(subject.get("tree").keys).to eq(["web", "db"])
+    expect(subject.get("tree")["web"]).to eq(
+      "provider" => "virtualbox", "snapshot" => "tree")
+  end
+
+  it "should persist trees" do
+    subject.add("tree", web, "tree")
+    index = described_class.new(data_path)
+    expect(index.names).to eq(["tree"])
+    expect(index.for_machine(:web)).to eq("tree" => "tree")
+  end
+
+  it "should remove the tree when the last machine is removed" do
+    subject.add("tree", web, "tree")
+    subject.add("tree", db, "tree")
+    subject.remove("tree", :web)
+    expect(subject.get("tree").keys).to eq(["db"])
+    subject.remove("tree", :db)
+    expect(subject.names).to be_empty
+  end
+
+  it "should ignore an invalid index" do
+    data_path.join(described_class.const_get(:FILENAME)).write("{")
+    expect(subject.names).to be_empty
+  end
+end
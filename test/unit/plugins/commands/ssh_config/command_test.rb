@@ -209,5 +209,136 @@ Host #{machine.name}
       expect(output).not_to include("HostKeyAlgorithms +ssh-rsa")
     end
 
+    it "uses the host name as the host key alias when verifying host keys" do
+      allow(machine).to receive(:ssh_info) { ssh_info.merge(verify_host_key: true) }
+      output = ""
+      allow(subject).to receive(:safe_puts) do |data|
+        output += data if data
+      end
+
+      subject.execute
+
+      expect(output).to include("HostKeyAlias #{machine.name}")
+    end
+
+    it "does not set a host key alias when not verifying host keys" do
+      output = ""
+      allow(subject).to receive(:safe_puts) do |data|
+        output += data if data
+      end
+
+      subject.execute
+
+      expect(output).not_to include("HostKeyAlias")
+    end
+
+    it "includes a ProxyJump for a jump host string" do
+      allow(machine).to receive(:ssh_info) { ssh_info.merge(proxy_jump: "jump@bastion:2200") }
+      output = ""
+      allow(subject).to receive(:safe_puts) do |data|
+        output += data if data
+      end
+
+      subject.execute
+
+      expect(output).to include("ProxyJump jump@bastion:2200")
+    end
+
+    it "includes a ProxyCommand for jump host connection info" do
+      allow(machine).to receive(:ssh_info) {
+        ssh_info.merge(proxy_jump: {
+          host: "127.0.0.1", port: 2222, username: "vagrant",
+          private_key_path: ["/keys/host key"]
+        })
+      }
+      output = ""
+      allow(subject).to receive(:safe_puts) do |data|
+        output += data if data
+      end
+
+      subject.execute
+
+      expect(output).to include("ProxyCommand ssh -W %h:%p -q -p 2222 -i \"/keys/host key\" " \
+        "-o StrictHostKeyChecking=no -o UserKnownHostsFile=/dev/null vagrant@127.0.0.1")
+      expect(output).not_to include("ProxyJump")
+    end
+
+    it "prefers a configured proxy command over a jump host" do
+      allow(machine).to receive(:ssh_info) {
+        ssh_info.merge(proxy_command: "nc %h %p", proxy_jump: "jump@bastion")
+      }
+      output = ""
+      allow(subject).to receive(:safe_puts) do |data|
+        output += data if data
+      end
+
+      subject.execute
+
+      expect(output).to include("ProxyCommand nc %h %p")
+      expect(output).not_to include("ProxyJump")
+    end
+
+    it "raises an error if the machine is not ready" do
+      allow(machine).to receive(:ssh_info).and_return(nil)
+      expect { subject.execute }.to raise_error(Vagrant::Errors::SSHNotReady)
+    end
+
+    context "with --all" do
+      let(:argv) { ["--all"] }
+      let(:other) { double("other", name: :other, ui: machine.ui, ssh_info: ssh_info.merge(port: 2200)) }
+
+      before do
+        allow(subject).to receive(:with_target_vms) { |&block|
+          block.call machine
+          block.call other
+        }
+      end
+
+      it "prints a config for each machine" do
+        output = ""
+        allow(subject).to receive(:safe_puts) do |data|
+          output += data if data
+        end
+
+        subject.execute
+
+        expect(output.scan(/^Host .+$/)).to eq(["Host #{machine.name}", "Host other"])
+      end
+
+      it "skips machines which are not ready" do
+        allow(machine).to receive(:ssh_info).and_return(nil)
+        output = ""
+        allow(subject).to receive(:safe_puts) do |data|
+          output += data if data
+        end
+
+        subject.execute
+
+        expect(output.scan(/^Host .+$/)).to eq(["Host other"])
+      end
+
+      context "with --host" do
+        let(:argv) { ["--all", "--host", "project"] }
+
+        it "prefixes the host names" do
+          output = ""
+          allow(subject).to receive(:safe_puts) do |data|
+            output += data if data
+          end
+
+          subject.execute
+
+          expect(output.scan(/^Host .+$/)).to eq(["Host project-#{machine.name}", "Host project-other"])
+        end
+      end
+
+      context "with machine names" do
+        let(:argv) { ["--all", "default"] }
+
+        it "raises an invalid usage error" do
+          expect { subject.execute }.to raise_error(Vagrant::Errors::CLIInvalidUsage)
+        end
+      end
+    end
   end
 end
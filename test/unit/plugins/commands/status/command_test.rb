@@ -0,0 +1,127 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require File.expand_path("../../../../base", __FILE__)
+
+require Vagrant.source_root.join("plugins/commands/status/command")
+
+describe VagrantPlugins::CommandStatus::Command do
+  include_context "unit"
+
+  let(:iso_env) do
+    # We have to create a Vagrantfile so there is a root path
+    env = isolated_environment
+    env.vagrantfile("")
+    env.create_vagrant_env
+  end
+
+  let(:machine) { iso_env.machine(iso_env.machine_names[0], :dummy) }
+  let(:state) { Vagrant::MachineState.new(:running, "running", "The machine is running.") }
+  let(:machines) { [machine] }
+  let(:argv) { [] }
+
+  subject { described_class.new(argv, iso_env) }
+
+  before do
+    allow(machine).to receive(:state).and_return(state)
+    allow(subject).to receive(:with_target_vms) { |&block| machines.each(&block) }
+  end
+
+  describe "execute" do
+    it "prints the state table" do
+      expect(iso_env.ui).to receive(:info).
+        with(/default\s+running \(dummy\)/, prefix: false)
+      expect(subject.execute).to eq(0)
+    end
+
+    it "queries the state of each machine once" do
+      expect(machine).to receive(:state).once.and_return(state)
+      subject.execute
+    end
+
+    context "with an invalid format" do
+      let(:argv) { ["--format", "yaml"] }
+
+      it "shows help" do
+        expect { subject.execute }.
+          to raise_error(Vagrant::Errors::CLIInvalidUsage)
+      end
+    end
+
+    context "with --format=json" do
+      let(:argv) { ["--format=json"] }
+      let(:output) { [] }
+
+      before do
+        allow(iso_env.ui).to receive(:info) { |message, _| output << message }
+      end
+
+      it "prints the machine states" do
+        machine.id = "foo"
+        expect(subject.execute).to eq(0)
+
+        result = JSON.parse(output.last)
+        expect(result.length).to eq(1)
+        expect(result.first).to include(
+          "name" => "default",
+          "provider" => "dummy",
+          "state" => "running",
+          "state_human_short" => "running",
+          "state_human_long" => "The machine is running.",
+          "id" => "foo",
+          "basis" => iso_env.home_path.to_s,
+          "project" => iso_env.root_path.to_s,
+        )
+      end
+
+      context "with no machines" do
+        let(:machines) { [] }
+
+        it "prints an empty array" do
+          subject.execute
+          expect(output.last).to eq("[]")
+        end
+      end
+    end
+
+    context "with --long" do
+      let(:argv) { ["--long"] }
+      let(:output) { [] }
+
+      before do
+        machine.id = "foo"
+        allow(machine.communicate).to receive(:ready?).and_return(true)
+        allow(Vagrant::Util::GuestTools).to receive(:versions).
+          with(machine).and_return(["6.1.40", "7.0.12"])
+        allow(iso_env.ui).to receive(:info) { |message, _| output << message }
+      end
+
+      it "prints the guest tools versions" do
+        expect(subject.execute).to eq(0)
+        expect(output).to include("Guest tools:")
+        expect(output.last).to match(/default\s+6\.1\.40 \(expected 7\.0\.12\)/)
+      end
+
+      it "prints unknown when the guest is not reachable" do
+        allow(machine.communicate).to receive(:ready?).and_return(false)
+        expect(Vagrant::Util::GuestTools).not_to receive(:versions)
+        subject.execute
+        expect(output.last).to match(/default\s+unknown/)
+      end
+
+      context "with --format=json" do
+        let(:argv) { ["--long", "--format=json"] }
+
+        it "includes the guest tools versions" do
+          subject.execute
+          result = JSON.parse(output.last)
+          expect(result.first["guest_tools"]).to eq(
+            "installed" => "6.1.40",
+            "expected" => "7.0.12",
+            "mismatch" => "major",
+          )
+        end
+      end
+    end
+  end
+end
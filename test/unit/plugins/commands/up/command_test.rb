@@ -71,6 +71,55 @@ describe VagrantPlugins::CommandUp::Command do
           subject.execute
         end
       end
+
+      context "with --provider-order set" do
+        let(:argv){ ["--provider-order", "unknown,dummy"] }
+
+        it "should use the first usable provider" do
+          batch = double("environment_batch")
+          expect(iso_env).to receive(:batch).and_yield(batch)
+          expect(batch).to receive(:action) do |machine, action, _|
+            expect(machine.provider_name).to eq(:dummy)
+            expect(action).to eq(:up)
+          end
+          subject.execute
+        end
+      end
+
+      context "with --provider and --provider-order set" do
+        let(:argv){ ["--provider", "dummy", "--provider-order", "dummy"] }
+
+        it "should raise an error" do
+          expect { subject.execute }.to raise_error(Vagrant::Errors::CLIInvalidUsage)
+        end
+      end
+    end
+  end
+
+  context "with --check" do
+    let(:argv) { ["--check"] }
+    let(:vagrantfile_content){ "Vagrant.configure(2){|config| config.vm.box = 'dummy'}" }
+    let(:isolated) { isolated_environment }
+    let(:iso_env) do
+      isolated.vagrantfile(vagrantfile_content)
+      isolated.create_vagrant_env
+    end
+
+    it "does not bring up the machines" do
+      isolated.box3("dummy", "1.0", :dummy)
+      expect(iso_env).not_to receive(:batch)
+      expect(subject.execute).to eq(0)
+    end
+
+    it "fails when a check fails" do
+      expect(iso_env).not_to receive(:batch)
+      expect(subject.execute).to eq(1)
+    end
+
+    it "reports machines which can not be loaded" do
+      allow(subject).to receive(:with_target_vms).
+        and_raise(Vagrant::Errors::ProviderNotUsable, machine: "default", provider: "dummy", message: "nope")
+      expect(subject.execute).to eq(1)
     end
   end
 
@@ -0,0 +1,83 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require File.expand_path("../../../../base", __FILE__)
+
+require Vagrant.source_root.join("plugins/commands/up/precondition_check")
+
+describe VagrantPlugins::CommandUp::PreconditionCheck do
+  include_context "unit"
+
+  let(:vagrantfile_content) do
+    <<-VF
+    Vagrant.configure("2") do |config|
+      config.vm.box = "dummy"
+      config.vm.network "forwarded_port", guest: 80, host: 8080
+      config.vm.network "forwarded_port", guest: 443, host: 8443, auto_correct: true
+    end
+    VF
+  end
+  let(:isolated) { isolated_environment }
+  let(:env) do
+    isolated.vagrantfile(vagrantfile_content)
+    isolated.create_vagrant_env
+  end
+  let(:machine) { env.machine(env.machine_names[0], :dummy) }
+  let(:port_checker) { Vagrant::Action::Builtin::HandleForwardedPortCollisions }
+
+  subject { described_class.new(env) }
+
+  before do
+    allow(port_checker).to receive(:port_check).and_return(false)
+  end
+
+  after do
+    isolated.close
+  end
+
+  it "finds no problems when the machine can be brought up" do
+    isolated.box3("dummy", "1.0", :dummy)
+    expect(subject.check(machine)).to eq([])
+  end
+
+  it "reports a box which is not installed and can not be found" do
+    problems = subject.check(machine)
+    expect(problems.length).to eq(1)
+    expect(problems.first).to include("'dummy'")
+  end
+
+  it "checks a box URL with a HEAD request" do
+    downloader = double("downloader")
+    allow(machine.config.vm).to receive(:box_url).and_return("http://example.com/dummy.box")
+    expect(Vagrant::Util::Downloader).to receive(:new).
+      with("http://example.com/dummy.box", anything, anything).and_return(downloader)
+    expect(downloader).to receive(:head)
+    expect(downloader).not_to receive(:download!)
+
+    expect(subject.check(machine)).to eq([])
+  end
+
+  it "reports forwarded ports which are in use and not corrected" do
+    isolated.box3("dummy", "1.0", :dummy)
+    allow(port_checker).to receive(:port_check).and_return(true)
+
+    problems = subject.check(machine)
+    expect(problems.length).to eq(1)
+    expect(problems.first).to include("8080")
+  end
+
+  it "reports all the problems found" do
+    allow(port_checker).to receive(:port_check).and_return(true)
+    expect(subject.check(machine).length).to eq(2)
+  end
+
+  it "reports synced folders which can not be used" do
+    isolated.box3("dummy", "1.0", :dummy)
+    allow(subject).to receive(:synced_folders).
+      and_raise(Vagrant::Errors::SyncedFolderUnusable, type: "nfs")
+
+    problems = subject.check(machine)
+    expect(problems.length).to eq(1)
+    expect(problems.first).to include("nfs")
+  end
+end
@@ -162,6 +162,43 @@ describe VagrantPlugins::CommandValidate::Command do
       end
     end
 
+    context "with provisioner config of the wrong type" do
+      let(:vagrantfile_content) do
+        <<-VF
+        Vagrant.configure("2") do |config|
+          config.vm.box = "hashicorp/precise64"
+          config.vm.synced_folder ".", "/vagrant", disabled: true
+          config.vm.provision "shell", inline: "echo hi", privileged: "yes"
+        end
+        VF
+      end
+
+      before do
+        allow(machine).to receive(:action_raw)
+      end
+
+      it "warns about the value and succeeds" do
+        expect(machine.ui).to receive(:warn).with(any_args) { |message, _|
+          expect(message).to include("shell provisioner")
+          expect(message).to include("setting 'privileged' must be of type boolean, not String")
+          expect(message).to include("Vagrantfile:4")
+        }
+
+        expect(subject.execute).to eq(0)
+      end
+
+      context "with the strict flag" do
+        let(:argv) { ["--strict"] }
+
+        it "fails validation" do
+          allow(machine.ui).to receive(:warn)
+
+          expect { subject.execute }.
+            to raise_error(Vagrant::Errors::ConfigValidationFindings)
+        end
+      end
+    end
+
     context "no vagrantfile" do
       let(:vagrantfile_content){ "" }
       let(:env) { isolated_environment.create_vagrant_env }
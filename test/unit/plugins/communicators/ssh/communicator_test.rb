@@ -20,6 +20,7 @@ describe VagrantPlugins::CommunicatorSSH::Communicator do
       guest_port: 5986,
       pty: false,
       keep_alive: false,
+      connection_pool: false,
       insert_key: insert_ssh_key,
       export_command_template: export_command_template,
       shell: 'bash -l'
@@ -28,7 +29,8 @@ describe VagrantPlugins::CommunicatorSSH::Communicator do
   # Do not insert public key by default
   let(:insert_ssh_key){ false }
   # Configuration mock
-  let(:config) { double("config", ssh: ssh) }
+  let(:config) { double("config", ssh: ssh, vm: vm) }
+  let(:vm) { double("vm", upload_chunk_size: 1024, upload_checksum_type: nil) }
   # Provider mock
   let(:provider) { double("provider") }
   let(:ui) { Vagrant::UI::Silent.new }
@@ -99,8 +101,13 @@ describe VagrantPlugins::CommunicatorSSH::Communicator do
       and_yield(:stdout, sudo_supported_key_list).and_return(0)
   end
 
+  # Pool of connections shared by communicators
+  let(:connection_pool) { VagrantPlugins::CommunicatorSSH::ConnectionPool.new }
+
   before do
     allow(host).to receive(:capability?).and_return(false)
+    allow(VagrantPlugins::CommunicatorSSH::ConnectionPool).to receive(:instance).
+      and_return(connection_pool)
   end
 
   describe "#wait_for_ready" do
@@ -166,6 +173,82 @@ describe VagrantPlugins::CommunicatorSSH::Communicator do
     end
   end
 
+  describe "#wait_for_ready retry policy" do
+    let(:ssh_info) { {host: '10.1.2.3', port: 22} }
+
+    before do
+      allow(machine).to receive(:ssh_info).and_return(ssh_info)
+      allow(ui).to receive(:detail)
+      allow(communicator).to receive(:ready?).and_return(true)
+    end
+
+    it "should fail fast on authentication failure" do
+      expect(communicator).to receive(:connect).once.
+        and_raise(Vagrant::Errors::SSHAuthenticationFailed)
+      expect { communicator.wait_for_ready(5) }.
+        to raise_error(Vagrant::Errors::SSHAuthenticationFailed)
+    end
+
+    it "should fail fast on handshake failure" do
+      expect(communicator).to receive(:connect).once.
+        and_raise(Vagrant::Errors::NetSSHException, message: "kex failure")
+      expect { communicator.wait_for_ready(5) }.
+        to raise_error(Vagrant::Errors::NetSSHException)
+    end
+
+    it "should keep trying when the connection is refused" do
+      expect(communicator).to receive(:connect).
+        and_raise(Vagrant::Errors::SSHConnectionRefused).twice.ordered
+      expect(communicator).to receive(:connect).ordered
+      expect(communicator.wait_for_ready(5)).to eq(true)
+    end
+
+    context "with a retry delay" do
+      let(:ssh_info) { {host: '10.1.2.3', port: 22, connect_retry_delay: 0.2} }
+
+      it "should wait between connection attempts" do
+        expect(communicator).to receive(:connect).
+          and_raise(Vagrant::Errors::SSHConnectionRefused).ordered
+        expect(communicator).to receive(:connect).ordered
+        expect(communicator).to receive(:sleep).at_least(:once)
+        expect(communicator.wait_for_ready(5)).to eq(true)
+      end
+    end
+
+    it "should stop retrying when interrupted" do
+      allow(Vagrant::Util::Busy).to receive(:busy) { |callback, &block|
+        callback.call
+        block.call
+      }
+      expect(communicator).not_to receive(:connect)
+      expect { communicator.wait_for_ready(5) }.
+        to raise_error(Vagrant::Errors::VagrantInterrupt)
+    end
+  end
+
+  describe "#connect_retry_delay" do
+    let(:ssh_info) { {connect_retry_delay: 2, connect_retry_max_delay: 10} }
+
+    it "should use the configured delay" do
+      expect(communicator.send(:connect_retry_delay, ssh_info, 1)).to eq(2)
+      expect(communicator.send(:connect_retry_delay, ssh_info, 4)).to eq(2)
+    end
+
+    it "should return zero without a configured delay" do
+      expect(communicator.send(:connect_retry_delay, {}, 3)).to eq(0)
+    end
+
+    context "with exponential backoff" do
+      let(:ssh_info) { {connect_retry_delay: 2, connect_retry_max_delay: 10,
+                        connect_retry_backoff: :exponential} }
+
+      it "should double the delay after each attempt up to the max delay" do
+        delays = (1..4).map { |i| communicator.send(:connect_retry_delay, ssh_info, i) }
+        expect(delays).to eq([2, 4, 8, 10])
+      end
+    end
+  end
+
   describe "#reset!" do
     let(:connection) { double("connection") }
 
@@ -176,7 +259,9 @@ describe VagrantPlugins::CommunicatorSSH::Communicator do
     end
 
     it "should close existing connection" do
-      expect(connection).to receive(:close)
+      expect(connection).to receive(:close) do
+        allow(connection).to receive(:closed?).and_return(true)
+      end
       communicator.reset!
     end
 
@@ -702,6 +787,80 @@ describe VagrantPlugins::CommunicatorSSH::Communicator do
         file.delete
       end
     end
+
+    it "does not verify uploaded files without a checksum type" do
+      file = Tempfile.new('vagrant-test')
+      begin
+        expect(scp).to receive(:upload!).with(instance_of(File), '/destination/file')
+        expect_any_instance_of(Vagrant::Util::ChunkedUpload).not_to receive(:verify!)
+        communicator.upload(file.path, '/destination/file')
+      ensure
+        file.delete
+      end
+    end
+
+    it "verifies uploaded files with a checksum type" do
+      allow(vm).to receive(:upload_checksum_type).and_return(:sha256)
+      file = Tempfile.new('vagrant-test')
+      begin
+        expect(scp).to receive(:upload!).with(instance_of(File), '/destination/file')
+        expect_any_instance_of(Vagrant::Util::ChunkedUpload).to receive(:verify!).
+          with(file.path, '/destination/file')
+        communicator.upload(file.path, '/destination/file')
+      ensure
+        file.delete
+      end
+    end
+
+    it "uploads files larger than the chunk size in chunks" do
+      file = Tempfile.new('vagrant-test')
+      begin
+        file.write("x" * 2048)
+        file.close
+        expect(scp).not_to receive(:upload!)
+        expect_any_instance_of(Vagrant::Util::ChunkedUpload).to receive(:upload).
+          with(file.path, '/destination/file')
+        communicator.upload(file.path, '/destination/file')
+      ensure
+        file.delete
+      end
+    end
+  end
+
+  describe "#upload_chunk" do
+    it "uploads the chunk and appends it to the file" do
+      expect(communicator).to receive(:upload).with("/local/chunk", "/remote/file.chunk", verify: false)
+      expect(communicator).to receive(:execute).
+        with("cat /remote/file.chunk >> /remote/file && rm -f /remote/file.chunk")
+      communicator.upload_chunk("/local/chunk", "/remote/file", 1024)
+    end
+
+    it "replaces the file with the first chunk" do
+      allow(communicator).to receive(:upload)
+      expect(communicator).to receive(:execute).with(/cat \/remote\/file.chunk > \/remote\/file/)
+      communicator.upload_chunk("/local/chunk", "/remote/file", 0)
+    end
+  end
+
+  describe "#file_size" do
+    it "returns the size of the file" do
+      expect(communicator).to receive(:execute).with(/wc -c < \/remote\/file/, error_check: false).
+        and_yield(:stdout, "2048\n")
+      expect(communicator.file_size("/remote/file")).to eq(2048)
+    end
+
+    it "returns nil if the file does not exist" do
+      expect(communicator).to receive(:execute)
+      expect(communicator.file_size("/remote/file")).to be_nil
+    end
+  end
+
+  describe "#file_checksum" do
+    it "returns the checksum of the file" do
+      expect(communicator).to receive(:execute).with(/sha256sum \/remote\/file/, error_check: false).
+        and_yield(:stdout, "abc123  /remote/file\n")
+      expect(communicator.file_checksum("/remote/file", :sha256)).to eq("abc123")
+    end
   end
 
   describe "#download" do
@@ -780,6 +939,7 @@ describe VagrantPlugins::CommunicatorSSH::Communicator do
             guest_port: 5986,
             pty: false,
             keep_alive: true,
+            connection_pool: false,
             insert_key: insert_ssh_key,
             export_command_template: export_command_template,
             shell: 'bash -l'
@@ -1096,6 +1256,30 @@ describe VagrantPlugins::CommunicatorSSH::Communicator do
       end
 
     end
+
+    context "with exponential connect retry backoff configured" do
+      before do
+        expect(machine).to receive(:ssh_info).and_return(
+          host: '127.0.0.1',
+          port: 2222,
+          connect_retries: 4,
+          connect_retry_delay: 5,
+          connect_retry_backoff: :exponential,
+          connect_retry_max_delay: 15
+        )
+      end
+
+      it "should increase the sleep between retries up to the max delay" do
+        expect(Net::SSH).to receive(:start).and_raise(Errno::EACCES).thrice
+        expect(Net::SSH).to receive(:start)
+
+        expect(communicator).to receive(:sleep).with(5).ordered
+        expect(communicator).to receive(:sleep).with(10).ordered
+        expect(communicator).to receive(:sleep).with(15).ordered
+
+        communicator.send(:connect)
+      end
+    end
   end
 
   describe "#insecure_key?" do
@@ -1275,4 +1459,72 @@ rekeylimit 0 0"
       end
     end
   end
+
+  describe "connection pooling" do
+    let(:pool) { true }
+    let(:ssh) do
+      double("ssh",
+        timeout: 1,
+        host: nil,
+        port: 5986,
+        guest_port: 5986,
+        pty: false,
+        keep_alive: true,
+        connection_pool: pool,
+        insert_key: insert_ssh_key,
+        export_command_template: export_command_template,
+        shell: 'bash -l'
+      )
+    end
+    let(:other_communicator) { described_class.new(machine) }
+
+    before do
+      allow(machine).to receive(:ssh_info).and_return(machine_ssh_info)
+      allow(connection).to receive(:closed?).and_return(false)
+      allow(connection).to receive(:exec!)
+      allow(connection).to receive(:close)
+    end
+
+    it "shares the connection with communicators for the same target" do
+      expect(Net::SSH).to receive(:start).once.and_return(connection)
+      communicator.send(:connect)
+      expect(other_communicator.send(:connect) { |c| c }).to eq(connection)
+    end
+
+    it "reconnects when the pooled connection is broken" do
+      broken = double("broken", closed?: false, close: nil)
+      expect(broken).to receive(:exec!).and_raise(IOError)
+      expect(Net::SSH).to receive(:start).and_return(broken, connection)
+
+      communicator.send(:connect)
+      expect(other_communicator.send(:connect) { |c| c }).to eq(connection)
+      expect(connection_pool.get(
+        VagrantPlugins::CommunicatorSSH::ConnectionPool.key_for(machine_ssh_info))).
+        to eq(connection)
+    end
+
+    it "removes the connection from the pool on reset" do
+      expect(Net::SSH).to receive(:start).and_return(connection)
+      allow(communicator).to receive(:wait_for_ready)
+      communicator.send(:connect)
+
+      expect(connection).to receive(:close) do
+        allow(connection).to receive(:closed?).and_return(true)
+      end
+      communicator.reset!
+      expect(connection_pool.get(
+        VagrantPlugins::CommunicatorSSH::ConnectionPool.key_for(machine_ssh_info))).
+        to be_nil
+    end
+
+    context "with connection pooling disabled" do
+      let(:pool) { false }
+
+      it "does not share connections" do
+        expect(Net::SSH).to receive(:start).twice.and_return(connection)
+        communicator.send(:connect)
+        other_communicator.send(:connect)
+      end
+    end
+  end
 end
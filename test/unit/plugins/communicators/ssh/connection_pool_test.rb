@@ -0,0 +1,119 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require File.expand_path("../../../../base", __FILE__)
+
+require Vagrant.source_root.join("plugins/communicators/ssh/connection_pool")
+
+describe VagrantPlugins::CommunicatorSSH::ConnectionPool do
+  include_context "unit"
+
+  let(:key) { described_class.key_for(host: "127.0.0.1", port: 2222, username: "vagrant") }
+  let(:connection) { double("connection", closed?: false, close: nil) }
+
+  describe ".key_for" do
+    it "is the same for the same target" do
+      expect(described_class.key_for(host: "127.0.0.1", port: 2222, username: "vagrant")).
+        to eq(key)
+    end
+
+    it "is different for a different port" do
+      expect(described_class.key_for(host: "127.0.0.1", port: 2200, username: "vagrant")).
+        not_to eq(key)
+    end
+  end
+
+  describe "#get" do
+    it "returns nil when there is no connection" do
+      expect(subject.get(key)).to be_nil
+    end
+
+    it "returns the stored connection" do
+      subject.set(key, connection)
+      expect(subject.get(key)).to eq(connection)
+    end
+
+    it "removes closed connections" do
+      subject.set(key, connection)
+      allow(connection).to receive(:closed?).and_return(true)
+      expect(subject.get(key)).to be_nil
+    end
+  end
+
+  describe "#set" do
+    it "closes the previous connection" do
+      previous = double("previous", closed?: false)
+      expect(previous).to receive(:close)
+      subject.set(key, previous)
+      subject.set(key, connection)
+    end
+
+    it "closes the previous connection once another thread stops using it" do
+      previous = double("previous", closed?: false, close: nil)
+      subject.set(key, previous)
+      locked = Queue.new
+      release = Queue.new
+      thread = Thread.new do
+        subject.synchronize(key) do
+          locked << true
+          release.pop
+        end
+      end
+      locked.pop
+
+      subject.set(key, connection)
+      expect(previous).not_to have_received(:close)
+      expect(subject.get(key)).to eq(connection)
+
+      release << true
+      thread.join
+      expect(previous).to have_received(:close)
+    end
+
+    it "closes the previous connection once the outermost lock is released" do
+      previous = double("previous", closed?: false, close: nil)
+      subject.set(key, previous)
+
+      subject.synchronize(key) do
+        subject.synchronize(key) { subject.set(key, connection) }
+        expect(previous).not_to have_received(:close)
+      end
+      expect(previous).to have_received(:close)
+    end
+  end
+
+  describe "#remove" do
+    it "closes the connection" do
+      subject.set(key, connection)
+      expect(connection).to receive(:close)
+      subject.remove(key)
+      expect(subject.get(key)).to be_nil
+    end
+
+    it "does not remove a different connection" do
+      subject.set(key, connection)
+      subject.remove(key, double("other"))
+      expect(subject.get(key)).to eq(connection)
+    end
+  end
+
+  describe "#synchronize" do
+    it "can be nested" do
+      expect(subject.synchronize(key) { subject.synchronize(key) { :result } }).
+        to eq(:result)
+    end
+  end
+
+  describe "#close_all" do
+    it "closes all connections" do
+      other = double("other", closed?: false)
+      subject.set(key, connection)
+      subject.set(described_class.key_for(host: "other"), other)
+
+      expect(connection).to receive(:close)
+      expect(other).to receive(:close)
+      subject.close_all
+      expect(subject.get(key)).to be_nil
+    end
+  end
+end
@@ -0,0 +1,109 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require File.expand_path("../../../../base", __FILE__)
+
+require Vagrant.source_root.join("plugins/communicators/winrm/client_certificate")
+require Vagrant.source_root.join("plugins/communicators/winrm/config")
+
+describe VagrantPlugins::CommunicatorWinRM::ClientCertificate do
+  include_context "unit"
+
+  let(:root_path) { Pathname.new(Dir.mktmpdir("vagrant-test")) }
+  let(:data_dir) { root_path.join("data") }
+  let(:key) { OpenSSL::PKey::RSA.new(2048) }
+  let(:cert) {
+    OpenSSL::X509::Certificate.new.tap do |c|
+      c.version = 2
+      c.serial = 1
+      c.subject = c.issuer = OpenSSL::X509::Name.parse("/CN=vagrant")
+      c.public_key = key.public_key
+      c.not_before = Time.now
+      c.not_after = Time.now + 3600
+      c.sign(key, OpenSSL::Digest::SHA256.new)
+    end
+  }
+  let(:thumbprint) { nil }
+  let(:config) {
+    VagrantPlugins::CommunicatorWinRM::Config.new.tap do |c|
+      c.transport = :ssl
+      c.client_cert = "client.pem"
+      c.client_key = "client.key"
+      c.client_cert_thumbprint = thumbprint
+      c.finalize!
+    end
+  }
+
+  subject { described_class.new(config, root_path: root_path, data_dir: data_dir) }
+
+  before do
+    root_path.join("client.pem").write(cert.to_pem)
+    root_path.join("client.key").write(key.to_pem)
+  end
+
+  after { FileUtils.rm_rf(root_path) }
+
+  describe ".thumbprint" do
+    it "is the uppercase SHA1 digest of the certificate" do
+      expect(described_class.thumbprint(cert)).to eq(
+        OpenSSL::Digest::SHA1.hexdigest(cert.to_der).upcase)
+    end
+  end
+
+  describe "#resolve" do
+    it "uses the configured files" do
+      subject.resolve
+      expect(subject.cert_path).to eq(root_path.join("client.pem").to_s)
+      expect(subject.key_path).to eq(root_path.join("client.key").to_s)
+    end
+
+    context "with the thumbprint of the certificate" do
+      let(:thumbprint) { described_class.thumbprint(cert).downcase }
+
+      it "uses the configured files" do
+        expect { subject.resolve }.not_to raise_error
+      end
+    end
+
+    context "with another thumbprint" do
+      let(:thumbprint) { "ab" * 20 }
+
+      it "raises an error" do
+        expect { subject.resolve }.to raise_error(
+          VagrantPlugins::CommunicatorWinRM::Errors::ClientCertificateMismatch)
+      end
+    end
+
+    context "without certificate files" do
+      let(:thumbprint) { described_class.thumbprint(cert) }
+      let(:config) {
+        VagrantPlugins::CommunicatorWinRM::Config.new.tap do |c|
+          c.transport = :ssl
+          c.client_cert_thumbprint = thumbprint
+          c.finalize!
+        end
+      }
+
+      it "exports the certificate from the certificate store" do
+        expect(Vagrant::Util::PowerShell).to receive(:execute_cmd) do |command|
+          path = command[/WriteAllBytes\('([^']+)'/, 1]
+          pass = command[/Export\('Pfx', '([^']+)'\)/, 1]
+          File.binwrite(path, OpenSSL::PKCS12.create(pass, "vagrant", key, cert).to_der)
+          ""
+        end
+
+        subject.resolve
+        expect(File.read(subject.cert_path)).to eq(cert.to_pem)
+        expect(OpenSSL::PKey::RSA.new(File.read(subject.key_path), subject.key_pass).to_pem).
+          to eq(key.to_pem)
+        expect(data_dir.join("winrm", "client.pfx")).not_to exist
+      end
+
+      it "raises an error when the certificate cannot be exported" do
+        expect(Vagrant::Util::PowerShell).to receive(:execute_cmd).and_return(nil)
+        expect { subject.resolve }.to raise_error(
+          VagrantPlugins::CommunicatorWinRM::Errors::ClientCertificateNotFound)
+      end
+    end
+  end
+end
@@ -9,7 +9,8 @@ describe VagrantPlugins::CommunicatorWinRM::Communicator do
   include_context "unit"
 
   let(:winrm) { double("winrm", timeout: 1, host: nil, port: 5986, guest_port: 5986) }
-  let(:config) { double("config", winrm: winrm) }
+  let(:config) { double("config", winrm: winrm, vm: vm) }
+  let(:vm) { double("vm", upload_chunk_size: 1024, upload_checksum_type: nil) }
   let(:provider) { double("provider") }
   let(:ui) { Vagrant::UI::Silent.new }
   let(:machine) { double("machine", config: config, provider: provider, ui: ui) }
@@ -152,6 +153,70 @@ describe VagrantPlugins::CommunicatorWinRM::Communicator do
       expect(shell).to receive(:upload).with("from", "to")
       subject.upload("from", "to")
     end
+
+    context "with a checksum type" do
+      let(:file) { Tempfile.new("vagrant-test") }
+
+      before do
+        allow(vm).to receive(:upload_checksum_type).and_return(:sha256)
+        file.close
+      end
+
+      after { file.unlink }
+
+      it "verifies the uploaded file" do
+        expect(shell).to receive(:upload).with(file.path, "C:/file")
+        expect_any_instance_of(Vagrant::Util::ChunkedUpload).to receive(:verify!).
+          with(file.path, "C:/file")
+        subject.upload(file.path, "C:/file")
+      end
+
+      it "does not verify files uploaded to a directory" do
+        expect(shell).to receive(:upload).with(file.path, "C:/dir/")
+        expect_any_instance_of(Vagrant::Util::ChunkedUpload).not_to receive(:verify!)
+        subject.upload(file.path, "C:/dir/")
+      end
+    end
+
+    context "with a file larger than the chunk size" do
+      let(:file) { Tempfile.new("vagrant-test") }
+
+      before do
+        file.write("x" * 2048)
+        file.close
+      end
+
+      after { file.unlink }
+
+      it "uploads the file in chunks" do
+        expect(shell).not_to receive(:upload)
+        expect_any_instance_of(Vagrant::Util::ChunkedUpload).to receive(:upload).
+          with(file.path, "C:/file").and_return(true)
+        subject.upload(file.path, "C:/file")
+      end
+
+      it "does not upload the file in chunks to a directory" do
+        expect(shell).to receive(:upload).with(file.path, "C:/dir/")
+        subject.upload(file.path, "C:/dir/")
+      end
+    end
+  end
+
+  describe ".upload_chunk" do
+    it "uploads the chunk and writes it to the file" do
+      expect(shell).to receive(:upload).with("chunk", "C:/file.chunk")
+      expect(shell).to receive(:powershell).with(/SetLength\(1024\)/, anything).
+        and_return(double("output", exitcode: 0))
+      subject.upload_chunk("chunk", "C:/file", 1024)
+    end
+  end
+
+  describe ".file_size" do
+    it "returns the size of the file" do
+      expect(shell).to receive(:powershell).with(/Get-Item -LiteralPath 'C:\/file'/, anything).
+        and_yield(:stdout, "2048\r\n").and_return(double("output", exitcode: 0))
+      expect(subject.file_size("C:/file")).to eq(2048)
+    end
   end
 
   describe ".download" do
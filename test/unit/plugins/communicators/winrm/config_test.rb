@@ -6,7 +6,11 @@ require File.expand_path("../../../../base", __FILE__)
 require Vagrant.source_root.join("plugins/communicators/winrm/config")
 
 describe VagrantPlugins::CommunicatorWinRM::Config do
-  let(:machine) { double("machine") }
+  include_context "unit"
+
+  let(:machine) { double("machine", env: env) }
+  let(:env) { double("env", root_path: root_path) }
+  let(:root_path) { temporary_dir }
 
   subject { described_class.new }
 
@@ -15,4 +19,95 @@ describe VagrantPlugins::CommunicatorWinRM::Config do
     result = subject.validate(machine)
     expect(result["WinRM"]).to be_empty
   end
+
+  it "converts the transport to a symbol" do
+    subject.transport = "ntlm"
+    subject.finalize!
+    expect(subject.transport).to eq(:ntlm)
+  end
+
+  it "uses the ssl port when transport is a string" do
+    subject.transport = "ssl"
+    subject.finalize!
+    expect(subject.port).to eq(5986)
+  end
+
+  it "is invalid with an unknown transport" do
+    subject.transport = :kerberos
+    subject.finalize!
+    result = subject.validate(machine)
+    expect(result["WinRM"]).not_to be_empty
+  end
+
+  context "with only a client certificate thumbprint" do
+    before do
+      subject.transport = :ssl
+      subject.client_cert_thumbprint = "ab" * 20
+      subject.finalize!
+    end
+
+    it "is valid on Windows hosts" do
+      allow(Vagrant::Util::Platform).to receive(:windows?).and_return(true)
+      result = subject.validate(machine)
+      expect(result["WinRM"]).to be_empty
+    end
+
+    it "is invalid on other hosts" do
+      allow(Vagrant::Util::Platform).to receive(:windows?).and_return(false)
+      result = subject.validate(machine)
+      expect(result["WinRM"]).not_to be_empty
+    end
+  end
+
+  context "with a client certificate" do
+    before do
+      File.write(root_path.join("client.pem"), "")
+      File.write(root_path.join("client.key"), "")
+      subject.transport = :ssl
+      subject.client_cert = "client.pem"
+      subject.client_key = "client.key"
+    end
+
+    it "is valid with files relative to the root path" do
+      subject.finalize!
+      result = subject.validate(machine)
+      expect(result["WinRM"]).to be_empty
+    end
+
+    it "is invalid without the ssl transport" do
+      subject.transport = :negotiate
+      subject.finalize!
+      result = subject.validate(machine)
+      expect(result["WinRM"]).not_to be_empty
+    end
+
+    it "is invalid without a client key" do
+      subject.client_key = nil
+      subject.finalize!
+      result = subject.validate(machine)
+      expect(result["WinRM"]).not_to be_empty
+    end
+
+    it "is valid with a thumbprint" do
+      subject.client_cert_thumbprint = "AB " * 20
+      subject.finalize!
+      expect(subject.client_cert_thumbprint).to eq("AB" * 20)
+      result = subject.validate(machine)
+      expect(result["WinRM"]).to be_empty
+    end
+
+    it "is invalid with a malformed thumbprint" do
+      subject.client_cert_thumbprint = "abc"
+      subject.finalize!
+      result = subject.validate(machine)
+      expect(result["WinRM"]).not_to be_empty
+    end
+
+    it "is invalid when the certificate does not exist" do
+      subject.client_cert = "missing.pem"
+      subject.finalize!
+      result = subject.validate(machine)
+      expect(result["WinRM"]).not_to be_empty
+    end
+  end
 end
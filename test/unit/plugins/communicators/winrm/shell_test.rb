@@ -197,6 +197,46 @@ describe VagrantPlugins::CommunicatorWinRM::WinRMShell do
       expect { subject.wql("select * from Win32_OperatingSystem") }.to raise_error(
         VagrantPlugins::CommunicatorWinRM::Errors::AuthenticationFailed)
     end
+
+    it "should raise a transport negotiation error when the service is unavailable" do
+      expect(connection).to receive(:run_wql).with("select * from Win32_OperatingSystem").and_raise(
+        WinRM::WinRMHTTPTransportError.new("Bad HTTP response", 503)
+      )
+      expect { subject.wql("select * from Win32_OperatingSystem") }.to raise_error(
+        VagrantPlugins::CommunicatorWinRM::Errors::TransportNegotiationFailed)
+    end
+
+    it "should raise an execution error on other server errors" do
+      expect(connection).to receive(:run_wql).with("select * from Win32_OperatingSystem").and_raise(
+        WinRM::WinRMHTTPTransportError.new("Bad HTTP response", 500)
+      )
+      expect { subject.wql("select * from Win32_OperatingSystem") }.to raise_error(
+        VagrantPlugins::CommunicatorWinRM::Errors::ExecutionError)
+    end
+
+    it "should raise an untrusted certificate error when verification fails" do
+      expect(connection).to receive(:run_wql).with("select * from Win32_OperatingSystem").and_raise(
+        OpenSSL::SSL::SSLError.new("SSL_connect returned=1 errno=0 state=error: certificate verify failed")
+      )
+      expect { subject.wql("select * from Win32_OperatingSystem") }.to raise_error(
+        VagrantPlugins::CommunicatorWinRM::Errors::SSLUntrustedCertificate)
+    end
+
+    it "should raise a transport negotiation error when the handshake is interrupted" do
+      expect(connection).to receive(:run_wql).with("select * from Win32_OperatingSystem").and_raise(
+        OpenSSL::SSL::SSLError.new("SSL_connect SYSCALL returned=5 errno=0 state=unknown state")
+      )
+      expect { subject.wql("select * from Win32_OperatingSystem") }.to raise_error(
+        VagrantPlugins::CommunicatorWinRM::Errors::TransportNegotiationFailed)
+    end
+
+    it "should raise an ssl error on other ssl errors" do
+      expect(connection).to receive(:run_wql).with("select * from Win32_OperatingSystem").and_raise(
+        OpenSSL::SSL::SSLError.new("SSL_connect returned=1 errno=0 state=error: no protocols available")
+      )
+      expect { subject.wql("select * from Win32_OperatingSystem") }.to raise_error(
+        VagrantPlugins::CommunicatorWinRM::Errors::SSLError)
+    end
   end
 
   describe ".endpoint" do
@@ -229,6 +269,18 @@ describe VagrantPlugins::CommunicatorWinRM::WinRMShell do
         expect(subject.send(:endpoint)).to eq("http://localhost:5985/wsman")
       end
     end
+
+    context "when transport is :ntlm" do
+      let(:config)  {
+        VagrantPlugins::CommunicatorWinRM::Config.new.tap do |c|
+          c.transport = :ntlm
+          c.finalize!
+        end
+      }
+      it "should create winrm endpoint address using http" do
+        expect(subject.send(:endpoint)).to eq("http://localhost:5985/wsman")
+      end
+    end
   end
 
   describe ".endpoint_options" do
@@ -239,6 +291,58 @@ describe VagrantPlugins::CommunicatorWinRM::WinRMShell do
           basic_auth_only: false, no_ssl_peer_verification: false,
           retry_delay: 1, retry_limit: 2, transport: :negotiate })
     end
+
+    context "when transport is :ntlm" do
+      before { config.transport = :ntlm }
+
+      it "should use the negotiate transport" do
+        expect(subject.send(:endpoint_options)[:transport]).to eq(:negotiate)
+      end
+    end
+
+    context "with a client certificate" do
+      let(:config)  {
+        VagrantPlugins::CommunicatorWinRM::Config.new.tap do |c|
+          c.transport = "ssl"
+          c.client_cert = "/certs/client.pem"
+          c.client_key = "/certs/client.key"
+          c.client_key_pass = "secret"
+          c.finalize!
+        end
+      }
+
+      it "should include the client certificate options" do
+        options = subject.send(:endpoint_options)
+        expect(options[:transport]).to eq(:certificate)
+        expect(options[:client_cert]).to eq("/certs/client.pem")
+        expect(options[:client_key]).to eq("/certs/client.key")
+        expect(options[:key_pass]).to eq("secret")
+      end
+    end
+
+    context "with a client certificate thumbprint" do
+      let(:data_dir) { Pathname.new(Dir.mktmpdir("vagrant-test")) }
+      let(:config)  {
+        VagrantPlugins::CommunicatorWinRM::Config.new.tap do |c|
+          c.transport = :ssl
+          c.client_cert_thumbprint = "ab" * 20
+          c.finalize!
+        end
+      }
+
+      subject do
+        described_class.new('localhost', port, config, data_dir: data_dir)
+      end
+
+      after { FileUtils.rm_rf(data_dir) }
+
+      it "should export the certificate from the certificate store" do
+        expect(Vagrant::Util::PowerShell).to receive(:execute_cmd).
+          with(/Cert:\\CurrentUser\\My\\#{"AB" * 20}/).and_return(nil)
+        expect { subject.send(:endpoint_options) }.to raise_error(
+          VagrantPlugins::CommunicatorWinRM::Errors::ClientCertificateNotFound)
+      end
+    end
   end
 
   describe "#elevated_username" do
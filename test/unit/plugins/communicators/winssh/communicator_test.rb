@@ -33,7 +33,8 @@ describe VagrantPlugins::CommunicatorWinSSH::Communicator do
     )
   end
   # Configuration mock
-  let(:config) { double("config", winssh: winssh, ssh: ssh) }
+  let(:config) { double("config", winssh: winssh, ssh: ssh, vm: vm) }
+  let(:vm) { double("vm", upload_chunk_size: 1024, upload_checksum_type: nil) }
   # Provider mock
   let(:provider) { double("provider") }
   let(:ui) { Vagrant::UI::Silent.new }
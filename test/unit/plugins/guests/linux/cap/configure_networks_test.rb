@@ -0,0 +1,64 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require_relative "../../../../base"
+
+describe "VagrantPlugins::GuestLinux::Cap::ConfigureNetworks" do
+  let(:caps) do
+    VagrantPlugins::GuestLinux::Plugin
+      .components
+      .guest_capabilities[:linux]
+  end
+
+  let(:cap) { caps.get(:configure_networks) }
+  let(:comm) { VagrantTests::DummyCommunicator::Communicator.new(machine) }
+  let(:machine) { double("machine", ui: ui) }
+  let(:ui) { Vagrant::UI::Silent.new }
+  let(:networks) { [{interface: 0, type: "dhcp"}] }
+
+  before do
+    allow(machine).to receive(:communicate).and_return(comm)
+  end
+
+  after do
+    comm.verify_expectations!
+  end
+
+  context "when NetworkManager is active" do
+    before do
+      comm.stub_command("systemctl -q is-active NetworkManager.service", exit_code: 0)
+      comm.stub_command("command -v nmcli", exit_code: 0)
+      comm.stub_command("systemctl -q is-active systemd-networkd.service", exit_code: 0)
+    end
+
+    it "configures networks with NetworkManager" do
+      expect(cap).to receive(:configure_network_manager).with(machine, networks)
+      expect(cap).not_to receive(:configure_networkd)
+      cap.configure_networks(machine, networks)
+    end
+  end
+
+  context "when systemd-networkd is active" do
+    before do
+      comm.stub_command("systemctl -q is-active systemd-networkd.service", exit_code: 0)
+    end
+
+    it "configures networks with systemd-networkd" do
+      expect(cap).to receive(:configure_networkd).with(machine, networks)
+      cap.configure_networks(machine, networks)
+    end
+  end
+
+  context "when systemd is installed without an active network configurator" do
+    before do
+      comm.stub_command("ps -o comm= 1 | grep systemd", exit_code: 0)
+    end
+
+    it "warns the user" do
+      expect(cap).not_to receive(:configure_networkd)
+      expect(cap).not_to receive(:configure_network_manager)
+      expect(ui).to receive(:warn).with(/could not detect an active network configurator/)
+      cap.configure_networks(machine, networks)
+    end
+  end
+end
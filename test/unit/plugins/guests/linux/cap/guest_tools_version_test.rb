@@ -0,0 +1,38 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require_relative "../../../../base"
+
+describe "VagrantPlugins::GuestLinux::Cap::GuestToolsVersion" do
+  let(:caps) do
+    VagrantPlugins::GuestLinux::Plugin
+      .components
+      .guest_capabilities[:linux]
+  end
+
+  let(:machine) { double("machine") }
+  let(:comm) { VagrantTests::DummyCommunicator::Communicator.new(machine) }
+
+  before do
+    allow(machine).to receive(:communicate).and_return(comm)
+  end
+
+  describe ".guest_tools_version" do
+    let(:cap) { caps.get(:guest_tools_version) }
+
+    it "returns the open-vm-tools version" do
+      comm.stub_command("vmware-toolbox-cmd -v", stdout: "12.1.5.49591 (build-20735119)\n", exit_code: 0)
+      expect(cap.guest_tools_version(machine, :vmware_desktop)).to eq("12.1.5")
+    end
+
+    it "returns nil when the guest tools are not installed" do
+      comm.stub_command("vmware-toolbox-cmd -v", exit_code: 127)
+      expect(cap.guest_tools_version(machine, :vmware_desktop)).to be_nil
+    end
+
+    it "returns nil for other providers" do
+      expect(cap.guest_tools_version(machine, :virtualbox)).to be_nil
+      expect(cap.guest_tools_version(machine, :docker)).to be_nil
+    end
+  end
+end
@@ -0,0 +1,34 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require_relative "../../../../base"
+
+describe "VagrantPlugins::GuestLinux::Cap::MachineId" do
+  let(:caps) do
+    VagrantPlugins::GuestLinux::Plugin
+      .components
+      .guest_capabilities[:linux]
+  end
+
+  let(:machine) { double("machine") }
+  let(:comm) { VagrantTests::DummyCommunicator::Communicator.new(machine) }
+
+  before do
+    allow(machine).to receive(:communicate).and_return(comm)
+  end
+
+  after do
+    comm.verify_expectations!
+  end
+
+  describe ".regenerate_machine_id" do
+    let(:cap) { caps.get(:regenerate_machine_id) }
+
+    it "replaces the machine ID" do
+      cap.regenerate_machine_id(machine)
+      expect(comm.received_commands[0]).to match(/rm -f \/etc\/machine-id/)
+      expect(comm.received_commands[0]).to match(/systemd-machine-id-setup/)
+      expect(comm.received_commands[0]).to match(/dbus-uuidgen --ensure=\/etc\/machine-id/)
+    end
+  end
+end
@@ -78,6 +78,33 @@ describe "VagrantPlugins::GuestLinux::Cap::MountNFS" do
         "/sbin/initctl emit --no-wait vagrant-mounted MOUNTPOINT=#{guestpath}")
     end
 
+    context "when read-only" do
+      let(:folders) do
+        {
+          "/vagrant-nfs" => {
+            type: :nfs,
+            guestpath: "/guest",
+            hostpath: "/host",
+            readonly: true,
+          }
+        }
+      end
+
+      it "mounts the folder read-only" do
+        comm.stub_command("cat /proc/mounts", stdout: "#{ip}:/host /guest nfs ro,relatime 0 0\n")
+        cap.mount_nfs_folder(machine, ip, folders)
+
+        expect(comm.received_commands[1]).to match(/mount -o ro /)
+      end
+
+      it "raises an error if the folder is not mounted read-only" do
+        comm.stub_command("cat /proc/mounts", stdout: "#{ip}:/host /guest nfs rw,relatime 0 0\n")
+
+        expect { cap.mount_nfs_folder(machine, ip, folders) }.
+          to raise_error(Vagrant::Errors::SyncedFolderReadonlyFailed)
+      end
+    end
+
     it "escapes host and guest paths" do
       folders = {
         "/vagrant-nfs" => {
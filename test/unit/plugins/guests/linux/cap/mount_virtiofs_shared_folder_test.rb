@@ -0,0 +1,105 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require_relative "../../../../base"
+
+describe "VagrantPlugins::GuestLinux::Cap::MountVirtiofsSharedFolder" do
+  let(:caps) do
+    VagrantPlugins::GuestLinux::Plugin
+      .components
+      .guest_capabilities[:linux]
+  end
+
+  let(:machine) { double("machine") }
+  let(:guest) { double("guest") }
+  let(:comm) { VagrantTests::DummyCommunicator::Communicator.new(machine) }
+  let(:mount_tag) { "vagrant" }
+  let(:mount_guest_path) { "/vagrant" }
+  let(:folder_options) { { hostpath: "/host/directory/path" } }
+
+  before do
+    allow(machine).to receive(:communicate).and_return(comm)
+    allow(machine).to receive(:guest).and_return(guest)
+    allow(guest).to receive(:capability).with(:shell_expand_guest_path, mount_guest_path).
+      and_return(mount_guest_path)
+  end
+
+  after do
+    comm.verify_expectations!
+  end
+
+  describe ".mount_virtiofs_shared_folder" do
+    let(:cap) { caps.get(:mount_virtiofs_shared_folder) }
+
+    before do
+      allow(comm).to receive(:sudo).with(any_args).and_return(0)
+    end
+
+    it "creates the directory and mounts the tag" do
+      expect(comm).to receive(:sudo).with("mkdir -p #{mount_guest_path}")
+      expect(comm).to receive(:sudo).
+        with("mount -t virtiofs #{mount_tag} #{mount_guest_path}", error_check: false).
+        and_return(0)
+
+      cap.mount_virtiofs_shared_folder(machine, mount_tag, mount_guest_path, folder_options)
+    end
+
+    it "includes the mount options" do
+      folder_options[:mount_options] = ["ro", "noatime"]
+      expect(comm).to receive(:sudo).
+        with("mount -t virtiofs -o ro,noatime #{mount_tag} #{mount_guest_path}", error_check: false).
+        and_return(0)
+
+      cap.mount_virtiofs_shared_folder(machine, mount_tag, mount_guest_path, folder_options)
+    end
+
+    it "enables dax" do
+      folder_options[:virtiofs_dax] = true
+      expect(comm).to receive(:sudo).with(/-o dax /, error_check: false).and_return(0)
+
+      cap.mount_virtiofs_shared_folder(machine, mount_tag, mount_guest_path, folder_options)
+    end
+
+    it "sets the dax mode" do
+      folder_options[:virtiofs_dax] = "inode"
+      expect(comm).to receive(:sudo).with(/-o dax=inode /, error_check: false).and_return(0)
+
+      cap.mount_virtiofs_shared_folder(machine, mount_tag, mount_guest_path, folder_options)
+    end
+
+    it "skips folders which are already mounted" do
+      comm.stub_command("mountpoint -q #{mount_guest_path}", exit_code: 0)
+      expect(comm).not_to receive(:sudo).with(/mount -t virtiofs/, any_args)
+
+      cap.mount_virtiofs_shared_folder(machine, mount_tag, mount_guest_path, folder_options)
+    end
+
+    it "raises an error when the mount fails" do
+      allow(cap).to receive(:sleep)
+      expect(comm).to receive(:sudo).with(/mount -t virtiofs/, error_check: false).
+        and_return(32).exactly(3).times
+
+      expect {
+        cap.mount_virtiofs_shared_folder(machine, mount_tag, mount_guest_path, folder_options)
+      }.to raise_error(Vagrant::Errors::LinuxMountFailed)
+    end
+  end
+
+  describe ".unmount_virtiofs_shared_folder" do
+    let(:cap) { caps.get(:unmount_virtiofs_shared_folder) }
+
+    it "unmounts and removes the directory" do
+      comm.stub_command("mountpoint -q #{mount_guest_path}", exit_code: 0)
+      expect(comm).to receive(:sudo).with("umount #{mount_guest_path}", error_check: false).and_return(0)
+      expect(comm).to receive(:sudo).with("rmdir #{mount_guest_path}", error_check: false)
+
+      cap.unmount_virtiofs_shared_folder(machine, mount_guest_path, folder_options)
+    end
+
+    it "does nothing when the folder is not mounted" do
+      expect(comm).not_to receive(:sudo)
+
+      cap.unmount_virtiofs_shared_folder(machine, mount_guest_path, folder_options)
+    end
+  end
+end
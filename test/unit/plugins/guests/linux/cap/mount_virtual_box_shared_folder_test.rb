@@ -65,6 +65,29 @@ describe "VagrantPlugins::GuestLinux::Cap::MountVirtualBoxSharedFolder" do
       cap.mount_virtualbox_shared_folder(machine, mount_name, mount_guest_path, folder_options)
     end
 
+    context "when read-only" do
+      before do
+        folder_options[:readonly] = true
+        allow(folder_plugin).to receive(:capability).with(:mount_options, mount_name, mount_guest_path, folder_options).
+          and_return(["uid=#{mount_uid},gid=#{mount_gid},ro", mount_uid, mount_gid])
+        allow(folder_plugin).to receive(:capability).with(:mount_type).and_return("vboxsf")
+      end
+
+      it "verifies the folder is mounted read-only" do
+        comm.stub_command("cat /proc/mounts", stdout: "#{mount_name} #{mount_guest_path} vboxsf ro,relatime 0 0\n")
+        expect(comm).not_to receive(:sudo).with(/chown/)
+
+        cap.mount_virtualbox_shared_folder(machine, mount_name, mount_guest_path, folder_options)
+      end
+
+      it "raises an error if the folder is not mounted read-only" do
+        comm.stub_command("cat /proc/mounts", stdout: "#{mount_name} #{mount_guest_path} vboxsf rw,relatime 0 0\n")
+
+        expect { cap.mount_virtualbox_shared_folder(machine, mount_name, mount_guest_path, folder_options) }.
+          to raise_error(Vagrant::Errors::SyncedFolderReadonlyFailed)
+      end
+    end
+
     context "with upstart init" do
 
       it "emits mount event" do
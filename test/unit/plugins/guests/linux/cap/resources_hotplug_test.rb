@@ -0,0 +1,43 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require_relative "../../../../base"
+
+describe "VagrantPlugins::GuestLinux::Cap::ResourcesHotplug" do
+  let(:caps) do
+    VagrantPlugins::GuestLinux::Plugin
+      .components
+      .guest_capabilities[:linux]
+  end
+
+  let(:machine) { double("machine") }
+  let(:comm) { VagrantTests::DummyCommunicator::Communicator.new(machine) }
+  let(:cpu_check) { "ls /sys/devices/system/cpu/cpu*/online" }
+  let(:memory_check) { "grep -q '^online' /sys/devices/system/memory/auto_online_blocks" }
+
+  before do
+    allow(machine).to receive(:communicate).and_return(comm)
+  end
+
+  describe ".resources_hotplug" do
+    let(:cap) { caps.get(:resources_hotplug) }
+
+    it "returns cpus and memory when supported" do
+      comm.stub_command(cpu_check, exit_code: 0)
+      comm.stub_command(memory_check, exit_code: 0)
+      expect(cap.resources_hotplug(machine)).to eq([:cpus, :memory])
+    end
+
+    it "does not return memory when blocks are not onlined automatically" do
+      comm.stub_command(cpu_check, exit_code: 0)
+      comm.stub_command(memory_check, exit_code: 1)
+      expect(cap.resources_hotplug(machine)).to eq([:cpus])
+    end
+
+    it "returns nothing when not supported" do
+      comm.stub_command(cpu_check, exit_code: 2)
+      comm.stub_command(memory_check, exit_code: 2)
+      expect(cap.resources_hotplug(machine)).to be_empty
+    end
+  end
+end
@@ -0,0 +1,70 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require_relative "../../../../base"
+
+describe "VagrantPlugins::GuestLinux::Cap::SyncTime" do
+  let(:caps) do
+    VagrantPlugins::GuestLinux::Plugin
+      .components
+      .guest_capabilities[:linux]
+  end
+
+  let(:machine) { double("machine") }
+  let(:comm) { VagrantTests::DummyCommunicator::Communicator.new(machine) }
+  let(:cap) { caps.get(:sync_time) }
+
+  before do
+    allow(machine).to receive(:communicate).and_return(comm)
+  end
+
+  after do
+    comm.verify_expectations!
+  end
+
+  describe ".sync_time" do
+    context "with chrony" do
+      before do
+        comm.stub_command("chronyc tracking", exit_code: 0,
+          stdout: "Reference ID    : 0A000202\nSystem time     : 12.500 seconds slow of NTP time\n")
+      end
+
+      it "steps the clock" do
+        comm.expect_command("chronyc makestep")
+        cap.sync_time(machine)
+      end
+
+      it "reports the offsets" do
+        expect(cap.sync_time(machine)).to eq(
+          before: "12.500 seconds slow of NTP time",
+          after: "12.500 seconds slow of NTP time",
+        )
+      end
+    end
+
+    context "with systemd-timesyncd" do
+      before do
+        comm.stub_command("ps -o comm= 1 | grep systemd", exit_code: 0)
+        comm.stub_command("systemctl -q is-active systemd-timesyncd", exit_code: 0)
+        comm.stub_command("timedatectl timesync-status", exit_code: 0,
+          stdout: "       Server: 10.0.2.3\n       Offset: -1min 2.5s\n")
+      end
+
+      it "restarts the service" do
+        comm.expect_command("systemctl restart systemd-timesyncd")
+        cap.sync_time(machine)
+      end
+
+      it "reports the offset before synchronizing" do
+        expect(cap.sync_time(machine)).to eq(before: "-1min 2.5s", after: nil)
+      end
+    end
+
+    context "without a time service" do
+      it "sets the clock from the hardware clock" do
+        comm.expect_command("hwclock --hctosys")
+        expect(cap.sync_time(machine)).to eq(before: nil, after: nil)
+      end
+    end
+  end
+end
@@ -61,6 +61,30 @@ describe "VagrantPlugins::GuestRedHat::Cap::ConfigureNetworks" do
     end
   end
 
+  context "with systemd-networkd active" do
+    let(:cap) { caps.get(:configure_networks) }
+
+    let(:network_1) do
+      {
+        interface: 0,
+        type: "dhcp",
+      }
+    end
+
+    before do
+      allow(guest).to receive(:capability)
+                        .with(:network_scripts_dir)
+                        .and_return("/network-scripts")
+      comm.stub_command("systemctl -q is-active systemd-networkd.service", exit_code: 0)
+    end
+
+    it "should configure with systemd-networkd" do
+      expect(cap).to receive(:configure_networkd).with(machine, [network_1])
+      expect(cap).not_to receive(:configure_networks_legacy)
+      cap.configure_networks(machine, [network_1])
+    end
+  end
+
   describe ".configure_networks" do
     context "when version is less than 10" do
       let(:cap) { caps.get(:configure_networks) }
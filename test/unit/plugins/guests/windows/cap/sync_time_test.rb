@@ -0,0 +1,42 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require_relative "../../../../base"
+
+require Vagrant.source_root.join("plugins/guests/windows/cap/sync_time")
+
+describe "VagrantPlugins::GuestWindows::Cap::SyncTime" do
+  let(:described_class) do
+    VagrantPlugins::GuestWindows::Plugin.components.guest_capabilities[:windows].get(:sync_time)
+  end
+  let(:machine) { double("machine") }
+  let(:communicator) { VagrantTests::DummyCommunicator::Communicator.new(machine) }
+
+  before do
+    allow(machine).to receive(:communicate).and_return(communicator)
+  end
+
+  after do
+    communicator.verify_expectations!
+  end
+
+  describe ".sync_time" do
+    it "starts the Windows Time service" do
+      communicator.expect_command("Start-Service -Name w32time")
+      described_class.sync_time(machine)
+    end
+
+    it "resynchronizes the clock" do
+      communicator.expect_command("w32tm /resync /force\nif ($LASTEXITCODE -ne 0) { exit $LASTEXITCODE }\n")
+      described_class.sync_time(machine)
+    end
+
+    it "reports the offsets" do
+      communicator.stub_command("w32tm /query /status /verbose",
+        stdout: "Leap Indicator: 0(no warning)\nPhase Offset: 0.0012345s\n")
+
+      expect(described_class.sync_time(machine)).to eq(
+        before: "0.0012345s", after: "0.0012345s")
+    end
+  end
+end
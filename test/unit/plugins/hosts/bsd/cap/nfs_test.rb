@@ -18,7 +18,10 @@ describe VagrantPlugins::HostBSD::Cap::NFS do
 
     before do
       allow(host).to receive(:capability).and_return("")
-      allow(Vagrant::Util::TemplateRenderer).to receive(:render).and_return("")
+      allow(Vagrant::Util::TemplateRenderer).to receive(:render).and_return("/vagrant -alldirs\n")
+      allow(described_class).to receive(:nfs_exports_content).and_return("")
+      allow(described_class).to receive(:nfs_exports_lock_path).
+        and_return(temporary_dir.join("nfs-exports.lock"))
       allow(described_class).to receive(:sleep)
       allow(described_class).to receive(:nfs_cleanup)
       allow(described_class).to receive(:system)
@@ -72,5 +75,35 @@ describe VagrantPlugins::HostBSD::Cap::NFS do
         described_class.nfs_export(environment, ui, id, ips, folders)
       end
     end
+
+    context "when the exports are unchanged" do
+      before do
+        allow(described_class).to receive(:nfs_exports_content).and_return(<<-EOH)
+# VAGRANT-BEGIN: #{Process.uid} #{id}
+/vagrant -alldirs
+# VAGRANT-END: #{Process.uid} #{id}
+EOH
+      end
+
+      it "does not edit the exports or update nfsd" do
+        expect(described_class).not_to receive(:nfs_cleanup)
+        expect(described_class).not_to receive(:system)
+        described_class.nfs_export(environment, ui, id, ips, folders)
+      end
+
+      it "does not check the status of nfsd" do
+        expect(described_class).not_to receive(:nfs_running?)
+        described_class.nfs_export(environment, ui, id, ips, folders)
+      end
+    end
+
+    it "updates nfsd once for a batch of machines" do
+      expect(host).to receive(:capability).with(:nfs_update_command).and_return(["update"])
+      expect(described_class).to receive(:nfs_cleanup).with("first")
+      expect(described_class).to receive(:nfs_cleanup).with("second")
+      expect(described_class).to receive(:system).with("update").once
+      described_class.nfs_export_batch(environment, ui,
+        [["first", ips, folders], ["second", ips, folders]])
+    end
   end
 end
@@ -0,0 +1,58 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require_relative "../../../../base"
+
+require_relative "../../../../../../plugins/hosts/linux/cap/hosts"
+
+describe VagrantPlugins::HostLinux::Cap::Hosts do
+  include_context "unit"
+
+  let(:subject){ VagrantPlugins::HostLinux::Cap::Hosts }
+
+  let(:data_dir){ Pathname.new(temporary_dir) }
+  let(:env){ double("env", data_dir: data_dir) }
+  let(:hosts_file){ double("hosts_file") }
+  let(:entries){ [["192.168.56.10", "web.test"]] }
+
+  it "uses /etc/hosts with sudo" do
+    expect(Vagrant::Util::HostsFile).to receive(:new).
+      with("/etc/hosts", lock_path: data_dir.join("hosts.lock"), sudo: true).
+      and_return(hosts_file)
+    expect(hosts_file).to receive(:update).with("id", entries).and_return(true)
+    expect(subject.hosts_file_update(env, "id", entries)).to be(true)
+  end
+
+  it "removes entries from the hosts file" do
+    allow(Vagrant::Util::HostsFile).to receive(:new).and_return(hosts_file)
+    expect(hosts_file).to receive(:remove).with("id").and_return(true)
+    expect(subject.hosts_file_remove(env, "id")).to be(true)
+  end
+
+  context "with a hosts file" do
+    let(:path){ data_dir.join("hosts") }
+
+    before do
+      path.write("127.0.0.1\tlocalhost\n")
+      allow(subject).to receive(:hosts_file_path).and_return(path.to_s)
+    end
+
+    it "adds the entries to the hosts file" do
+      expect(subject.hosts_file_update(env, "id", entries)).to be(true)
+      expect(path.read).to include("127.0.0.1\tlocalhost")
+      expect(path.read).to include("192.168.56.10\tweb.test")
+    end
+
+    it "does not modify the hosts file when the entries are unchanged" do
+      subject.hosts_file_update(env, "id", entries)
+      expect(subject.hosts_file_update(env, "id", entries)).to be(false)
+    end
+
+    it "removes the entries from the hosts file" do
+      subject.hosts_file_update(env, "id", entries)
+      expect(subject.hosts_file_remove(env, "id")).to be(true)
+      expect(path.read).not_to include("web.test")
+      expect(path.read).to include("127.0.0.1\tlocalhost")
+    end
+  end
+end
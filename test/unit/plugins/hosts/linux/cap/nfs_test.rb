@@ -30,6 +30,8 @@ describe VagrantPlugins::HostLinux::Cap::NFS do
     allow(Vagrant::Util::Subprocess).to receive(:execute).with("systemctl", "list-units", any_args).
       and_return(Vagrant::Util::Subprocess::Result.new(1, "", ""))
     allow(Vagrant::Util::Platform).to receive(:systemd?).and_return(false)
+    allow(described_class).to receive(:nfs_exports_lock_path).
+      and_return(temporary_dir.join("nfs-exports.lock"))
   end
 
   after do
@@ -201,6 +203,69 @@ EOH
       expect(exports_content).to eq(content)
     end
 
+    it "applies the exports once" do
+      expect(Vagrant::Util::Subprocess).to receive(:execute).with("sudo", "/bin/true").once
+      cap.nfs_export(env, ui, SecureRandom.uuid, ["127.0.0.1"], "tmp" => {:hostpath => "/tmp"})
+    end
+
+    it "exports a batch of machines and applies the exports once" do
+      first_id = SecureRandom.uuid
+      second_id = SecureRandom.uuid
+      expect(Vagrant::Util::Subprocess).to receive(:execute).with("sudo", "/bin/true").once
+
+      cap = caps.get(:nfs_export_batch)
+      cap.nfs_export_batch(env, ui, [
+        [first_id, ["127.0.0.1"], "tmp" => {:hostpath => "/tmp"}],
+        [second_id, ["127.0.0.2"], "var" => {:hostpath => "/var"}],
+      ])
+      exports_content = File.read(exports_path)
+      expect(exports_content).to include("# VAGRANT-END: #{Process.uid} #{first_id}\n")
+      expect(exports_content).to match(/"\/tmp" 127\.0\.0\.1/)
+      expect(exports_content).to match(/"\/var" 127\.0\.0\.2/)
+    end
+
+    context "when the exports are unchanged" do
+      let(:id) { SecureRandom.uuid }
+
+      before do
+        cap.nfs_export(env, ui, id, ["127.0.0.1"], "tmp" => {:hostpath => "/tmp"})
+      end
+
+      it "does not edit or apply the exports" do
+        original_stat = File.stat(exports_path)
+        expect(Vagrant::Util::Subprocess).not_to receive(:execute).with("sudo", any_args)
+        expect(Vagrant::Util::Subprocess).not_to receive(:execute).with("mv", any_args)
+
+        cap.nfs_export(env, ui, id, ["127.0.0.1"], "tmp" => {:hostpath => "/tmp"})
+        expect(File.stat(exports_path)).to eq(original_stat)
+      end
+
+      it "ignores the order of the machine blocks" do
+        other_id = SecureRandom.uuid
+        cap.nfs_export(env, ui, other_id, ["127.0.0.1"], "var" => {:hostpath => "/var"})
+        expect(Vagrant::Util::Subprocess).not_to receive(:execute).with("sudo", any_args)
+
+        cap.nfs_export(env, ui, id, ["127.0.0.1"], "tmp" => {:hostpath => "/tmp"})
+      end
+
+      it "starts NFS if it is not running" do
+        allow(cap).to receive(:nfs_running?).and_return(false)
+        expect(Vagrant::Util::Subprocess).to receive(:execute).with("sudo", "/bin/true").once
+
+        cap.nfs_export(env, ui, id, ["127.0.0.1"], "tmp" => {:hostpath => "/tmp"})
+      end
+    end
+
+    it "holds the exports lock while editing the exports" do
+      expect(cap).to receive(:nfs_exports_lock).and_call_original
+      expect(cap).to receive(:nfs_write_exports) do
+        File.open(described_class.nfs_exports_lock_path) do |f|
+          expect(f.flock(File::LOCK_EX | File::LOCK_NB)).to be(false)
+        end
+      end
+
+      cap.nfs_export(env, ui, SecureRandom.uuid, ["127.0.0.1"], "tmp" => {:hostpath => "/tmp"})
+    end
   end
 
   describe ".nfs_prune" do
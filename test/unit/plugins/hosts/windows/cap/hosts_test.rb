@@ -0,0 +1,34 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require_relative "../../../../base"
+
+require_relative "../../../../../../plugins/hosts/windows/cap/hosts"
+
+describe VagrantPlugins::HostWindows::Cap::Hosts do
+  let(:subject){ VagrantPlugins::HostWindows::Cap::Hosts }
+
+  let(:env){ double("env", data_dir: Pathname.new("/vagrant/data")) }
+  let(:hosts_file){ double("hosts_file") }
+  let(:entries){ [["192.168.56.10", "web.test"]] }
+
+  before do
+    allow(ENV).to receive(:fetch).and_call_original
+    allow(ENV).to receive(:fetch).with("SystemRoot", anything).and_return("C:\\Windows")
+  end
+
+  it "uses the Windows hosts file without sudo" do
+    expect(Vagrant::Util::HostsFile).to receive(:new).
+      with(File.join("C:\\Windows", "System32", "drivers", "etc", "hosts"),
+        lock_path: env.data_dir.join("hosts.lock"), sudo: false).
+      and_return(hosts_file)
+    expect(hosts_file).to receive(:update).with("id", entries).and_return(true)
+    expect(subject.hosts_file_update(env, "id", entries)).to be(true)
+  end
+
+  it "removes entries from the hosts file" do
+    allow(Vagrant::Util::HostsFile).to receive(:new).and_return(hosts_file)
+    expect(hosts_file).to receive(:remove).with("id").and_return(true)
+    expect(subject.hosts_file_remove(env, "id")).to be(true)
+  end
+end
@@ -310,4 +310,44 @@ describe VagrantPlugins::Kernel_V2::SSHConnectConfig do
       end
     end
   end
+
+  describe "#connect_retry_backoff" do
+    it "should default to fixed" do
+      subject.finalize!
+      expect(subject.connect_retry_backoff).to eq(:fixed)
+    end
+
+    it "should convert a string value to a symbol" do
+      subject.connect_retry_backoff = "exponential"
+      subject.finalize!
+      expect(subject.connect_retry_backoff).to eq(:exponential)
+      expect(subject.validate(machine)).to be_empty
+    end
+
+    it "should not validate an unknown value" do
+      subject.connect_retry_backoff = :linear
+      subject.finalize!
+      expect(subject.validate(machine)).not_to be_empty
+    end
+  end
+
+  describe "#connect_retry_max_delay" do
+    it "should default to the default value" do
+      subject.finalize!
+      expect(subject.connect_retry_max_delay).
+        to eq(described_class.const_get(:DEFAULT_SSH_CONNECT_RETRY_MAX_DELAY))
+    end
+
+    it "should not validate when value is not numeric" do
+      subject.connect_retry_max_delay = "30"
+      subject.finalize!
+      expect(subject.validate(machine)).not_to be_empty
+    end
+
+    it "should not validate when value is less than 0" do
+      subject.connect_retry_max_delay = -1
+      subject.finalize!
+      expect(subject.validate(machine)).not_to be_empty
+    end
+  end
 end
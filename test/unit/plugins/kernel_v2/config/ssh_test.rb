@@ -16,6 +16,14 @@ describe VagrantPlugins::Kernel_V2::SSHConfig do
     end
   end
 
+  describe "#connection_pool" do
+    it "defaults to false" do
+      subject.finalize!
+      expect(subject.connection_pool).to eq(false)
+      expect(subject.keep_alive).to eq(true)
+    end
+  end
+
   describe "#sudo_command" do
     it "defaults properly" do
       subject.finalize!
@@ -23,6 +23,19 @@ describe VagrantPlugins::Kernel_V2::VagrantConfig do
     end
   end
 
+  describe "#deterministic_ids" do
+    it "defaults to false" do
+      subject.finalize!
+      expect(subject.deterministic_ids).to be(false)
+    end
+
+    it "can be enabled" do
+      subject.deterministic_ids = true
+      subject.finalize!
+      expect(subject.deterministic_ids).to be(true)
+    end
+  end
+
   describe "#sensitive" do
     after{ Vagrant::Util::CredentialScrubber.reset! }
 
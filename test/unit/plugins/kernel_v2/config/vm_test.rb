@@ -68,6 +68,45 @@ describe VagrantPlugins::Kernel_V2::VMConfig do
     assert_invalid
   end
 
+  it "validates graceful_halt_command option" do
+    subject.finalize!
+    expect(subject.graceful_halt_command).to be_nil
+
+    subject.graceful_halt_command = "poweroff"
+    subject.finalize!
+    assert_valid
+
+    subject.graceful_halt_command = ["poweroff"]
+    subject.finalize!
+    assert_invalid
+  end
+
+  it "validates guest_cache option" do
+    subject.finalize!
+    expect(subject.guest_cache).to be(false)
+
+    subject.guest_cache = true
+    subject.finalize!
+    assert_valid
+
+    subject.guest_cache = "yes"
+    subject.finalize!
+    assert_invalid
+  end
+
+  it "validates sync_time option" do
+    subject.finalize!
+    expect(subject.sync_time).to be(true)
+
+    subject.sync_time = false
+    subject.finalize!
+    assert_valid
+
+    subject.sync_time = "no"
+    subject.finalize!
+    assert_invalid
+  end
+
   it "does not check for fstab caps if already set" do
     expect(machine).to_not receive(:synced_folder_types)
     subject.allow_fstab_modification = true
@@ -234,6 +273,123 @@ describe VagrantPlugins::Kernel_V2::VMConfig do
     end
   end
 
+  describe "#define_clone" do
+    it "defines the clone with the configuration of the source" do
+      block = proc {}
+      subject.define(:web, primary: true, &block)
+      subject.define_clone(:web2, :web)
+
+      expect(subject.defined_vm_keys).to eq([:web, :web2])
+      clone = subject.defined_vms[:web2]
+      expect(clone.config_procs).to eq([["2", block]])
+      expect(clone.options[:clone_of]).to eq(:web)
+      expect(clone.options).not_to have_key(:primary)
+    end
+
+    it "includes the configuration defined for the clone" do
+      subject.define(:web)
+      block = proc {}
+      subject.define(:web2, autostart: false, &block)
+      subject.define_clone(:web2, :web)
+
+      clone = subject.defined_vms[:web2]
+      expect(clone.config_procs.last).to eq(["2", block])
+      expect(clone.options[:autostart]).to be(false)
+    end
+
+    it "keeps a single machine as the primary machine" do
+      subject.define(:default)
+      subject.define_clone(:web2, :default)
+
+      expect(subject.defined_vms[:default].options[:primary]).to be(true)
+    end
+
+    it "does not change the primary machine" do
+      subject.define(:web)
+      subject.define(:db, primary: true)
+      subject.define_clone(:web2, :web)
+
+      expect(subject.defined_vms[:web].options).not_to have_key(:primary)
+    end
+
+    it "does not define a clone of an undefined machine" do
+      subject.define_clone(:web2, :web)
+
+      expect(subject.defined_vm_keys).to be_empty
+    end
+  end
+
+  describe "#depends_on" do
+    before do
+      allow(machine.env).to receive(:machine_names).and_return([:default, :db])
+    end
+
+    it "defaults to an empty list" do
+      subject.finalize!
+      expect(subject.depends_on).to eq([])
+    end
+
+    it "converts names to strings" do
+      subject.depends_on = :db
+      subject.finalize!
+      expect(subject.depends_on).to eq(["db"])
+    end
+
+    it "is valid when depending on a defined machine" do
+      subject.depends_on = ["db"]
+      subject.finalize!
+      assert_valid
+    end
+
+    it "is invalid when depending on an undefined machine" do
+      subject.depends_on = ["web"]
+      subject.finalize!
+      assert_invalid
+    end
+
+    it "is invalid when depending on itself" do
+      subject.depends_on = ["default"]
+      subject.finalize!
+      assert_invalid
+    end
+  end
+
+  describe "#environment" do
+    it "defaults to an empty hash" do
+      subject.finalize!
+      expect(subject.environment).to eq({})
+    end
+
+    it "converts names to strings" do
+      subject.environment = { ROLE: "web" }
+      subject.finalize!
+      expect(subject.environment).to eq("ROLE" => "web")
+      assert_valid
+    end
+
+    it "is invalid when not a hash" do
+      subject.environment = "ROLE=web"
+      subject.finalize!
+      assert_invalid
+    end
+
+    it "is invalid with an invalid variable name" do
+      subject.environment = { "1ROLE" => "web" }
+      subject.finalize!
+      assert_invalid
+    end
+
+    it "replaces only the variables set when merged" do
+      subject.environment = { "ROLE" => "base", ZONE: "a" }
+      other = described_class.new
+      other.environment = { ROLE: "web" }
+
+      merged = subject.merge(other)
+      merged.finalize!
+      expect(merged.environment).to eq("ROLE" => "web", "ZONE" => "a")
+    end
+  end
+
   describe "#hostname" do
     ["a", "foo", "foo-bar", "baz0"].each do |valid|
       it "is valid: #{valid}" do
@@ -364,6 +520,31 @@ describe VagrantPlugins::Kernel_V2::VMConfig do
       assert_invalid
     end
 
+    it "enables auto correct when a host range is set" do
+      subject.network "forwarded_port",
+        guest: 80, host: 8080, host_range: 8080..8090, id: "test"
+      subject.finalize!
+      n = subject.networks.find do |type, data|
+        type == :forwarded_port && data[:id] == "test"
+      end
+      expect(n[1][:auto_correct]).to be(true)
+      assert_valid
+    end
+
+    it "is an error if the host range is invalid" do
+      subject.network "forwarded_port",
+        guest: 80, host: 8080, host_range: "8080-8090"
+      subject.finalize!
+      assert_invalid
+    end
+
+    it "is an error if the host range is out of bounds" do
+      subject.network "forwarded_port",
+        guest: 80, host: 8080, host_range: 65530..65540
+      subject.finalize!
+      assert_invalid
+    end
+
     it "is an error if multiple networks set hostname" do
       subject.network "public_network", ip: "192.168.0.1", hostname: true
       subject.network "public_network", ip: "192.168.0.2", hostname: true
@@ -383,6 +564,12 @@ describe VagrantPlugins::Kernel_V2::VMConfig do
       assert_valid
     end
 
+    it "is not an error if a private network sets hostnames" do
+      subject.network "private_network", ip: "192.168.0.1", hostname: ["web.test", "www.web.test"]
+      subject.finalize!
+      assert_valid
+    end
+
     it "is not an error if one hostname is true" do
       subject.network "public_network",  ip: "192.168.0.1", hostname: true
       subject.network "public_network",  ip: "192.168.0.2", hostname: false
@@ -404,6 +591,25 @@ describe VagrantPlugins::Kernel_V2::VMConfig do
     end
   end
 
+  describe "#provider_order" do
+    it "defaults to empty" do
+      subject.finalize!
+      expect(subject.provider_order).to eq([])
+    end
+
+    it "converts names to symbols" do
+      subject.provider_order = ["vmware_desktop", :virtualbox]
+      subject.finalize!
+      expect(subject.provider_order).to eq([:vmware_desktop, :virtualbox])
+    end
+
+    it "splits a comma separated string" do
+      subject.provider_order = "vmware_desktop, virtualbox"
+      subject.finalize!
+      expect(subject.provider_order).to eq([:vmware_desktop, :virtualbox])
+    end
+  end
+
   describe "#provider and #__providers" do
     it "returns the providers in order" do
       subject.provider "foo"
@@ -481,6 +687,118 @@ describe VagrantPlugins::Kernel_V2::VMConfig do
     end
   end
 
+  describe "#provider_profile" do
+    before do
+      subject.provider_profile "big" do |vb|
+        vb.gui = true
+        vb.name = "big"
+      end
+    end
+
+    it "merges the profile under the settings of the provider" do
+      subject.provider "virtualbox", profile: "big" do |vb|
+        vb.name = "foo"
+      end
+      subject.finalize!
+
+      config = subject.get_provider_config(:virtualbox)
+      expect(config.name).to eq("foo")
+      expect(config.gui).to be(true)
+      expect(subject.provider_profile_name(:virtualbox)).to eq("big")
+    end
+
+    it "reports the settings inherited from the profile" do
+      subject.provider "virtualbox", profile: "big" do |vb|
+        vb.name = "foo"
+      end
+      subject.finalize!
+
+      expect(subject.inherited_provider_settings(:virtualbox)).to eq("gui" => "big")
+    end
+
+    it "applies the profile without a provider block" do
+      subject.provider "virtualbox", profile: "big"
+      subject.finalize!
+
+      expect(subject.get_provider_config(:virtualbox).name).to eq("big")
+    end
+
+    it "is not applied to providers which do not use it" do
+      subject.provider "virtualbox" do |vb|
+        vb.name = "foo"
+      end
+      subject.finalize!
+
+      expect(subject.get_provider_config(:virtualbox).gui).to be(false)
+      expect(subject.inherited_provider_settings(:virtualbox)).to be_empty
+    end
+
+    it "applies the overrides of the profile before those of the provider" do
+      subject.provider_profile "big" do |_, override|
+        override.vm.box = "profile"
+      end
+      subject.provider "virtualbox", profile: "big" do |_, override|
+        override.vm.box = "provider"
+      end
+
+      expect(subject.get_provider_overrides(:virtualbox).length).to eq(2)
+    end
+
+    it "is invalid if the profile is not defined" do
+      subject.provider "virtualbox", profile: "small"
+      subject.finalize!
+
+      assert_invalid
+    end
+
+    describe "merging" do
+      it "resolves profiles defined in other configs" do
+        other = described_class.new
+        other.provider "virtualbox", profile: "big" do |vb|
+          vb.name = "foo"
+        end
+
+        merged = subject.merge(other)
+        merged.finalize!
+
+        config = merged.get_provider_config(:virtualbox)
+        expect(config.name).to eq("foo")
+        expect(config.gui).to be(true)
+      end
+
+      it "keeps the settings of the provider over a profile in a later config" do
+        subject.provider "virtualbox", profile: "big" do |vb|
+          vb.name = "foo"
+        end
+
+        other = described_class.new
+        other.provider_profile "big" do |vb|
+          vb.name = "other"
+        end
+
+        merged = subject.merge(other)
+        merged.finalize!
+
+        expect(merged.get_provider_config(:virtualbox).name).to eq("foo")
+      end
+
+      it "uses the profile of the later config" do
+        subject.provider_profile "small" do |vb|
+          vb.name = "small"
+        end
+        subject.provider "virtualbox", profile: "big"
+
+        other = described_class.new
+        other.provider "virtualbox", profile: "small"
+
+        merged = subject.merge(other)
+        merged.finalize!
+
+        expect(merged.get_provider_config(:virtualbox).name).to eq("small")
+      end
+    end
+  end
+
   describe "#provision" do
     it "stores the provisioners" do
       subject.provision("shell", inline: "foo")
@@ -499,6 +817,17 @@ describe VagrantPlugins::Kernel_V2::VMConfig do
       expect(r[2].communicator_required).to eql(false)
     end
 
+    it "stores whether unchanged provisioners are skipped" do
+      subject.provision("shell", inline: "foo")
+      subject.provision("shell", inline: "bar", skip_unchanged: true)
+      subject.finalize!
+
+      r = subject.provisioners
+      expect(r[0].skip_unchanged).to eql(false)
+      expect(r[0].config.respond_to?(:skip_unchanged)).to eql(false)
+      expect(r[1].skip_unchanged).to eql(true)
+    end
+
     it "allows provisioner settings to be overridden" do
       subject.provision("s", path: "foo", type: "shell") { |s| s.inline = "foo" }
       subject.provision("s", inline: "bar", type: "shell") { |s| s.args = "bar" }
@@ -861,6 +1190,39 @@ describe VagrantPlugins::Kernel_V2::VMConfig do
     end
   end
 
+  describe "#upload_chunk_size" do
+    it "defaults to the default chunk size" do
+      subject.finalize!
+      expect(subject.upload_chunk_size).to eq(Vagrant::Util::ChunkedUpload::DEFAULT_CHUNK_SIZE)
+    end
+
+    it "is invalid when not a positive integer" do
+      subject.upload_chunk_size = 0
+      subject.finalize!
+      assert_invalid
+    end
+  end
+
+  describe "#upload_checksum_type" do
+    it "defaults to nil" do
+      subject.finalize!
+      expect(subject.upload_checksum_type).to be_nil
+    end
+
+    it "converts the type to a symbol" do
+      subject.upload_checksum_type = "sha256"
+      subject.finalize!
+      expect(subject.upload_checksum_type).to eq(:sha256)
+      assert_valid
+    end
+
+    it "is invalid with an unsupported type" do
+      subject.upload_checksum_type = "crc32"
+      subject.finalize!
+      assert_invalid
+    end
+  end
+
   describe "#usable_port_range" do
     it "defaults properly" do
       subject.finalize!
@@ -65,6 +65,33 @@ describe VagrantPlugins::Kernel_V2::VagrantConfigTrigger do
     end
   end
 
+  describe "with conditions" do
+    it "is valid with valid conditions" do
+      subject.only_if = "state == :running"
+      subject.unless = "env.SKIP_TRIGGER"
+      subject.finalize!
+      assert_valid
+    end
+
+    it "is invalid if a condition can not be parsed" do
+      subject.only_if = "state == "
+      subject.finalize!
+      assert_invalid
+    end
+
+    it "is invalid if a condition uses an unknown variable" do
+      subject.unless = "status == :running"
+      subject.finalize!
+      assert_invalid
+    end
+
+    it "is invalid if a condition is not a string" do
+      subject.only_if = true
+      subject.finalize!
+      assert_invalid
+    end
+  end
+
   describe "defining a new config that needs to match internal restraints" do
     let(:cmd) { :destroy }
     let(:cfg) { described_class.new(cmd) }
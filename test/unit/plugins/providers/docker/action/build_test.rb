@@ -0,0 +1,157 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require_relative "../../../../base"
+require_relative "../../../../../../plugins/providers/docker/action/build"
+
+describe VagrantPlugins::DockerProvider::Action::Build do
+  include_context "unit"
+
+  let(:sandbox) { isolated_environment }
+
+  let(:iso_env) do
+    # We have to create a Vagrantfile so there is a root path
+    sandbox.vagrantfile("")
+    sandbox.create_vagrant_env
+  end
+
+  let(:build_dir) { iso_env.root_path.join("app") }
+  let(:build_args) { [] }
+  let(:build_opts) { {} }
+
+  let(:provider_config) do
+    double("provider_config",
+      build_dir: build_dir.to_s,
+      git_repo: nil,
+      dockerfile: nil,
+      build_args: build_args,
+      build_opts: build_opts)
+  end
+
+  let(:machine_id) { "12345" }
+
+  let(:machine) do
+    iso_env.machine(iso_env.machine_names[0], :docker).tap do |m|
+      allow(m).to receive(:id).and_return(machine_id)
+      allow(m).to receive(:provider_config).and_return(provider_config)
+      allow(m.provider).to receive(:driver).and_return(driver)
+    end
+  end
+
+  let(:env)    {{ machine: machine, ui: machine.ui, build_dir: build_dir.to_s }}
+  let(:app)    { lambda { |*args| }}
+  let(:driver) { double("driver", image?: true) }
+
+  subject { described_class.new(app, env) }
+
+  before do
+    build_dir.mkpath
+    build_dir.join("Dockerfile").write("FROM scratch")
+    allow(driver).to receive(:build).and_return("abcd1234")
+  end
+
+  after do
+    sandbox.close
+  end
+
+  describe "#call" do
+    it "builds the image with a tag for the machine" do
+      expect(driver).to receive(:build) do |dir, **opts|
+        expect(dir).to eq(build_dir.to_s)
+        expect(opts[:extra_args].each_slice(2).to_a).to include(
+          ["--tag", /\Avagrant-[0-9a-f]{8}-default:latest\z/])
+        "abcd1234"
+      end
+      subject.call(env)
+      expect(env[:create_image]).to eq("abcd1234")
+    end
+
+    it "streams the build output" do
+      allow(driver).to receive(:build).and_yield(:stdout, "Step 1/1 : FROM scratch\n").
+        and_return("abcd1234")
+      allow(machine.ui).to receive(:detail)
+      expect(machine.ui).to receive(:detail).with("Step 1/1 : FROM scratch")
+      subject.call(env)
+    end
+
+    context "with build arguments and options" do
+      let(:build_args) { { "APP_ENV" => "dev" } }
+      let(:build_opts) { { no_cache: true, pull: false, target: "dev", cache_from: ["a", "b"] } }
+
+      it "passes them to docker build" do
+        expect(driver).to receive(:build) do |_, **opts|
+          expect(opts[:extra_args][0, 10]).to eq([
+            "--build-arg", "APP_ENV=dev", "--no-cache", "--target", "dev",
+            "--cache-from", "a", "--cache-from", "b", "--tag"])
+          "abcd1234"
+        end
+        subject.call(env)
+      end
+    end
+
+    context "when the image was built" do
+      before { described_class.new(app, env).call(env) }
+
+      it "does not rebuild when nothing changed" do
+        expect(driver).not_to receive(:build)
+        subject.call(env)
+        expect(env[:create_image]).to eq("abcd1234")
+      end
+
+      it "rebuilds when the Dockerfile changes" do
+        build_dir.join("Dockerfile").write("FROM alpine")
+        expect(driver).to receive(:build).and_return("efgh5678")
+        subject.call(env)
+        expect(env[:create_image]).to eq("efgh5678")
+      end
+
+      it "rebuilds when a file in the build context is added" do
+        build_dir.join("app.rb").write("puts 1")
+        expect(driver).to receive(:build)
+        subject.call(env)
+      end
+
+      it "does not rebuild when an ignored file changes" do
+        build_dir.join(".dockerignore").write("log\n")
+        described_class.new(app, env).call(env)
+
+        build_dir.join("log").mkpath
+        build_dir.join("log", "app.log").write("started")
+        expect(driver).not_to receive(:build)
+        subject.call(env)
+      end
+
+      it "rebuilds when the image no longer exists" do
+        allow(driver).to receive(:image?).and_return(false)
+        expect(driver).to receive(:build)
+        subject.call(env)
+      end
+    end
+
+    context "when the build fails" do
+      before do
+        allow(driver).to receive(:build).
+          and_raise(VagrantPlugins::DockerProvider::Errors::ExecuteError.new(command: "docker build", stderr: "", stdout: ""))
+      end
+
+      context "while the machine is being created" do
+        let(:machine_id) { "preparing" }
+
+        it "removes the preparing state" do
+          expect(machine).to receive(:id=).with(nil)
+          expect { subject.call(env) }.to raise_error(VagrantPlugins::DockerProvider::Errors::ExecuteError)
+        end
+      end
+
+      it "does not change an existing machine" do
+        expect(machine).not_to receive(:id=)
+        expect { subject.call(env) }.to raise_error(VagrantPlugins::DockerProvider::Errors::ExecuteError)
+      end
+
+      it "does not store the build hash" do
+        expect { subject.call(env) }.to raise_error(VagrantPlugins::DockerProvider::Errors::ExecuteError)
+        expect(machine.provider_state.key?(described_class::BUILD_HASH_KEY)).to be(false)
+      end
+    end
+  end
+end
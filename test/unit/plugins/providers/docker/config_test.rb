@@ -45,7 +45,9 @@ describe VagrantPlugins::DockerProvider::Config do
   describe "defaults" do
     before { subject.finalize! }
 
+    its(:build_args) { should eq([]) }
     its(:build_dir) { should be_nil }
+    its(:build_opts) { should eq({}) }
     its(:git_repo) { should be_nil }
     its(:expose) { should eq([]) }
     its(:cmd) { should eq([]) }
@@ -121,6 +123,44 @@ describe VagrantPlugins::DockerProvider::Config do
     end
   end
 
+  describe "#build_args" do
+    before { valid_defaults }
+
+    it "should be valid with an array" do
+      subject.build_args = ["--pull"]
+      subject.finalize!
+      assert_valid
+    end
+
+    it "should be valid with a hash" do
+      subject.build_args = { "APP_ENV" => "development" }
+      subject.finalize!
+      assert_valid
+    end
+
+    it "should be invalid with a string" do
+      subject.build_args = "--pull"
+      subject.finalize!
+      assert_invalid
+    end
+  end
+
+  describe "#build_opts" do
+    before { valid_defaults }
+
+    it "should be valid with a hash" do
+      subject.build_opts = { no_cache: true }
+      subject.finalize!
+      assert_valid
+    end
+
+    it "should be invalid with an array" do
+      subject.build_opts = ["--no-cache"]
+      subject.finalize!
+      assert_invalid
+    end
+  end
+
   describe "#git_repo" do
     it "should be valid if not set with image or build dir" do
       subject.build_dir = nil
@@ -130,5 +130,31 @@ describe VagrantPlugins::DockerProvider::Provider do
 
       expect(subject.ssh_info).to eq(ssh_info)
     end
+
+    context "with a host VM" do
+      let(:host_vm) { double("host_vm", ssh_info: host_ssh_info) }
+      let(:host_ssh_info) {{
+        host: "127.0.0.1", port: 2222, username: "vagrant",
+        private_key_path: ["/keys/host"], forward_agent: false
+      }}
+
+      before do
+        allow(subject).to receive(:state).and_return(double("state", id: :running))
+        allow(subject).to receive(:host_vm?).and_return(true)
+        allow(subject).to receive(:host_vm).and_return(host_vm)
+        allow(driver_obj).to receive(:inspect_container).and_return(network_settings)
+      end
+
+      it "includes the host VM as the jump host" do
+        expect(subject.ssh_info[:proxy_jump]).to eq(
+          host: "127.0.0.1", port: 2222, username: "vagrant",
+          private_key_path: ["/keys/host"])
+      end
+
+      it "does not include a jump host if the host VM is not ready" do
+        allow(host_vm).to receive(:ssh_info).and_return(nil)
+        expect(subject.ssh_info).to eq(ssh_info)
+      end
+    end
   end
 end
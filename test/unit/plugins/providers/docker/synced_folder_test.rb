@@ -73,5 +73,12 @@ describe VagrantPlugins::DockerProvider::SyncedFolder do
       subject.prepare(machine, consistency_folders, options)
       expect(machine.provider_config.volumes).to eq(consistency_volumes)
     end
+
+    it "mounts read-only folders as read-only volumes" do
+      consistency_folders["/guest/dir1"][:readonly] = true
+      subject.prepare(machine, consistency_folders, options)
+      expect(machine.provider_config.volumes.first).
+        to eq("/Users/brian/code/vagrant-sandbox:/guest/dir1:ro,cached")
+    end
   end
 end
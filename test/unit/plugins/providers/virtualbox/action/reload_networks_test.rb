@@ -0,0 +1,100 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require_relative "../base"
+
+describe VagrantPlugins::ProviderVirtualBox::Action::ReloadNetworks do
+  include_context "unit"
+  include_context "virtualbox"
+
+  let(:iso_env) do
+    # We have to create a Vagrantfile so there is a root path
+    env = isolated_environment
+    env.vagrantfile("")
+    env.create_vagrant_env
+  end
+
+  let(:machine) do
+    iso_env.machine(iso_env.machine_names[0], :virtualbox).tap do |m|
+      allow(m.provider).to receive(:driver).and_return(driver)
+      allow(m).to receive(:guest).and_return(guest)
+      m.id = "uuid"
+    end
+  end
+
+  let(:env)    {{ machine: machine, ui: machine.ui }}
+  let(:app)    { lambda { |*args| }}
+  let(:driver) { double("driver", version: "6.1.0") }
+  let(:guest)  { double("guest") }
+  let(:nics) do
+    {
+      1 => {type: :nat},
+      2 => {type: :hostonly, hostonly: "vboxnet1"},
+    }
+  end
+
+  subject { described_class.new(app, env) }
+
+  before do
+    machine.config.vm.network "private_network", type: :static, ip: "dead:beef::100"
+    allow(driver).to receive(:read_network_interfaces) { nics }
+    allow(driver).to receive(:read_host_only_interfaces) { [] }
+    allow(driver).to receive(:create_host_only_network) {{ name: "vboxnet0" }}
+    allow(driver).to receive(:execute_command)
+    allow(guest).to receive(:capability)
+  end
+
+  it "does not enable the adapters" do
+    expect(driver).not_to receive(:enable_adapters)
+    subject.call(env)
+  end
+
+  it "attaches the changed adapters of the running VM" do
+    expect(driver).to receive(:execute_command).
+      with(["controlvm", "uuid", "nic2", "hostonly", "vboxnet0"])
+    expect(driver).not_to receive(:execute_command).with(array_including("nic1"))
+    subject.call(env)
+  end
+
+  it "configures the networks in the guest" do
+    subject.call(env)
+    expect(guest).to have_received(:capability).with(:configure_networks,
+      [hash_including(ip: "dead:beef::100")])
+  end
+
+  context "when the adapter is already attached" do
+    let(:nics) do
+      {
+        1 => {type: :nat},
+        2 => {type: :hostonly, hostonly: "vboxnet0"},
+      }
+    end
+
+    it "does not attach it again" do
+      expect(driver).not_to receive(:execute_command)
+      subject.call(env)
+    end
+  end
+
+  context "when the adapter is not enabled" do
+    let(:nics) { {1 => {type: :nat}} }
+
+    it "requires a reboot" do
+      expect { subject.call(env) }.
+        to raise_error(Vagrant::Errors::ReloadNetworksRebootRequired)
+      expect(guest).not_to have_received(:capability)
+    end
+  end
+
+  context "when a later adapter is not enabled" do
+    before do
+      machine.config.vm.network "private_network", type: :static, ip: "dead:beef::101"
+    end
+
+    it "does not attach any adapter" do
+      expect(driver).not_to receive(:execute_command)
+      expect { subject.call(env) }.
+        to raise_error(Vagrant::Errors::ReloadNetworksRebootRequired)
+    end
+  end
+end
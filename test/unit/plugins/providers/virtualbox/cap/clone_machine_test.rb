@@ -0,0 +1,30 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require_relative "../base"
+
+require Vagrant.source_root.join("plugins/providers/virtualbox/cap/clone_machine")
+
+describe VagrantPlugins::ProviderVirtualBox::Cap::CloneMachine do
+  include_context "unit"
+
+  let(:driver) { double("driver") }
+  let(:machine) { double("machine", id: "SOURCE_ID", provider: double("provider", driver: driver)) }
+  let(:target) { double("target", name: :web2) }
+
+  describe ".clone_machine" do
+    it "creates a full clone" do
+      expect(driver).not_to receive(:create_snapshot)
+      expect(driver).to receive(:clonevm).with("SOURCE_ID", nil).and_return("CLONE_ID")
+
+      expect(described_class.clone_machine(machine, target, false)).to eq("CLONE_ID")
+    end
+
+    it "creates a linked clone from a new snapshot" do
+      expect(driver).to receive(:create_snapshot).with("SOURCE_ID", /^vagrant-clone-web2-/)
+      expect(driver).to receive(:clonevm).with("SOURCE_ID", /^vagrant-clone-web2-/).and_return("CLONE_ID")
+
+      expect(described_class.clone_machine(machine, target, true)).to eq("CLONE_ID")
+    end
+  end
+end
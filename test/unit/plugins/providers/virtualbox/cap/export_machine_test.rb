@@ -0,0 +1,48 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require_relative "../base"
+
+require Vagrant.source_root.join("plugins/providers/virtualbox/cap/export_machine")
+
+describe VagrantPlugins::ProviderVirtualBox::Cap::ExportMachine do
+  include_context "unit"
+
+  let(:directory) { Dir.mktmpdir("vagrant-test-export-machine") }
+  let(:driver) { double("driver", read_mac_address: "080027000000") }
+  let(:ui) { Vagrant::UI::Silent.new }
+  let(:state) { double("state", id: :poweroff) }
+  let(:machine) do
+    double("machine", name: :default, ui: ui, state: state,
+      provider: double("provider", driver: driver))
+  end
+
+  after { FileUtils.rm_rf(directory) }
+
+  describe ".export_machine" do
+    before do
+      allow(Vagrant::Util::Platform).to receive(:wsl?).and_return(false)
+    end
+
+    it "exports the machine to an OVF file" do
+      expect(driver).to receive(:export).with(File.join(directory, "box.ovf"))
+      described_class.export_machine(machine, directory)
+    end
+
+    it "writes a Vagrantfile with the MAC address" do
+      allow(driver).to receive(:export)
+      described_class.export_machine(machine, directory)
+      expect(File.read(File.join(directory, "Vagrantfile"))).to include("080027000000")
+    end
+
+    context "when the machine is running" do
+      let(:state) { double("state", id: :running) }
+
+      it "raises an error without exporting the machine" do
+        expect(driver).not_to receive(:export)
+        expect { described_class.export_machine(machine, directory) }.
+          to raise_error(Vagrant::Errors::VirtualBoxExportRunning)
+      end
+    end
+  end
+end
@@ -131,6 +131,24 @@ describe "VagrantPlugins::ProviderVirtualBox::Cap::MountOptions" do
       end
     end
 
+    context "with readonly defined" do
+      it "mounts the folder with the ro option" do
+        expect(comm).to receive(:execute).with("id -u #{mount_owner}", anything).and_yield(:stdout, mount_uid)
+        expect(comm).to receive(:execute).with("getent group #{mount_group}", anything).and_yield(:stdout, "vagrant:x:#{mount_gid}:")
+        out_mount_options, _, _ = cap.mount_options(machine, mount_name, mount_guest_path, folder_options.merge(readonly: true))
+        expect(out_mount_options).to eq("uid=#{mount_uid},gid=#{mount_gid},ro,_netdev")
+      end
+    end
+
+    context "with mode defined" do
+      it "sets the mode of the files" do
+        expect(comm).to receive(:execute).with("id -u #{mount_owner}", anything).and_yield(:stdout, mount_uid)
+        expect(comm).to receive(:execute).with("getent group #{mount_group}", anything).and_yield(:stdout, "vagrant:x:#{mount_gid}:")
+        out_mount_options, _, _ = cap.mount_options(machine, mount_name, mount_guest_path, folder_options.merge(mode: "0755"))
+        expect(out_mount_options).to eq("uid=#{mount_uid},gid=#{mount_gid},dmode=0755,fmode=0755,_netdev")
+      end
+    end
+
     context "with custom mount options" do
       let(:ui){ Vagrant::UI::Silent.new }
       before do
@@ -0,0 +1,116 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require_relative "../base"
+
+require Vagrant.source_root.join("plugins/providers/virtualbox/cap/resources")
+
+describe VagrantPlugins::ProviderVirtualBox::Cap::Resources do
+  include_context "unit"
+
+  let(:provider_config) { VagrantPlugins::ProviderVirtualBox::Config.new }
+  let(:driver) { double("driver") }
+  let(:provider) { double("provider", driver: driver) }
+  let(:machine) {
+    double("machine", id: "uuid", provider: provider, provider_config: provider_config)
+  }
+
+  describe ".resources" do
+    it "returns the configured cpus and memory" do
+      provider_config.cpus = 2
+      provider_config.memory = 1024
+      provider_config.memory = 2048
+      expect(described_class.resources(machine)).to eq(cpus: 2, memory: 2048)
+    end
+
+    it "returns nothing if resources are not configured" do
+      expect(described_class.resources(machine)).to be_empty
+    end
+  end
+
+  describe ".resources_config" do
+    it "does not include the resources" do
+      provider_config.cpus = 2
+      provider_config.memory = 1024
+      provider_config.customize ["modifyvm", :id, "--vram", "16"]
+      config = described_class.resources_config(machine)
+      expect(config["customizations"]).to eq(
+        [["pre-boot", ["modifyvm", :id, "--vram", "16"]]])
+    end
+
+    it "is the same when only the resources change" do
+      provider_config.cpus = 2
+      config = described_class.resources_config(machine)
+      provider_config.cpus = 4
+      expect(described_class.resources_config(machine)).to eq(config)
+    end
+
+    it "changes when other settings change" do
+      config = described_class.resources_config(machine)
+      provider_config.gui = true
+      expect(described_class.resources_config(machine)).not_to eq(config)
+    end
+  end
+
+  describe ".modify_resources" do
+    let(:info) { "cpus=4\ncpuhotplug=\"on\"\ncpu0=\"attached\"\ncpu1=\"attached\"\n" }
+
+    before do
+      allow(driver).to receive(:execute_command).
+        with(["showvminfo", "uuid", "--machinereadable"]).and_return(info)
+    end
+
+    it "does not modify memory" do
+      expect(driver).not_to receive(:execute_command)
+      expect(described_class.modify_resources(machine, memory: 2048)).to be(false)
+    end
+
+    it "adds cpus" do
+      expect(driver).to receive(:execute_command).with(["controlvm", "uuid", "plugcpu", "2"])
+      expect(driver).to receive(:execute_command).with(["controlvm", "uuid", "plugcpu", "3"])
+      expect(described_class.modify_resources(machine, cpus: 4)).to be(true)
+    end
+
+    it "removes cpus" do
+      expect(driver).to receive(:execute_command).with(["controlvm", "uuid", "unplugcpu", "1"])
+      expect(described_class.modify_resources(machine, cpus: 1)).to be(true)
+    end
+
+    it "does not add more cpus than the maximum" do
+      expect(driver).not_to receive(:execute_command).with(array_including("controlvm"))
+      expect(described_class.modify_resources(machine, cpus: 6)).to be(false)
+    end
+
+    context "when cpus which are not the last are plugged" do
+      let(:info) { "cpus=4\ncpuhotplug=\"on\"\ncpu0=\"attached\"\ncpu2=\"attached\"\n" }
+
+      it "plugs the missing cpus" do
+        expect(driver).to receive(:execute_command).with(["controlvm", "uuid", "plugcpu", "1"])
+        expect(described_class.modify_resources(machine, cpus: 3)).to be(true)
+      end
+
+      it "unplugs the plugged cpus" do
+        expect(driver).to receive(:execute_command).with(["controlvm", "uuid", "unplugcpu", "2"])
+        expect(described_class.modify_resources(machine, cpus: 1)).to be(true)
+      end
+    end
+
+    context "when the plugged cpus are not listed" do
+      let(:info) { "cpus=4\ncpuhotplug=\"on\"\n" }
+
+      it "does not modify cpus" do
+        expect(driver).not_to receive(:execute_command).with(array_including("controlvm"))
+        expect(described_class.modify_resources(machine, cpus: 2)).to be(false)
+      end
+    end
+
+    context "when cpu hot-plug is disabled" do
+      let(:info) { "cpus=2\ncpuhotplug=\"off\"\n" }
+
+      it "does not modify cpus" do
+        expect(driver).not_to receive(:execute_command).with(array_including("controlvm"))
+        expect(described_class.modify_resources(machine, cpus: 4)).to be(false)
+      end
+    end
+  end
+end
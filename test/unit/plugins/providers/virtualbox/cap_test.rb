@@ -44,6 +44,13 @@ describe VagrantPlugins::ProviderVirtualBox::Cap do
     end
   end
 
+  describe "#acpi_shutdown" do
+    it "sends the ACPI shutdown signal" do
+      expect(driver).to receive(:acpi_shutdown)
+      described_class.acpi_shutdown(machine)
+    end
+  end
+
   describe "#snapshot_list" do
     it "returns all the snapshots" do
       allow(machine).to receive(:id).and_return("1234")
@@ -58,4 +65,33 @@ describe VagrantPlugins::ProviderVirtualBox::Cap do
       expect(described_class.snapshot_list(machine)).to eq([])
     end
   end
+
+  describe "#guest_tools_version" do
+    let(:check_guest_additions) { true }
+
+    before do
+      allow(machine).to receive(:provider_config).
+        and_return(double("provider_config", check_guest_additions: check_guest_additions))
+      allow(driver).to receive(:version).and_return("7.0.12")
+    end
+
+    it "returns the VirtualBox version" do
+      expect(described_class.guest_tools_version(machine)).to eq("7.0.12")
+    end
+
+    context "when guest additions are not checked" do
+      let(:check_guest_additions) { false }
+
+      it "returns nil" do
+        expect(described_class.guest_tools_version(machine)).to be_nil
+      end
+    end
+  end
+
+  describe "#guest_tools_installed_version" do
+    it "returns the version reported by the guest additions" do
+      expect(driver).to receive(:read_guest_additions_version).and_return("7.0.10")
+      expect(described_class.guest_tools_installed_version(machine)).to eq("7.0.10")
+    end
+  end
 end
@@ -76,6 +76,45 @@ describe VagrantPlugins::ProviderVirtualBox::Provider do
     end
   end
 
+  describe ".machine_ids" do
+    subject { described_class }
+
+    it "returns the IDs of all the VMs" do
+      allow(VagrantPlugins::ProviderVirtualBox::Driver::Meta).to receive(:new).and_return(driver)
+      allow(driver).to receive(:read_vms).and_return(
+        "project_default_1700000000000_12345" => "VAGRANT_UUID",
+        "Windows 11" => "OTHER_UUID",
+      )
+
+      expect(subject.machine_ids).to eq(["VAGRANT_UUID", "OTHER_UUID"])
+    end
+  end
+
+  describe ".created_machine_ids" do
+    subject { described_class }
+
+    let(:env) { double("env", boxes_path: boxes_path) }
+    let(:boxes_path) { Pathname.new(Dir.mktmpdir("vagrant-test")) }
+
+    after { FileUtils.rm_rf(boxes_path) }
+
+    before do
+      allow(VagrantPlugins::ProviderVirtualBox::Driver::Meta).to receive(:new).and_return(driver)
+      allow(driver).to receive(:read_vms).and_return(
+        "project_default_1700000000000_12345" => "VAGRANT_UUID",
+        "box_1700000000000_54321" => "MASTER_UUID",
+        "Windows 11" => "OTHER_UUID",
+      )
+      master_dir = boxes_path.join("box", "0", "virtualbox")
+      master_dir.mkpath
+      master_dir.join("master_id").write("MASTER_UUID")
+    end
+
+    it "returns the IDs of the VMs created by Vagrant, except linked clone masters" do
+      expect(subject.created_machine_ids(env)).to eq(["VAGRANT_UUID"])
+    end
+  end
+
   describe "#driver" do
     it "is initialized" do
       allow(VagrantPlugins::ProviderVirtualBox::Driver::Meta).to receive(:new).and_return(driver)
@@ -47,6 +47,14 @@ shared_examples "a version 5.x virtualbox driver" do |options|
 
     end
 
+    it "shares the folder read-only if option is true" do
+      expect(subprocess).to receive(:execute).
+        with("VBoxManage", "sharedfolder", "add", anything, "--name", "folder", "--hostpath", "/Users/brian/vagrant-folder", "--readonly", {:env => {:LANG => "C"}, :notify=>[:stdout, :stderr]}).
+        and_return(subprocess_result(exit_code: 0))
+      subject.share_folders([folders_disabled.first.merge(readonly: true)])
+
+    end
+
     it "disables SharedFoldersEnableSymlinksCreate if false" do
       expect(subprocess).to receive(:execute).
         with("VBoxManage", "sharedfolder", "add", anything, "--name", "folder", "--hostpath", "/Users/brian/vagrant-folder", {:env => {:LANG => "C"}, :notify=>[:stdout, :stderr]}).
@@ -113,36 +113,43 @@ describe VagrantPlugins::ProviderVirtualBox::SyncedFolder do
       allow(machine).to receive(:provider).and_return(provider)
       allow(machine).to receive(:env)
       allow(subject).to receive(:display_symlink_create_warning)
+      allow(driver).to receive(:read_state).and_return(:poweroff)
     end
 
     it "should prepare and share the folders" do
-      expect(driver).to receive(:share_folders).with([{:name=>"folder", :hostpath=>"/Users/brian/vagrant-folder", :transient=>false, :automount=>false, :SharedFoldersEnableSymlinksCreate=>true}])
+      expect(driver).to receive(:share_folders).with([{:name=>"folder", :hostpath=>"/Users/brian/vagrant-folder", :transient=>false, :automount=>false, :SharedFoldersEnableSymlinksCreate=>true, :readonly=>false}])
       subject.prepare(machine, folders, nil)
     end
 
     it "should prepare and share the folders without symlinks enabled" do
-      expect(driver).to receive(:share_folders).with([{:name=>"folder", :hostpath=>"/Users/brian/vagrant-folder", :transient=>false, :automount=>false, :SharedFoldersEnableSymlinksCreate=>false}])
+      expect(driver).to receive(:share_folders).with([{:name=>"folder", :hostpath=>"/Users/brian/vagrant-folder", :transient=>false, :automount=>false, :SharedFoldersEnableSymlinksCreate=>false, :readonly=>false}])
       subject.prepare(machine, folders_disabled, nil)
     end
 
     it "should prepare and share the folders without symlinks enabled with env var set" do
       stub_env('VAGRANT_DISABLE_VBOXSYMLINKCREATE'=>'1')
 
-      expect(driver).to receive(:share_folders).with([{:name=>"folder", :hostpath=>"/Users/brian/vagrant-folder", :transient=>false, :automount=>false, :SharedFoldersEnableSymlinksCreate=>false}])
+      expect(driver).to receive(:share_folders).with([{:name=>"folder", :hostpath=>"/Users/brian/vagrant-folder", :transient=>false, :automount=>false, :SharedFoldersEnableSymlinksCreate=>false, :readonly=>false}])
       subject.prepare(machine, folders_nosymvar, nil)
     end
 
     it "should prepare and share the folders and override symlink setting" do
       stub_env('VAGRANT_DISABLE_VBOXSYMLINKCREATE'=>'1')
 
-      expect(driver).to receive(:share_folders).with([{:name=>"folder", :hostpath=>"/Users/brian/vagrant-folder", :transient=>false, :automount=>false, :SharedFoldersEnableSymlinksCreate=>true}])
+      expect(driver).to receive(:share_folders).with([{:name=>"folder", :hostpath=>"/Users/brian/vagrant-folder", :transient=>false, :automount=>false, :SharedFoldersEnableSymlinksCreate=>true, :readonly=>false}])
       subject.prepare(machine, folders, nil)
     end
 
     it "should prepare and share the folders with automount enabled" do
-      expect(driver).to receive(:share_folders).with([{:name=>"folder", :hostpath=>"/Users/brian/vagrant-folder", :transient=>false, :SharedFoldersEnableSymlinksCreate=>true, :automount=>true}])
+      expect(driver).to receive(:share_folders).with([{:name=>"folder", :hostpath=>"/Users/brian/vagrant-folder", :transient=>false, :SharedFoldersEnableSymlinksCreate=>true, :automount=>true, :readonly=>false}])
       subject.prepare(machine, folders_automount, nil)
     end
+
+    it "should share the folders as transient folders when the VM is running" do
+      allow(driver).to receive(:read_state).and_return(:running)
+      expect(driver).to receive(:share_folders).with([{:name=>"folder", :hostpath=>"/Users/brian/vagrant-folder", :transient=>true, :automount=>false, :SharedFoldersEnableSymlinksCreate=>true, :readonly=>false}])
+      subject.prepare(machine, folders, nil)
+    end
   end
 
   describe "#os_friendly_id" do
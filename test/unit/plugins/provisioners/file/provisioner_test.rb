@@ -112,4 +112,58 @@ describe VagrantPlugins::FileUpload::Provisioner do
       subject.provision
     end
   end
+
+  describe "#plan" do
+    it "reports the source and destination" do
+      allow(config).to receive(:source).and_return("/source")
+      allow(config).to receive(:destination).and_return("~/foo")
+
+      expect(communicator).not_to receive(:upload)
+      expect(subject.plan).to eq(["/source => ~/foo"])
+    end
+  end
+
+  describe "#fingerprint" do
+    let(:source) { iso_env.cwd.join("files") }
+
+    before do
+      source.mkpath
+      source.join("a.txt").write("one")
+      allow(config).to receive(:source).and_return("files")
+      allow(config).to receive(:destination).and_return("/foo/bar")
+    end
+
+    it "is the same when the files are unchanged" do
+      expect(subject.fingerprint).to eq(subject.fingerprint)
+    end
+
+    it "changes when a file changes size" do
+      fingerprint = subject.fingerprint
+      source.join("a.txt").write("three")
+      expect(subject.fingerprint).not_to eq(fingerprint)
+    end
+
+    it "changes when a file is modified" do
+      fingerprint = subject.fingerprint
+      File.utime(Time.now + 60, Time.now + 60, source.join("a.txt").to_s)
+      expect(subject.fingerprint).not_to eq(fingerprint)
+    end
+
+    it "changes when a file is added" do
+      fingerprint = subject.fingerprint
+      source.join("b.txt").write("two")
+      expect(subject.fingerprint).not_to eq(fingerprint)
+    end
+
+    it "changes when the destination changes" do
+      fingerprint = subject.fingerprint
+      allow(config).to receive(:destination).and_return("/foo/baz")
+      expect(subject.fingerprint).not_to eq(fingerprint)
+    end
+
+    it "is nil when the source does not exist" do
+      allow(config).to receive(:source).and_return("missing")
+      expect(subject.fingerprint).to be_nil
+    end
+  end
 end
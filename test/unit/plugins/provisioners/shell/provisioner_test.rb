@@ -13,6 +13,7 @@ describe "Vagrant::Shell::Provisioner" do
     double(:machine, env: env, id: "ID").tap { |machine|
       allow(machine).to receive_message_chain(:config, :vm, :communicator).and_return(:not_winrm)
       allow(machine).to receive_message_chain(:config, :vm, :guest).and_return(:linux)
+      allow(machine).to receive_message_chain(:config, :vm, :environment).and_return({})
       allow(machine).to receive_message_chain(:communicate, :tap) {}
     }
   }
@@ -421,6 +422,233 @@ describe "Vagrant::Shell::Provisioner" do
     end
   end
 
+  describe "#plan" do
+    let(:path) { "scripts/setup.sh" }
+    let(:inline) { nil }
+    let(:args) { ["one", "two"] }
+    let(:privileged) { true }
+
+    let(:config) {
+      double(
+        :config,
+        :args        => args,
+        :env         => {},
+        :upload_path => "/tmp/vagrant-shell",
+        :remote?     => false,
+        :path        => path,
+        :inline      => inline,
+        :privileged  => privileged,
+        :binary      => false,
+        :reset       => false,
+        :reboot      => false,
+      )
+    }
+
+    let(:vsp) {
+      VagrantPlugins::Shell::Provisioner.new(machine, config)
+    }
+
+    it "does not run anything on the machine" do
+      expect(machine).not_to receive(:communicate)
+      vsp.plan
+    end
+
+    it "reports the script path and arguments" do
+      expect(vsp.plan).to eq([
+        "Upload script scripts/setup.sh to /tmp/vagrant-shell",
+        "Run /tmp/vagrant-shell \"one\" \"two\" with elevated privileges",
+      ])
+    end
+
+    context "with an inline script" do
+      let(:path) { nil }
+      let(:inline) { "echo hello" }
+      let(:args) { "--flag" }
+      let(:privileged) { false }
+
+      it "reports the inline script length" do
+        expect(vsp.plan).to eq([
+          "Upload inline script (10 bytes) to /tmp/vagrant-shell",
+          "Run /tmp/vagrant-shell --flag",
+        ])
+      end
+    end
+  end
+
+  describe "#fingerprint" do
+    let(:path) { nil }
+    let(:inline) { "echo hello" }
+    let(:args) { ["one"] }
+    let(:remote) { false }
+    let(:sha256) { nil }
+
+    let(:config) {
+      double(
+        :config,
+        :args    => args,
+        :remote? => remote,
+        :path    => path,
+        :inline  => inline,
+        :md5     => nil,
+        :sha1    => nil,
+        :sha256  => sha256,
+        :sha384  => nil,
+        :sha512  => nil,
+      )
+    }
+
+    let(:vsp) {
+      VagrantPlugins::Shell::Provisioner.new(machine, config)
+    }
+
+    it "is the same for the same script and arguments" do
+      other = VagrantPlugins::Shell::Provisioner.new(machine, config)
+      expect(vsp.fingerprint).to eq(other.fingerprint)
+    end
+
+    it "changes when the inline script changes" do
+      fingerprint = vsp.fingerprint
+      allow(config).to receive(:inline).and_return("echo goodbye")
+      expect(vsp.fingerprint).not_to eq(fingerprint)
+    end
+
+    it "changes when the arguments change" do
+      fingerprint = vsp.fingerprint
+      allow(config).to receive(:args).and_return(["two"])
+      expect(vsp.fingerprint).not_to eq(fingerprint)
+    end
+
+    context "with a provisioner configuration" do
+      let(:config) {
+        VagrantPlugins::Shell::Config.new.tap do |c|
+          c.inline = inline
+          c.finalize!
+        end
+      }
+
+      it "changes when any option changes" do
+        fingerprint = vsp.fingerprint
+        config.env = {"MODE" => "test"}
+        expect(vsp.fingerprint).not_to eq(fingerprint)
+        fingerprint = vsp.fingerprint
+        config.privileged = false
+        expect(vsp.fingerprint).not_to eq(fingerprint)
+        fingerprint = vsp.fingerprint
+        config.upload_path = "/tmp/other"
+        expect(vsp.fingerprint).not_to eq(fingerprint)
+      end
+    end
+
+    context "with a script path" do
+      let(:inline) { nil }
+      let(:path) { "setup.sh" }
+      let(:script) { env.workdir.join("setup.sh") }
+
+      before do
+        allow(env).to receive(:root_path).and_return(env.workdir)
+        script.write("echo one")
+      end
+
+      it "changes when the script contents change" do
+        fingerprint = vsp.fingerprint
+        script.write("echo two")
+        expect(vsp.fingerprint).not_to eq(fingerprint)
+      end
+
+      it "is nil when the script does not exist" do
+        script.delete
+        expect(vsp.fingerprint).to be_nil
+      end
+    end
+
+    context "with a remote script" do
+      let(:inline) { nil }
+      let(:path) { "http://example.com/setup.sh" }
+      let(:remote) { true }
+
+      it "is nil without a checksum" do
+        expect(vsp.fingerprint).to be_nil
+      end
+
+      context "with a checksum" do
+        let(:sha256) { "abc123" }
+
+        it "uses the checksum" do
+          fingerprint = vsp.fingerprint
+          expect(fingerprint).not_to be_nil
+          allow(config).to receive(:sha256).and_return("def456")
+          expect(vsp.fingerprint).not_to eq(fingerprint)
+        end
+      end
+    end
+  end
+
+  describe "#provision_ssh" do
+    let(:config) {
+      double(
+        :config,
+        :args        => "doesn't matter",
+        :env         => { "MODE" => "test" },
+        :upload_path => "/tmp/vagrant-shell",
+        :remote?     => false,
+        :path        => nil,
+        :inline      => "echo hi",
+        :binary      => false,
+        :reset       => false,
+        :reboot      => false,
+        :name        => nil,
+        :privileged  => true,
+        :keep_color  => false,
+      )
+    }
+
+    let(:vsp) {
+      VagrantPlugins::Shell::Provisioner.new(machine, config)
+    }
+
+    let(:communicator) { double("communicator") }
+    let(:ui) { Vagrant::UI::Silent.new }
+
+    before {
+      allow(communicator).to receive(:upload)
+      allow(communicator).to receive(:sudo)
+      allow(machine).to receive(:communicate).and_return(communicator)
+      allow(machine).to receive(:ssh_info).and_return(username: "vagrant")
+      allow(machine).to receive(:ui).and_return(ui)
+      allow(vsp).to receive(:with_script_file).and_yield("script")
+    }
+
+    it "includes the escaped environment of the machine" do
+      allow(machine).to receive_message_chain(:config, :vm, :environment).
+        and_return("ROLE" => "web $HOME")
+      expect(communicator).to receive(:execute).
+        with(/ ROLE=web\\ \\\$HOME MODE="test" \/tmp\/vagrant-shell/, anything)
+      vsp.send(:provision_ssh, "")
+    end
+
+    it "registers the escaped values of secrets as sensitive" do
+      allow(machine).to receive_message_chain(:config, :vm, :environment).
+        and_return("TOKEN" => "s3cret value")
+      allow(communicator).to receive(:execute)
+      Vagrant::Util::CredentialScrubber.sensitive("s3cret value")
+      vsp.send(:provision_ssh, "")
+      expect(Vagrant::Util::CredentialScrubber.sensitive_strings).
+        to include("s3cret\\ value")
+    ensure
+      Vagrant::Util::CredentialScrubber.reset!
+    end
+
+    it "prefers the environment of the provisioner" do
+      allow(machine).to receive_message_chain(:config, :vm, :environment).
+        and_return("MODE" => "machine")
+      expect(communicator).to receive(:execute) do |command, _|
+        expect(command).to include('MODE="test"')
+        expect(command).not_to include("machine")
+      end
+      vsp.send(:provision_ssh, "")
+    end
+  end
+
   describe "#provision_winrm" do
     let(:config) {
       double(
@@ -480,6 +708,30 @@ describe "Vagrant::Shell::Provisioner" do
       vsp.send(:provision_winrm, "")
     end
 
+    it "stores the exit code of the script" do
+      expect(communicator).to receive(:sudo).
+        with(anything, hash_including(error_check: true)).and_return(3)
+      vsp.send(:provision_winrm, "")
+      expect(vsp.exit_code).to eq(3)
+    end
+
+    it "does not check the exit code when error checking is disabled" do
+      vsp.error_check = false
+      expect(communicator).to receive(:sudo).
+        with(anything, hash_including(error_check: false)).and_return(3)
+      vsp.send(:provision_winrm, "")
+      expect(vsp.exit_code).to eq(3)
+    end
+
+    it "stores the combined output of the script" do
+      expect(communicator).to receive(:sudo).
+        and_yield(:stdout, "out\n").
+        and_yield(:stderr, "err\n")
+      allow(machine).to receive_message_chain(:config, :vm, :communicator).and_return(:winrm)
+      vsp.provision
+      expect(vsp.output).to eq("out\nerr\n")
+    end
+
     it "ensures that files are uploaded with an extension" do
       allow(vsp).to receive(:with_script_file).and_yield(config.path)
       expect(communicator).to receive(:upload).with(config.path, /arbitrary.ps1$/)
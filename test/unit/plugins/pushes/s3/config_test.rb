@@ -0,0 +1,144 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require_relative "../../../base"
+
+require Vagrant.source_root.join("plugins/pushes/s3/config")
+
+describe VagrantPlugins::S3Push::Config do
+  include_context "unit"
+
+  before(:all) do
+    I18n.load_path << Vagrant.source_root.join("plugins/pushes/s3/locales/en.yml")
+    I18n.reload!
+  end
+
+  subject { described_class.new }
+
+  let(:machine) { double("machine") }
+
+  describe "#bucket" do
+    it "defaults to nil" do
+      subject.finalize!
+      expect(subject.bucket).to be(nil)
+    end
+  end
+
+  describe "#prefix" do
+    it "defaults to empty" do
+      subject.finalize!
+      expect(subject.prefix).to eq("")
+    end
+
+    it "removes leading and trailing slashes" do
+      subject.prefix = "/releases/latest/"
+      subject.finalize!
+      expect(subject.prefix).to eq("releases/latest")
+    end
+  end
+
+  describe "#force_path_style" do
+    it "defaults to false" do
+      subject.finalize!
+      expect(subject.force_path_style).to be(false)
+    end
+
+    it "defaults to true with an endpoint" do
+      subject.endpoint = "http://localhost:9000"
+      subject.finalize!
+      expect(subject.force_path_style).to be(true)
+    end
+  end
+
+  describe "#multipart_threshold" do
+    it "defaults to 8 MiB" do
+      subject.finalize!
+      expect(subject.multipart_threshold).to eq(8 * 1024 * 1024)
+    end
+  end
+
+  describe "#dir" do
+    it "defaults to ." do
+      subject.finalize!
+      expect(subject.dir).to eq(".")
+    end
+  end
+
+  describe "#merge" do
+    let(:one) { described_class.new }
+    let(:two) { described_class.new }
+
+    it "merges the includes and excludes" do
+      one.includes = %w(a b)
+      one.excludes = %w(c)
+      two.includes = %w(b d)
+      two.excludes = %w(e)
+      result = one.merge(two)
+      expect(result.includes).to eq(%w(a b d))
+      expect(result.excludes).to eq(%w(c e))
+    end
+  end
+
+  describe "#validate" do
+    before do
+      subject.bucket = "artifacts"
+    end
+
+    let(:result) { subject.validate(machine) }
+    let(:errors) { result["S3 push"] }
+
+    it "is valid with a bucket" do
+      subject.finalize!
+      expect(errors).to be_empty
+    end
+
+    context "when the bucket is missing" do
+      it "returns an error" do
+        subject.bucket = ""
+        subject.finalize!
+        expect(errors).to include(I18n.t("s3_push.errors.missing_attribute",
+          attribute: "bucket",
+        ))
+      end
+    end
+
+    context "when only the access key ID is given" do
+      it "returns an error" do
+        subject.access_key_id = "AKID"
+        subject.finalize!
+        expect(errors).to include(I18n.t("s3_push.errors.incomplete_credentials"))
+      end
+    end
+
+    context "when the multipart threshold is invalid" do
+      it "returns an error" do
+        subject.multipart_threshold = "big"
+        subject.finalize!
+        expect(errors.first).to include("multipart_threshold")
+      end
+    end
+
+    context "when the multipart chunk size is too small" do
+      it "returns an error" do
+        subject.multipart_chunk_size = 1024
+        subject.finalize!
+        expect(errors).to include(I18n.t("s3_push.errors.chunk_size_too_small",
+          minimum: described_class::MINIMUM_PART_SIZE))
+      end
+    end
+  end
+
+  describe "#include" do
+    it "adds the item to the list" do
+      subject.include("me")
+      expect(subject.includes).to include("me")
+    end
+  end
+
+  describe "#exclude" do
+    it "adds the item to the list" do
+      subject.exclude("not me")
+      expect(subject.excludes).to include("not me")
+    end
+  end
+end
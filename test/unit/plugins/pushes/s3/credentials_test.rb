@@ -0,0 +1,165 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require_relative "../../../base"
+
+require Vagrant.source_root.join("plugins/pushes/s3/credentials")
+
+describe VagrantPlugins::S3Push::Credentials do
+  include_context "unit"
+
+  before(:all) do
+    I18n.load_path << Vagrant.source_root.join("plugins/pushes/s3/locales/en.yml")
+    I18n.reload!
+  end
+
+  let(:dir) { Dir.mktmpdir("vagrant-s3-push-credentials") }
+  let(:config) do
+    double("config", access_key_id: nil, secret_access_key: nil, session_token: nil,
+      profile: nil, region: nil)
+  end
+  let(:env) do
+    {
+      "AWS_CONFIG_FILE" => File.join(dir, "config"),
+      "AWS_EC2_METADATA_DISABLED" => "true",
+      "AWS_SHARED_CREDENTIALS_FILE" => File.join(dir, "credentials"),
+    }
+  end
+
+  subject { described_class.new(config, env) }
+
+  after { FileUtils.rm_rf(dir) }
+
+  describe "#resolve" do
+    it "raises an error without credentials" do
+      expect { subject.resolve }.
+        to raise_error(VagrantPlugins::S3Push::Errors::CredentialsMissing)
+    end
+
+    context "with credentials in the configuration" do
+      before do
+        allow(config).to receive(:access_key_id).and_return("CONFIG")
+        allow(config).to receive(:secret_access_key).and_return("SECRET")
+        env["AWS_ACCESS_KEY_ID"] = "ENV"
+        env["AWS_SECRET_ACCESS_KEY"] = "SECRET"
+      end
+
+      it "uses the configuration first" do
+        expect(subject.resolve.access_key_id).to eq("CONFIG")
+      end
+    end
+
+    context "with credentials in the environment" do
+      before do
+        env["AWS_ACCESS_KEY_ID"] = "ENV"
+        env["AWS_SECRET_ACCESS_KEY"] = "SECRET"
+        env["AWS_SESSION_TOKEN"] = "TOKEN"
+      end
+
+      it "uses the environment" do
+        credential = subject.resolve
+        expect(credential.access_key_id).to eq("ENV")
+        expect(credential.session_token).to eq("TOKEN")
+      end
+    end
+
+    context "with a shared credentials file" do
+      before do
+        File.write(env["AWS_SHARED_CREDENTIALS_FILE"], <<-EOF.gsub(/^ {10}/, ""))
+          [default]
+          aws_access_key_id = DEFAULT
+          aws_secret_access_key = SECRET
+
+          [deploy]
+          aws_access_key_id = DEPLOY
+          aws_secret_access_key = SECRET
+        EOF
+      end
+
+      it "uses the default profile" do
+        expect(subject.resolve.access_key_id).to eq("DEFAULT")
+      end
+
+      it "uses the profile from the environment" do
+        env["AWS_PROFILE"] = "deploy"
+        expect(subject.resolve.access_key_id).to eq("DEPLOY")
+      end
+
+      it "uses the profile from the configuration" do
+        env["AWS_PROFILE"] = "other"
+        allow(config).to receive(:profile).and_return("deploy")
+        expect(subject.resolve.access_key_id).to eq("DEPLOY")
+      end
+    end
+
+    context "with credentials in the config file" do
+      before do
+        File.write(env["AWS_CONFIG_FILE"], <<-EOF.gsub(/^ {10}/, ""))
+          [profile deploy]
+          aws_access_key_id = DEPLOY
+          aws_secret_access_key = SECRET
+        EOF
+        env["AWS_PROFILE"] = "deploy"
+      end
+
+      it "uses the profile of the config file" do
+        expect(subject.resolve.access_key_id).to eq("DEPLOY")
+      end
+    end
+
+    context "with the container credentials endpoint" do
+      let(:http) { double("http", :use_ssl= => nil, :open_timeout= => nil, :read_timeout= => nil) }
+      let(:response) { Net::HTTPOK.new("1.1", "200", "OK") }
+
+      before do
+        env["AWS_CONTAINER_CREDENTIALS_RELATIVE_URI"] = "/v2/credentials/ID"
+        allow(response).to receive(:body).and_return(
+          JSON.dump("AccessKeyId" => "CONTAINER", "SecretAccessKey" => "SECRET",
+            "Token" => "TOKEN"))
+      end
+
+      it "uses the credentials of the container" do
+        expect(Net::HTTP).to receive(:new).
+          with(described_class::CONTAINER_CREDENTIALS_HOST, 80, nil).and_return(http)
+        expect(http).to receive(:request) { |request|
+          expect(request.path).to eq("/v2/credentials/ID")
+          response
+        }
+
+        credential = subject.resolve
+        expect(credential.access_key_id).to eq("CONTAINER")
+        expect(credential.session_token).to eq("TOKEN")
+      end
+
+      it "continues when the endpoint is not available" do
+        allow(Net::HTTP).to receive(:new).and_return(http)
+        allow(http).to receive(:request).and_raise(Errno::ECONNREFUSED)
+
+        expect { subject.resolve }.
+          to raise_error(VagrantPlugins::S3Push::Errors::CredentialsMissing)
+      end
+    end
+  end
+
+  describe "#region" do
+    it "defaults to us-east-1" do
+      expect(subject.region).to eq("us-east-1")
+    end
+
+    it "uses the region from the environment" do
+      env["AWS_DEFAULT_REGION"] = "eu-west-1"
+      expect(subject.region).to eq("eu-west-1")
+    end
+
+    it "uses the region of the profile" do
+      File.write(env["AWS_CONFIG_FILE"], "[default]\nregion = ap-south-1\n")
+      expect(subject.region).to eq("ap-south-1")
+    end
+
+    it "uses the region from the configuration first" do
+      env["AWS_REGION"] = "eu-west-1"
+      allow(config).to receive(:region).and_return("us-west-2")
+      expect(subject.region).to eq("us-west-2")
+    end
+  end
+end
@@ -0,0 +1,153 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require_relative "../../../base"
+
+require Vagrant.source_root.join("plugins/pushes/s3/push")
+
+describe VagrantPlugins::S3Push::Push do
+  include_context "unit"
+
+  before(:all) do
+    I18n.load_path << Vagrant.source_root.join("plugins/pushes/s3/locales/en.yml")
+    I18n.reload!
+  end
+
+  let(:env) { isolated_environment.create_vagrant_env }
+  let(:dir) { Dir.mktmpdir("vagrant-s3-push") }
+  let(:config) do
+    double("config",
+      bucket: "artifacts",
+      prefix: "site",
+      endpoint: nil,
+      dir: dir,
+      includes: [],
+      excludes: %w(*.log),
+      multipart_threshold: 10,
+      multipart_chunk_size: 4,
+    )
+  end
+  let(:client) do
+    double("client", close: nil, put_object: nil, abort_multipart_upload: nil,
+      complete_multipart_upload: nil, create_multipart_upload: "UPLOAD")
+  end
+  let(:ui) { Vagrant::UI::Silent.new }
+
+  subject { described_class.new(env, config) }
+
+  before do
+    allow(env).to receive(:ui).and_return(ui)
+    allow(subject).to receive(:client).and_return(client)
+    allow(client).to receive(:upload_part) { |_, _, number, _| "ETAG#{number}" }
+
+    File.write(File.join(dir, "index.html"), "index")
+    FileUtils.mkdir_p(File.join(dir, "assets"))
+    File.write(File.join(dir, "assets", "app.js"), "app")
+    File.write(File.join(dir, "debug.log"), "log")
+    FileUtils.mkdir_p(File.join(dir, ".git"))
+    File.write(File.join(dir, ".git", "HEAD"), "ref")
+  end
+
+  after { FileUtils.rm_rf(dir) }
+
+  def uploaded_keys
+    keys = []
+    allow(client).to receive(:put_object) { |key, *| keys << key }
+    keys
+  end
+
+  describe "#push" do
+    it "uploads the files which are not excluded" do
+      keys = uploaded_keys
+      subject.push
+      expect(keys).to eq(["site/assets/app.js", "site/index.html"])
+    end
+
+    it "does not upload the files again when they did not change" do
+      subject.push
+
+      keys = uploaded_keys
+      subject.push
+      expect(keys).to be_empty
+    end
+
+    it "uploads the files which changed since the last push" do
+      subject.push
+
+      File.write(File.join(dir, "index.html"), "changed")
+      keys = uploaded_keys
+      subject.push
+      expect(keys).to eq(["site/index.html"])
+    end
+
+    it "uploads the files again when the manifest is invalid" do
+      subject.push
+      File.write(subject.manifest_path, "{")
+
+      keys = uploaded_keys
+      subject.push
+      expect(keys.length).to eq(2)
+    end
+
+    context "with includes" do
+      before { allow(config).to receive(:includes).and_return(%w(assets/**/*)) }
+
+      it "only uploads the included files" do
+        keys = uploaded_keys
+        subject.push
+        expect(keys).to eq(["site/assets/app.js"])
+      end
+    end
+
+    context "with a file larger than the multipart threshold" do
+      before { File.write(File.join(dir, "index.html"), "0123456789abc") }
+
+      it "uploads the file in parts" do
+        expect(client).to receive(:upload_part).
+          with("site/index.html", "UPLOAD", 1, "0123").and_return("ETAG1")
+        expect(client).to receive(:upload_part).
+          with("site/index.html", "UPLOAD", 4, "c").and_return("ETAG4")
+        expect(client).to receive(:complete_multipart_upload).
+          with("site/index.html", "UPLOAD", ["ETAG1", "ETAG2", "ETAG3", "ETAG4"])
+        subject.push
+      end
+
+      it "aborts the upload when a part fails" do
+        allow(client).to receive(:upload_part).and_raise(Errno::ECONNRESET)
+        expect(client).to receive(:abort_multipart_upload).with("site/index.html", "UPLOAD")
+
+        expect { subject.push }.
+          to raise_error(VagrantPlugins::S3Push::Errors::UploadFailed)
+      end
+    end
+
+    context "when an upload fails" do
+      before do
+        allow(client).to receive(:put_object) do |key, *|
+          if key == "site/index.html"
+            raise VagrantPlugins::S3Push::Errors::RequestFailed,
+              method: "PUT", key: key, status: "403", message: "Access Denied"
+          end
+        end
+      end
+
+      it "reports the objects which failed" do
+        expect { subject.push }.to raise_error { |error|
+          expect(error).to be_a(VagrantPlugins::S3Push::Errors::UploadFailed)
+          expect(error.message).to include("site/index.html")
+          expect(error.message).to include("Access Denied")
+          expect(error.message).not_to include("site/assets/app.js:")
+        }
+      end
+
+      it "only records the objects which were uploaded" do
+        expect { subject.push }.
+          to raise_error(VagrantPlugins::S3Push::Errors::UploadFailed)
+
+        keys = uploaded_keys
+        subject.push
+        expect(keys).to eq(["site/index.html"])
+      end
+    end
+  end
+end
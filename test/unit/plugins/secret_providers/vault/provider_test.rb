@@ -0,0 +1,95 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require File.expand_path("../../../../base", __FILE__)
+
+require Vagrant.source_root.join("plugins/secret_providers/vault/provider")
+
+describe VagrantPlugins::SecretVault::Provider do
+  include_context "unit"
+
+  let(:response) { double("response", code: code, message: "OK", body: JSON.dump(body)) }
+  let(:code) { "200" }
+  let(:body) { { "data" => { "value" => "from-v1", "token" => "t0ken" } } }
+  let(:http) { double("http") }
+  let(:requests) { [] }
+
+  subject { described_class.new }
+
+  before do
+    @env = ENV.to_h.select { |k, _| k.start_with?("VAULT_") }
+    ENV["VAULT_ADDR"] = "https://vault.example.com:8200"
+    ENV["VAULT_TOKEN"] = "root-token"
+    allow(Net::HTTP).to receive(:start).and_yield(http)
+    allow(http).to receive(:request) { |r| requests << r; response }
+  end
+
+  after do
+    ENV.keys.select { |k| k.start_with?("VAULT_") }.each { |k| ENV.delete(k) }
+    @env.each { |k, v| ENV[k] = v }
+  end
+
+  describe "#resolve" do
+    it "requests the secret with the token" do
+      subject.resolve("secret/app", "token")
+      expect(requests.first.path).to eq("/v1/secret/app")
+      expect(requests.first["X-Vault-Token"]).to eq("root-token")
+      expect(Net::HTTP).to have_received(:start).
+        with("vault.example.com", 8200, hash_including(use_ssl: true))
+    end
+
+    it "reads the field" do
+      expect(subject.resolve("secret/app", "token")).to eq("t0ken")
+    end
+
+    it "reads the value field by default" do
+      expect(subject.resolve("secret/app", nil)).to eq("from-v1")
+    end
+
+    it "returns nil if the field does not exist" do
+      expect(subject.resolve("secret/app", "missing")).to be_nil
+    end
+
+    it "sends the namespace if set" do
+      ENV["VAULT_NAMESPACE"] = "team"
+      subject.resolve("secret/app", nil)
+      expect(requests.first["X-Vault-Namespace"]).to eq("team")
+    end
+
+    context "with a version 2 secret" do
+      let(:body) { { "data" => { "data" => { "token" => "v2-token" }, "metadata" => {} } } }
+
+      it "reads the field from the secret data" do
+        expect(subject.resolve("secret/data/app", "token")).to eq("v2-token")
+      end
+    end
+
+    context "when the secret does not exist" do
+      let(:code) { "404" }
+
+      it "returns nil" do
+        expect(subject.resolve("secret/app", "token")).to be_nil
+      end
+    end
+
+    context "when the request is denied" do
+      let(:code) { "403" }
+
+      it "raises an error" do
+        expect { subject.resolve("secret/app", "token") }.to raise_error(/403/)
+      end
+    end
+
+    context "without a token" do
+      before do
+        ENV.delete("VAULT_TOKEN")
+        allow(File).to receive(:file?).and_call_original
+        allow(File).to receive(:file?).with(File.expand_path("~/.vault-token")).and_return(false)
+      end
+
+      it "raises an error" do
+        expect { subject.resolve("secret/app", "token") }.to raise_error(/VAULT_TOKEN/)
+      end
+    end
+  end
+end
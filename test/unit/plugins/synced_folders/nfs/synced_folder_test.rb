@@ -0,0 +1,80 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require_relative "../../../base"
+
+require Vagrant.source_root.join("plugins/synced_folders/nfs/synced_folder")
+
+describe VagrantPlugins::SyncedFolderNFS::SyncedFolder do
+  include_context "unit"
+
+  let(:iso_env) do
+    # We have to create a Vagrantfile so there is a root path
+    env = isolated_environment
+    env.vagrantfile("")
+    env.create_vagrant_env
+  end
+
+  let(:host)    { double("host") }
+  let(:machine) { iso_env.machine(iso_env.machine_names[0], :dummy) }
+  let(:ips)     { ["192.168.56.10"] }
+  let(:folders) { {"/vagrant" => {hostpath: "/host"}} }
+  let(:pending) { described_class.class_variable_get(:@@pending) }
+  let(:failed)  { described_class.class_variable_get(:@@failed) }
+
+  before do
+    allow(machine).to receive(:id).and_return("first")
+    allow(machine.env).to receive(:host).and_return(host)
+    allow(host).to receive(:capability?).with(:nfs_export_batch).and_return(true)
+  end
+
+  after do
+    pending.clear
+    failed.clear
+  end
+
+  describe "#export" do
+    it "exports the folders of the machine" do
+      expect(host).to receive(:capability).
+        with(:nfs_export_batch, machine.ui, [["first", ips, folders]])
+      subject.send(:export, machine, ips, folders)
+    end
+
+    it "exports the folders of waiting machines in the same pass" do
+      pending[[machine.env.object_id, "second"]] = [["192.168.56.11"], folders]
+      expect(host).to receive(:capability).once.
+        with(:nfs_export_batch, machine.ui, [
+          ["second", ["192.168.56.11"], folders],
+          ["first", ips, folders],
+        ])
+      subject.send(:export, machine, ips, folders)
+      expect(pending).to be_empty
+    end
+
+    it "does not export the folders again once exported by another machine" do
+      expect(host).not_to receive(:capability)
+      allow(pending).to receive(:[]=)
+      subject.send(:export, machine, ips, folders)
+    end
+
+    it "raises the error of the pass which exported the folders" do
+      allow(pending).to receive(:[]=)
+      failed[[machine.env.object_id, "first"]] = Vagrant::Errors::NFSExportsFailed.new(
+        command: "exportfs", stdout: "", stderr: "failed")
+      expect { subject.send(:export, machine, ips, folders) }.
+        to raise_error(Vagrant::Errors::NFSExportsFailed)
+    end
+
+    context "when the host can not export a batch" do
+      before do
+        allow(host).to receive(:capability?).with(:nfs_export_batch).and_return(false)
+      end
+
+      it "exports the folders of the machine" do
+        expect(host).to receive(:capability).
+          with(:nfs_export, machine.ui, "first", ips, folders)
+        subject.send(:export, machine, ips, folders)
+      end
+    end
+  end
+end
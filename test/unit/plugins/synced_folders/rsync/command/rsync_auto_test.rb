@@ -214,6 +214,26 @@ describe VagrantPlugins::SyncedFolderRSync::Command::RsyncAuto do
         to_not raise_error
     end
 
+    it "syncs folders back from the guest first when auto_back is enabled" do
+      paths["/foo"] = [
+        { machine: machine_stub("m1"), opts: double("opts_m1"), auto_back: true },
+      ]
+
+      paths["/foo"].each do |data|
+        expect(helper_class).to receive(:rsync_back).
+          with(data[:machine], data[:machine].ssh_info, data[:opts]).
+          ordered
+        expect(helper_class).to receive(:rsync_single).
+          with(data[:machine], data[:machine].ssh_info, data[:opts]).
+          ordered
+      end
+
+      m = ["/foo/bar"]
+      a = []
+      r = []
+      subject.callback(paths, m, a, r)
+    end
+
     it "doesn't sync machines with no ID" do
       paths["/foo"] = [
         { machine: machine_stub("m1"), opts: double("opts_m1") },
@@ -0,0 +1,128 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require_relative "../../../../base"
+
+require Vagrant.source_root.join("plugins/synced_folders/rsync/command/rsync_back")
+
+describe VagrantPlugins::SyncedFolderRSync::Command::RsyncBack do
+  include_context "unit"
+
+  let(:argv) { [] }
+  let(:vagrantfile_content) { "" }
+  let(:iso_env) do
+    # We have to create a Vagrantfile so there is a root path
+    env = isolated_environment
+    env.vagrantfile(vagrantfile_content)
+    env.create_vagrant_env
+  end
+
+  let(:communicator) { double("comm") }
+
+  let(:synced_folders) { {} }
+
+  let(:helper_class) { VagrantPlugins::SyncedFolderRSync::RsyncHelper }
+
+  subject do
+    described_class.new(argv, iso_env).tap do |s|
+      allow(s).to receive(:synced_folders).and_return(synced_folders)
+    end
+  end
+
+  before do
+    iso_env.machine_names.each do |name|
+      m = iso_env.machine(name, iso_env.default_provider)
+      allow(m).to receive(:communicate).and_return(communicator)
+    end
+  end
+
+  describe "#execute" do
+    context "with a single machine" do
+      let(:ssh_info) {{
+        private_key_path: [],
+      }}
+
+      let(:machine) { iso_env.machine(iso_env.machine_names[0], iso_env.default_provider) }
+
+      before do
+        allow(communicator).to receive(:ready?).and_return(true)
+        allow(machine).to receive(:ssh_info).and_return(ssh_info)
+
+        synced_folders[:rsync] = [
+          [:one, {guestpath: "/one"}],
+          [:two, {guestpath: "/two"}],
+        ]
+      end
+
+      it "doesn't sync if communicator isn't ready and exits with 1" do
+        allow(communicator).to receive(:ready?).and_return(false)
+
+        expect(helper_class).to receive(:rsync_back).never
+
+        expect(subject.execute).to eql(1)
+      end
+
+      it "rsyncs each folder back and exits successfully" do
+        synced_folders[:rsync].each do |_, opts|
+          expect(helper_class).to receive(:rsync_back).
+            with(machine, ssh_info, opts).
+            ordered
+        end
+
+        expect(subject.execute).to eql(0)
+      end
+
+      context "with --no-update option" do
+        let(:argv) { ["--no-update"] }
+
+        it "should disable update on folder options" do
+          synced_folders[:rsync].each do |_, opts|
+            expect(helper_class).to receive(:rsync_back).
+              with(machine, ssh_info, hash_including(update: false)).
+              ordered
+          end
+
+          subject.execute
+        end
+      end
+
+      context "with --guest-path option" do
+        let(:argv) { ["--guest-path", "/two"] }
+
+        it "only rsyncs the matching folder" do
+          expect(helper_class).to receive(:rsync_back).
+            with(machine, ssh_info, hash_including(guestpath: "/two")).once
+
+          expect(subject.execute).to eql(0)
+        end
+
+        context "when the folder is not an rsync folder" do
+          let(:argv) { ["--guest-path", "/three"] }
+
+          it "raises an error" do
+            expect(helper_class).not_to receive(:rsync_back)
+            expect { subject.execute }.
+              to raise_error(Vagrant::Errors::RSyncBackFolderNotFound)
+          end
+        end
+
+        context "when the folder is disabled" do
+          let(:argv) { ["--guest-path", "/disabled"] }
+          let(:vagrantfile_content) do
+            <<-VF
+            Vagrant.configure("2") do |config|
+              config.vm.synced_folder ".", "/disabled", type: "rsync", disabled: true
+            end
+            VF
+          end
+
+          it "raises an error" do
+            expect(helper_class).not_to receive(:rsync_back)
+            expect { subject.execute }.
+              to raise_error(Vagrant::Errors::RSyncBackFolderDisabled)
+          end
+        end
+      end
+    end
+  end
+end
@@ -193,6 +193,93 @@ describe VagrantPlugins::SyncedFolderRSync::RsyncHelper do
       end
     end
 
+    context "with bwlimit option" do
+      it "limits the bandwidth of rsync" do
+        opts[:bwlimit] = "500K"
+
+        expect(Vagrant::Util::Subprocess).to receive(:execute) { |*args|
+          expect(args).to include("--bwlimit=500K")
+          result
+        }
+        subject.rsync_single(machine, ssh_info, opts)
+      end
+
+      it "does not override a limit given in the args" do
+        opts[:bwlimit] = "500K"
+        opts[:args] = ["--archive", "--bwlimit=1M"]
+
+        expect(Vagrant::Util::Subprocess).to receive(:execute) { |*args|
+          expect(args).to include("--bwlimit=1M")
+          expect(args).not_to include("--bwlimit=500K")
+          result
+        }
+        subject.rsync_single(machine, ssh_info, opts)
+      end
+    end
+
+    context "with progress" do
+      let(:rsync_local_version) { "3.1.3" }
+
+      before do
+        allow(subject).to receive(:local_rsync_version).and_return(rsync_local_version)
+        allow(ui).to receive(:clear_line)
+      end
+
+      it "reports the progress of the transfer" do
+        expect(Vagrant::Util::Subprocess).to receive(:execute) { |*args, &block|
+          expect(args).to include("--info=progress2")
+          expect(args.last[:notify]).to eq([:stdout])
+          block.call(:stdout, "      1,024  50%    1.00MB/s    0:00:01 (xfr#1, to-chk=1/2)\r")
+          result
+        }
+        expect(ui).to receive(:detail).with(/50%.*1\.00MB\/s.*0:00:01/, new_line: false)
+        subject.rsync_single(machine, ssh_info, opts, progress: true)
+      end
+
+      it "does not report progress when disabled for the folder" do
+        opts[:progress] = false
+
+        expect(Vagrant::Util::Subprocess).to receive(:execute) { |*args|
+          expect(args).not_to include("--info=progress2")
+          result
+        }
+        subject.rsync_single(machine, ssh_info, opts, progress: true)
+      end
+
+      it "does not report progress with the verbose option" do
+        opts[:verbose] = true
+
+        expect(Vagrant::Util::Subprocess).to receive(:execute) { |*args|
+          expect(args).not_to include("--info=progress2")
+          result
+        }
+        subject.rsync_single(machine, ssh_info, opts, progress: true)
+      end
+
+      context "when local rsync version does not support progress" do
+        let(:rsync_local_version) { "3.0.9" }
+
+        it "does not report progress" do
+          expect(Vagrant::Util::Subprocess).to receive(:execute) { |*args|
+            expect(args).not_to include("--info=progress2")
+            result
+          }
+          subject.rsync_single(machine, ssh_info, opts, progress: true)
+        end
+      end
+    end
+
+    it "stops rsync when interrupted" do
+      expect(Vagrant::Util::Subprocess).to receive(:execute) {
+        Vagrant::Util::Busy.fire_callbacks
+        sleep(5)
+        result
+      }
+
+      expect { subject.rsync_single(machine, ssh_info, opts) }.
+        to raise_error(Vagrant::Errors::VagrantInterrupt)
+    end
+
     context "with rsync_ownership option" do
       let(:rsync_local_version) { "3.1.1" }
       let(:rsync_remote_version) { "3.1.1" }
@@ -277,6 +364,28 @@ describe VagrantPlugins::SyncedFolderRSync::RsyncHelper do
       end
     end
 
+    context "permissions" do
+      it "sets the mode of the files" do
+        opts[:mode] = "0755"
+
+        expect(Vagrant::Util::Subprocess).to receive(:execute).with(any_args) { |*args|
+          expect(args).to include("--chmod=D0755,F0755")
+        }.and_return(result)
+
+        subject.rsync_single(machine, ssh_info, opts)
+      end
+
+      it "write protects the files of a read-only folder" do
+        opts[:readonly] = true
+
+        expect(Vagrant::Util::Subprocess).to receive(:execute).with(any_args) { |*args|
+          expect(args).to include("--chmod=ugo-w")
+        }.and_return(result)
+
+        subject.rsync_single(machine, ssh_info, opts)
+      end
+    end
+
     context "custom arguments" do
       it "uses the default arguments if not given" do
         expect(Vagrant::Util::Subprocess).to receive(:execute).with(any_args) { |*args|
@@ -335,6 +444,85 @@ describe VagrantPlugins::SyncedFolderRSync::RsyncHelper do
     end
   end
 
+  describe "#rsync_back" do
+    let(:result) { Vagrant::Util::Subprocess::Result.new(0, "", "") }
+
+    let(:ssh_info) {{
+      private_key_path: [],
+    }}
+    let(:opts)      {{
+      hostpath: "/foo",
+      guestpath: "/bar",
+    }}
+
+    before do
+      allow(FileUtils).to receive(:mkdir_p)
+      allow(Vagrant::Util::Subprocess).to receive(:execute){ result }
+
+      allow(guest).to receive(:capability?){ false }
+    end
+
+    it "syncs the guest path to the hostpath" do
+      expect(Vagrant::Util::Subprocess).to receive(:execute).with(any_args) { |*args|
+        expected = Vagrant::Util::Platform.fs_real_path("/foo").to_s
+        expect(args[args.length - 3]).to end_with(":/bar/")
+        expect(args[args.length - 2]).to eql("#{expected}/")
+      }.and_return(result)
+
+      subject.rsync_back(machine, ssh_info, opts)
+    end
+
+    it "creates the hostpath" do
+      expect(FileUtils).to receive(:mkdir_p).with("/foo")
+
+      subject.rsync_back(machine, ssh_info, opts)
+    end
+
+    it "does not delete files on the host" do
+      opts[:args] = ["--archive", "--delete", "--delete-excluded"]
+
+      expect(Vagrant::Util::Subprocess).to receive(:execute).with(any_args) { |*args|
+        expect(args).to include("--archive")
+        expect(args.any? { |arg| arg.to_s.start_with?("--delete") }).to be(false)
+      }.and_return(result)
+
+      subject.rsync_back(machine, ssh_info, opts)
+    end
+
+    it "skips files that are newer on the host" do
+      expect(Vagrant::Util::Subprocess).to receive(:execute).with(any_args) { |*args|
+        expect(args).to include("--update")
+      }.and_return(result)
+
+      subject.rsync_back(machine, ssh_info, opts)
+    end
+
+    it "replaces newer files on the host when update is disabled" do
+      opts[:update] = false
+
+      expect(Vagrant::Util::Subprocess).to receive(:execute).with(any_args) { |*args|
+        expect(args).not_to include("--update")
+      }.and_return(result)
+
+      subject.rsync_back(machine, ssh_info, opts)
+    end
+
+    it "does not run the guest rsync capabilities" do
+      expect(guest).not_to receive(:capability?).with(:rsync_pre)
+      expect(guest).not_to receive(:capability?).with(:rsync_post)
+
+      subject.rsync_back(machine, ssh_info, opts)
+    end
+
+    it "raises an error if the folder is disabled" do
+      opts[:disabled] = true
+
+      expect(Vagrant::Util::Subprocess).not_to receive(:execute)
+      expect { subject.rsync_back(machine, ssh_info, opts) }.
+        to raise_error(Vagrant::Errors::RSyncBackFolderDisabled)
+    end
+  end
+
   describe "#rsync_single with custom ssh_info" do
     let(:result) { Vagrant::Util::Subprocess::Result.new(0, "", "") }
 
@@ -0,0 +1,53 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require_relative "../../../base"
+
+require Vagrant.source_root.join("plugins/synced_folders/rsync/progress")
+
+describe VagrantPlugins::SyncedFolderRSync::RsyncProgress do
+  include_context "unit"
+
+  let(:ui) { Vagrant::UI::Basic.new }
+
+  subject { described_class.new(ui) }
+
+  before do
+    allow(ui).to receive(:clear_line)
+  end
+
+  it "reports the progress of the transfer" do
+    expect(ui).to receive(:detail).
+      with("Progress: 42% (Transferred: 1,238,099, Rate: 13.47MB/s, Estimated time remaining: 0:00:05)",
+        new_line: false)
+
+    subject.update("      1,238,099  42%   13.47MB/s    0:00:05 (xfr#13, to-chk=52/80)\r")
+  end
+
+  it "reports progress split across updates" do
+    expect(ui).to receive(:detail).with(/Progress: 42%/, new_line: false)
+
+    subject.update("      1,238,099  42%   13.47M")
+    subject.update("B/s    0:00:05 (xfr#13, to-chk=52/80)\r")
+  end
+
+  it "ignores other output" do
+    expect(ui).not_to receive(:detail)
+
+    subject.update("sending incremental file list\n")
+    subject.update("foo/bar.txt\n")
+  end
+
+  it "clears the progress when finished" do
+    allow(ui).to receive(:detail)
+    subject.update("      1,238,099 100%   13.47MB/s    0:00:05 (xfr#13, to-chk=0/80)\n")
+
+    expect(ui).to receive(:clear_line)
+    subject.finish
+  end
+
+  it "does not clear the line when no progress was reported" do
+    expect(ui).not_to receive(:clear_line)
+    subject.finish
+  end
+end
@@ -62,7 +62,7 @@ describe VagrantPlugins::SyncedFolderRSync::SyncedFolder do
 
       folders.each do |_, opts|
         expect(helper_class).to receive(:rsync_single).
-          with(machine, ssh_info, opts).
+          with(machine, ssh_info, opts, progress: true).
           ordered
       end
 
@@ -92,6 +92,25 @@ describe VagrantPlugins::SyncedFolderSMB::Cap::MountOptions do
         expect(out_gid).to eq(mount_gid)
       end
 
+      it "sets the mode of the files" do
+        folder_options[:mode] = "0755"
+        out_opts, _, _ = cap.mount_options(machine, mount_name, mount_guest_path, folder_options)
+        expect(out_opts).to eq("sec=ntlmssp,credentials=/etc/smb_creds_vagrant,uid=1000,gid=1000,dir_mode=0755,file_mode=0755,_netdev")
+      end
+
+      it "mounts read-only folders with the ro option" do
+        folder_options[:readonly] = true
+        out_opts, _, _ = cap.mount_options(machine, mount_name, mount_guest_path, folder_options)
+        expect(out_opts).to eq("sec=ntlmssp,credentials=/etc/smb_creds_vagrant,uid=1000,gid=1000,ro,_netdev")
+      end
+
+      it "does not add the ro option twice" do
+        folder_options[:readonly] = true
+        folder_options[:mount_options] = ["ro"]
+        out_opts, _, _ = cap.mount_options(machine, mount_name, mount_guest_path, folder_options)
+        expect(out_opts.split(",").count("ro")).to eq(1)
+      end
+
       it "does not add mfsymlinks option if env var VAGRANT_DISABLE_SMBMFSYMLINKS exists" do
         expect(ENV).to receive(:[]).with("VAGRANT_DISABLE_SMBMFSYMLINKS").and_return(false)
         out_opts, out_uid, out_gid = cap.mount_options(machine, mount_name, mount_guest_path, folder_options)
@@ -39,4 +39,57 @@ describe VagrantPlugins::SyncedFolder::UnixMountHelpers do
       end
     end
   end
+
+  describe ".verify_readonly_mount" do
+    let(:machine) { double("machine", communicate: comm) }
+    let(:comm) { VagrantTests::DummyCommunicator::Communicator.new(machine) }
+    let(:mounts) { "" }
+
+    before { comm.stub_command("cat /proc/mounts", stdout: mounts) }
+
+    context "when the folder is mounted read-only" do
+      let(:mounts) { "vagrant /vagrant vboxsf ro,relatime 0 0\n" }
+
+      it "does not raise an error" do
+        expect { subject.verify_readonly_mount(machine, "/vagrant") }.not_to raise_error
+      end
+
+      it "ignores trailing slashes of the path" do
+        expect { subject.verify_readonly_mount(machine, "/vagrant/") }.not_to raise_error
+      end
+    end
+
+    context "when the path contains spaces" do
+      let(:mounts) { "vagrant /my\\040folder vboxsf ro,relatime 0 0\n" }
+
+      it "decodes the path of the mount" do
+        expect { subject.verify_readonly_mount(machine, "/my folder") }.not_to raise_error
+      end
+    end
+
+    context "when the folder is mounted read-write" do
+      let(:mounts) { "vagrant /vagrant vboxsf rw,relatime 0 0\n" }
+
+      it "raises an error" do
+        expect { subject.verify_readonly_mount(machine, "/vagrant") }.
+          to raise_error(Vagrant::Errors::SyncedFolderReadonlyFailed)
+      end
+    end
+
+    context "when the folder is mounted over a read-only mount" do
+      let(:mounts) { "vagrant /vagrant vboxsf ro 0 0\nother /vagrant vboxsf rw 0 0\n" }
+
+      it "raises an error" do
+        expect { subject.verify_readonly_mount(machine, "/vagrant") }.
+          to raise_error(Vagrant::Errors::SyncedFolderReadonlyFailed)
+      end
+    end
+
+    context "when the folder is not mounted" do
+      it "raises an error" do
+        expect { subject.verify_readonly_mount(machine, "/vagrant") }.
+          to raise_error(Vagrant::Errors::SyncedFolderReadonlyFailed)
+      end
+    end
+  end
 end
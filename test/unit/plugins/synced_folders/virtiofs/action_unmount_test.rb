@@ -0,0 +1,67 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require_relative "../../../base"
+
+require Vagrant.source_root.join("plugins/synced_folders/virtiofs/action_unmount")
+
+describe VagrantPlugins::SyncedFolderVirtiofs::ActionUnmount do
+  include_context "unit"
+
+  let(:iso_env) do
+    # We have to create a Vagrantfile so there is a root path
+    env = isolated_environment
+    env.vagrantfile("")
+    env.create_vagrant_env
+  end
+
+  let(:guest) { double("guest") }
+  let(:communicator) { double("communicator", ready?: true) }
+  let(:machine) { iso_env.machine(iso_env.machine_names[0], :dummy) }
+  let(:folders) { {"/vagrant" => {hostpath: "/host", guestpath: "/vagrant"}} }
+
+  let(:app) { lambda { |_env| } }
+  let(:env) { { machine: machine, ui: Vagrant::UI::Silent.new } }
+
+  subject { described_class.new(app, env) }
+
+  before do
+    allow(machine).to receive(:guest).and_return(guest)
+    allow(machine).to receive(:communicate).and_return(communicator)
+    allow(guest).to receive(:capability?).with(:unmount_virtiofs_shared_folder).and_return(true)
+    allow(subject).to receive(:synced_folders).with(machine, cached: true).
+      and_return(virtiofs: folders)
+  end
+
+  it "unmounts the virtiofs folders before halting" do
+    expect(guest).to receive(:capability).
+      with(:unmount_virtiofs_shared_folder, "/vagrant", folders["/vagrant"]).ordered
+    expect(app).to receive(:call).with(env).ordered
+
+    subject.call(env)
+  end
+
+  it "does nothing when the machine is forced to halt" do
+    env[:force_halt] = true
+    expect(guest).not_to receive(:capability)
+    expect(app).to receive(:call).with(env)
+
+    subject.call(env)
+  end
+
+  it "does nothing when the machine can not be reached" do
+    allow(communicator).to receive(:ready?).and_return(false)
+    expect(guest).not_to receive(:capability)
+    expect(app).to receive(:call).with(env)
+
+    subject.call(env)
+  end
+
+  it "does nothing without virtiofs folders" do
+    allow(subject).to receive(:synced_folders).and_return({})
+    expect(guest).not_to receive(:capability)
+    expect(app).to receive(:call).with(env)
+
+    subject.call(env)
+  end
+end
@@ -0,0 +1,171 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require_relative "../../../base"
+
+require Vagrant.source_root.join("plugins/synced_folders/virtiofs/synced_folder")
+
+describe VagrantPlugins::SyncedFolderVirtiofs::SyncedFolder do
+  include_context "unit"
+
+  let(:iso_env) do
+    env = isolated_environment
+    env.vagrantfile("")
+    env.create_vagrant_env
+  end
+
+  let(:guest) { double("guest", name: :linux) }
+  let(:provider) { double("provider") }
+  let(:communicator) { double("communicator", ready?: true) }
+  let(:machine) { iso_env.machine(iso_env.machine_names[0], :dummy) }
+  let(:provider_caps) { [:virtiofs_attach] }
+  let(:guest_caps) { [:mount_virtiofs_shared_folder, :unmount_virtiofs_shared_folder] }
+  let(:folders) { {"/vagrant" => {hostpath: "/host", guestpath: "/vagrant"}} }
+
+  before do
+    allow(machine).to receive(:guest).and_return(guest)
+    allow(machine).to receive(:provider).and_return(provider)
+    allow(machine).to receive(:communicate).and_return(communicator)
+    allow(provider).to receive(:capability?).and_return(false)
+    provider_caps.each do |cap|
+      allow(provider).to receive(:capability?).with(cap).and_return(true)
+      allow(provider).to receive(:capability).with(cap, any_args)
+    end
+    allow(guest).to receive(:capability?).and_return(false)
+    guest_caps.each do |cap|
+      allow(guest).to receive(:capability?).with(cap).and_return(true)
+      allow(guest).to receive(:capability).with(cap, any_args)
+    end
+  end
+
+  describe "#usable?" do
+    it "is usable when the provider and guest support virtiofs" do
+      expect(subject.usable?(machine)).to be(true)
+    end
+
+    context "when the provider does not support virtiofs" do
+      let(:provider_caps) { [] }
+
+      it "is not usable" do
+        expect(subject.usable?(machine)).to be(false)
+      end
+
+      it "raises an error when raise_error enabled" do
+        expect { subject.usable?(machine, true) }.
+          to raise_error(VagrantPlugins::SyncedFolderVirtiofs::Errors::ProviderUnsupported)
+      end
+    end
+
+    context "when the guest does not support virtiofs" do
+      let(:guest_caps) { [] }
+
+      it "is not usable" do
+        expect(subject.usable?(machine)).to be(false)
+      end
+
+      it "raises an error when raise_error enabled" do
+        expect { subject.usable?(machine, true) }.
+          to raise_error(VagrantPlugins::SyncedFolderVirtiofs::Errors::GuestUnsupported)
+      end
+    end
+
+    context "when the guest can not be reached" do
+      let(:communicator) { double("communicator", ready?: false) }
+
+      it "is not usable without a configured guest" do
+        expect(machine.config.vm).to receive(:guest).and_return(nil)
+        expect(subject.usable?(machine)).to be(false)
+      end
+
+      it "checks the capabilities of the configured guest" do
+        expect(machine.config.vm).to receive(:guest).and_return(:ubuntu)
+        expect(subject.usable?(machine)).to be(true)
+      end
+    end
+  end
+
+  describe "#prepare" do
+    it "attaches the devices with the tags of the folders" do
+      expect(provider).to receive(:capability).with(:virtiofs_attach, folders)
+
+      subject.prepare(machine, folders, {})
+      expect(folders["/vagrant"][:virtiofs_tag]).to match(/\Avagrant-[0-9a-f]{28}\z/)
+    end
+
+    it "derives unique tags from long IDs" do
+      first = "/#{"a" * 50}/first"
+      second = "/#{"a" * 50}/second"
+      folders[first] = {hostpath: "/first", guestpath: first}
+      folders[second] = {hostpath: "/second", guestpath: second}
+
+      subject.prepare(machine, folders, {})
+      expect(folders[first][:virtiofs_tag].length).to eq(36)
+      expect(folders[first][:virtiofs_tag]).not_to eq(folders[second][:virtiofs_tag])
+    end
+
+    it "accepts valid cache and dax modes" do
+      folders["/vagrant"].merge!(virtiofs_cache: "always", virtiofs_dax: :inode)
+
+      expect { subject.prepare(machine, folders, {}) }.not_to raise_error
+    end
+
+    it "raises an error for an invalid cache mode" do
+      folders["/vagrant"][:virtiofs_cache] = "sometimes"
+
+      expect { subject.prepare(machine, folders, {}) }.
+        to raise_error(VagrantPlugins::SyncedFolderVirtiofs::Errors::OptionInvalid)
+    end
+
+    it "raises an error for an invalid dax mode" do
+      folders["/vagrant"][:virtiofs_dax] = "maybe"
+
+      expect { subject.prepare(machine, folders, {}) }.
+        to raise_error(VagrantPlugins::SyncedFolderVirtiofs::Errors::OptionInvalid)
+    end
+  end
+
+  describe "#enable" do
+    it "mounts the folders" do
+      expect(guest).to receive(:capability).
+        with(:mount_virtiofs_shared_folder, /\Avagrant-/, "/vagrant", folders["/vagrant"])
+
+      subject.enable(machine, folders, {})
+    end
+
+    it "uses the tag of the folder" do
+      folders["/vagrant"][:virtiofs_tag] = "custom"
+      expect(guest).to receive(:capability).
+        with(:mount_virtiofs_shared_folder, "custom", "/vagrant", anything)
+
+      subject.enable(machine, folders, {})
+    end
+
+    context "when the guest does not support virtiofs" do
+      let(:guest_caps) { [] }
+
+      it "raises an error" do
+        expect { subject.enable(machine, folders, {}) }.
+          to raise_error(Vagrant::Errors::GuestCapabilityNotFound)
+      end
+    end
+  end
+
+  describe "#disable" do
+    it "unmounts the folders" do
+      expect(guest).to receive(:capability).
+        with(:unmount_virtiofs_shared_folder, "/vagrant", folders["/vagrant"])
+
+      subject.disable(machine, folders, {})
+    end
+
+    context "when the provider can detach devices" do
+      let(:provider_caps) { [:virtiofs_attach, :virtiofs_detach] }
+
+      it "detaches the devices" do
+        expect(provider).to receive(:capability).with(:virtiofs_detach, folders)
+
+        subject.disable(machine, folders, {})
+      end
+    end
+  end
+end
@@ -89,7 +89,7 @@ describe Vagrant::Action::Builtin::BoxAdd, :skip_windows, :bsdtar do
 
   context "the download location is locked" do
     let(:box_path) { iso_env.box2_file(:virtualbox) }
-    let(:mutex_path) {  env[:tmp_path].join("box" + Digest::SHA1.hexdigest("file://" + box_path.to_s) + ".lock").to_s }
+    let(:mutex_path) {  env[:tmp_path].join("box" + Digest::SHA1.hexdigest("file://" + box_path.to_s) + ".part.lock").to_s }
 
     before do
       # Lock file
@@ -109,6 +109,43 @@ describe Vagrant::Action::Builtin::BoxAdd, :skip_windows, :bsdtar do
   end
 
   context "with box file directly" do
+    context "with a derived box" do
+      let(:action_runner) { double("action_runner") }
+      let(:base_error) {
+        Vagrant::Errors::BoxBaseNotFound.new(
+          box: "foo", name: "base", version: "1.0", provider: "virtualbox", url: nil)
+      }
+
+      before do
+        env[:box_name] = "foo"
+        env[:box_url] = iso_env.box2_file(:virtualbox).to_s
+        env[:action_runner] = action_runner
+      end
+
+      it "adds the base box first" do
+        expect(box_collection).to receive(:add).ordered.and_raise(base_error)
+        expect(action_runner).to receive(:run).with(anything, hash_including(
+          box_name: "base",
+          box_url: "base",
+          box_version: "1.0",
+          box_provider: "virtualbox",
+        )).ordered
+        expect(box_collection).to receive(:add).ordered.and_return(box)
+        expect(app).to receive(:call).with(env)
+
+        subject.call(env)
+      end
+
+      it "raises an error naming the base box if it can't be added" do
+        expect(box_collection).to receive(:add).and_raise(base_error)
+        expect(action_runner).to receive(:run).and_raise(Vagrant::Errors::VagrantError)
+
+        expect { subject.call(env) }.to raise_error(Vagrant::Errors::BoxBaseUnavailable) { |e|
+          expect(e.message).to include("base")
+        }
+      end
+    end
+
     it "adds it" do
       box_path = iso_env.box2_file(:virtualbox)
 
@@ -235,6 +272,58 @@ describe Vagrant::Action::Builtin::BoxAdd, :skip_windows, :bsdtar do
         to raise_error(Vagrant::Errors::BoxChecksumMismatch)
     end
 
+    it "removes the downloaded box if checksum doesn't match" do
+      box_path = iso_env.box2_file(:virtualbox)
+
+      env[:box_name] = "foo"
+      env[:box_url] = box_path.to_s
+      env[:box_checksum] = checksum(box_path) + "A"
+      env[:box_checksum_type] = "sha1"
+
+      expect { subject.call(env) }.
+        to raise_error(Vagrant::Errors::BoxChecksumMismatch) { |err|
+          expect(err.message).to include(checksum(box_path) + "A")
+          expect(err.message).to include(checksum(box_path))
+        }
+      temp_path = env[:tmp_path].join(
+        "box" + Digest::SHA1.hexdigest("file://" + box_path.to_s) + ".part")
+      expect(temp_path).not_to exist
+      expect(box_path).to be_file
+    end
+
+    it "validates sha512 checksums" do
+      box_path = iso_env.box2_file(:virtualbox)
+
+      box = double(
+        name: "foo",
+        version: "1.2.3",
+        provider: "virtualbox",
+      )
+
+      env[:box_name] = "foo"
+      env[:box_url] = box_path.to_s
+      env[:box_checksum] = FileChecksum.new(box_path, :sha512).checksum
+      env[:box_checksum_type] = "sha512"
+
+      expect(box_collection).to receive(:add).and_return(box)
+
+      expect { subject.call(env) }.to_not raise_error
+    end
+
+    it "raises an error if checksum type is minisign and no key is provided" do
+      box_path = iso_env.box2_file(:virtualbox)
+
+      env[:box_name] = "foo"
+      env[:box_url] = box_path.to_s
+      env[:box_checksum] = "signature"
+      env[:box_checksum_type] = "minisign"
+
+      expect(box_collection).to receive(:add).never
+
+      expect { subject.call(env) }.
+        to raise_error(Vagrant::Errors::BoxSignatureKeyMissing)
+    end
+
     it "strips space if checksum specified ends or begins with blank space" do
       box_path = iso_env.box2_file(:virtualbox)
 
@@ -1862,6 +1951,35 @@ describe Vagrant::Action::Builtin::BoxAdd, :skip_windows, :bsdtar do
         end
       end
 
+      context "when resume is not set" do
+        it "should enable continue" do
+          expect(Vagrant::Util::Downloader).to receive(:new) do |_, _, opts|
+            expect(opts[:continue]).to be(true)
+          end
+        end
+      end
+
+      context "when resume is disabled" do
+        let(:temp_path) { env[:tmp_path].join("box" + Digest::SHA1.hexdigest(url) + ".part") }
+
+        before do
+          env[:box_download_resume] = false
+          temp_path.write("partial")
+        end
+
+        it "should disable continue" do
+          expect(Vagrant::Util::Downloader).to receive(:new) do |_, _, opts|
+            expect(opts[:continue]).to be(false)
+          end
+        end
+
+        it "should remove the partial download" do
+          allow(Vagrant::Util::Downloader).to receive(:new)
+          subject.send(:downloader, url, env)
+          expect(temp_path).not_to exist
+        end
+      end
+
       context "when disable ssl revoke best effort is set" do
         before { env[:box_download_disable_ssl_revoke_best_effort] = true }
 
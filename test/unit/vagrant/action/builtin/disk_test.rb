@@ -12,7 +12,7 @@ describe Vagrant::Action::Builtin::Disk do
                          provider_name: "provider", data_dir: Pathname.new("/fake/dir")) }
   let(:env) { { ui: ui, machine: machine} }
 
-  let(:disks) { [double("disk")] }
+  let(:disks) { [double("disk", name: "storage", primary: false, size: 1024)] }
 
   let(:ui)  { Vagrant::UI::Silent.new }
 
@@ -46,17 +46,49 @@ describe Vagrant::Action::Builtin::Disk do
       subject.call(env)
     end
 
-    it "prints a warning if disk config capability is unsupported" do
+    it "raises an error if disk config capability is unsupported" do
       allow(vm).to receive(:disks).and_return(disks)
+      allow(machine).to receive(:name).and_return("default")
       allow(machine.provider).to receive(:capability?).with(:configure_disks).and_return(false)
       subject = described_class.new(app, env)
-      allow(subject).to receive(:write_disk_metadata)
 
-      expect(app).to receive(:call).with(env).ordered
+      expect(app).not_to receive(:call)
       expect(machine.provider).not_to receive(:capability).with(:configure_disks, disks)
-      expect(ui).to receive(:warn)
+      expect(subject).not_to receive(:write_disk_metadata)
 
-      subject.call(env)
+      expect { subject.call(env) }.
+        to raise_error(Vagrant::Errors::DisksProviderUnsupported)
+    end
+
+    context "with a primary disk size" do
+      let(:primary) { double("primary", name: "vagrant_primary", primary: true, size: 42949672960) }
+      let(:disks) { [primary] }
+
+      before do
+        allow(vm).to receive(:disks).and_return(disks)
+        allow(machine).to receive(:name).and_return("default")
+        allow(machine.provider).to receive(:capability?).with(:configure_disks).and_return(true)
+        allow(machine.provider).to receive(:capability).with(:configure_disks, disks).and_return(disk_data)
+      end
+
+      it "notifies the user the primary disk will be grown" do
+        subject = described_class.new(app, env)
+        allow(subject).to receive(:write_disk_metadata)
+        allow(subject).to receive(:read_disk_metadata).and_return({})
+
+        expect(ui).to receive(:detail).with(/primary disk 'vagrant_primary' will be grown to 40GB/)
+        subject.call(env)
+      end
+
+      it "does not notify the user if the primary disk was previously configured" do
+        subject = described_class.new(app, env)
+        allow(subject).to receive(:write_disk_metadata)
+        allow(subject).to receive(:read_disk_metadata).
+          and_return({"disk" => [{"name" => "vagrant_primary"}]})
+
+        expect(ui).not_to receive(:detail)
+        subject.call(env)
+      end
     end
 
     it "writes down a disk_meta file if disks are configured" do
@@ -67,4 +99,29 @@ describe Vagrant::Action::Builtin::Disk do
       subject.write_disk_metadata(machine, disk_data)
     end
   end
+
+  describe "#primary_resize" do
+    let(:primary) { double("primary", name: "vagrant_primary", primary: true, size: 1024) }
+    let(:storage) { double("storage", name: "storage", primary: false, size: 1024) }
+
+    subject { described_class.new(app, env) }
+
+    it "returns the primary disk if it has not been configured" do
+      expect(subject.primary_resize([primary, storage], {})).to eq(primary)
+    end
+
+    it "does not return a primary disk which has been configured" do
+      meta = {"disk" => [{"name" => "vagrant_primary"}], "dvd" => [], "floppy" => []}
+      expect(subject.primary_resize([primary, storage], meta)).to be_nil
+    end
+
+    it "does not return secondary disks" do
+      expect(subject.primary_resize([storage], {})).to be_nil
+    end
+
+    it "does not return a primary disk without a size" do
+      allow(primary).to receive(:size).and_return(nil)
+      expect(subject.primary_resize([primary], {})).to be_nil
+    end
+  end
 end
@@ -11,15 +11,25 @@ describe Vagrant::Action::Builtin::GracefulHalt do
     allow(result).to receive(:config).and_return(machine_config)
     allow(result).to receive(:guest).and_return(machine_guest)
     allow(result).to receive(:state).and_return(machine_state)
+    allow(result).to receive(:provider).and_return(machine_provider)
+    allow(result).to receive(:communicate).and_return(communicator)
     result
   end
+  let(:halt_command) { nil }
   let(:machine_config) do
     double("machine_config").tap do |top_config|
       vm_config = double("machine_vm_config")
       allow(vm_config).to receive(:graceful_halt_timeout).and_return(10)
+      allow(vm_config).to receive(:graceful_halt_command).and_return(halt_command)
       allow(top_config).to receive(:vm).and_return(vm_config)
     end
   end
+  let(:machine_provider) do
+    double("machine_provider").tap do |result|
+      allow(result).to receive(:capability?).with(:acpi_shutdown).and_return(false)
+    end
+  end
+  let(:communicator) { double("communicator") }
   let(:machine_guest) { double("machine_guest") }
   let(:machine_state) do
     double("machine_state").tap do |result|
@@ -59,5 +69,84 @@ describe Vagrant::Action::Builtin::GracefulHalt do
     described_class.new(app, env, target_state).call(env)
 
     expect(env[:result]).to eq(true)
+    expect(env[:halt_method]).to eq(:guest)
+  end
+
+  context "with a halt command configured" do
+    let(:halt_command) { "poweroff -f" }
+
+    it "runs the command instead of the halt capability" do
+      expect(machine_guest).not_to receive(:capability)
+      expect(communicator).to receive(:sudo).with(halt_command, error_check: false)
+      allow(machine_state).to receive(:id).and_return(target_state)
+
+      described_class.new(app, env, target_state).call(env)
+
+      expect(env[:result]).to eq(true)
+    end
+
+    it "ignores the connection being lost" do
+      expect(communicator).to receive(:sudo).and_raise(IOError)
+      allow(machine_state).to receive(:id).and_return(target_state)
+
+      described_class.new(app, env, target_state).call(env)
+
+      expect(env[:result]).to eq(true)
+    end
+  end
+
+  context "when the guest does not halt" do
+    let(:subject) { described_class.new(app, env, target_state) }
+
+    before do
+      allow(machine_guest).to receive(:capability).with(:halt)
+      allow(subject).to receive(:wait_for_target_state).and_return(false)
+    end
+
+    it "does not succeed if ACPI shutdown is not supported" do
+      subject.call(env)
+
+      expect(env[:result]).to eq(false)
+      expect(env[:halt_method]).to be_nil
+    end
+
+    context "with ACPI shutdown supported" do
+      before do
+        allow(machine_provider).to receive(:capability?).with(:acpi_shutdown).and_return(true)
+      end
+
+      it "sends the ACPI shutdown signal and waits for the target state" do
+        expect(machine_provider).to receive(:capability).with(:acpi_shutdown)
+        expect(subject).to receive(:wait_for_target_state).and_return(false, true)
+        allow(machine_state).to receive(:id).and_return(:unknown, target_state)
+
+        subject.call(env)
+
+        expect(env[:result]).to eq(true)
+        expect(env[:halt_method]).to eq(:acpi)
+      end
+
+      it "does not succeed if the machine does not halt" do
+        expect(machine_provider).to receive(:capability).with(:acpi_shutdown)
+
+        subject.call(env)
+
+        expect(env[:result]).to eq(false)
+      end
+    end
+
+    context "when the guest is not ready" do
+      before do
+        allow(machine_guest).to receive(:capability).with(:halt).
+          and_raise(Vagrant::Errors::MachineGuestNotReady)
+        allow(machine_provider).to receive(:capability?).with(:acpi_shutdown).and_return(true)
+      end
+
+      it "sends the ACPI shutdown signal" do
+        expect(machine_provider).to receive(:capability).with(:acpi_shutdown)
+
+        subject.call(env)
+      end
+    end
   end
 end
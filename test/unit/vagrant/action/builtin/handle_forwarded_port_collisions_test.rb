@@ -102,6 +102,27 @@ describe Vagrant::Action::Builtin::HandleForwardedPortCollisions do
             it "should automatically correct collision" do
               expect{ instance.call(env) }.not_to raise_error
             end
+
+            context "with a host range" do
+              let(:extra_in_use){ [8080, 8081] }
+              let(:collision_port_check){ lambda { |host_port| host_port == 8082 } }
+
+              before{ port_options[:host_range] = 8080..8084 }
+
+              it "should use the first free port in the range" do
+                instance.call(env)
+                expect(port_options[:host]).to eq(8083)
+              end
+
+              context "when all ports in the range are in use" do
+                before{ port_options[:host_range] = 8080..8082 }
+
+                it "should raise a range exhausted error" do
+                  expect{ instance.call(env) }.
+                    to raise_error(Vagrant::Errors::ForwardPortHostRangeExhausted)
+                end
+              end
+            end
           end
         end
       end
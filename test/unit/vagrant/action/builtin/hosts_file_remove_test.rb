@@ -0,0 +1,41 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require File.expand_path("../../../../base", __FILE__)
+
+describe Vagrant::Action::Builtin::HostsFileRemove do
+  let(:env) { { machine: machine, ui: ui, env: vagrant_env } }
+  let(:app) { lambda { |env| } }
+  let(:machine) { double("machine", name: "default", index_uuid: "uuid", id: "id") }
+  let(:vagrant_env) { double("vagrant_env", host: host) }
+  let(:host) { double("host") }
+  let(:ui) { Vagrant::UI::Silent.new }
+
+  subject { described_class.new(app, env) }
+
+  before do
+    allow(host).to receive(:capability?).with(:hosts_file_remove).and_return(true)
+  end
+
+  it "removes the hosts file entries before calling the next middleware" do
+    called = false
+    app = lambda { |_| called = true }
+    expect(host).to receive(:capability).with(:hosts_file_remove, "uuid") {
+      expect(called).to be(false)
+    }
+    described_class.new(app, env).call(env)
+    expect(called).to be(true)
+  end
+
+  it "uses the machine ID when the machine is not in the index" do
+    allow(machine).to receive(:index_uuid).and_return(nil)
+    expect(host).to receive(:capability).with(:hosts_file_remove, "id")
+    subject.call(env)
+  end
+
+  it "does nothing if the host does not support the capability" do
+    allow(host).to receive(:capability?).with(:hosts_file_remove).and_return(false)
+    expect(host).not_to receive(:capability)
+    subject.call(env)
+  end
+end
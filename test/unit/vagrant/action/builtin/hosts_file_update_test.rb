@@ -0,0 +1,82 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require File.expand_path("../../../../base", __FILE__)
+
+describe Vagrant::Action::Builtin::HostsFileUpdate do
+  let(:env) { { machine: machine, ui: ui, env: vagrant_env } }
+  let(:app) { lambda { |env| } }
+  let(:machine) { double("machine", name: "default", index_uuid: "uuid", id: "id") }
+  let(:vagrant_env) { double("vagrant_env", host: host) }
+  let(:host) { double("host") }
+  let(:ui) { Vagrant::UI::Silent.new }
+  let(:networks) {
+    [
+      [:forwarded_port, {guest: 80, host: 8080, id: "tcp80"}],
+      [:private_network, {ip: "192.168.56.10", hostname: "web.test"}],
+      [:private_network, {ip: "192.168.56.11", hostname: ["db.test", "cache.test"]}],
+      [:private_network, {ip: "192.168.56.12", hostname: true}],
+      [:public_network, {ip: "10.0.0.10", hostname: "public.test"}],
+    ]
+  }
+
+  subject { described_class.new(app, env) }
+
+  before do
+    allow(machine).to receive_message_chain(:config, :vm, :networks).and_return(networks)
+    allow(host).to receive(:capability?).with(:hosts_file_update).and_return(true)
+  end
+
+  describe ".entries" do
+    it "returns the string hostnames of private networks" do
+      expect(described_class.entries(machine)).to eq([
+        ["192.168.56.10", "web.test"],
+        ["192.168.56.11", "db.test"],
+        ["192.168.56.11", "cache.test"],
+      ])
+    end
+  end
+
+  it "updates the hosts file entries of the machine" do
+    expect(host).to receive(:capability).with(:hosts_file_update, "uuid",
+      described_class.entries(machine))
+    subject.call(env)
+  end
+
+  it "updates the hosts file after calling the next middleware" do
+    called = false
+    app = lambda { |_| called = true }
+    expect(host).to receive(:capability) { expect(called).to be(true) }
+    described_class.new(app, env).call(env)
+  end
+
+  context "when no hostnames are configured" do
+    let(:networks) { [[:private_network, {ip: "192.168.56.10"}]] }
+
+    it "updates the hosts file to remove stale entries" do
+      expect(host).to receive(:capability).with(:hosts_file_update, "uuid", [])
+      subject.call(env)
+    end
+  end
+
+  context "when the host does not support the capability" do
+    before do
+      allow(host).to receive(:capability?).with(:hosts_file_update).and_return(false)
+    end
+
+    it "warns the user" do
+      expect(ui).to receive(:warn).with(/does not support managing hosts file/)
+      expect(host).not_to receive(:capability)
+      subject.call(env)
+    end
+
+    context "when no hostnames are configured" do
+      let(:networks) { [] }
+
+      it "does not warn the user" do
+        expect(ui).not_to receive(:warn)
+        subject.call(env)
+      end
+    end
+  end
+end
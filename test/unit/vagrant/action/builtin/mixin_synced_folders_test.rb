@@ -324,6 +324,19 @@ describe Vagrant::Action::Builtin::MixinSyncedFolders do
       expect(result[:modified]).to_not be_empty
     end
 
+    it "sees type changes as modifications" do
+      one = {
+        default: { "foo" => {} },
+      }
+
+      two = {
+        nfs: { "foo" => {} },
+      }
+
+      result = subject.synced_folders_diff(one, two)
+      expect(result[:modified]).to include("foo")
+    end
+
     it "sees adding" do
       one = {
         default: { "foo" => {} },
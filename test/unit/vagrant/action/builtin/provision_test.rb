@@ -70,7 +70,7 @@ describe Vagrant::Action::Builtin::Provision do
         prov.config = provisioner_config
         prov
       end
-      let(:provisioner_config){ double("provisioner_config", name: "spec-test") }
+      let(:provisioner_config){ double("provisioner_config", name: "spec-test", remote?: false, path: nil, inline: nil) }
 
       before{ expect(vm_config).to receive(:provisioners).and_return([provisioner]) }
 
@@ -203,5 +203,134 @@ describe Vagrant::Action::Builtin::Provision do
         end
       end
     end
+
+    context "with provisioner fingerprints" do
+      let(:first) { double("first", configure: nil, fingerprint: "first-1") }
+      let(:second) { double("second", configure: nil, fingerprint: "second-1") }
+      let(:second_run) { :once }
+      let(:skip_unchanged) { true }
+      let(:fingerprints_path) { data_dir.join("provision_fingerprints") }
+
+      before do
+        env[:provision_ignore_sentinel] = true
+        allow(instance).to receive(:provisioner_instances).
+          and_return([
+            [first, { name: :first, skip_unchanged: skip_unchanged }],
+            [second, { name: :second, run: second_run, skip_unchanged: skip_unchanged }],
+          ])
+        allow(instance).to receive(:provisioner_type_map).
+          and_return(first => :shell, second => :shell)
+        allow(hook).to receive(:call)
+      end
+
+      def ran
+        provisioners = []
+        allow(hook).to receive(:call) { |_, e| provisioners << e[:provisioner] }
+        instance.call(env)
+        provisioners
+      end
+
+      it "runs provisioners without stored fingerprints" do
+        expect(ran).to eq([first, second])
+      end
+
+      it "stores the fingerprints after running" do
+        instance.call(env)
+        data = JSON.parse(fingerprints_path.read)
+        expect(data["id"]).to eq("machine-id")
+        expect(data["fingerprints"]).to eq("first" => "first-1", "second" => "second-1")
+      end
+
+      it "does not store the fingerprint of a failed provisioner" do
+        allow(hook).to receive(:call) { |_, e| raise "failed" if e[:provisioner] == second }
+        expect { instance.call(env) }.to raise_error("failed")
+        data = JSON.parse(fingerprints_path.read)
+        expect(data["fingerprints"]).to eq("first" => "first-1")
+      end
+
+      context "when the provisioners ran before" do
+        before { instance.call(env) }
+
+        it "skips unchanged provisioners" do
+          expect(ui).to receive(:info).with(/Skipping provisioner first/)
+          expect(ui).to receive(:info).with(/Skipping provisioner second/)
+          expect(ran).to eq([])
+        end
+
+        it "runs unchanged provisioners when forced" do
+          env[:provision_force] = true
+          expect(ran).to eq([first, second])
+        end
+
+        it "runs changed provisioners" do
+          allow(second).to receive(:fingerprint).and_return("second-2")
+          expect(ran).to eq([second])
+        end
+
+        it "ignores fingerprints stored for another machine" do
+          allow(machine).to receive(:id).and_return("other-id")
+          expect(ran).to eq([first, second])
+        end
+
+        context "with a provisioner which runs always" do
+          let(:second_run) { :always }
+
+          it "never skips it" do
+            expect(ran).to eq([second])
+          end
+        end
+      end
+
+      context "when skipping unchanged provisioners is not enabled" do
+        let(:skip_unchanged) { false }
+
+        it "runs the provisioners again" do
+          instance.call(env)
+          expect(ran).to eq([first, second])
+        end
+
+        it "does not compute the fingerprints" do
+          expect(first).not_to receive(:fingerprint)
+          instance.call(env)
+        end
+      end
+    end
+
+    context "with dry run enabled" do
+      let(:provisioner) { double("provisioner", configure: nil) }
+
+      before do
+        env[:provision_dry_run] = true
+        allow(instance).to receive(:provisioner_instances).
+          and_return([[provisioner, { name: "spec-test" }]])
+        allow(instance).to receive(:provisioner_type_map).
+          and_return(provisioner => :shell)
+        allow(ui).to receive(:detail)
+      end
+
+      it "should not run the provisioner" do
+        allow(provisioner).to receive(:plan).and_return(["step"])
+        expect(hook).not_to receive(:call)
+        instance.call(env)
+      end
+
+      it "should show the planned actions" do
+        expect(provisioner).to receive(:plan).and_return(["upload script"])
+        expect(ui).to receive(:detail).with(/upload script/)
+        instance.call(env)
+      end
+
+      it "should report provisioners without dry run support" do
+        expect(provisioner).to receive(:plan).and_return(nil)
+        expect(ui).to receive(:detail).with(/no dry-run support/)
+        instance.call(env)
+      end
+
+      it "should not write the provision sentinel" do
+        allow(provisioner).to receive(:plan).and_return([])
+        instance.call(env)
+        expect(data_dir.join("action_provision")).not_to be_file
+      end
+    end
   end
 end
@@ -7,10 +7,13 @@ describe Vagrant::Action::Builtin::SSHExec do
   let(:app) { lambda { |env| } }
   let(:env) { { machine: machine } }
   let(:machine) do
-    result = double("machine")
+    result = double("machine", config: config)
     allow(result).to receive(:ssh_info).and_return(machine_ssh_info)
     result
   end
+  let(:config) { double("config", vm: vm) }
+  let(:vm) { double("vm", communicator: nil, environment: environment) }
+  let(:environment) { {} }
   let(:machine_ssh_info) { {} }
   let(:ssh_klass) { Vagrant::Util::SSH }
 
@@ -48,4 +51,34 @@ describe Vagrant::Action::Builtin::SSHExec do
     env[:ssh_opts] = ssh_opts
     described_class.new(app, env).call(env)
   end
+
+  context "with an environment" do
+    let(:environment) { { "ROLE" => "web server" } }
+
+    it "should export the environment in a login shell" do
+      expect(ssh_klass).to receive(:exec).
+        with(machine_ssh_info, extra_args: ["-t", "export ROLE=web\\ server; exec \"$SHELL\" -l"])
+
+      described_class.new(app, env).call(env)
+    end
+
+    it "should not export the environment with extra arguments" do
+      ssh_opts = { extra_args: ["uptime"] }
+
+      expect(ssh_klass).to receive(:exec).
+        with(machine_ssh_info, ssh_opts)
+
+      env[:ssh_opts] = ssh_opts
+      described_class.new(app, env).call(env)
+    end
+
+    it "should not export the environment with WinSSH" do
+      allow(vm).to receive(:communicator).and_return(:winssh)
+
+      expect(ssh_klass).to receive(:exec).
+        with(machine_ssh_info, nil)
+
+      described_class.new(app, env).call(env)
+    end
+  end
 end
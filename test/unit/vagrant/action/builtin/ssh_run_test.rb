@@ -23,7 +23,8 @@ describe Vagrant::Action::Builtin::SSHRun do
 
   let(:vm) do
     double("vm",
-      communicator: nil
+      communicator: nil,
+      environment: {}
     )
   end
 
@@ -90,6 +91,19 @@ describe Vagrant::Action::Builtin::SSHRun do
     described_class.new(app, env).call(env)
   end
 
+  it "should export the environment of the machine before the command" do
+    ssh_info = { foo: :bar }
+    allow(vm).to receive(:environment).and_return("ROLE" => "web's")
+    opts = {:extra_args=>["-t", "bash -l -c 'export ROLE=web\\'\\''s; echo test'"], :subprocess=>true}
+
+    expect(ssh_klass).to receive(:exec).
+      with(ssh_info, opts)
+
+    env[:ssh_info] = ssh_info
+    env[:ssh_run_command] = "echo test"
+    described_class.new(app, env).call(env)
+  end
+
   context "when using the WinSSH communicator" do
     let(:winssh) { double("winssh", shell: "foo") }
 
@@ -0,0 +1,68 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require File.expand_path("../../../../base", __FILE__)
+
+describe Vagrant::Action::Builtin::SyncTime do
+  let(:env) { { machine: machine, ui: ui } }
+  let(:app) { lambda { |env| } }
+  let(:machine) { double("machine", communicate: communicator, guest: guest) }
+  let(:communicator) { double("communicator", ready?: true) }
+  let(:guest) { double("guest", capability?: true) }
+  let(:ui) { Vagrant::UI::Silent.new }
+  let(:sync_time) { true }
+
+  subject { described_class.new(app, env) }
+
+  before do
+    allow(machine).to receive_message_chain(:config, :vm, :sync_time).and_return(sync_time)
+    allow(guest).to receive(:capability).with(:sync_time).and_return({})
+  end
+
+  it "synchronizes the time of the guest" do
+    expect(guest).to receive(:capability).with(:sync_time)
+    subject.call(env)
+  end
+
+  it "reports the offsets" do
+    allow(guest).to receive(:capability).with(:sync_time).
+      and_return(before: "12.5 seconds slow", after: "0.001 seconds fast")
+    expect(ui).to receive(:detail).with(/12\.5 seconds slow.*0\.001 seconds fast/)
+    subject.call(env)
+  end
+
+  it "warns instead of failing when the synchronization fails" do
+    allow(guest).to receive(:capability).with(:sync_time).
+      and_raise(Vagrant::Errors::VagrantError)
+    expect(ui).to receive(:warn)
+    expect { subject.call(env) }.not_to raise_error
+  end
+
+  context "when disabled" do
+    let(:sync_time) { false }
+
+    it "does not synchronize the time" do
+      expect(guest).not_to receive(:capability)
+      subject.call(env)
+    end
+  end
+
+  context "when the communicator is not ready" do
+    before { allow(communicator).to receive(:ready?).and_return(false) }
+
+    it "does not synchronize the time" do
+      expect(guest).not_to receive(:capability?)
+      expect(guest).not_to receive(:capability)
+      subject.call(env)
+    end
+  end
+
+  context "when the guest does not support the capability" do
+    before { allow(guest).to receive(:capability?).with(:sync_time).and_return(false) }
+
+    it "does not synchronize the time" do
+      expect(guest).not_to receive(:capability)
+      subject.call(env)
+    end
+  end
+end
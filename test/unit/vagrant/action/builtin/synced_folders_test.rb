@@ -17,6 +17,7 @@ describe Vagrant::Action::Builtin::SyncedFolders do
   let(:machine) do
     double("machine").tap do |machine|
       allow(machine).to receive(:config).and_return(machine_config)
+      allow(machine).to receive(:provider_name).and_return(:dummy)
     end
   end
 
@@ -160,6 +161,30 @@ describe Vagrant::Action::Builtin::SyncedFolders do
       expect(ids[0]).to eq(ids[1])
     end
 
+    it "warns about options the implementation does not support" do
+      tracker = Class.new(impl(true, "good")) do
+        define_method(:unsupported_options) do |machine|
+          [:owner, :mount_options]
+        end
+      end
+
+      plugins[:tracker] = [tracker, 15]
+
+      synced_folders["tracker"] = {
+        "root" => {
+          hostpath: "foo",
+          guestpath: "/vagrant",
+          owner: "www-data",
+          mount_options: [],
+        },
+      }
+
+      allow(machine).to receive(:ui).and_return(ui)
+      expect(ui).to receive(:warn).with(/owner.*\/vagrant/m).once
+
+      subject.call(env)
+    end
+
     it "syncs custom folders" do
       ids   = []
       order = []
@@ -255,6 +280,160 @@ describe Vagrant::Action::Builtin::SyncedFolders do
       end
     end
 
+    context "when reloading the folders of a running machine" do
+      let(:cached) { {} }
+      let(:calls) { [] }
+
+      before do
+        env[:synced_folders_reload] = true
+        allow(machine).to receive(:name).and_return(:default)
+        allow(machine).to receive(:ui).and_return(ui)
+        allow(subject).to receive(:synced_folders) { |_, **opts|
+          opts[:cached] ? cached : synced_folders
+        }
+
+        recorder = calls
+        tracker = Class.new(impl(true, "tracker")) do
+          define_method(:hot_mount?) { |machine| true }
+
+          [:prepare, :enable, :disable].each do |action|
+            define_method(action) do |machine, folders, opts|
+              recorder << [action, folders.keys.sort]
+            end
+          end
+        end
+        plugins[:tracker] = [tracker, 15]
+      end
+
+      it "only mounts and unmounts changed folders" do
+        cached["tracker"] = {
+          "same" => { hostpath: "/same", guestpath: "/same" },
+          "changed" => { hostpath: "/old", guestpath: "/changed" },
+          "removed" => { hostpath: "/removed", guestpath: "/removed" },
+        }
+        synced_folders["tracker"] = {
+          "same" => { hostpath: "/same", guestpath: "/same" },
+          "changed" => { hostpath: "/new", guestpath: "/changed" },
+          "added" => { hostpath: "/added", guestpath: "/added" },
+        }
+
+        expect(subject).to receive(:save_synced_folders) do |_, folders|
+          expect(folders["tracker"].keys).to contain_exactly("same", "changed", "added")
+          expect(folders["tracker"]["changed"][:hostpath]).to eq("/new")
+        end
+
+        subject.call(env)
+
+        expect(calls).to eq([
+          [:disable, ["changed", "removed"]],
+          [:prepare, ["added", "changed"]],
+          [:enable, ["added", "changed"]],
+        ])
+      end
+
+      it "does nothing if no folders changed" do
+        cached["tracker"] = { "same" => { hostpath: "/same", guestpath: "/same" } }
+        synced_folders["tracker"] = { "same" => { hostpath: "/same", guestpath: "/same" } }
+
+        expect(subject).not_to receive(:save_synced_folders)
+
+        subject.call(env)
+
+        expect(calls).to be_empty
+      end
+
+      it "raises an error without applying changes if a type can not be mounted" do
+        cached["tracker"] = { "removed" => { hostpath: "/removed", guestpath: "/removed" } }
+        synced_folders["default"] = { "added" => { hostpath: "/added", guestpath: "/added" } }
+
+        expect(subject).not_to receive(:save_synced_folders)
+
+        expect { subject.call(env) }.
+          to raise_error(Vagrant::Errors::ReloadSyncedFoldersRebootRequired)
+        expect(calls).to be_empty
+      end
+    end
+
+    context "with the synced folder type of the provider" do
+      let(:installed) { "7.0.10" }
+      let(:mount_error) { nil }
+      let(:mounted) { [] }
+
+      before do
+        allow(machine).to receive(:provider_name).and_return(:tracker)
+        allow(machine).to receive(:ui).and_return(ui)
+        allow(Vagrant::Util::GuestTools).to receive(:versions).
+          with(machine) { [installed, "7.0.12"] }
+
+        error = mount_error
+        mounts = mounted
+        tracker = Class.new(impl(true, "tracker")) do
+          define_method(:enable) do |machine, folders, opts|
+            mounts.concat(folders.keys)
+            raise error if error
+          end
+        end
+        plugins[:tracker] = [tracker, 15]
+        synced_folders["tracker"] = { "root" => { hostpath: "/vagrant-test-root" } }
+      end
+
+      it "does not warn when the guest tools versions match" do
+        expect(ui).not_to receive(:warn)
+        subject.call(env)
+      end
+
+      context "when the guest tools major version is different" do
+        let(:installed) { "6.1.40" }
+
+        it "warns about the mismatch before mounting" do
+          expect(ui).to receive(:warn).with(/6\.1\.40.+7\.0\.12/m) {
+            expect(mounted).to be_empty
+          }
+          subject.call(env)
+          expect(mounted).to eq(["root"])
+        end
+
+        context "when the mount fails" do
+          let(:mount_error) {
+            Vagrant::Errors::VirtualBoxMountFailed.new(command: "mount", output: "failed")
+          }
+
+          it "raises an error suggesting the fix" do
+            expect { subject.call(env) }.
+              to raise_error(Vagrant::Errors::GuestToolsMismatchMountFailed) { |e|
+                expect(e.message).to include("7.0.12")
+                expect(e.message).to include("failed")
+              }
+          end
+        end
+      end
+
+      context "when the guest tools minor version is different" do
+        let(:installed) { "7.1.0" }
+        let(:mount_error) {
+          Vagrant::Errors::VirtualBoxMountFailed.new(command: "mount", output: "failed")
+        }
+
+        it "raises the mount error" do
+          expect { subject.call(env) }.
+            to raise_error(Vagrant::Errors::VirtualBoxMountFailed)
+        end
+      end
+
+      context "when the guest tools are not installed" do
+        let(:installed) { nil }
+        let(:mount_error) {
+          Vagrant::Errors::VirtualBoxMountFailed.new(command: "mount", output: "failed")
+        }
+
+        it "raises an error suggesting the guest tools are installed" do
+          expect(ui).to receive(:warn).with(/No guest tools/)
+          expect { subject.call(env) }.
+            to raise_error(Vagrant::Errors::GuestToolsMissingMountFailed)
+        end
+      end
+    end
+
     context "when guest is not available" do
       it "does not persist folders if guest is not available" do
       allow(machine).to receive_message_chain(:guest, :capability?).and_raise(Vagrant::Errors::MachineGuestNotReady)
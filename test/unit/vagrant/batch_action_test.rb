@@ -12,10 +12,13 @@ describe Vagrant::BatchAction do
   let(:provider_name) { "test" }
   let(:provider_options) { {} }
 
-  def new_machine(options)
+  def new_machine(options, name: "default", depends_on: [], provider: provider_name)
     double("machine").tap do |m|
-      allow(m).to receive(:provider_name).and_return(provider_name)
+      allow(m).to receive(:name).and_return(name)
+      allow(m).to receive_message_chain(:config, :vm, :depends_on).and_return(depends_on)
+      allow(m).to receive(:provider_name).and_return(provider)
       allow(m).to receive(:provider_options).and_return(options)
+      allow(m).to receive(:ui).and_return(Vagrant::UI::Silent.new)
       allow(m).to receive(:action) do |action, opts|
         lock.synchronize do
           called_actions << [m, action, opts]
@@ -25,8 +28,8 @@ describe Vagrant::BatchAction do
   end
 
   describe "#run" do
-    let(:machine) { new_machine(provider_options) }
-    let(:machine2) { new_machine(provider_options) }
+    let(:machine) { new_machine(provider_options, name: "one") }
+    let(:machine2) { new_machine(provider_options, name: "two") }
 
     it "should run the actions on the machines in order" do
       subject.action(machine, "up")
@@ -67,6 +70,36 @@ describe Vagrant::BatchAction do
       subject.run
     end
 
+    it "should run actions after the machines they depend on" do
+      dependent = new_machine(provider_options, name: "web", depends_on: ["two"])
+      called = []
+      subject.custom(dependent) { |m| called << m }
+      subject.custom(machine) { |m| called << m }
+      subject.custom(machine2) { |m| called << m }
+      subject.run
+
+      expect(called).to eq([machine, machine2, dependent])
+    end
+
+    it "should ignore dependencies on machines not in the batch" do
+      dependent = new_machine(provider_options, name: "web", depends_on: ["db"])
+      subject.action(dependent, "up")
+      subject.run
+
+      expect(called_actions).to eq([[dependent, "up", nil]])
+    end
+
+    it "should raise an error on circular dependencies" do
+      machine = new_machine(provider_options, name: "one", depends_on: ["two"])
+      machine2 = new_machine(provider_options, name: "two", depends_on: ["one"])
+      subject.action(machine, "up")
+      subject.action(machine2, "up")
+
+      expect { subject.run }.
+        to raise_error(Vagrant::Errors::BatchDependencyCycle)
+      expect(called_actions).to be_empty
+    end
+
     context "with provider supporting parallel actions" do
       let(:provider_options) { {parallel: true} }
 
@@ -84,6 +117,74 @@ describe Vagrant::BatchAction do
         expect(Process).to receive(:exit!).with(1)
         subject.run
       end
+
+      it "should not start dependent actions until dependencies complete" do
+        dependent = new_machine(provider_options, name: "web", depends_on: ["one"])
+        finished = false
+        started_early = nil
+        subject.custom(machine) { |*_| sleep(0.1); finished = true }
+        subject.custom(dependent) { |*_| started_early = !finished }
+        subject.run
+
+        expect(started_early).to eq(false)
+      end
+
+      it "should cancel pending actions when an action fails" do
+        called = []
+        dependent = new_machine(provider_options, name: "web", depends_on: ["one"])
+        subject.custom(machine) { |*_| raise Vagrant::Errors::VagrantError }
+        subject.custom(dependent) { |m| called << m }
+
+        expect { subject.run }.to raise_error(Vagrant::Errors::BatchMultiError)
+        expect(called).to be_empty
+      end
+
+      it "should allow running actions to complete when an action fails" do
+        completed = false
+        subject.custom(machine) { |*_| sleep(0.1); completed = true }
+        subject.custom(machine2) { |*_| raise Vagrant::Errors::VagrantError }
+
+        expect { subject.run }.to raise_error(Vagrant::Errors::BatchMultiError)
+        expect(completed).to eq(true)
+      end
+
+      context "with a maximum number of workers" do
+        subject { described_class.new(true, max_workers: 2) }
+
+        it "should not run more actions at once than the maximum" do
+          running = 0
+          max_running = 0
+          4.times do |i|
+            subject.custom(new_machine(provider_options, name: "m#{i}")) do |*_|
+              lock.synchronize { running += 1; max_running = [max_running, running].max }
+              sleep(0.05)
+              lock.synchronize { running -= 1 }
+            end
+          end
+          subject.run
+
+          expect(max_running).to eq(2)
+        end
+      end
+
+      context "with a provider not supporting parallel actions" do
+        it "should run actions on that provider one at a time" do
+          running = 0
+          max_running = 0
+          subject.custom(machine) { |*_| }
+          2.times do |i|
+            serial = new_machine({}, name: "serial#{i}", provider: "serial")
+            subject.custom(serial) do |*_|
+              lock.synchronize { running += 1; max_running = [max_running, running].max }
+              sleep(0.05)
+              lock.synchronize { running -= 1 }
+            end
+          end
+          subject.run
+
+          expect(max_running).to eq(1)
+        end
+      end
     end
   end
 end
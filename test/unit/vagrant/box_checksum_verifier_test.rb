@@ -0,0 +1,157 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require File.expand_path("../../base", __FILE__)
+
+require "ed25519"
+
+require "vagrant/box_checksum_verifier"
+
+describe Vagrant::BoxChecksumVerifier do
+  include_context "unit"
+
+  let(:content) { "box content" * 2048 }
+  let(:path) { temporary_file(content) }
+
+  describe ".for" do
+    it "returns a digest verifier for digest types" do
+      %w(md5 sha1 sha256 sha384 sha512).each do |type|
+        expect(described_class.for(type, "abc")).
+          to be_a(described_class::DigestVerifier)
+      end
+    end
+
+    it "accepts symbol types" do
+      expect(described_class.for(:sha256, "abc")).
+        to be_a(described_class::DigestVerifier)
+    end
+
+    it "raises an error for unknown types" do
+      expect { described_class.for("unknown", "abc") }.
+        to raise_error(Vagrant::Errors::BoxChecksumInvalidType)
+    end
+
+    it "returns registered custom verifiers" do
+      klass = Class.new(described_class)
+      described_class.register(:custom, klass)
+      begin
+        expect(described_class.for("custom", "abc")).to be_a(klass)
+      ensure
+        described_class.verifiers.delete(:custom)
+      end
+    end
+  end
+
+  describe described_class::DigestVerifier do
+    it "verifies a matching checksum" do
+      verifier = described_class.new(:sha256, Digest::SHA256.hexdigest(content))
+      expect { verifier.verify_file!(path) }.not_to raise_error
+    end
+
+    ["sha256", "SHA256"].each do |type|
+      it "verifies a matching checksum with the type #{type.inspect}" do
+        verifier = described_class.new(type, Digest::SHA256.hexdigest(content))
+        expect { verifier.verify_file!(path) }.not_to raise_error
+      end
+    end
+
+    it "ignores case and surrounding whitespace" do
+      verifier = described_class.new(:sha512, " #{Digest::SHA512.hexdigest(content).upcase} ")
+      expect { verifier.verify_file!(path) }.not_to raise_error
+    end
+
+    it "raises an error including both values on mismatch" do
+      actual = Digest::SHA256.hexdigest(content)
+      verifier = described_class.new(:sha256, "abc123")
+      expect { verifier.verify_file!(path) }.
+        to raise_error(Vagrant::Errors::BoxChecksumMismatch) { |err|
+          expect(err.message).to include("abc123")
+          expect(err.message).to include(actual)
+        }
+    end
+  end
+
+  describe described_class::MinisignVerifier do
+    let(:signing_key) { Ed25519::SigningKey.generate }
+    let(:key_id) { "\x01\x02\x03\x04\x05\x06\x07\x08".b }
+    let(:other_key_id) { "\x08\x07\x06\x05\x04\x03\x02\x01".b }
+    let(:signature_key_id) { key_id }
+    let(:signed_content) { content }
+    let(:algorithm) { "ED" }
+    let(:trusted_comment) { "timestamp:1700000000\tfile:test.box" }
+    let(:public_key_path) do
+      temporary_file(<<-KEY)
+untrusted comment: minisign public key
+#{Base64.strict_encode64("Ed" + key_id + signing_key.verify_key.to_bytes)}
+      KEY
+    end
+    let(:signature) do
+      digest = OpenSSL::Digest.new("BLAKE2b512").digest(signed_content)
+      sig = signing_key.sign(digest)
+      global = signing_key.sign(sig + trusted_comment)
+      [
+        "untrusted comment: signature from minisign secret key",
+        Base64.strict_encode64(algorithm + signature_key_id + sig),
+        "trusted comment: #{trusted_comment}",
+        Base64.strict_encode64(global),
+      ].join("\n")
+    end
+
+    subject { described_class.new(:minisign, signature, key: public_key_path.to_s) }
+
+    it "verifies a valid signature" do
+      expect { subject.verify_file!(path) }.not_to raise_error
+    end
+
+    it "reads the signature from a file" do
+      sig_path = temporary_dir.join("test.box.minisig")
+      File.write(sig_path, signature)
+      verifier = described_class.new(:minisign, sig_path.to_s, key: public_key_path.to_s)
+      expect { verifier.verify_file!(path) }.not_to raise_error
+    end
+
+    it "raises an error when a key is not provided" do
+      expect { described_class.new(:minisign, signature) }.
+        to raise_error(Vagrant::Errors::BoxSignatureKeyMissing)
+    end
+
+    it "raises an error when the key does not exist" do
+      expect { described_class.new(:minisign, signature, key: "/nonexistent.pub") }.
+        to raise_error(Vagrant::Errors::BoxSignatureMalformed)
+    end
+
+    it "raises an error when the signature is malformed" do
+      expect { described_class.new(:minisign, "invalid", key: public_key_path.to_s) }.
+        to raise_error(Vagrant::Errors::BoxSignatureMalformed)
+    end
+
+    context "with modified content" do
+      let(:signed_content) { "other content" }
+
+      it "raises an error" do
+        expect { subject.verify_file!(path) }.
+          to raise_error(Vagrant::Errors::BoxSignatureInvalid)
+      end
+    end
+
+    context "with a signature from a different key" do
+      let(:signature_key_id) { other_key_id }
+
+      it "raises an error naming both keys" do
+        expect { subject.verify_file!(path) }.
+          to raise_error(Vagrant::Errors::BoxSignatureInvalid) { |err|
+            expect(err.message).to include("0807060504030201")
+            expect(err.message).to include("0102030405060708")
+          }
+      end
+    end
+
+    context "with a legacy signature" do
+      let(:algorithm) { "Ed" }
+
+      it "raises an error" do
+        expect { subject }.to raise_error(Vagrant::Errors::BoxSignatureMalformed)
+      end
+    end
+  end
+end
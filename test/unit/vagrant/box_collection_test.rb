@@ -408,6 +408,41 @@ describe Vagrant::BoxCollection, :skip_windows, :bsdtar do
       expect(box.metadata_url).to eq("bar")
     end
 
+    context "with a base box" do
+      let(:base_box) { { "name" => "base", "version" => "1.0" } }
+      let(:box_path) {
+        environment.box2_file(:virtualbox, metadata: { "base_box" => base_box })
+      }
+
+      it "should raise an error if the base box is not installed" do
+        expect { subject.add(box_path, "derived", "1.0") }.
+          to raise_error(Vagrant::Errors::BoxBaseNotFound) { |e|
+            expect(e.extra_data[:name]).to eq("base")
+            expect(e.extra_data[:provider]).to eq("virtualbox")
+          }
+      end
+
+      it "should layer the box over the base box" do
+        base_dir = environment.box3("base", "1.0", :virtualbox)
+        base_dir.join("box.ovf").open("w") { |f| f.write("base") }
+
+        box = subject.add(box_path, "derived", "1.0")
+        expect(box.directory.join("box.ovf").read).to eq("base")
+        expect(box.metadata["provider"]).to eq("virtualbox")
+        expect(box.metadata["base_box"]["resolved_version"]).to eq("1.0")
+        expect(base_dir.join("box.ovf")).to be_file
+      end
+
+      context "with an invalid base box entry" do
+        let(:base_box) { "base" }
+
+        it "should raise an error" do
+          expect { subject.add(box_path, "derived", "1.0") }.
+            to raise_error(Vagrant::Errors::BoxBaseInvalid)
+        end
+      end
+    end
+
     it "should add a V1 box" do
       # Create a V1 box.
       box_path = environment.box1_file
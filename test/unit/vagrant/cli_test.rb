@@ -78,6 +78,32 @@ describe Vagrant::CLI do
       expect(subject.execute).to eql(42)
     end
 
+    it "passes the exit code of the command to after triggers" do
+      commands[:destroy] = [command_lambda("destroy", 42), {}]
+      allow(Vagrant::Plugin::V2::Trigger).to receive(:new).and_return(triggers)
+
+      subject = described_class.new(["destroy"], env)
+
+      expect(triggers).to receive(:fire).with(:destroy, :before, nil, :command)
+      expect(triggers).to receive(:fire).with(:destroy, :after, nil, :command, exit_code: 42)
+
+      expect(subject.execute).to eql(42)
+    end
+
+    it "fires conditional after triggers when the command fails" do
+      commands[:destroy] = [command_lambda("destroy", 42,
+        exception: Vagrant::Errors::VagrantError), {}]
+      allow(Vagrant::Plugin::V2::Trigger).to receive(:new).and_return(triggers)
+
+      subject = described_class.new(["destroy"], env)
+
+      expect(triggers).to receive(:fire).with(:destroy, :before, nil, :command)
+      expect(triggers).to receive(:fire).
+        with(:destroy, :after, nil, :command, exit_code: 1, conditional_only: true)
+
+      expect { subject.execute }.to raise_error(Vagrant::Errors::VagrantError)
+    end
+
     it "does not fire triggers if disabled" do
       allow(Vagrant::Util::Experimental).to receive(:feature_enabled?).
         with("typed_triggers").and_return(false)
@@ -0,0 +1,132 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require File.expand_path("../../base", __FILE__)
+
+describe Vagrant::Client do
+  include_context "unit"
+
+  let(:vagrantfile) do
+    <<-VF
+    Vagrant.configure("2") do |config|
+      config.vm.box = "base"
+      config.vm.define "web"
+      config.vm.define "db", autostart: false
+    end
+    VF
+  end
+  let(:iso_env) do
+    isolated_environment.tap { |env| env.vagrantfile(vagrantfile) }
+  end
+  let(:ui) { Vagrant::UI::Silent.new }
+
+  subject { described_class.new(iso_env.workdir, ui: ui, home_path: iso_env.homedir) }
+
+  let(:web) { subject.env.machine(:web, :dummy) }
+  let(:db) { subject.env.machine(:db, :dummy) }
+
+  after { subject.close }
+
+  it "uses the given UI" do
+    expect(subject.env.ui).to equal(ui)
+  end
+
+  describe ".open" do
+    it "closes the client after the block" do
+      client = nil
+      result = described_class.open(iso_env.workdir, home_path: iso_env.homedir) do |c|
+        client = c
+        :result
+      end
+
+      expect(result).to eq(:result)
+      expect(client).to be_closed
+    end
+  end
+
+  describe "#up" do
+    it "brings up the machines which start automatically" do
+      expect(web).to receive(:action).with(:up, hash_including(destroy_on_error: true))
+      expect(db).not_to receive(:action)
+
+      results = subject.up
+      expect(results.map(&:name)).to eq([:web])
+      expect(results.first).to be_success
+    end
+
+    it "brings up the given machines" do
+      expect(db).to receive(:action).with(:up, any_args)
+
+      expect(subject.up(["db"]).map(&:name)).to eq([:db])
+    end
+
+    it "sets the provision options" do
+      expect(web).to receive(:action).with(:up,
+        hash_including(provision_enabled: false, provision_ignore_sentinel: true))
+
+      subject.up(provision: false)
+    end
+
+    it "returns errors for the machines which fail" do
+      error = Vagrant::Errors::VagrantError.new
+      expect(web).to receive(:action).and_raise(error)
+      expect(db).to receive(:action)
+
+      results = subject.up([:web, :db])
+      expect(results[0].error).to equal(error)
+      expect(results[0]).not_to be_success
+      expect(results[1]).to be_success
+    end
+
+    it "raises an error for an unknown machine" do
+      expect { subject.up(["unknown"]) }.
+        to raise_error(Vagrant::Errors::VMNotFoundError)
+    end
+  end
+
+  describe "#destroy" do
+    it "destroys the machines in reverse order without confirmation" do
+      destroyed = []
+      [web, db].each do |m|
+        expect(m).to receive(:action).with(:destroy,
+          force_confirm_destroy: true, force_halt: true) { destroyed << m.name }
+      end
+
+      subject.destroy
+      expect(destroyed).to eq([:db, :web])
+    end
+  end
+
+  describe "#status" do
+    it "returns the state of the machines" do
+      statuses = subject.status
+      expect(statuses.map(&:name)).to eq([:web, :db])
+      expect(statuses.map(&:provider)).to eq([:dummy, :dummy])
+      expect(statuses.map(&:state)).to eq([:not_created, :not_created])
+    end
+  end
+
+  describe "#close" do
+    it "unloads the environment" do
+      expect(subject.env).to receive(:unload).once
+
+      subject.close
+      subject.close
+    end
+
+    it "does not allow operations once closed" do
+      subject.close
+      expect { subject.status }.to raise_error(Vagrant::Errors::ClientClosed)
+    end
+  end
+
+  it "can be used for independent projects from multiple threads" do
+    other_env = isolated_environment.tap { |env| env.vagrantfile(vagrantfile) }
+    other = described_class.new(other_env.workdir, home_path: other_env.homedir)
+
+    statuses = [subject, other].map { |c| Thread.new { c.status } }.map(&:value)
+    expect(statuses.map { |s| s.map(&:name) }).to eq([[:web, :db], [:web, :db]])
+  ensure
+    other.close if other
+  end
+end
@@ -127,6 +127,66 @@ describe Vagrant::Config::Loader do
     end
   end
 
+  describe "secrets" do
+    before do
+      def test_loader.defined_values(obj)
+        obj
+      end
+      ENV["VAGRANT_TEST_LOADER_SECRET"] = "hunter2"
+    end
+
+    after do
+      ENV.delete("VAGRANT_TEST_LOADER_SECRET")
+      Vagrant::Secret.reset!
+      Vagrant::Util::CredentialScrubber.reset!
+    end
+
+    it "should resolve secrets once merged" do
+      instance.set(:a, [[current_version, proc { |c| c[:foo] = [Vagrant.secret("env:VAGRANT_TEST_LOADER_SECRET")] }]])
+      config, _ = instance.load([:a])
+
+      expect(config[:foo]).to eq(["hunter2"])
+    end
+
+    it "should record the source defining the secret" do
+      instance.set(:a, [[current_version, proc { |c| c[:bar] = "a" }]])
+      instance.set(:b, [[current_version, proc { |c| c[:foo] = Vagrant.secret("env:VAGRANT_TEST_LOADER_SECRET") }]])
+      instance.load([:a, :b])
+
+      expect(instance.provenance["foo"]).to eq([:b])
+    end
+
+    it "should resolve secrets set while finalizing" do
+      def test_loader.finalize(obj)
+        obj[:finalized] = Vagrant.secret("env:VAGRANT_TEST_LOADER_SECRET")
+        obj
+      end
+
+      instance.set(:a, [[current_version, proc { |c| c[:foo] = "a" }]])
+      config, _ = instance.load([:a])
+
+      expect(config[:finalized]).to eq("hunter2")
+    end
+
+    it "should provide the secret helper within Vagrantfiles" do
+      instance.set(:a, temporary_file(<<-VF))
+Vagrant.configure("1") do |config|
+  config[:foo] = secret("env:VAGRANT_TEST_LOADER_SECRET")
+end
+VF
+      config, _ = instance.load([:a])
+
+      expect(config[:foo]).to eq("hunter2")
+    end
+
+    it "should raise an error naming a missing secret" do
+      instance.set(:a, [[current_version, proc { |c| c[:foo] = Vagrant.secret("env:VAGRANT_TEST_MISSING") }]])
+
+      expect { instance.load([:a]) }.
+        to raise_error(Vagrant::Errors::SecretNotFound, /env:VAGRANT_TEST_MISSING/)
+    end
+  end
+
   describe "upgrading" do
     it "should do an upgrade to the latest version" do
       test_loader_v2 = Class.new(test_loader) do
@@ -173,6 +233,51 @@ describe Vagrant::Config::Loader do
     end
   end
 
+  describe "#provenance" do
+    before do
+      def test_loader.defined_values(obj)
+        obj
+      end
+    end
+
+    it "should be empty before loading" do
+      expect(instance.provenance).to eq({})
+    end
+
+    it "should record the source of each value" do
+      instance.set(:a, [[current_version, proc { |c| c[:foo] = "a" }]])
+      instance.set(:b, [[current_version, proc { |c| c[:bar] = "b" }]])
+      instance.load([:a, :b])
+
+      expect(instance.provenance).to eq("foo" => [:a], "bar" => [:b])
+    end
+
+    it "should only record the last source when a value is replaced" do
+      instance.set(:a, [[current_version, proc { |c| c[:foo] = "a" }]])
+      instance.set(:b, [[current_version, proc { |c| c[:foo] = "b" }]])
+      instance.load([:a, :b])
+
+      expect(instance.provenance["foo"]).to eq([:b])
+    end
+
+    it "should record every source when a value is combined" do
+      instance.set(:a, [[current_version, proc { |c| c[:foo] = ["a"] }]])
+      instance.set(:b, [[current_version, proc { |c| c[:foo] = ["b"] }]])
+      instance.load([:a, :b])
+
+      expect(instance.provenance["foo"]).to eq([:a, :b])
+    end
+
+    it "should reflect the most recent load" do
+      instance.set(:a, [[current_version, proc { |c| c[:foo] = "a" }]])
+      instance.set(:b, [[current_version, proc { |c| c[:foo] = "b" }]])
+      instance.load([:a, :b])
+      instance.load([:a])
+
+      expect(instance.provenance["foo"]).to eq([:a])
+    end
+  end
+
   describe "loading edge cases" do
     it "should only run the same proc once" do
       count = 0
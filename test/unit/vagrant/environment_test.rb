@@ -983,6 +983,76 @@ VF
         expect(subject.default_provider(machine: :sub)).to eq(:bar)
       end
     end
+
+    context "with a provider order" do
+      before do
+        plugin_providers[:foo] = [provider_usable_class(false), { priority: 7 }]
+        plugin_providers[:bar] = [provider_usable_class(true), { priority: 5 }]
+        plugin_providers[:baz] = [provider_usable_class(true), { priority: 2 }]
+      end
+
+      it "is the first usable provider in the given order" do
+        with_temp_env("VAGRANT_DEFAULT_PROVIDER" => nil,
+                      "VAGRANT_PREFERRED_PROVIDERS" => nil) do
+          expect(subject.default_provider(provider_order: [:foo, :baz, :bar])).to eq(:baz)
+        end
+      end
+
+      it "prefers the given order over the default provider" do
+        with_temp_env("VAGRANT_DEFAULT_PROVIDER" => "bar",
+                      "VAGRANT_PREFERRED_PROVIDERS" => nil) do
+          expect(subject.default_provider(provider_order: [:baz])).to eq(:baz)
+        end
+      end
+
+      it "is the first usable provider in the Vagrantfile order" do
+        subject.vagrantfile.config.vm.provider "bar"
+        subject.vagrantfile.config.vm.provider_order = "foo,missing,baz"
+        subject.vagrantfile.config.vm.finalize!
+
+        with_temp_env("VAGRANT_DEFAULT_PROVIDER" => nil,
+                      "VAGRANT_PREFERRED_PROVIDERS" => nil) do
+          expect(subject.default_provider).to eq(:baz)
+        end
+      end
+
+      it "shows why providers were skipped" do
+        expect(subject.ui).to receive(:detail).with(/foo.*not usable/, any_args).ordered
+        expect(subject.ui).to receive(:detail).with(/missing.*not installed/, any_args).ordered
+        expect(subject.ui).to receive(:info).with(/baz/, any_args).once.ordered
+
+        with_temp_env("VAGRANT_DEFAULT_PROVIDER" => nil,
+                      "VAGRANT_PREFERRED_PROVIDERS" => nil) do
+          2.times do
+            expect(subject.default_provider(provider_order: [:foo, :missing, :baz])).to eq(:baz)
+          end
+        end
+      end
+
+      it "skips excluded providers" do
+        with_temp_env("VAGRANT_DEFAULT_PROVIDER" => nil,
+                      "VAGRANT_PREFERRED_PROVIDERS" => nil) do
+          expect(subject.default_provider(
+            provider_order: [:baz, :bar], exclude: [:baz])).to eq(:bar)
+        end
+      end
+
+      it "is the first known provider when not checking usability" do
+        with_temp_env("VAGRANT_DEFAULT_PROVIDER" => nil,
+                      "VAGRANT_PREFERRED_PROVIDERS" => nil) do
+          expect(subject.default_provider(
+            provider_order: [:missing, :foo], check_usable: false)).to eq(:foo)
+        end
+      end
+
+      it "raises an error if no provider is usable" do
+        with_temp_env("VAGRANT_DEFAULT_PROVIDER" => nil,
+                      "VAGRANT_PREFERRED_PROVIDERS" => nil) do
+          expect { subject.default_provider(provider_order: [:foo, :missing]) }.
+            to raise_error(Vagrant::Errors::ProviderOrderNotUsable)
+        end
+      end
+    end
   end
 
   describe "local data path" do
@@ -1386,6 +1456,58 @@ VF
 
       expect(env.vagrantfile.config.ssh.port).to eq(400)
     end
+
+    context "with an organization Vagrantfile" do
+      let(:org_vagrantfile) do
+        temporary_file(<<-VF)
+Vagrant.configure("2") do |config|
+  config.ssh.port = 300
+  config.ssh.username = "org"
+end
+VF
+      end
+
+      let(:environment) do
+        isolated_environment do |env|
+          env.vagrantfile(<<-VF)
+Vagrant.configure("2") do |config|
+  config.ssh.port = 200
+end
+VF
+        end
+      end
+
+      it "should load the organization Vagrantfile before the root Vagrantfile" do
+        config = with_temp_env("VAGRANT_ORG_VAGRANTFILE" => org_vagrantfile.to_s) do
+          environment.create_vagrant_env.vagrantfile.config
+        end
+
+        expect(config.ssh.port).to eq(200)
+        expect(config.ssh.username).to eq("org")
+      end
+
+      it "should raise an error if the organization Vagrantfile does not exist" do
+        with_temp_env("VAGRANT_ORG_VAGRANTFILE" => "/nonexistent/Vagrantfile") do
+          expect { environment.create_vagrant_env.vagrantfile }.
+            to raise_error(Vagrant::Errors::OrgVagrantfileNotFound)
+        end
+      end
+
+      it "should fetch the organization Vagrantfile from an OCI registry" do
+        reference = "oci://registry.example.com/vagrant/base:latest"
+        registry = double("registry")
+        expect(Vagrant::Util::OCIRegistry).to receive(:new).with(reference).
+          and_return(registry)
+        expect(registry).to receive(:fetch_vagrantfile).
+          and_return(Pathname.new(org_vagrantfile.to_s))
+
+        config = with_temp_env("VAGRANT_ORG_VAGRANTFILE" => reference) do
+          environment.create_vagrant_env.vagrantfile.config
+        end
+
+        expect(config.ssh.username).to eq("org")
+      end
+    end
   end
 
   describe "ui" do
@@ -1439,6 +1561,53 @@ VF
     end
   end
 
+  describe "clones" do
+    let(:isolated_env) do
+      isolated_environment do |e|
+        e.vagrantfile(<<-VF)
+Vagrant.configure("2") do |config|
+  config.vm.define "web"
+  config.vm.define "db"
+end
+VF
+      end
+    end
+
+    subject { isolated_env.create_vagrant_env }
+
+    it "has no clones by default" do
+      expect(subject.clones).to eq({})
+    end
+
+    it "defines registered clones" do
+      subject.register_clone(:web2, :web)
+
+      expect(subject.clones).to eq(web2: :web)
+      expect(subject.machine_names).to eq([:web, :db, :web2])
+    end
+
+    it "stores the clones in the local data path" do
+      subject.register_clone(:web2, :web)
+
+      env = isolated_env.create_vagrant_env
+      expect(env.machine_names).to eq([:web, :db, :web2])
+    end
+
+    it "removes unregistered clones" do
+      subject.register_clone(:web2, :web)
+      subject.unregister_clone(:web2)
+
+      expect(subject.clones).to eq({})
+      expect(subject.machine_names).to eq([:web, :db])
+    end
+
+    it "ignores clones of undefined machines" do
+      subject.register_clone(:app2, :app)
+
+      expect(subject.machine_names).to eq([:web, :db])
+    end
+  end
+
   describe "guess_provider" do
     before { allow_any_instance_of(described_class).to receive(:process_configured_plugins) }
 
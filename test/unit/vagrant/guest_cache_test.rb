@@ -0,0 +1,116 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require "json"
+require "pathname"
+require "tmpdir"
+
+require File.expand_path("../../base", __FILE__)
+
+describe Vagrant::GuestCache do
+  include_context "unit"
+
+  let(:data_dir) { Pathname.new(Dir.mktmpdir("vagrant-test-guest-cache")) }
+  let(:guest_cache) { true }
+  let(:id) { "ID" }
+  let(:box) { double("box", name: "foo/bar", version: "1.0.0", provider: :virtualbox) }
+  let(:machine) do
+    double("machine",
+      data_dir: data_dir,
+      provider_name: :virtualbox).tap do |m|
+      allow(m).to receive(:id) { id }
+      allow(m).to receive(:box) { box }
+      allow(m).to receive_message_chain(:config, :vm, :guest_cache) { guest_cache }
+    end
+  end
+
+  subject { described_class.new(machine) }
+
+  after do
+    FileUtils.rm_rf(data_dir)
+  end
+
+  describe "#fetch" do
+    it "detects the result once" do
+      calls = 0
+      2.times { expect(subject.fetch(:systemd) { calls += 1; true }).to be(true) }
+      expect(calls).to eq(1)
+    end
+
+    it "does not cache errors" do
+      expect { subject.fetch(:systemd) { raise "failed" } }.to raise_error("failed")
+      expect(subject.fetch(:systemd) { true }).to be(true)
+    end
+
+    it "stores positive results" do
+      subject.fetch(:guest) { "ubuntu" }
+      expect(described_class.new(machine).fetch(:guest) { raise "detected" }).to eq("ubuntu")
+    end
+
+    it "does not store negative results" do
+      subject.fetch(:systemd) { false }
+      expect(subject.fetch(:systemd) { raise "detected" }).to be(false)
+      expect(described_class.new(machine).fetch(:systemd) { true }).to be(true)
+    end
+
+    context "when the box changes" do
+      it "detects the result again" do
+        subject.fetch(:guest) { "ubuntu" }
+        allow(box).to receive(:version).and_return("2.0.0")
+        expect(described_class.new(machine).fetch(:guest) { "debian" }).to eq("debian")
+      end
+    end
+
+    context "when the machine is recreated" do
+      it "detects the result again" do
+        subject.fetch(:guest) { "ubuntu" }
+        allow(machine).to receive(:id).and_return("OTHER")
+        expect(described_class.new(machine).fetch(:guest) { "debian" }).to eq("debian")
+      end
+    end
+
+    context "when the machine is not created" do
+      let(:id) { nil }
+
+      it "does not store results" do
+        subject.fetch(:guest) { "ubuntu" }
+        expect(subject.path).not_to be_file
+      end
+    end
+
+    context "when storing results is disabled" do
+      let(:guest_cache) { false }
+
+      it "only caches results in memory" do
+        subject.fetch(:guest) { "ubuntu" }
+        expect(subject.fetch(:guest) { raise "detected" }).to eq("ubuntu")
+        expect(subject.path).not_to be_file
+      end
+    end
+
+    it "ignores invalid stored results" do
+      subject.path.write("{")
+      expect(subject.fetch(:guest) { "ubuntu" }).to eq("ubuntu")
+    end
+  end
+
+  describe "#get" do
+    it "returns nil if there is no result" do
+      expect(subject.get(:guest)).to be_nil
+    end
+
+    it "returns stored results" do
+      subject.store(:guest, "ubuntu")
+      expect(described_class.new(machine).get(:guest)).to eq("ubuntu")
+    end
+  end
+
+  describe "#clear" do
+    it "removes cached and stored results" do
+      subject.store(:guest, "ubuntu")
+      subject.clear
+      expect(subject.get(:guest)).to be_nil
+      expect(subject.path).not_to be_file
+    end
+  end
+end
@@ -10,9 +10,11 @@ describe Vagrant::Guest do
 
   let(:capabilities) { {} }
   let(:guests)  { {} }
+  let(:guest_cache) { double("guest_cache", get: nil, store: nil) }
   let(:machine) do
     double("machine").tap do |m|
       allow(m).to receive(:inspect).and_return("machine")
+      allow(m).to receive(:guest_cache).and_return(guest_cache)
       allow(m).to receive(:config).and_return(double("config"))
       allow(m.config).to receive(:vm).and_return(double("vm_config"))
       allow(m.config.vm).to receive(:guest).and_return(nil)
@@ -76,6 +78,46 @@ describe Vagrant::Guest do
       expect { subject.detect! }.
         to raise_error(Vagrant::Errors::GuestNotDetected)
     end
+
+    it "caches the auto-detected guest" do
+      guests[:foo] = [detect_class(true), nil]
+      expect(guest_cache).to receive(:store).with(:guest, "foo")
+
+      subject.detect!
+    end
+
+    it "uses the cached guest" do
+      guests[:foo] = [detect_class(false), nil]
+      allow(guest_cache).to receive(:get).with(:guest).and_return("foo")
+
+      subject.detect!
+      expect(subject.name).to eq(:foo)
+    end
+
+    it "ignores a cached guest which is not installed" do
+      allow(guest_cache).to receive(:get).with(:guest).and_return("bar")
+      expect(subject).to receive(:initialize_capabilities!).
+        with(nil, guests, capabilities, machine)
+
+      subject.detect!
+    end
+
+    it "does not cache an explicit guest" do
+      guests[:foo] = [detect_class(false), nil]
+      allow(machine.config.vm).to receive(:guest).and_return(:foo)
+      expect(guest_cache).not_to receive(:store)
+
+      subject.detect!
+    end
+  end
+
+  describe "#reset!" do
+    it "is not ready after resetting" do
+      guests[:foo] = [detect_class(true), nil]
+      subject.detect!
+      subject.reset!
+      expect(subject.ready?).not_to be
+    end
   end
 
   describe "#name" do
@@ -282,6 +282,55 @@ describe Vagrant::MachineIndex do
     end
   end
 
+  describe "#set with a new ID" do
+    let(:entry) do
+      entry_klass.new.tap do |e|
+        e.name = "foo"
+        e.provider = "bar"
+        e.vagrantfile_path = "/bar"
+      end
+    end
+
+    it "uses the given ID for a new entry" do
+      result = subject.set(entry, new_id: "abc123")
+      expect(result.id).to eq("abc123")
+      subject.release(result)
+
+      expect(described_class.new(data_dir).include?("abc123")).to be(true)
+    end
+
+    it "uses the ID of an existing entry for the same machine" do
+      result = subject.set(entry)
+      subject.release(result)
+
+      entry2 = entry_klass.new
+      entry2.name = entry.name
+      entry2.provider = entry.provider
+      entry2.vagrantfile_path = entry.vagrantfile_path
+
+      nextresult = subject.set(entry2, new_id: "abc123")
+      expect(nextresult.id).to eq(result.id)
+      subject.release(nextresult)
+    end
+  end
+
+  describe ".deterministic_id" do
+    it "is stable for the same path and name" do
+      expect(described_class.deterministic_id("/foo", "default")).
+        to eq(described_class.deterministic_id(Pathname.new("/foo"), :default))
+    end
+
+    it "is different for different paths or names" do
+      id = described_class.deterministic_id("/foo", "default")
+      expect(described_class.deterministic_id("/bar", "default")).not_to eq(id)
+      expect(described_class.deterministic_id("/foo", "web")).not_to eq(id)
+    end
+
+    it "has the format of a random UUID" do
+      expect(described_class.deterministic_id("/foo", "default")).to match(/\A\h{32}\z/)
+    end
+  end
+
   describe "#recover" do
     it "recovers an entry if not in the index" do
       result = subject.recover(new_entry)
@@ -294,6 +294,13 @@ describe Vagrant::Machine do
       expect(machine).to eql(instance)
     end
 
+    it "should clear the guest detection results when redetecting the guest" do
+      allow(provider).to receive(:action).and_return(lambda { |_env| })
+      expect(instance.guest_cache).to receive(:clear)
+
+      instance.action(:up, guest_redetect: true)
+    end
+
     it "should pass any extra options to the environment" do
       action_name = :destroy
       foo         = nil
@@ -416,6 +423,81 @@ describe Vagrant::Machine do
     end
   end
 
+  describe "#action with lifecycle hooks" do
+    let(:events) { [] }
+    let(:hook_error) { nil }
+    let(:callable) { lambda { |_env| } }
+    let(:running) { Vagrant::MachineState.new(:running, "running", "") }
+    let(:poweroff) { Vagrant::MachineState.new(:poweroff, "poweroff", "") }
+
+    before do
+      allow(Vagrant::Plugin::Manager.instance).to receive(:installed_plugins).and_return({})
+
+      # Initialize the machine before stubbing the state changes
+      instance
+      allow(provider).to receive(:action).with(:halt).and_return(callable)
+      allow(provider).to receive(:state).and_return(running, poweroff)
+
+      recorded = events
+      error = hook_error
+      hook = Class.new(Vagrant.plugin("2", :lifecycle)) do
+        define_method(:on_state_change) do |old_state, new_state, target, phase:, action:|
+          recorded << [phase, action, old_state && old_state.id,
+                       new_state && new_state.id, target.name, target.provider]
+          raise error if error && phase == :before
+        end
+      end
+
+      register_plugin do |p|
+        p.lifecycle("test") { hook }
+      end
+    end
+
+    it "notifies hooks before and after the state changes" do
+      instance.action(:halt)
+
+      expect(events).to eq([
+        [:before, :halt, :running, nil, "foo", "test"],
+        [:after, :halt, :running, :poweroff, "foo", "test"],
+      ])
+    end
+
+    it "does not notify hooks after the action if the state did not change" do
+      allow(provider).to receive(:state).and_return(running)
+      instance.action(:halt)
+
+      expect(events.map(&:first)).to eq([:before])
+    end
+
+    context "when a hook raises a Vagrant error before the action" do
+      let(:hook_error) { Vagrant::Errors::VagrantError }
+
+      it "does not run the action" do
+        called = false
+        allow(provider).to receive(:action).with(:halt).
+          and_return(lambda { |_env| called = true })
+
+        expect { instance.action(:halt) }.
+          to raise_error(Vagrant::Errors::MachineLifecycleVetoed)
+        expect(called).to be(false)
+      end
+    end
+
+    context "when a hook fails unexpectedly" do
+      let(:hook_error) { RuntimeError.new("broken hook") }
+
+      it "runs the action and warns the user" do
+        called = false
+        allow(provider).to receive(:action).with(:halt).
+          and_return(lambda { |_env| called = true })
+        expect(instance.ui).to receive(:warn).with(/broken hook/)
+
+        instance.action(:halt)
+        expect(called).to be(true)
+      end
+    end
+  end
+
   describe "#action_raw" do
     let(:callable) {lambda { |e|
       e[:called] = true
@@ -447,6 +529,14 @@ describe Vagrant::Machine do
       expect(@env[:called]).to be(true)
       expect(@env[:foo]).to eq(:bar)
     end
+
+    it "should add the machine and action to the log fields" do
+      fields = nil
+      subject.action_raw(:foo, lambda { |_| fields = Vagrant::Util::Logging.fields })
+
+      expect(fields).to eq(machine: subject.name.to_s, action: "foo")
+      expect(Vagrant::Util::Logging.fields).to eq({})
+    end
   end
 
   describe "#communicate" do
@@ -563,6 +653,14 @@ describe Vagrant::Machine do
     end
   end
 
+  describe "#provider_state" do
+    it "stores state in the data directory" do
+      subject.provider_state.set("foo", "bar")
+      expect(subject.data_dir.join(Vagrant::ProviderState::STATE_FILE)).to be_file
+      expect(new_instance.provider_state.get("foo")).to eq("bar")
+    end
+  end
+
   describe "#index_uuid" do
     before(:each) do
       allow(provider).to receive(:machine_id_changed)
@@ -615,6 +713,65 @@ describe Vagrant::Machine do
       expect(subject.index_uuid).to be_nil
       expect(env.machine_index.get(uuid)).to be_nil
     end
+
+    it "is random by default" do
+      subject.id = "foo"
+
+      expect(subject.index_uuid).not_to eq(
+        Vagrant::MachineIndex.deterministic_id(env.root_path, name))
+    end
+
+    context "with deterministic IDs" do
+      let(:uuid) { Vagrant::MachineIndex.deterministic_id(env.root_path, name) }
+
+      before do
+        allow(config.vagrant).to receive(:deterministic_ids).and_return(true)
+      end
+
+      it "derives the UUID from the project path and name" do
+        subject.id = "foo"
+        expect(subject.index_uuid).to eq(uuid)
+      end
+
+      it "uses the same UUID when the machine is created again" do
+        subject.id = "foo"
+        subject.id = nil
+
+        instance = new_instance
+        instance.id = "bar"
+        expect(instance.index_uuid).to eq(uuid)
+      end
+
+      context "when the UUID belongs to a machine of another project" do
+        before do
+          other = Vagrant::MachineIndex::Entry.new
+          other.name = "other"
+          other.provider = "dummy"
+          other.vagrantfile_path = "/other/project"
+          env.machine_index.release(env.machine_index.set(other, new_id: uuid))
+        end
+
+        it "raises an error if the other machine exists" do
+          allow_any_instance_of(Vagrant::MachineIndex::Entry).
+            to receive(:valid?).and_return(true)
+
+          expect { subject.id = "foo" }.
+            to raise_error(Vagrant::Errors::MachineIndexIdCollision)
+        end
+
+        it "replaces the entry if the other machine no longer exists" do
+          allow_any_instance_of(Vagrant::MachineIndex::Entry).
+            to receive(:valid?).and_return(false)
+
+          subject.id = "foo"
+          expect(subject.index_uuid).to eq(uuid)
+
+          entry = env.machine_index.get(uuid)
+          expect(entry.name).to eq(name)
+          env.machine_index.release(entry)
+        end
+      end
+    end
   end
 
   describe "#reload" do
@@ -63,6 +63,65 @@ describe Vagrant::Plugin::V2::Config do
     end
   end
 
+  describe "#validate_config" do
+    let(:schema_class) do
+      Class.new(described_class) do
+        attr_accessor :enabled
+        attr_accessor :name
+
+        def self.schema
+          { enabled: [:boolean], name: [String] }
+        end
+      end
+    end
+
+    subject { schema_class.new }
+
+    it "returns nothing for a valid config" do
+      subject.enabled = false
+      subject.name = "foo"
+      expect(subject.validate_config(nil)).to be_empty
+    end
+
+    it "ignores values which are not set" do
+      subject.name = nil
+      expect(subject.validate_config(nil)).to be_empty
+    end
+
+    it "warns about values of the wrong type" do
+      subject.enabled = "yes"
+
+      result = subject.validate_config(nil)
+      expect(result.size).to eq(1)
+      expect(result.first.level).to eq(:warning)
+      expect(result.first.key).to eq("enabled")
+      expect(result.first.message).to include("boolean")
+    end
+
+    it "does not report unknown settings" do
+      subject.nmae = "foo"
+      expect(subject.validate_config(nil)).to be_empty
+    end
+  end
+
+  describe "#_detected_errors" do
+    it "reports unknown settings with their location" do
+      subject.nmae = "foo"
+
+      result = subject._detected_errors
+      expect(result.size).to eq(1)
+      expect(result.first).to include("nmae (#{__FILE__}:#{__LINE__ - 4})")
+    end
+
+    it "keeps the location of unknown settings when merged" do
+      one = described_class.new
+      one.nmae = "foo"
+
+      result = one.merge(described_class.new)._detected_errors
+      expect(result.first).to include("nmae (#{__FILE__}:")
+    end
+  end
+
   describe "#method_missing" do
     it "returns a DummyConfig object" do
       expect(subject.i_should_not_exist).
@@ -344,6 +344,23 @@ describe Vagrant::Plugin::V2::Manager do
   end
 
 
+  it "should enumerate all registered lifecycle hooks" do
+    pA = plugin do |p|
+      p.lifecycle("foo") { "bar" }
+    end
+
+    pB = plugin do |p|
+      p.lifecycle("bar") { "baz" }
+    end
+
+    instance.register(pA)
+    instance.register(pB)
+
+    expect(instance.lifecycles.to_hash.length).to eq(2)
+    expect(instance.lifecycles[:foo]).to eq("bar")
+    expect(instance.lifecycles[:bar]).to eq("baz")
+  end
+
   it "should enumerate all registered synced folder implementations" do
     pA = plugin do |p|
       p.synced_folder("foo") { "bar" }
@@ -326,6 +326,27 @@ describe Vagrant::Plugin::V2::Plugin do
     end
   end
 
+  describe "lifecycles" do
+    it "should register lifecycle hooks" do
+      plugin = Class.new(described_class) do
+        lifecycle("foo") { "bar" }
+      end
+
+      expect(plugin.components.lifecycles[:foo]).to eq("bar")
+    end
+
+    it "should lazily register lifecycle hooks" do
+      plugin = nil
+      expect {
+        plugin = Class.new(described_class) do
+          lifecycle("foo") { raise StandardError, "FAIL!" }
+        end
+      }.to_not raise_error
+
+      expect { plugin.components.lifecycles[:foo] }.to raise_error(StandardError)
+    end
+  end
+
   describe "pushes" do
     it "should register implementations" do
       plugin = Class.new(described_class) do
@@ -0,0 +1,113 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require File.expand_path("../../../../base", __FILE__)
+
+describe Vagrant::Plugin::V2::TriggerCondition do
+  include_context "unit"
+
+  let(:machine) { double("machine", state: double("state", id: :running)) }
+  let(:exit_code) { nil }
+  let(:env) { {"DEPLOY" => "1", "NAME" => "web-1"} }
+  let(:context) do
+    described_class::Context.new(machine: machine, exit_code: exit_code, env: env)
+  end
+
+  def evaluate(condition)
+    described_class.parse(condition).evaluate(context)
+  end
+
+  describe ".parse" do
+    it "raises an error for an empty condition" do
+      expect { described_class.parse(" ") }.
+        to raise_error(Vagrant::Errors::TriggersConditionInvalid)
+    end
+
+    it "raises an error for an unknown variable" do
+      expect { described_class.parse("status == :running") }.
+        to raise_error(Vagrant::Errors::TriggersConditionInvalid, /unknown variable 'status'/)
+    end
+
+    it "raises an error for an incomplete comparison" do
+      expect { described_class.parse("exit_code ==") }.
+        to raise_error(Vagrant::Errors::TriggersConditionInvalid, /unexpected end/)
+    end
+
+    it "raises an error for unbalanced parentheses" do
+      expect { described_class.parse("(state == :running") }.
+        to raise_error(Vagrant::Errors::TriggersConditionInvalid, /missing '\)'/)
+    end
+
+    it "raises an error for trailing tokens" do
+      expect { described_class.parse("state :running") }.
+        to raise_error(Vagrant::Errors::TriggersConditionInvalid, /unexpected ':running'/)
+    end
+
+    it "raises an error for unknown characters" do
+      expect { described_class.parse("state = :running") }.
+        to raise_error(Vagrant::Errors::TriggersConditionInvalid)
+    end
+  end
+
+  describe "#evaluate" do
+    it "compares the machine state" do
+      expect(evaluate("state == :running")).to be(true)
+      expect(evaluate("state == 'running'")).to be(true)
+      expect(evaluate("state != :running")).to be(false)
+    end
+
+    it "compares environment variables" do
+      expect(evaluate("env.DEPLOY == \"1\"")).to be(true)
+      expect(evaluate("env.DEPLOY == 1")).to be(true)
+      expect(evaluate("env.NAME =~ /^web-/")).to be(true)
+      expect(evaluate("env.NAME !~ /^db-/")).to be(true)
+    end
+
+    it "checks if environment variables are set" do
+      expect(evaluate("env.DEPLOY")).to be(true)
+      expect(evaluate("!env.MISSING")).to be(true)
+      expect(evaluate("env.MISSING == nil")).to be(true)
+    end
+
+    it "combines conditions" do
+      expect(evaluate("state == :running && env.DEPLOY == 1")).to be(true)
+      expect(evaluate("state == :poweroff || env.DEPLOY == 2")).to be(false)
+      expect(evaluate("!(state == :poweroff || env.DEPLOY == 2)")).to be(true)
+    end
+
+    it "gives && precedence over ||" do
+      expect(evaluate("state == :running || env.DEPLOY == 2 && env.DEPLOY == 3")).to be(true)
+    end
+
+    context "without an exit code" do
+      it "is nil" do
+        expect(evaluate("exit_code == nil")).to be(true)
+        expect(evaluate("exit_code > 0")).to be(false)
+      end
+    end
+
+    context "with an exit code" do
+      let(:exit_code) { 2 }
+
+      it "compares the exit code" do
+        expect(evaluate("exit_code != 0")).to be(true)
+        expect(evaluate("exit_code >= 2")).to be(true)
+        expect(evaluate("exit_code < 2")).to be(false)
+      end
+    end
+
+    context "without a machine" do
+      let(:machine) { nil }
+
+      it "has no state" do
+        expect(evaluate("state == nil")).to be(true)
+        expect(evaluate("state")).to be(false)
+      end
+    end
+
+    it "only reads the machine state when it is used" do
+      expect(machine).not_to receive(:state)
+      evaluate("env.DEPLOY")
+    end
+  end
+end
@@ -60,6 +60,85 @@ describe Vagrant::Plugin::V2::Trigger do
       expect(subject).to receive(:execute)
       subject.fire(:up, :before, "guest", :action)
     end
+
+    context "with conditional triggers" do
+      let(:triggers) {
+        @triggers ||= VagrantPlugins::Kernel_V2::TriggerConfig.new.tap do |triggers|
+          triggers.after(:up, hash_block)
+          triggers.after(:up, info: "failed", only_if: "exit_code != 0")
+          triggers.finalize!
+        end
+      }
+
+      before { allow(subject).to receive(:community_plugin_detected?).and_return(false) }
+
+      it "passes the exit code to the conditions" do
+        expect(subject).to receive(:execute) { |t| expect(t.size).to eq(2) }
+        subject.fire(:up, :after, "guest", :action, exit_code: 1)
+      end
+
+      it "skips triggers with false conditions" do
+        expect(subject).to receive(:execute) { |t| expect(t.size).to eq(1) }
+        subject.fire(:up, :after, "guest", :action, exit_code: 0)
+      end
+
+      it "only fires triggers with conditions if requested" do
+        expect(subject).to receive(:execute) { |t| expect(t.map(&:info)).to eq(["failed"]) }
+        subject.fire(:up, :after, "guest", :action, exit_code: 1, conditional_only: true)
+      end
+    end
+  end
+
+  describe "#filter_conditions" do
+    let(:trigger_config) { {info: "hi"} }
+    let(:conditional_triggers) {
+      VagrantPlugins::Kernel_V2::TriggerConfig.new.tap do |triggers|
+        triggers.before(:up, trigger_config)
+        triggers.finalize!
+      end.before_triggers
+    }
+
+    it "keeps triggers without conditions" do
+      expect(subject.send(:filter_conditions, conditional_triggers, nil).size).to eq(1)
+    end
+
+    context "with an only_if condition" do
+      let(:trigger_config) { {info: "hi", only_if: "state == :running"} }
+
+      it "keeps the trigger if the condition is true" do
+        expect(subject.send(:filter_conditions, conditional_triggers, nil).size).to eq(1)
+      end
+
+      it "skips the trigger if the condition is false" do
+        allow(state).to receive(:id).and_return(:poweroff)
+        expect(subject.send(:filter_conditions, conditional_triggers, nil)).to be_empty
+      end
+    end
+
+    context "with an unless condition" do
+      let(:trigger_config) { {info: "hi", unless: "state == :running"} }
+
+      it "skips the trigger if the condition is true" do
+        expect(subject.send(:filter_conditions, conditional_triggers, nil)).to be_empty
+      end
+    end
+
+    context "with a condition which can not be evaluated" do
+      let(:trigger_config) { {info: "hi", only_if: "state =="} }
+
+      it "raises an error" do
+        expect { subject.send(:filter_conditions, conditional_triggers, nil) }.
+          to raise_error(Vagrant::Errors::TriggersConditionInvalid)
+      end
+
+      context "when on_error is continue" do
+        let(:trigger_config) { {info: "hi", only_if: "state ==", on_error: :continue} }
+
+        it "skips the trigger" do
+          expect(subject.send(:filter_conditions, conditional_triggers, nil)).to be_empty
+        end
+      end
+    end
   end
 
   describe "#find" do
@@ -263,6 +342,7 @@ describe Vagrant::Plugin::V2::Trigger do
     let(:subprocess_result) do
       double("subprocess_result").tap do |result|
         allow(result).to receive(:exit_code).and_return(exit_code)
+        allow(result).to receive(:stdout).and_return("")
         allow(result).to receive(:stderr).and_return("")
       end
     end
@@ -270,6 +350,7 @@ describe Vagrant::Plugin::V2::Trigger do
     let(:subprocess_result_failure) do
       double("subprocess_result_failure").tap do |result|
         allow(result).to receive(:exit_code).and_return(1)
+        allow(result).to receive(:stdout).and_return("")
         allow(result).to receive(:stderr).and_return("")
       end
     end
@@ -277,6 +358,7 @@ describe Vagrant::Plugin::V2::Trigger do
     let(:subprocess_result_custom) do
       double("subprocess_result_custom").tap do |result|
         allow(result).to receive(:exit_code).and_return(50)
+        allow(result).to receive(:stdout).and_return("failed\n")
         allow(result).to receive(:stderr).and_return("")
       end
     end
@@ -407,7 +489,8 @@ describe Vagrant::Plugin::V2::Trigger do
 
       expect(Vagrant::Util::Subprocess).to receive(:execute).
         with("echo", "hi", options)
-      expect { subject.send(:run, shell_config, on_error, exit_codes) }.to raise_error(Vagrant::Errors::TriggersBadExitCodes)
+      expect { subject.send(:run, shell_config, on_error, exit_codes) }.
+        to raise_error(Vagrant::Errors::TriggersBadExitCodes, /failed/)
     end
   end
 
@@ -448,35 +531,78 @@ describe Vagrant::Plugin::V2::Trigger do
       end
     end
 
-    it "raises an error and halts if guest is not running" do
-      allow(machine.state).to receive(:id).and_return(:not_running)
+    context "when the guest is not reachable" do
+      before do
+        allow(machine.state).to receive(:id).and_return(:not_running)
+        allow(machine).to receive_message_chain(:communicate, :ready?).and_return(false)
+      end
 
-      trigger = trigger_run.after_triggers.first
-      shell_config = trigger.run_remote
-      on_error = trigger.on_error
-      exit_codes = trigger.exit_codes
+      it "skips the script with a warning" do
+        expect(VagrantPlugins::Shell::Provisioner).not_to receive(:new)
+        expect(machine.ui).to receive(:warn).
+          with(/Skipping remote script on #{machine.name}/)
 
-      expect { subject.send(:run_remote, shell_config, on_error, exit_codes) }.
-        to raise_error(Vagrant::Errors::TriggersGuestNotRunning)
+        trigger = trigger_run.after_triggers.first
+        subject.send(:run_remote, trigger.run_remote, trigger.on_error, trigger.exit_codes)
+      end
     end
 
-    it "continues on if guest is not running but is configured to continue on error" do
-      allow(machine.state).to receive(:id).and_return(:not_running)
+    context "with the guest reachable" do
+      before do
+        allow(machine).to receive_message_chain(:communicate, :ready?).and_return(true)
+        allow(VagrantPlugins::Shell::Provisioner).to receive(:new).
+          and_return(provision)
+        allow(provision).to receive(:error_check=)
+        allow(provision).to receive(:exit_code).and_return(0)
+        allow(provision).to receive(:output).and_return("")
+      end
 
-      allow(env).to receive(:root_path).and_return("/vagrant/home")
-      allow(FileUtils).to receive(:chmod).and_return(true)
+      it "disables the exit code check of the shell provisioner" do
+        allow(provision).to receive(:provision)
+        expect(provision).to receive(:error_check=).with(false)
 
-      trigger = trigger_run.before_triggers.first
-      shell_config = trigger.run_remote
-      on_error = trigger.on_error
-      exit_codes = trigger.exit_codes
+        trigger = trigger_run.after_triggers.first
+        subject.send(:run_remote, trigger.run_remote, trigger.on_error, trigger.exit_codes)
+      end
 
-      subject.send(:run_remote, shell_config, on_error, exit_codes)
+      it "raises an error if the script exits with an unacceptable exit code" do
+        allow(provision).to receive(:provision)
+        allow(provision).to receive(:exit_code).and_return(2)
+        allow(provision).to receive(:output).and_return("failed\n")
+
+        trigger = trigger_run.after_triggers.first
+        expect { subject.send(:run_remote, trigger.run_remote, trigger.on_error, trigger.exit_codes) }.
+          to raise_error(Vagrant::Errors::TriggersBadExitCodes) { |e|
+            expect(e.extra_data[:code]).to eq(2)
+            expect(e.extra_data[:output]).to eq("failed\n")
+            expect(e.message).to include("failed")
+          }
+      end
+
+      it "accepts configured exit codes" do
+        allow(provision).to receive(:provision)
+        allow(provision).to receive(:exit_code).and_return(2)
+
+        trigger = trigger_run.after_triggers.first
+        subject.send(:run_remote, trigger.run_remote, trigger.on_error, [0, 2])
+      end
+
+      it "continues on an unacceptable exit code if configured to continue on error" do
+        allow(provision).to receive(:provision)
+        allow(provision).to receive(:exit_code).and_return(2)
+        expect(machine.ui).to receive(:warn).
+          with(I18n.t("vagrant.trigger.on_error_continue"))
+
+        trigger = trigger_run.before_triggers.first
+        subject.send(:run_remote, trigger.run_remote, trigger.on_error, trigger.exit_codes)
+      end
     end
 
     it "calls the provision function on the shell provisioner" do
-      allow(machine.state).to receive(:id).and_return(:running)
+      allow(machine).to receive_message_chain(:communicate, :ready?).and_return(true)
       allow(provision).to receive(:provision).and_return("Provision!")
+      allow(provision).to receive(:error_check=)
+      allow(provision).to receive(:exit_code).and_return(0)
       allow(VagrantPlugins::Shell::Provisioner).to receive(:new).
         and_return(provision)
 
@@ -489,7 +615,8 @@ describe Vagrant::Plugin::V2::Trigger do
     end
 
     it "continues on if provision fails" do
-      allow(machine.state).to receive(:id).and_return(:running)
+      allow(machine).to receive_message_chain(:communicate, :ready?).and_return(true)
+      allow(provision).to receive(:error_check=)
       allow(provision).to receive(:provision).and_raise("Nope!")
       allow(VagrantPlugins::Shell::Provisioner).to receive(:new).
         and_return(provision)
@@ -503,7 +630,8 @@ describe Vagrant::Plugin::V2::Trigger do
     end
 
     it "fails if it encounters an error" do
-      allow(machine.state).to receive(:id).and_return(:running)
+      allow(machine).to receive_message_chain(:communicate, :ready?).and_return(true)
+      allow(provision).to receive(:error_check=)
       allow(provision).to receive(:provision).and_raise("Nope!")
       allow(VagrantPlugins::Shell::Provisioner).to receive(:new).
         and_return(provision)
@@ -572,6 +700,17 @@ describe Vagrant::Plugin::V2::Trigger do
       expect(block).to receive(:call)
       subject.send(:execute_ruby, block)
     end
+
+    it "raises errors from the block" do
+      expect(block).to receive(:call).and_raise(StandardError, "failed")
+      expect { subject.send(:execute_ruby, block, :halt) }.
+        to raise_error(StandardError, "failed")
+    end
+
+    it "continues after errors from the block if on_error is continue" do
+      expect(block).to receive(:call).and_raise(StandardError, "failed")
+      expect { subject.send(:execute_ruby, block, :continue) }.not_to raise_error
+    end
   end
 
   describe "#nameify" do
@@ -0,0 +1,152 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require "json"
+require "pathname"
+require "tmpdir"
+
+require File.expand_path("../../base", __FILE__)
+
+describe Vagrant::ProviderState do
+  include_context "unit"
+
+  let(:data_dir) { Pathname.new(Dir.mktmpdir("vagrant-test-provider-state")) }
+
+  subject { described_class.new(data_dir) }
+
+  after do
+    FileUtils.rm_rf(data_dir)
+  end
+
+  describe "#get" do
+    it "returns the default if the key is not set" do
+      expect(subject.get("foo")).to be_nil
+      expect(subject.get("foo", default: "bar")).to eq("bar")
+    end
+
+    it "returns the stored value" do
+      subject.set("foo", "bar" => [1, 2])
+      expect(subject.get("foo")).to eq("bar" => [1, 2])
+    end
+
+    it "returns the value if it has the required type" do
+      subject.set(:foo, 1)
+      expect(subject.get(:foo, type: :integer)).to eq(1)
+    end
+
+    it "raises an error if the value is not of the required type" do
+      subject.set("foo", "1")
+      expect { subject.get("foo", type: :integer) }.
+        to raise_error(Vagrant::Errors::ProviderStateTypeMismatch)
+    end
+
+    it "raises an error if the stored state is corrupt" do
+      data_dir.join(described_class::STATE_FILE).write("{")
+      expect { subject.get("foo") }.
+        to raise_error(Vagrant::Errors::ProviderStateCorrupt)
+    end
+  end
+
+  describe "#set" do
+    it "stores the value with its type" do
+      subject.set("foo", true)
+      expect(subject.to_h).to eq("foo" => { "type" => "boolean", "value" => true })
+    end
+
+    it "persists the value" do
+      subject.set("foo", 1.5)
+      expect(described_class.new(data_dir).get("foo", type: :float)).to eq(1.5)
+    end
+
+    it "does not leave temporary files" do
+      subject.set("foo", "bar")
+      expect(data_dir.children.map { |c| c.basename.to_s }).to contain_exactly(
+        described_class::STATE_FILE, described_class::LOCK_FILE)
+    end
+
+    it "does not modify the state if the write fails" do
+      subject.set("foo", "bar")
+      allow(File).to receive(:rename).and_raise(Errno::ENOSPC)
+      expect { subject.set("foo", "baz") }.to raise_error(Errno::ENOSPC)
+      expect(subject.get("foo")).to eq("bar")
+      expect(data_dir.children.length).to eq(2)
+    end
+
+    it "raises an error for values which can not be stored" do
+      expect { subject.set("foo", Object.new) }.
+        to raise_error(Vagrant::Errors::ProviderStateInvalidValue)
+      expect { subject.set("foo", foo: "bar") }.
+        to raise_error(Vagrant::Errors::ProviderStateInvalidValue)
+    end
+  end
+
+  describe "#update" do
+    it "stores the value returned by the block" do
+      subject.set("foo", 1)
+      expect(subject.update("foo") { |v| v + 1 }).to eq(2)
+      expect(subject.get("foo", type: :integer)).to eq(2)
+    end
+
+    it "gives the default to the block if the key is not set" do
+      expect(subject.update("foo", default: []) { |v| v + ["bar"] }).to eq(["bar"])
+      expect(subject.get("foo", type: :array)).to eq(["bar"])
+    end
+
+    it "does not modify other values" do
+      subject.set("foo", "bar")
+      subject.update("baz", default: 0) { |v| v + 1 }
+      expect(subject.get("foo")).to eq("bar")
+    end
+
+    it "holds an exclusive lock while the block runs" do
+      expect(subject).to receive(:with_lock).with(File::LOCK_EX).and_call_original
+      subject.update("foo", default: 0) { |v| v + 1 }
+    end
+
+    it "raises an error if the type does not match" do
+      subject.set("foo", "bar")
+      expect { subject.update("foo", type: :integer) { |v| v } }.
+        to raise_error(Vagrant::Errors::ProviderStateTypeMismatch)
+    end
+
+    it "does not modify the state if the block raises an error" do
+      subject.set("foo", "bar")
+      expect { subject.update("foo") { raise "failed" } }.to raise_error("failed")
+      expect(subject.get("foo")).to eq("bar")
+    end
+
+    it "raises an error for values which can not be stored" do
+      expect { subject.update("foo") { Object.new } }.
+        to raise_error(Vagrant::Errors::ProviderStateInvalidValue)
+      expect(subject.key?("foo")).to be(false)
+    end
+  end
+
+  describe "#delete" do
+    it "removes the value" do
+      subject.set("foo", "bar")
+      expect(subject.delete("foo")).to eq("bar")
+      expect(subject.key?("foo")).to be(false)
+    end
+
+    it "returns nil if the key is not set" do
+      expect(subject.delete("foo")).to be_nil
+    end
+  end
+
+  describe "#keys" do
+    it "returns the sorted keys" do
+      subject.set("b", 1)
+      subject.set("a", 2)
+      expect(subject.keys).to eq(["a", "b"])
+    end
+  end
+
+  it "serializes concurrent writes" do
+    10.times.map { |i|
+      Thread.new { described_class.new(data_dir).set("key#{i}", i) }
+    }.each(&:join)
+
+    expect(subject.keys.length).to eq(10)
+  end
+end
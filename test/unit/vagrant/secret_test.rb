@@ -0,0 +1,125 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require File.expand_path("../../base", __FILE__)
+
+describe Vagrant::Secret do
+  include_context "unit"
+
+  let(:provider) do
+    Class.new(Vagrant.plugin("2", :secret_provider)) do
+      def resolve(path, field)
+        values = { "app" => { "token" => "s3cret", nil => "default" } }
+        raise "unavailable" if path == "broken"
+
+        values.fetch(path, {})[field]
+      end
+    end
+  end
+
+  let(:plugin) do
+    klass = provider
+    register_plugin("2") do |p|
+      p.secret_provider(:test) { klass }
+    end
+  end
+
+  before { plugin }
+
+  after do
+    described_class.reset!
+    Vagrant::Util::CredentialScrubber.reset!
+  end
+
+  describe Vagrant::Secret::Reference do
+    it "parses the provider, path and field" do
+      reference = described_class.new("vault:secret/data/app#token")
+      expect(reference.provider).to eq(:vault)
+      expect(reference.path).to eq("secret/data/app")
+      expect(reference.field).to eq("token")
+    end
+
+    it "has no field if none is given" do
+      expect(described_class.new("env:TOKEN").field).to be_nil
+    end
+
+    it "raises an error without a provider" do
+      expect { described_class.new("TOKEN") }.
+        to raise_error(Vagrant::Errors::SecretReferenceInvalid)
+    end
+
+    it "raises an error without a path" do
+      expect { described_class.new("env:") }.
+        to raise_error(Vagrant::Errors::SecretReferenceInvalid)
+    end
+
+    it "does not include a value when inspected" do
+      expect(described_class.new("env:TOKEN").inspect).to eq("secret(env:TOKEN)")
+    end
+  end
+
+  describe ".resolve" do
+    it "reads the secret from the provider" do
+      expect(described_class.resolve(Vagrant.secret("test:app#token"))).to eq("s3cret")
+    end
+
+    it "reads the secret without a field" do
+      expect(described_class.resolve(Vagrant.secret("test:app"))).to eq("default")
+    end
+
+    it "registers the value as sensitive" do
+      described_class.resolve(Vagrant.secret("test:app#token"))
+      expect(Vagrant::Util::CredentialScrubber.desensitize("token is s3cret")).
+        to eq("token is *****")
+    end
+
+    it "only reads each secret once" do
+      expect_any_instance_of(provider).to receive(:resolve).once.and_return("s3cret")
+      2.times { described_class.resolve(Vagrant.secret("test:app#token")) }
+    end
+
+    it "raises an error if the secret does not exist" do
+      expect { described_class.resolve(Vagrant.secret("test:app#missing")) }.
+        to raise_error(Vagrant::Errors::SecretNotFound, /test:app#missing/)
+    end
+
+    it "raises an error if the provider fails" do
+      expect { described_class.resolve(Vagrant.secret("test:broken")) }.
+        to raise_error(Vagrant::Errors::SecretProviderFailed, /unavailable/)
+    end
+
+    it "raises an error if the provider does not exist" do
+      expect { described_class.resolve(Vagrant.secret("unknown:app")) }.
+        to raise_error(Vagrant::Errors::SecretProviderNotFound, /unknown/)
+    end
+  end
+
+  describe ".resolve_all" do
+    let(:config_class) do
+      Class.new(Vagrant.plugin("2", :config)) do
+        attr_accessor :password
+        attr_accessor :options
+      end
+    end
+
+    it "resolves references within configuration objects" do
+      config = config_class.new
+      config.password = Vagrant.secret("test:app#token")
+      config.options = { "nested" => [Vagrant.secret("test:app")] }
+
+      described_class.resolve_all(config)
+
+      expect(config.password).to eq("s3cret")
+      expect(config.options).to eq("nested" => ["default"])
+    end
+
+    it "returns the value of a reference" do
+      expect(described_class.resolve_all(Vagrant.secret("test:app"))).to eq("default")
+    end
+
+    it "leaves other values alone" do
+      value = "plain"
+      expect(described_class.resolve_all(value)).to equal(value)
+    end
+  end
+end
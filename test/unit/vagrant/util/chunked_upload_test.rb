@@ -0,0 +1,158 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require File.expand_path("../../../base", __FILE__)
+
+require "vagrant/util/chunked_upload"
+
+describe Vagrant::Util::ChunkedUpload do
+  include_context "unit"
+
+  # Communicator writing the "remote" files to a local directory
+  let(:communicator_class) do
+    Class.new(Vagrant.plugin("2", :communicator)) do
+      attr_reader :chunks
+
+      def initialize(*)
+        @chunks = []
+      end
+
+      def chunked_upload?
+        true
+      end
+
+      def upload_chunk(from, to, offset)
+        @chunks << [File.size(from), offset]
+        File.open(to, offset == 0 ? "wb" : "ab") { |f| f.write(File.binread(from)) }
+      end
+
+      def file_size(path)
+        File.file?(path) ? File.size(path) : nil
+      end
+
+      def file_checksum(path, type)
+        Vagrant::Util::ChunkedUpload::CHECKSUM_TYPES[type].file(path).hexdigest
+      end
+
+      def move_file(from, to)
+        FileUtils.mv(from, to)
+      end
+    end
+  end
+  let(:communicator) { communicator_class.new }
+  let(:chunk_size) { 10 }
+  let(:checksum_type) { nil }
+  let(:dir) { Dir.mktmpdir("vagrant-test-chunked-upload") }
+  let(:source) { File.join(dir, "source") }
+  let(:destination) { File.join(dir, "destination") }
+
+  subject do
+    described_class.new(communicator, chunk_size: chunk_size,
+      checksum_type: checksum_type)
+  end
+
+  before { File.binwrite(source, "0123456789" * 2 + "abcde") }
+
+  after { FileUtils.rm_rf(dir) }
+
+  def partial_path
+    Dir.glob("#{destination}.vagrant-partial-*").first ||
+      "#{destination}.vagrant-partial-" +
+      Digest::SHA1.hexdigest("#{File.expand_path(source)}:#{File.size(source)}:" \
+        "#{File.mtime(source).to_i}")[0, 8]
+  end
+
+  describe "#checksum?" do
+    it "is false without a checksum type" do
+      expect(subject.checksum?).to be(false)
+    end
+
+    context "with a checksum type" do
+      let(:checksum_type) { "sha256" }
+
+      it "is true" do
+        expect(subject.checksum?).to be(true)
+      end
+    end
+  end
+
+  describe "#chunked?" do
+    it "is true for files larger than the chunk size" do
+      expect(subject.chunked?(source)).to be(true)
+    end
+
+    it "is false for files within the chunk size" do
+      File.binwrite(source, "0123456789")
+      expect(subject.chunked?(source)).to be(false)
+    end
+
+    it "is false for directories" do
+      expect(subject.chunked?(dir)).to be(false)
+    end
+  end
+
+  describe "#upload" do
+    it "uploads the file in chunks" do
+      expect(subject.upload(source, destination)).to be(true)
+      expect(File.binread(destination)).to eq(File.binread(source))
+      expect(communicator.chunks).to eq([[10, 0], [10, 10], [5, 20]])
+    end
+
+    it "does not leave the partial file" do
+      subject.upload(source, destination)
+      expect(Dir.glob("#{destination}.vagrant-partial-*")).to be_empty
+    end
+
+    it "resumes an interrupted upload" do
+      File.binwrite(partial_path, "0123456789" * 2)
+
+      subject.upload(source, destination)
+      expect(File.binread(destination)).to eq(File.binread(source))
+      expect(communicator.chunks).to eq([[5, 20]])
+    end
+
+    it "uploads the whole file if the resumed upload is invalid" do
+      File.binwrite(partial_path, "9876543210" * 2)
+      subject = described_class.new(communicator, chunk_size: chunk_size,
+        checksum_type: :sha256)
+
+      subject.upload(source, destination)
+      expect(File.binread(destination)).to eq(File.binread(source))
+      expect(communicator.chunks.first).to eq([5, 20])
+      expect(communicator.chunks[1]).to eq([10, 0])
+    end
+
+    it "raises an error if the uploaded file does not match" do
+      allow(communicator).to receive(:file_size).and_return(nil, 3)
+
+      expect { subject.upload(source, destination) }.
+        to raise_error(Vagrant::Errors::UploadVerificationFailed)
+    end
+
+    context "with a checksum type" do
+      let(:checksum_type) { :sha256 }
+
+      it "verifies the checksum of the uploaded file" do
+        expect(communicator).to receive(:file_checksum).with(partial_path, :sha256).
+          and_call_original
+        subject.upload(source, destination)
+      end
+
+      it "raises an error if the checksum does not match" do
+        allow(communicator).to receive(:file_checksum).and_return("invalid")
+
+        expect { subject.upload(source, destination) }.
+          to raise_error(Vagrant::Errors::UploadVerificationFailed)
+      end
+    end
+
+    context "when the communicator does not support chunked uploads" do
+      before { allow(communicator).to receive(:chunked_upload?).and_return(false) }
+
+      it "does not upload the file" do
+        expect(communicator).not_to receive(:upload_chunk)
+        expect(subject.upload(source, destination)).to be(false)
+      end
+    end
+  end
+end
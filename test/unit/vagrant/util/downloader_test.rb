@@ -302,6 +302,132 @@ describe Vagrant::Util::Downloader do
         expect(subject.download!).to be(true)
         end
       end
+
+      context "with a rate limit" do
+        let(:options) { {limit_rate: "512k"} }
+
+        it "limits the transfer rate" do
+          i = curl_options.index("--output")
+          curl_options.insert(i, "512k")
+          curl_options.insert(i, "--limit-rate")
+          expect(Vagrant::Util::Subprocess).to receive(:execute).
+            with("curl", *curl_options).
+            and_return(subprocess_result)
+
+          expect(subject.download!).to be(true)
+        end
+      end
+    end
+
+    context "with continue" do
+      let(:tmp_dir) { Dir.mktmpdir("vagrant-downloader") }
+      let(:source) { "http://example.org/vagrant.box" }
+      let(:destination) { File.join(tmp_dir, "box.part") }
+      let(:resume_path) { destination + described_class::RESUME_SUFFIX }
+      let(:options) { {continue: true} }
+      let(:etag) { "\"abc\"" }
+      let(:accept_ranges) { "bytes" }
+      let(:headers) do
+        "HTTP/1.1 302 Found\r\nLocation: http://example.org/other.box\r\n\r\n" \
+          "HTTP/1.1 200 OK\r\nETag: #{etag}\r\nContent-Length: 100\r\n" \
+          "Accept-Ranges: #{accept_ranges}\r\n\r\n"
+      end
+      let(:stored) { {"etag" => "\"abc\"", "last_modified" => nil, "size" => 100, "ranges" => true} }
+
+      before do
+        allow(subject).to receive(:head).and_return(headers)
+      end
+
+      after { FileUtils.rm_rf(tmp_dir) }
+
+      it "stores the source details while downloading" do
+        expect(subject).to receive(:execute_curl) do |opts, *_|
+          expect(opts).to include("--continue-at")
+          expect(JSON.parse(File.read(resume_path))).to eq(stored)
+        end
+
+        subject.download!
+      end
+
+      it "removes the source details after downloading" do
+        allow(subject).to receive(:execute_curl)
+
+        subject.download!
+        expect(File.exist?(resume_path)).to be(false)
+      end
+
+      context "with a partial download" do
+        before do
+          File.write(destination, "partial")
+          File.write(resume_path, JSON.dump(stored))
+        end
+
+        it "resumes the download" do
+          expect(subject).to receive(:execute_curl) do |opts, *_|
+            expect(opts).to include("--continue-at")
+            expect(File.read(destination)).to eq("partial")
+          end
+
+          subject.download!
+        end
+
+        context "when the source changed" do
+          let(:etag) { "\"def\"" }
+
+          it "downloads from scratch" do
+            expect(subject).to receive(:execute_curl) do |opts, *_|
+              expect(File.exist?(destination)).to be(false)
+            end
+
+            subject.download!
+          end
+        end
+
+        context "when the source details were not stored" do
+          before { File.delete(resume_path) }
+
+          it "downloads from scratch" do
+            expect(subject).to receive(:execute_curl) do |opts, *_|
+              expect(File.exist?(destination)).to be(false)
+            end
+
+            subject.download!
+          end
+        end
+      end
+
+      context "when the server does not accept range requests" do
+        let(:accept_ranges) { "none" }
+
+        it "does not resume" do
+          expect(subject).to receive(:execute_curl) do |opts, *_|
+            expect(opts).not_to include("--continue-at")
+          end
+
+          subject.download!
+        end
+      end
+
+      context "when the transfer is interrupted" do
+        it "resumes the transfer" do
+          expect(subject).to receive(:execute_curl).and_raise(
+            Vagrant::Errors::DownloaderError.new(code: 18, message: "partial file")).ordered
+          expect(subject).to receive(:execute_curl) do |opts, *_|
+            expect(opts).to include("--continue-at")
+          end.ordered
+
+          expect(subject.download!).to be(true)
+        end
+
+        it "fails after the retries are exhausted" do
+          expect(subject).to receive(:execute_curl).
+            exactly(described_class::RESUME_RETRIES + 1).times.
+            and_raise(Vagrant::Errors::DownloaderError.new(code: 56, message: "recv failure"))
+
+          expect { subject.download! }.
+            to raise_error(Vagrant::Errors::DownloaderError)
+        end
+      end
     end
   end
 
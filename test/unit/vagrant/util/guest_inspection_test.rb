@@ -17,5 +17,18 @@ describe Vagrant::Util::GuestInspection::Linux do
       expect(comm).to receive(:test).with(/ps/, {sudo: true}).and_return(true)
       expect(subject.systemd?(comm)).to be(true)
     end
+
+    context "with the machine of the communicator" do
+      let(:guest_cache) { double("guest_cache") }
+      let(:machine) { double("machine", guest_cache: guest_cache) }
+
+      before { allow(comm).to receive(:machine).and_return(machine) }
+
+      it "uses the guest cache of the machine" do
+        expect(guest_cache).to receive(:fetch).with(:systemd).and_return(false)
+        expect(comm).not_to receive(:test)
+        expect(subject.systemd?(comm)).to be(false)
+      end
+    end
   end
 end
@@ -146,6 +146,70 @@ describe Vagrant::Util::GuestNetworks::Linux do
     end
   end
 
+  describe "#network_configurator" do
+    it "should return nil when no configurator is active" do
+      expect(subject.network_configurator(comm)).to be_nil
+    end
+
+    it "should detect systemd-networkd" do
+      comm.stub_command("systemctl -q is-active systemd-networkd.service", exit_code: 0)
+      expect(subject.network_configurator(comm)).to eq(:networkd)
+    end
+
+    it "should prefer NetworkManager when it is active" do
+      comm.stub_command("systemctl -q is-active systemd-networkd.service", exit_code: 0)
+      comm.stub_command("systemctl -q is-active NetworkManager.service", exit_code: 0)
+      comm.stub_command("command -v nmcli", exit_code: 0)
+      expect(subject.network_configurator(comm)).to eq(:network_manager)
+    end
+
+    it "should not detect NetworkManager without nmcli" do
+      comm.stub_command("systemctl -q is-active NetworkManager.service", exit_code: 0)
+      expect(subject.network_configurator(comm)).to be_nil
+    end
+  end
+
+  describe "#configure_networkd" do
+    it "should move unit files into the networkd directory" do
+      subject.configure_networkd(machine, [network_1, network_2])
+
+      expect(comm.received_commands).to include(%r{mv -f '/tmp/vagrant.*eth1.*' '/etc/systemd/network/50-vagrant-eth1.network'})
+      expect(comm.received_commands).to include(%r{mv -f '/tmp/vagrant.*eth2.*' '/etc/systemd/network/50-vagrant-eth2.network'})
+    end
+
+    it "should reload networkd and reconfigure the devices" do
+      subject.configure_networkd(machine, [network_1, network_2])
+
+      expect(comm.received_commands).to include(
+        "networkctl reload && networkctl reconfigure 'eth1' && networkctl reconfigure 'eth2'" \
+        " || systemctl restart systemd-networkd.service")
+    end
+
+    context "network unit file" do
+      let(:tempfile) { double("tempfile") }
+
+      before do
+        allow(tempfile).to receive(:binmode)
+        allow(tempfile).to receive(:write)
+        allow(tempfile).to receive(:fsync)
+        allow(tempfile).to receive(:close)
+        allow(tempfile).to receive(:path)
+        allow(Tempfile).to receive(:open).and_yield(tempfile)
+      end
+
+      it "should enable DHCP for dhcp networks" do
+        expect(tempfile).to receive(:write).with("[Match]\nName=eth1\n[Network]\nDHCP=yes\n")
+        subject.configure_networkd(machine, [network_1])
+      end
+
+      it "should set the address and gateway for static networks" do
+        expect(tempfile).to receive(:write).with(
+          "[Match]\nName=eth2\n[Network]\nDHCP=no\nAddress=33.33.33.10/16\nGateway=33.33.0.1\n")
+        subject.configure_networkd(machine, [network_2])
+      end
+    end
+  end
+
   describe "#get_current_devices" do
     it "should return a hash of current devices" do
       expect(comm).to receive(:execute).with("nmcli -t c show").and_yield(:stderr, "").and_yield(:stdout, "1:eth1:ethernet:eth1\n2:eth2:ethernet:eth2\n3:eth3:ethernet:eth3\n")
@@ -0,0 +1,88 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require File.expand_path("../../../base", __FILE__)
+
+require "vagrant/util/guest_tools"
+
+describe Vagrant::Util::GuestTools do
+  include_context "unit"
+
+  subject { described_class }
+
+  describe ".versions" do
+    let(:machine) { double("machine", provider: provider, guest: guest, provider_name: :virtualbox) }
+    let(:provider) { double("provider") }
+    let(:guest) { double("guest") }
+
+    before do
+      allow(provider).to receive(:capability?).with(:guest_tools_version).and_return(true)
+      allow(provider).to receive(:capability).with(:guest_tools_version).and_return("7.0.12")
+      allow(provider).to receive(:capability?).with(:guest_tools_installed_version).
+        and_return(false)
+      allow(guest).to receive(:capability?).with(:guest_tools_version).and_return(true)
+      allow(guest).to receive(:capability).with(:guest_tools_version, :virtualbox).
+        and_return("7.0.10")
+    end
+
+    it "returns the installed and expected versions" do
+      expect(subject.versions(machine)).to eq(["7.0.10", "7.0.12"])
+    end
+
+    it "returns nil if the provider does not expect a version" do
+      allow(provider).to receive(:capability).with(:guest_tools_version).and_return(nil)
+      expect(guest).not_to receive(:capability)
+      expect(subject.versions(machine)).to be_nil
+    end
+
+    it "returns nil if the guest does not support the capability" do
+      allow(guest).to receive(:capability?).with(:guest_tools_version).and_return(false)
+      expect(subject.versions(machine)).to be_nil
+    end
+
+    it "reads the installed version from the provider when supported" do
+      allow(provider).to receive(:capability?).with(:guest_tools_installed_version).
+        and_return(true)
+      allow(provider).to receive(:capability).with(:guest_tools_installed_version).
+        and_return("7.0.8")
+      expect(guest).not_to receive(:capability)
+      expect(subject.versions(machine)).to eq(["7.0.8", "7.0.12"])
+    end
+
+    it "returns nil if the guest is not ready" do
+      allow(guest).to receive(:capability?).
+        and_raise(Vagrant::Errors::MachineGuestNotReady)
+      expect(subject.versions(machine)).to be_nil
+    end
+  end
+
+  describe ".mismatch" do
+    it "is missing when no version is installed" do
+      expect(subject.mismatch(nil, "7.0.12")).to eq(:missing)
+    end
+
+    it "is major when the major versions are different" do
+      expect(subject.mismatch("6.1.40", "7.0.12")).to eq(:major)
+    end
+
+    it "is minor when the minor versions are different" do
+      expect(subject.mismatch("7.1.0", "7.0.12")).to eq(:minor)
+    end
+
+    it "is nil when only the patch versions are different" do
+      expect(subject.mismatch("7.0.10r158379", "7.0.12_Ubuntu")).to be_nil
+    end
+  end
+
+  describe ".severe?" do
+    it "is true for missing and major mismatches" do
+      expect(subject.severe?(:missing)).to be(true)
+      expect(subject.severe?(:major)).to be(true)
+    end
+
+    it "is false for minor mismatches" do
+      expect(subject.severe?(:minor)).to be(false)
+      expect(subject.severe?(nil)).to be(false)
+    end
+  end
+end
@@ -0,0 +1,128 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require File.expand_path("../../../base", __FILE__)
+
+require "vagrant/util/hosts_file"
+
+describe Vagrant::Util::HostsFile do
+  include_context "unit"
+
+  let(:dir) { Pathname.new(Dir.mktmpdir("vagrant-test-hosts-file")) }
+  let(:path) { dir.join("hosts") }
+  let(:lock_path) { dir.join("hosts.lock") }
+  let(:sudo) { true }
+  let(:entries) { [["192.168.56.10", "web.test"], ["192.168.56.10", "www.web.test"]] }
+
+  subject { described_class.new(path, lock_path: lock_path, sudo: sudo) }
+
+  before { path.write("127.0.0.1\tlocalhost\n") }
+
+  after { FileUtils.rm_rf(dir) }
+
+  describe "#update" do
+    it "adds a managed block with the entries" do
+      expect(subject.update("id", entries)).to be(true)
+      expect(path.read).to eq(<<-EOF.gsub(/^ {8}/, ""))
+        127.0.0.1\tlocalhost
+        # VAGRANT-BEGIN: id
+        192.168.56.10\tweb.test
+        192.168.56.10\twww.web.test
+        # VAGRANT-END: id
+      EOF
+    end
+
+    it "creates the lock file" do
+      subject.update("id", entries)
+      expect(lock_path).to be_file
+    end
+
+    it "does not modify the file when the entries are unchanged" do
+      subject.update("id", entries)
+      expect(subject.update("id", entries)).to be(false)
+      expect(path.read.scan("VAGRANT-BEGIN: id").length).to eq(1)
+    end
+
+    it "replaces the entries of an existing block" do
+      subject.update("id", entries)
+      subject.update("id", [["192.168.56.11", "web.test"]])
+      content = path.read
+      expect(content.scan("VAGRANT-BEGIN: id").length).to eq(1)
+      expect(content).to include("192.168.56.11\tweb.test")
+      expect(content).not_to include("192.168.56.10")
+    end
+
+    it "does not modify blocks with other IDs" do
+      subject.update("other", [["192.168.56.20", "db.test"]])
+      subject.update("id", entries)
+      expect(path.read).to include("192.168.56.20\tdb.test")
+    end
+
+    it "adds a newline before the block if the file does not end with one" do
+      path.write("127.0.0.1\tlocalhost")
+      subject.update("id", entries)
+      expect(path.read).to start_with("127.0.0.1\tlocalhost\n# VAGRANT-BEGIN: id\n")
+    end
+
+    it "removes the block when there are no entries" do
+      subject.update("id", entries)
+      expect(subject.update("id", [])).to be(true)
+      expect(path.read).to eq("127.0.0.1\tlocalhost\n")
+    end
+
+    context "when the hosts file is not writable" do
+      let(:result) { Vagrant::Util::Subprocess::Result.new(exit_code, "", "denied") }
+      let(:exit_code) { 0 }
+
+      before do
+        allow(path).to receive(:writable?).and_return(false)
+        allow(Pathname).to receive(:new).and_call_original
+        allow(Pathname).to receive(:new).with(path.to_s).and_return(path)
+        allow(Vagrant::Util::Subprocess).to receive(:execute).and_return(result)
+      end
+
+      it "copies the content into place with sudo" do
+        expect(Vagrant::Util::Subprocess).to receive(:execute).
+          with("sudo", "cp", anything, path.to_s).and_return(result)
+        subject.update("id", entries)
+      end
+
+      context "when the copy fails" do
+        let(:exit_code) { 1 }
+
+        it "raises an error" do
+          expect { subject.update("id", entries) }.
+            to raise_error(Vagrant::Errors::HostsFileUpdateFailed)
+        end
+      end
+
+      context "when sudo is disabled" do
+        let(:sudo) { false }
+
+        it "raises an error" do
+          expect(Vagrant::Util::Subprocess).not_to receive(:execute)
+          expect { subject.update("id", entries) }.
+            to raise_error(Vagrant::Errors::HostsFileUpdateFailed)
+        end
+      end
+    end
+  end
+
+  describe "#remove" do
+    it "removes the managed block" do
+      subject.update("id", entries)
+      expect(subject.remove("id")).to be(true)
+      expect(path.read).to eq("127.0.0.1\tlocalhost\n")
+    end
+
+    it "does not modify the file when the block does not exist" do
+      expect(subject).not_to receive(:write)
+      expect(subject.remove("id")).to be(false)
+    end
+
+    it "does not fail when the hosts file does not exist" do
+      path.delete
+      expect(subject.remove("id")).to be(false)
+    end
+  end
+end
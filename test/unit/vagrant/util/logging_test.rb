@@ -0,0 +1,96 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require File.expand_path("../../../base", __FILE__)
+
+require "vagrant/util/logging"
+require "vagrant/util/logging_formatter"
+
+describe Vagrant::Util::Logging do
+  subject { described_class }
+
+  describe ".parse_filter" do
+    it "parses the level of each subsystem" do
+      expect(subject.parse_filter("communicator=debug,plugin=info")).to eq(
+        "communicator" => Log4r::LNAMES.index("DEBUG"),
+        "plugin" => Log4r::LNAMES.index("INFO"),
+      )
+    end
+
+    it "accepts dashes within subsystems" do
+      expect(subject.parse_filter(" synced-folder = warn ")).to eq(
+        "synced_folder" => Log4r::LNAMES.index("WARN"),
+      )
+    end
+
+    it "raises an error for an unknown subsystem" do
+      expect { subject.parse_filter("network=debug") }.
+        to raise_error(ArgumentError, /network/)
+    end
+
+    it "raises an error for an unknown level" do
+      expect { subject.parse_filter("communicator=loud") }.
+        to raise_error(ArgumentError, /loud/)
+    end
+
+    it "raises an error for a missing level" do
+      expect { subject.parse_filter("communicator") }.
+        to raise_error(ArgumentError)
+    end
+  end
+
+  describe ".subsystem" do
+    it "returns the subsystem of a logger" do
+      expect(subject.subsystem("vagrant::communication::ssh")).to eq("communicator")
+      expect(subject.subsystem("vagrant::config::loader")).to eq("loader")
+      expect(subject.subsystem("vagrant::provider::virtualbox")).to eq("provider")
+    end
+
+    it "only matches whole names" do
+      expect(subject.subsystem("vagrant::provider_state")).to be_nil
+      expect(subject.subsystem("vagrant::machine")).to be_nil
+    end
+  end
+
+  describe ".with_fields" do
+    it "adds fields within the block" do
+      subject.with_fields(machine: "web") do
+        expect(subject.fields).to eq(machine: "web")
+      end
+      expect(subject.fields).to eq({})
+    end
+
+    it "nests fields" do
+      subject.with_fields(machine: "web", action: "up") do
+        subject.with_fields(action: "provision") do
+          expect(subject.fields).to eq(machine: "web", action: "provision")
+        end
+        expect(subject.fields).to eq(machine: "web", action: "up")
+      end
+    end
+
+    it "returns the result of the block" do
+      expect(subject.with_fields(machine: "web") { :result }).to eq(:result)
+    end
+  end
+
+  describe Vagrant::Util::LoggingFormatter do
+    let(:logger) { Log4r::Logger.new("vagrant::communication::test") }
+    let(:event) { Log4r::LogEvent.new(Log4r::LNAMES.index("INFO"), logger, nil, "message") }
+
+    subject { described_class.new(Log4r::BasicFormatter.new) }
+
+    it "adds the fields to the message" do
+      Vagrant::Util::Logging.with_fields(machine: "web", action: "up") do
+        expect(subject.format(event)).
+          to end_with("message subsystem=communicator machine=web action=up\n")
+      end
+    end
+
+    it "quotes values with spaces" do
+      Vagrant::Util::Logging.with_fields(machine: "my web") do
+        expect(subject.format(event)).to end_with("machine=\"my web\"\n")
+      end
+    end
+  end
+end
@@ -0,0 +1,209 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require File.expand_path("../../../base", __FILE__)
+
+require "vagrant/util/oci_registry"
+
+describe Vagrant::Util::OCIRegistry do
+  include_context "unit"
+
+  let(:reference) { "oci://registry.example.com/vagrant/base:1.0" }
+  let(:vagrantfile) { "Vagrant.configure(\"2\") { |c| c.ssh.port = 200 }\n" }
+  let(:config_digest) { "sha256:#{Digest::SHA256.hexdigest(vagrantfile)}" }
+  let(:manifest) {
+    {
+      "schemaVersion" => 2,
+      "config" => {
+        "mediaType" => "application/vnd.hashicorp.vagrantfile",
+        "digest" => config_digest,
+      },
+      "layers" => [],
+    }.to_json
+  }
+
+  subject { described_class.new(reference) }
+
+  describe ".reference?" do
+    it "should be true for oci references" do
+      expect(described_class.reference?(reference)).to be(true)
+    end
+
+    it "should be false for paths" do
+      expect(described_class.reference?("/path/to/Vagrantfile")).to be(false)
+    end
+  end
+
+  describe ".parse" do
+    it "should parse a tagged reference" do
+      ref = described_class.parse(reference)
+      expect(ref.registry).to eq("registry.example.com")
+      expect(ref.repository).to eq("vagrant/base")
+      expect(ref.tag).to eq("1.0")
+      expect(ref.digest).to be_nil
+    end
+
+    it "should default the tag to latest" do
+      ref = described_class.parse("oci://registry.example.com/vagrant/base")
+      expect(ref.tag).to eq("latest")
+    end
+
+    it "should parse a registry with a port" do
+      ref = described_class.parse("oci://localhost:5000/base")
+      expect(ref.registry).to eq("localhost:5000")
+      expect(ref.repository).to eq("base")
+      expect(ref.tag).to eq("latest")
+    end
+
+    it "should parse a digest reference" do
+      ref = described_class.parse("oci://registry.example.com/base@sha256:abc123")
+      expect(ref.repository).to eq("base")
+      expect(ref.digest).to eq("sha256:abc123")
+      expect(ref.tag).to be_nil
+    end
+
+    it "should raise an error without a repository" do
+      expect { described_class.parse("oci://registry.example.com") }.
+        to raise_error(Vagrant::Errors::OCIReferenceInvalid)
+    end
+
+    it "should raise an error with an invalid digest" do
+      expect { described_class.parse("oci://registry.example.com/base@latest") }.
+        to raise_error(Vagrant::Errors::OCIReferenceInvalid)
+    end
+  end
+
+  describe "#fetch_vagrantfile" do
+    let(:cache_dir) { temporary_dir }
+
+    before do
+      allow(subject).to receive(:request).with("manifests/1.0", anything).
+        and_return(manifest)
+      allow(subject).to receive(:request).with("blobs/#{config_digest}", anything).
+        and_return(vagrantfile)
+    end
+
+    it "should write the config blob to the cache" do
+      path = subject.fetch_vagrantfile(cache_dir)
+      expect(path.read).to eq(vagrantfile)
+      expect(path.to_s).to include(config_digest.sub(":", "-"))
+    end
+
+    it "should not fetch the blob when it is cached" do
+      subject.fetch_vagrantfile(cache_dir)
+
+      registry = described_class.new(reference)
+      allow(registry).to receive(:request).with("manifests/1.0", anything).
+        and_return(manifest)
+      expect(registry).not_to receive(:request).with("blobs/#{config_digest}", anything)
+      expect(registry.fetch_vagrantfile(cache_dir).read).to eq(vagrantfile)
+    end
+
+    it "should raise an error when the blob does not match the digest" do
+      allow(subject).to receive(:request).with("blobs/#{config_digest}", anything).
+        and_return("modified")
+
+      expect { subject.fetch_vagrantfile(cache_dir) }.
+        to raise_error(Vagrant::Errors::OCIDigestMismatch)
+      expect(Dir.glob(cache_dir.join("**", "Vagrantfile").to_s)).to be_empty
+    end
+
+    it "should raise an error when the manifest has no config" do
+      allow(subject).to receive(:request).with("manifests/1.0", anything).
+        and_return({"layers" => []}.to_json)
+
+      expect { subject.fetch_vagrantfile(cache_dir) }.
+        to raise_error(Vagrant::Errors::OCIManifestInvalid)
+    end
+  end
+
+  describe "#manifest" do
+    let(:response) { double("response", code: code, body: manifest, message: "") }
+    let(:code) { "200" }
+
+    before do
+      allow(subject).to receive(:get).and_return(response)
+    end
+
+    it "should return the parsed manifest" do
+      expect(subject.manifest["config"]["digest"]).to eq(config_digest)
+    end
+
+    context "when the artifact does not exist" do
+      let(:code) { "404" }
+
+      it "should raise a not found error" do
+        expect { subject.manifest }.to raise_error(Vagrant::Errors::OCINotFound)
+      end
+    end
+
+    context "when access is denied" do
+      let(:code) { "403" }
+
+      it "should raise an unauthorized error" do
+        expect { subject.manifest }.to raise_error(Vagrant::Errors::OCIUnauthorized)
+      end
+    end
+
+    context "when authentication is requested" do
+      let(:code) { "401" }
+
+      before do
+        allow(response).to receive(:[]).with("www-authenticate").
+          and_return("Bearer realm=\"https://auth.example.com/token\",service=\"registry\"")
+      end
+
+      it "should authenticate and retry the request" do
+        expect(subject).to receive(:authenticate!).
+          with("Bearer realm=\"https://auth.example.com/token\",service=\"registry\"")
+        expect(subject).to receive(:get).twice.and_return(response)
+        expect { subject.manifest }.to raise_error(Vagrant::Errors::OCIUnauthorized)
+      end
+    end
+  end
+
+  describe "docker credentials" do
+    let(:docker_dir) { temporary_dir }
+    let(:docker_config) { {} }
+
+    before do
+      File.write(docker_dir.join("config.json"), docker_config.to_json)
+    end
+
+    around do |example|
+      with_temp_env("DOCKER_CONFIG" => docker_dir.to_s) { example.run }
+    end
+
+    context "with stored credentials" do
+      let(:docker_config) {
+        {"auths" => {"registry.example.com" => {"auth" => Base64.strict_encode64("user:pass")}}}
+      }
+
+      it "should return the stored credentials" do
+        expect(subject.send(:docker_credentials)).to eq(username: "user", secret: "pass")
+      end
+    end
+
+    context "with a credential helper" do
+      let(:docker_config) { {"credHelpers" => {"registry.example.com" => "test"}} }
+      let(:result) {
+        Vagrant::Util::Subprocess::Result.new(0, {"Username" => "helper", "Secret" => "token"}.to_json, "")
+      }
+
+      it "should request credentials from the helper" do
+        expect(Vagrant::Util::Which).to receive(:which).with("docker-credential-test").
+          and_return("/bin/docker-credential-test")
+        expect(Vagrant::Util::Subprocess).to receive(:execute).
+          with("/bin/docker-credential-test", "get", notify: [:stdin]).
+          and_return(result)
+        expect(subject.send(:docker_credentials)).to eq(username: "helper", secret: "token")
+      end
+    end
+
+    context "without credentials for the registry" do
+      it "should return nil" do
+        expect(subject.send(:docker_credentials)).to be_nil
+      end
+    end
+  end
+end
@@ -106,4 +106,18 @@ describe Vagrant::Util::Retryable do
     expect { klass.retryable(tries: 5, sleep: 10, &block) }.
       to raise_error(RuntimeError)
   end
+
+  it "increases the sleep between retries with a backoff" do
+    block = lambda do
+      raise RuntimeError, "Try"
+    end
+
+    expect(klass).to receive(:sleep).with(1).ordered
+    expect(klass).to receive(:sleep).with(2).ordered
+    expect(klass).to receive(:sleep).with(4).ordered
+    expect(klass).to receive(:sleep).with(5).ordered
+
+    expect { klass.retryable(tries: 5, sleep: 1, backoff: 2, max_sleep: 5, &block) }.
+      to raise_error(RuntimeError)
+  end
 end
@@ -32,6 +32,37 @@ describe Vagrant::Util::SSH do
     end
   end
 
+  describe ".environment_exports" do
+    let(:ssh_info) {{
+      host: "localhost",
+      port: 2222,
+      username: "vagrant",
+      private_key_path: [private_key_path],
+    }}
+
+    after { Vagrant::Util::CredentialScrubber.reset! }
+
+    it "escapes the values" do
+      expect(described_class.environment_exports("FOO" => "a b")).
+        to eq("export FOO=a\\ b; ")
+    end
+
+    it "scrubs escaped secrets from the logged SSH command" do
+      Vagrant::Util::CredentialScrubber.sensitive("s3cret value")
+      command = described_class.environment_exports("TOKEN" => "s3cret value")
+      allow(Vagrant::Util::Which).to receive(:which).and_return("ssh")
+      allow(described_class).to receive(:_raw_exec)
+      allow(described_class::LOGGER).to receive(:info)
+      expect(described_class::LOGGER).to receive(:info).with(/Invoking SSH/) do |message|
+        scrubbed = Vagrant::Util::CredentialScrubber.desensitize(message)
+        expect(scrubbed).to include("TOKEN=")
+        expect(scrubbed).not_to include("s3cret")
+      end
+
+      described_class.exec(ssh_info, extra_args: ["-t", "#{command}exec \"$SHELL\" -l"])
+    end
+  end
+
   describe "#exec" do
     let(:ssh_info) {{
       host: "localhost",
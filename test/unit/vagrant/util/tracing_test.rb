@@ -0,0 +1,107 @@
+# Copyright IBM Corp. 2010, 2025
+# SPDX-License-Identifier: BUSL-1.1
+
+require File.expand_path("../../../base", __FILE__)
+
+require "vagrant/util/tracing"
+
+describe Vagrant::Util::Tracing do
+  include_context "unit"
+
+  subject { described_class }
+
+  let(:otel_env) do
+    {
+      "OTEL_TRACES_EXPORTER" => nil,
+      "OTEL_EXPORTER_OTLP_ENDPOINT" => nil,
+      "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT" => nil,
+      "OTEL_SDK_DISABLED" => nil,
+    }
+  end
+
+  before { subject.reset! }
+  after { subject.reset! }
+
+  describe ".enabled?" do
+    it "is disabled when no exporter is configured" do
+      with_temp_env(otel_env) do
+        expect(subject).not_to receive(:require)
+        expect(subject.enabled?).to be(false)
+      end
+    end
+
+    it "is disabled when the exporter is none" do
+      with_temp_env(otel_env.merge("OTEL_TRACES_EXPORTER" => "none")) do
+        expect(subject).not_to receive(:require)
+        expect(subject.enabled?).to be(false)
+      end
+    end
+
+    it "is disabled when the SDK is disabled" do
+      with_temp_env(otel_env.merge(
+        "OTEL_EXPORTER_OTLP_ENDPOINT" => "http://localhost:4318",
+        "OTEL_SDK_DISABLED" => "true")) do
+        expect(subject).not_to receive(:require)
+        expect(subject.enabled?).to be(false)
+      end
+    end
+
+    it "is disabled when the OpenTelemetry libraries can not be loaded" do
+      with_temp_env(otel_env.merge("OTEL_EXPORTER_OTLP_ENDPOINT" => "http://localhost:4318")) do
+        expect(subject).to receive(:require).with("opentelemetry/sdk").
+          and_raise(LoadError)
+        expect(subject.enabled?).to be(false)
+        expect(subject.libraries_missing?).to be(true)
+      end
+    end
+
+    it "does not report missing libraries when no exporter is configured" do
+      with_temp_env(otel_env) do
+        expect(subject.libraries_missing?).to be(false)
+      end
+    end
+
+    it "only configures tracing once" do
+      with_temp_env(otel_env) do
+        expect(subject).to receive(:setup).once.and_call_original
+        2.times { subject.enabled? }
+      end
+    end
+  end
+
+  context "when disabled" do
+    before { allow(subject).to receive(:enabled?).and_return(false) }
+
+    it "yields nil to the span block" do
+      expect { |b| subject.span("test", &b) }.to yield_with_args(nil)
+    end
+
+    it "returns the result of the span block" do
+      expect(subject.span("test") { :result }).to eq(:result)
+    end
+
+    it "does not have a current context" do
+      expect(subject.current_context).to be_nil
+    end
+
+    it "yields to the context block" do
+      expect(subject.with_context(nil) { :result }).to eq(:result)
+    end
+
+    it "does not propagate environment variables" do
+      expect(subject.propagation_env).to eq({})
+    end
+  end
+
+  describe ".machine_attributes" do
+    let(:machine) { double("machine", name: :web, provider_name: :virtualbox) }
+
+    it "includes the target name, provider, and action" do
+      expect(subject.machine_attributes(machine, :up)).to eq(
+        "vagrant.target.name" => :web,
+        "vagrant.provider" => :virtualbox,
+        "vagrant.action" => :up,
+      )
+    end
+  end
+end
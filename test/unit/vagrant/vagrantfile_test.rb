@@ -189,6 +189,43 @@ describe Vagrant::Vagrantfile do
       expect(config.ssh.port).to eq(100)
     end
 
+    it "configures a clone with the configuration of its source" do
+      register_provider("foo")
+
+      configure do |config|
+        config.vm.define "foo" do |f|
+          f.ssh.port = 100
+          f.vm.network "forwarded_port", guest: 80, host: 8080
+        end
+      end
+
+      subject.config.vm.define_clone(:foo2, :foo)
+
+      results = subject.machine_config(:foo2, :foo, boxes)
+      config  = results[:config]
+      expect(config.vm.box).to be_nil
+      expect(config.ssh.port).to eq(100)
+
+      _, options = config.vm.networks.find { |_, o| o[:guest] == 80 }
+      expect(options[:auto_correct]).to be(true)
+    end
+
+    it "does not correct the forwarded ports of the source of a clone" do
+      register_provider("foo")
+
+      configure do |config|
+        config.vm.define "foo" do |f|
+          f.vm.network "forwarded_port", guest: 80, host: 8080
+        end
+      end
+
+      subject.config.vm.define_clone(:foo2, :foo)
+
+      config = subject.machine_config(:foo, :foo, boxes)[:config]
+      _, options = config.vm.networks.find { |_, o| o[:guest] == 80 }
+      expect(options[:auto_correct]).not_to be(true)
+    end
+
     it "configures with box configuration if it exists" do
       register_provider("foo")
 
@@ -505,6 +542,135 @@ describe Vagrant::Vagrantfile do
     end
   end
 
+  describe "#config_provenance" do
+    let(:iso_env) { isolated_environment }
+    let(:boxes) { Vagrant::BoxCollection.new(iso_env.boxes_dir) }
+
+    before { register_provider("foo") }
+
+    it "reports the location the value was set" do
+      configure do |config|
+        config.vm.box = "base"
+      end
+
+      expect(subject.config_provenance("vm.box", :default, :foo, boxes)).
+        to eq([:test])
+    end
+
+    it "reports an empty list for values that were not set" do
+      configure do |config|
+        config.vm.box = "base"
+      end
+
+      expect(subject.config_provenance("vm.box_url", :default, :foo, boxes)).
+        to be_empty
+    end
+
+    it "reports the sub-machine when it overrides the value" do
+      configure do |config|
+        config.vm.box_url = "http://example.com/root.box"
+
+        config.vm.define "foo" do |f|
+          f.vm.box_url = "http://example.com/foo.box"
+        end
+      end
+
+      expect(subject.config_provenance("vm.box_url", :foo, :foo, boxes)).
+        to eq([:machine])
+    end
+
+    it "reports the provider when a provider override wins" do
+      configure do |config|
+        config.ssh.port = 1
+
+        config.vm.provider "foo" do |_, c|
+          c.ssh.port = 100
+        end
+      end
+
+      expect(subject.config_provenance("ssh.port", :default, :foo, boxes)).
+        to eq([:provider])
+    end
+
+    it "reports the box when the value is only set in the box" do
+      configure do |config|
+        config.vm.box = "base"
+      end
+
+      iso_env.box3("base", "1.0", :foo, vagrantfile: <<-VF)
+      Vagrant.configure("2") do |config|
+        config.ssh.port = 123
+      end
+      VF
+
+      expect(subject.config_provenance("ssh.port", :default, :foo, boxes)).
+        to eq([:box])
+      expect(subject.config_provenance("vm.box", :default, :foo, boxes)).
+        to eq([:test])
+    end
+
+    it "reports multiple locations when values are combined" do
+      configure do |config|
+        config.vm.provision "shell", inline: "echo root"
+
+        config.vm.define "foo" do |f|
+          f.vm.provision "shell", inline: "echo foo"
+        end
+      end
+
+      expect(subject.config_provenance("vm.provisioners", :foo, :foo, boxes)).
+        to eq([:test, :machine])
+    end
+
+    it "reports the location of each entry of a hash" do
+      configure do |config|
+        config.vm.environment = { "ROLE" => "base", "ZONE" => "a" }
+
+        config.vm.define "foo" do |f|
+          f.vm.environment = { "ROLE" => "web" }
+        end
+      end
+
+      expect(subject.config_provenance("vm.environment.ROLE", :foo, :foo, boxes)).
+        to eq([:machine])
+      expect(subject.config_provenance("vm.environment.ZONE", :foo, :foo, boxes)).
+        to eq([:test])
+      expect(subject.config_provenance("vm.environment", :foo, :foo, boxes)).
+        to eq([:test, :machine])
+    end
+
+    context "with a provider profile" do
+      before do
+        config_class = Class.new(Vagrant.plugin("2", "config")) do
+          attr_accessor :cpus
+          attr_accessor :memory
+        end
+
+        register_provider("bar", config_class)
+      end
+
+      it "reports the profile for inherited settings of the provider" do
+        configure do |config|
+          config.vm.provider_profile "big" do |p|
+            p.cpus = 4
+            p.memory = 4096
+          end
+
+          config.vm.define "foo" do |f|
+            f.vm.provider "bar", profile: "big" do |b|
+              b.memory = 1024
+            end
+          end
+        end
+
+        expect(subject.config_provenance("bar.cpus", :foo, :bar, boxes)).
+          to eq([:"profile:big"])
+        expect(subject.config_provenance("bar.memory", :foo, :bar, boxes)).
+          to eq([:machine])
+      end
+    end
+  end
+
   describe "#machine_names" do
     it "returns the default name when single-VM" do
       configure { |config| }